@@ -0,0 +1,130 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+// InviteAttendeeV1 приглашает пользователя на событие. Приглашать может только владелец события.
+func (s *Server) InviteAttendeeV1(ctx context.Context, req *event.InviteAttendeeRequestV1) (*event.AttendeeResponseV1, error) {
+	callerID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	eventID, userID, err := parseAttendeeIDs(req.GetEventId(), req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwner(ctx, callerID, eventID); err != nil {
+		return nil, err
+	}
+
+	a, err := s.r.InviteAttendee(ctx, eventID, userID)
+	if err != nil {
+		return nil, attendeeError(err)
+	}
+
+	return &event.AttendeeResponseV1{
+		Attendee: newAttendeeV1(a),
+	}, nil
+}
+
+// RemoveAttendeeV1 удаляет участника события.
+// Удалить участника может владелец события, а также сам участник.
+func (s *Server) RemoveAttendeeV1(ctx context.Context, req *event.RemoveAttendeeRequestV1) (*emptypb.Empty, error) {
+	callerID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	eventID, userID, err := parseAttendeeIDs(req.GetEventId(), req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	if userID != callerID {
+		if err := s.checkOwner(ctx, callerID, eventID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.r.RemoveAttendee(ctx, eventID, userID); err != nil {
+		return nil, attendeeError(err)
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// RespondToEventV1 сохраняет ответ вызывающего пользователя на приглашение.
+func (s *Server) RespondToEventV1(ctx context.Context, req *event.RespondToEventRequestV1) (*event.AttendeeResponseV1, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	eventID, err := uuid.Parse(req.GetEventId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid uuid")
+	}
+
+	rsvp, err := calendar.ParseRSVPStatus(req.GetStatus())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid status")
+	}
+
+	a, err := s.r.RespondAttendee(ctx, eventID, userID, rsvp)
+	if err != nil {
+		return nil, attendeeError(err)
+	}
+
+	return &event.AttendeeResponseV1{
+		Attendee: newAttendeeV1(a),
+	}, nil
+}
+
+// parseAttendeeIDs разбирает идентификаторы события и участника.
+func parseAttendeeIDs(eventID, userID string) (uuid.UUID, uuid.UUID, error) {
+	eID, err := uuid.Parse(eventID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, "invalid uuid")
+	}
+
+	uID, err := uuid.Parse(userID)
+	if err != nil {
+		return uuid.Nil, uuid.Nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	return eID, uID, nil
+}
+
+// attendeeError преобразует ошибку репозитория при работе с участниками в статус gRPC.
+func attendeeError(err error) error {
+	switch {
+	case errors.Is(err, calendar.ErrNotFound):
+		return status.Error(codes.NotFound, "invitation not found")
+	case errors.Is(err, calendar.ErrInvalidAttendee):
+		return status.Error(codes.InvalidArgument, "owner cannot be invited to own event")
+	case errors.Is(err, calendar.ErrDateBusy):
+		return status.Error(codes.InvalidArgument, "that date is already taken by another event")
+	}
+
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+// newAttendeeV1 преобразует участника события в его представление в API.
+func newAttendeeV1(a *calendar.Attendee) *event.AttendeeV1 {
+	return &event.AttendeeV1{
+		UserId: a.UserID.String(),
+		Status: string(a.Status),
+	}
+}
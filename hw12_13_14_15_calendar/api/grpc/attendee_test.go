@@ -0,0 +1,145 @@
+package grpc
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/mocks"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+func TestServer_InviteAttendeeV1(t *testing.T) {
+	eventID := uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")
+	ownerID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	guestID := uuid.MustParse("2f0d2079-e9a2-4810-8cae-eb6729c50580")
+
+	req := &event.InviteAttendeeRequestV1{
+		EventId: eventID.String(),
+		UserId:  guestID.String(),
+	}
+
+	t.Run("base test", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		m.On("FindEventByID", mock.Anything, eventID).Return(&calendar.Event{ID: eventID, UserID: ownerID}, nil).Once()
+		m.On("InviteAttendee", mock.Anything, eventID, guestID).Return(&calendar.Attendee{
+			EventID: eventID,
+			UserID:  guestID,
+			Status:  calendar.RSVPNeedsAction,
+		}, nil).Once()
+
+		s := Server{r: m}
+		got, err := s.InviteAttendeeV1(userCtx, req)
+
+		require.NoError(t, err)
+		require.Equal(t, &event.AttendeeV1{UserId: guestID.String(), Status: "needs-action"}, got.Attendee)
+	})
+
+	t.Run("another user event", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		m.On("FindEventByID", mock.Anything, eventID).Return(&calendar.Event{ID: eventID, UserID: guestID}, nil).Once()
+
+		s := Server{r: m}
+		_, err := s.InviteAttendeeV1(userCtx, req)
+
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("invalid user id", func(t *testing.T) {
+		s := Server{r: mocks.NewRepository(t)}
+
+		_, err := s.InviteAttendeeV1(userCtx, &event.InviteAttendeeRequestV1{EventId: eventID.String(), UserId: "foo"})
+
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_RemoveAttendeeV1(t *testing.T) {
+	eventID := uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")
+	callerID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+
+	t.Run("attendee leaves event", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		m.On("RemoveAttendee", mock.Anything, eventID, callerID).Return(nil).Once()
+
+		s := Server{r: m}
+		_, err := s.RemoveAttendeeV1(userCtx, &event.RemoveAttendeeRequestV1{
+			EventId: eventID.String(),
+			UserId:  callerID.String(),
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("not owner", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		m.On("FindEventByID", mock.Anything, eventID).Return(&calendar.Event{ID: eventID, UserID: uuid.New()}, nil).Once()
+
+		s := Server{r: m}
+		_, err := s.RemoveAttendeeV1(userCtx, &event.RemoveAttendeeRequestV1{
+			EventId: eventID.String(),
+			UserId:  uuid.NewString(),
+		})
+
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+}
+
+func TestServer_RespondToEventV1(t *testing.T) {
+	eventID := uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")
+	callerID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+
+	tests := []struct {
+		name     string
+		status   string
+		repoErr  error
+		wantCode codes.Code
+	}{
+		{name: "accepted", status: "accepted", wantCode: codes.OK},
+		{name: "invalid status", status: "maybe", wantCode: codes.InvalidArgument},
+		{name: "not invited", status: "declined", repoErr: calendar.ErrNotFound, wantCode: codes.NotFound},
+		{name: "busy", status: "accepted", repoErr: calendar.ErrDateBusy, wantCode: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewRepository(t)
+			defer m.AssertExpectations(t)
+
+			if tt.wantCode != codes.InvalidArgument || tt.repoErr != nil {
+				var a *calendar.Attendee
+				if tt.repoErr == nil {
+					a = &calendar.Attendee{EventID: eventID, UserID: callerID, Status: calendar.RSVPStatus(tt.status)}
+				}
+
+				m.On("RespondAttendee", mock.Anything, eventID, callerID, calendar.RSVPStatus(tt.status)).
+					Return(a, errors.Wrap(tt.repoErr, "respond attendee")).Once()
+			}
+
+			s := Server{r: m}
+			got, err := s.RespondToEventV1(userCtx, &event.RespondToEventRequestV1{
+				EventId: eventID.String(),
+				Status:  tt.status,
+			})
+
+			require.Equal(t, tt.wantCode, status.Code(err))
+
+			if tt.wantCode == codes.OK {
+				require.Equal(t, tt.status, got.Attendee.Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+)
+
+// authorizationKey ключ метаданных с токеном доступа.
+const authorizationKey = "authorization"
+
+// healthServicePrefix префикс методов сервиса проверки состояния, доступных без токена.
+const healthServicePrefix = "/grpc.health.v1.Health/"
+
+// AuthUnaryInterceptor проверяет токен доступа из метаданных запроса
+// и кладет идентификатор пользователя в контекст.
+// Сам токен из метаданных удаляется, чтобы не попадать в логи.
+func AuthUnaryInterceptor(v *auth.Verifier) grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		if isPublicMethod(info.FullMethod) {
+			return handler(ctx, req)
+		}
+
+		ctx, err := authenticate(ctx, v)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// AuthStreamInterceptor аналог AuthUnaryInterceptor для потоковых вызовов.
+func AuthStreamInterceptor(v *auth.Verifier) grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		if isPublicMethod(info.FullMethod) {
+			return handler(srv, ss)
+		}
+
+		ctx, err := authenticate(ss.Context(), v)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &authStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// authStream поток с контекстом, содержащим идентификатор пользователя.
+type authStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context возвращает контекст потока.
+func (s *authStream) Context() context.Context {
+	return s.ctx
+}
+
+// isPublicMethod проверяет, доступен ли метод без токена.
+func isPublicMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, healthServicePrefix)
+}
+
+// authenticate проверяет токен доступа из метаданных и возвращает
+// контекст с идентификатором пользователя и метаданными без токена.
+func authenticate(ctx context.Context, v *auth.Verifier) (context.Context, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get(authorizationKey)
+	if len(values) == 0 {
+		return nil, status.Error(codes.Unauthenticated, "authorization token required")
+	}
+
+	token, err := auth.BearerToken(values[0])
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	userID, err := v.Verify(token)
+	if err != nil {
+		return nil, status.Error(codes.Unauthenticated, err.Error())
+	}
+
+	md = md.Copy()
+	md.Delete(authorizationKey)
+
+	ctx = metadata.NewIncomingContext(ctx, md)
+
+	return auth.WithUserID(ctx, userID), nil
+}
+
+// callerID возвращает идентификатор вызывающего пользователя.
+// Если в запросе передан user_id, он должен совпадать с пользователем из токена.
+func callerID(ctx context.Context, requested string) (uuid.UUID, error) {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return uuid.Nil, status.Error(codes.Unauthenticated, "unauthenticated")
+	}
+
+	if requested == "" {
+		return userID, nil
+	}
+
+	requestedID, err := uuid.Parse(requested)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	if requestedID != userID {
+		return uuid.Nil, status.Error(codes.PermissionDenied, "access to events of another user is denied")
+	}
+
+	return userID, nil
+}
+
+// checkOwner проверяет, что событие принадлежит пользователю.
+func (s *Server) checkOwner(ctx context.Context, userID, eventID uuid.UUID) error {
+	e, err := s.r.FindEventByID(ctx, eventID)
+	if err != nil {
+		if errors.Is(err, calendar.ErrNotFound) {
+			return status.Error(codes.NotFound, "event not found")
+		}
+
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	if e.UserID != userID {
+		return status.Error(codes.PermissionDenied, "event belongs to another user")
+	}
+
+	return nil
+}
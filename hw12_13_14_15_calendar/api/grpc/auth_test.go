@@ -0,0 +1,154 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+	eventmocks "github.com/RomanSarvarov/otus_go_home_work/calendar/mocks/proto/event"
+)
+
+func TestAuthUnaryInterceptor(t *testing.T) {
+	v, err := auth.NewVerifier(auth.Config{HMACSecret: "secret"})
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.RegisteredClaims{
+		Subject:   "123e4567-e89b-12d3-a456-426614174000",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	interceptor := AuthUnaryInterceptor(v)
+
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		userID, ok := auth.UserIDFromContext(ctx)
+		require.True(t, ok)
+
+		md, _ := metadata.FromIncomingContext(ctx)
+		require.Empty(t, md.Get("authorization"))
+
+		return userID, nil
+	}
+
+	tests := []struct {
+		name     string
+		md       metadata.MD
+		wantCode codes.Code
+	}{
+		{
+			name:     "valid token",
+			md:       metadata.Pairs("authorization", "Bearer "+token),
+			wantCode: codes.OK,
+		},
+		{
+			name:     "without token",
+			md:       metadata.MD{},
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "invalid token",
+			md:       metadata.Pairs("authorization", "Bearer "+token+"x"),
+			wantCode: codes.Unauthenticated,
+		},
+		{
+			name:     "not bearer",
+			md:       metadata.Pairs("authorization", token),
+			wantCode: codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := metadata.NewIncomingContext(context.Background(), tt.md)
+
+			got, err := interceptor(ctx, nil, &grpc.UnaryServerInfo{}, handler)
+
+			require.Equal(t, tt.wantCode, status.Code(err))
+
+			if tt.wantCode == codes.OK {
+				require.Equal(t, uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"), got)
+			}
+		})
+	}
+
+	t.Run("health check without token", func(t *testing.T) {
+		info := &grpc.UnaryServerInfo{FullMethod: "/grpc.health.v1.Health/Check"}
+
+		got, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+			return "serving", nil
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "serving", got)
+	})
+}
+
+func TestAuthStreamInterceptor(t *testing.T) {
+	v, err := auth.NewVerifier(auth.Config{HMACSecret: "secret"})
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.RegisteredClaims{
+		Subject:   "123e4567-e89b-12d3-a456-426614174000",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	interceptor := AuthStreamInterceptor(v)
+
+	t.Run("valid token", func(t *testing.T) {
+		ss := eventmocks.NewEventService_WatchEventsV1Server(t)
+		ss.On("Context").Return(metadata.NewIncomingContext(
+			context.Background(),
+			metadata.Pairs("authorization", "Bearer "+token),
+		))
+
+		err := interceptor(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+			userID, ok := auth.UserIDFromContext(stream.Context())
+			require.True(t, ok)
+			require.Equal(t, uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"), userID)
+
+			md, _ := metadata.FromIncomingContext(stream.Context())
+			require.Empty(t, md.Get("authorization"))
+
+			return nil
+		})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("without token", func(t *testing.T) {
+		ss := eventmocks.NewEventService_WatchEventsV1Server(t)
+		ss.On("Context").Return(context.Background())
+
+		err := interceptor(nil, ss, &grpc.StreamServerInfo{}, func(srv interface{}, stream grpc.ServerStream) error {
+			require.Fail(t, "handler must not be called")
+
+			return nil
+		})
+
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+
+	t.Run("health watch without token", func(t *testing.T) {
+		info := &grpc.StreamServerInfo{FullMethod: "/grpc.health.v1.Health/Watch"}
+
+		called := false
+
+		err := interceptor(nil, nil, info, func(srv interface{}, stream grpc.ServerStream) error {
+			called = true
+
+			return nil
+		})
+
+		require.NoError(t, err)
+		require.True(t, called)
+	})
+}
@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+// GetFreeBusyV1 возвращает занятые и свободные промежутки пользователя.
+// Сведения о занятости не раскрывают содержимое событий, поэтому
+// доступны любому аутентифицированному пользователю.
+func (s *Server) GetFreeBusyV1(ctx context.Context, req *event.GetFreeBusyRequestV1) (*event.GetFreeBusyResponseV1, error) {
+	userID, err := participantID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	from, to, err := searchRange(req.GetStartAt(), req.GetEndAt())
+	if err != nil {
+		return nil, err
+	}
+
+	windows, err := workingWindows(req.GetWorkingHours(), req.GetTimeZone(), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	busy, err := s.busyIntervals(ctx, userID, from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	free := calendar.FreeIntervals(busy, windows, time.Duration(req.GetMinSlotMinutes())*time.Minute)
+
+	return &event.GetFreeBusyResponseV1{
+		Busy: newIntervalsV1(busy),
+		Free: newIntervalsV1(free),
+	}, nil
+}
+
+// participantID возвращает идентификатор пользователя, о занятости которого запрашиваются сведения.
+// Пустое значение означает вызывающего пользователя.
+func participantID(ctx context.Context, requested string) (uuid.UUID, error) {
+	callerID, err := callerID(ctx, "")
+	if err != nil {
+		return uuid.Nil, err
+	}
+
+	if requested == "" {
+		return callerID, nil
+	}
+
+	userID, err := uuid.Parse(requested)
+	if err != nil {
+		return uuid.Nil, status.Error(codes.InvalidArgument, "invalid user id")
+	}
+
+	return userID, nil
+}
+
+// searchRange проверяет промежуток поиска свободного времени.
+func searchRange(startAt, endAt int64) (from, to time.Time, err error) {
+	from, to = time.Unix(startAt, 0), time.Unix(endAt, 0)
+
+	if !from.Before(to) {
+		return from, to, status.Error(codes.InvalidArgument, "invalid range")
+	}
+
+	if to.Sub(from) > calendar.OverlapHorizon {
+		return from, to, status.Error(codes.InvalidArgument, "range is too long")
+	}
+
+	return from, to, nil
+}
+
+// workingWindows возвращает промежутки, в которых ищется свободное время:
+// рабочие часы в часовом поясе tz, если они переданы, иначе весь промежуток from - to.
+func workingWindows(wh *event.WorkingHoursV1, tz string, from, to time.Time) ([]calendar.Interval, error) {
+	loc, err := loadLocation(tz)
+	if err != nil {
+		return nil, err
+	}
+
+	if wh == nil {
+		return []calendar.Interval{{Start: from, End: to}}, nil
+	}
+
+	invalid := status.Error(codes.InvalidArgument, "invalid working hours")
+
+	w := calendar.WorkingHours{Location: loc}
+
+	if w.Start, err = parseClock(wh.GetStart()); err != nil {
+		return nil, invalid
+	}
+
+	if w.End, err = parseClock(wh.GetEnd()); err != nil {
+		return nil, invalid
+	}
+
+	for _, d := range wh.GetWeekdays() {
+		w.Weekdays = append(w.Weekdays, time.Weekday(d))
+	}
+
+	if err := w.Validate(); err != nil {
+		return nil, invalid
+	}
+
+	return w.Windows(from, to), nil
+}
+
+// parseClock разбирает время суток в формате ЧЧ:ММ (24:00 означает конец суток).
+func parseClock(s string) (time.Duration, error) {
+	if s == "24:00" {
+		return 24 * time.Hour, nil
+	}
+
+	t, err := time.Parse("15:04", s)
+	if err != nil {
+		return 0, err
+	}
+
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
+
+// busyIntervals возвращает занятые промежутки пользователя внутри from - to.
+func (s *Server) busyIntervals(ctx context.Context, userID uuid.UUID, from, to time.Time) ([]calendar.Interval, error) {
+	events, err := s.r.FindEvents(ctx, calendar.EventFilter{
+		UserID:      userID,
+		Attending:   true,
+		From:        from,
+		To:          to,
+		Overlapping: true,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return calendar.BusyIntervals(events, from, to), nil
+}
+
+// newIntervalsV1 преобразует промежутки в их представление в API.
+func newIntervalsV1(intervals []calendar.Interval) []*event.IntervalV1 {
+	res := make([]*event.IntervalV1, 0, len(intervals))
+
+	for _, i := range intervals {
+		res = append(res, &event.IntervalV1{
+			StartAt: i.Start.Unix(),
+			EndAt:   i.End.Unix(),
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,144 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/mocks"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+func TestServer_GetFreeBusyV1(t *testing.T) {
+	// понедельник 2022-10-03 по Москве
+	from := time.Date(2022, 10, 2, 21, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	anotherUserID := uuid.MustParse("2f0d2079-e9a2-4810-8cae-eb6729c50580")
+
+	events := []*calendar.Event{
+		{StartAt: from.Add(9 * time.Hour), EndAt: from.Add(10 * time.Hour)},
+		{StartAt: from.Add(9*time.Hour + 30*time.Minute), EndAt: from.Add(11 * time.Hour)},
+		{StartAt: from.Add(11*time.Hour + 30*time.Minute), EndAt: from.Add(12 * time.Hour)},
+		{StartAt: from.Add(23 * time.Hour), EndAt: from.Add(26 * time.Hour)},
+	}
+
+	tests := []struct {
+		name string
+		req  *event.GetFreeBusyRequestV1
+		want *event.GetFreeBusyResponseV1
+	}{
+		{
+			name: "whole range",
+			req: &event.GetFreeBusyRequestV1{
+				UserId:  anotherUserID.String(),
+				StartAt: from.Unix(),
+				EndAt:   to.Unix(),
+			},
+			want: &event.GetFreeBusyResponseV1{
+				Busy: []*event.IntervalV1{
+					{StartAt: from.Add(9 * time.Hour).Unix(), EndAt: from.Add(11 * time.Hour).Unix()},
+					{StartAt: from.Add(11*time.Hour + 30*time.Minute).Unix(), EndAt: from.Add(12 * time.Hour).Unix()},
+					{StartAt: from.Add(23 * time.Hour).Unix(), EndAt: to.Unix()},
+				},
+				Free: []*event.IntervalV1{
+					{StartAt: from.Unix(), EndAt: from.Add(9 * time.Hour).Unix()},
+					{StartAt: from.Add(11 * time.Hour).Unix(), EndAt: from.Add(11*time.Hour + 30*time.Minute).Unix()},
+					{StartAt: from.Add(12 * time.Hour).Unix(), EndAt: from.Add(23 * time.Hour).Unix()},
+				},
+			},
+		},
+		{
+			name: "working hours and min slot",
+			req: &event.GetFreeBusyRequestV1{
+				UserId:         anotherUserID.String(),
+				StartAt:        from.Unix(),
+				EndAt:          to.Unix(),
+				TimeZone:       "Europe/Moscow",
+				WorkingHours:   &event.WorkingHoursV1{Start: "08:00", End: "18:00"},
+				MinSlotMinutes: 60,
+			},
+			want: &event.GetFreeBusyResponseV1{
+				Busy: []*event.IntervalV1{
+					{StartAt: from.Add(9 * time.Hour).Unix(), EndAt: from.Add(11 * time.Hour).Unix()},
+					{StartAt: from.Add(11*time.Hour + 30*time.Minute).Unix(), EndAt: from.Add(12 * time.Hour).Unix()},
+					{StartAt: from.Add(23 * time.Hour).Unix(), EndAt: to.Unix()},
+				},
+				Free: []*event.IntervalV1{
+					{StartAt: from.Add(8 * time.Hour).Unix(), EndAt: from.Add(9 * time.Hour).Unix()},
+					{StartAt: from.Add(12 * time.Hour).Unix(), EndAt: from.Add(18 * time.Hour).Unix()},
+				},
+			},
+		},
+		{
+			name: "day off",
+			req: &event.GetFreeBusyRequestV1{
+				UserId:       anotherUserID.String(),
+				StartAt:      from.Unix(),
+				EndAt:        to.Unix(),
+				TimeZone:     "Europe/Moscow",
+				WorkingHours: &event.WorkingHoursV1{Start: "08:00", End: "18:00", Weekdays: []int32{2, 3, 4, 5}},
+			},
+			want: &event.GetFreeBusyResponseV1{
+				Busy: []*event.IntervalV1{
+					{StartAt: from.Add(9 * time.Hour).Unix(), EndAt: from.Add(11 * time.Hour).Unix()},
+					{StartAt: from.Add(11*time.Hour + 30*time.Minute).Unix(), EndAt: from.Add(12 * time.Hour).Unix()},
+					{StartAt: from.Add(23 * time.Hour).Unix(), EndAt: to.Unix()},
+				},
+				Free: []*event.IntervalV1{},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewRepository(t)
+			defer m.AssertExpectations(t)
+
+			m.On("FindEvents", mock.Anything, calendar.EventFilter{
+				UserID:      anotherUserID,
+				Attending:   true,
+				From:        time.Unix(from.Unix(), 0),
+				To:          time.Unix(to.Unix(), 0),
+				Overlapping: true,
+			}).Return(events, nil).Once()
+
+			s := Server{r: m}
+			got, err := s.GetFreeBusyV1(userCtx, tt.req)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+
+	t.Run("invalid request", func(t *testing.T) {
+		s := Server{r: mocks.NewRepository(t)}
+
+		for _, req := range []*event.GetFreeBusyRequestV1{
+			{StartAt: to.Unix(), EndAt: from.Unix()},
+			{StartAt: from.Unix(), EndAt: from.Add(400 * 24 * time.Hour).Unix()},
+			{StartAt: from.Unix(), EndAt: to.Unix(), UserId: "foo"},
+			{StartAt: from.Unix(), EndAt: to.Unix(), WorkingHours: &event.WorkingHoursV1{Start: "18:00", End: "09:00"}},
+			{StartAt: from.Unix(), EndAt: to.Unix(), WorkingHours: &event.WorkingHoursV1{Start: "9", End: "18:00"}},
+		} {
+			_, err := s.GetFreeBusyV1(userCtx, req)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		}
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		s := Server{r: mocks.NewRepository(t)}
+
+		_, err := s.GetFreeBusyV1(context.Background(), &event.GetFreeBusyRequestV1{
+			StartAt: from.Unix(),
+			EndAt:   to.Unix(),
+		})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
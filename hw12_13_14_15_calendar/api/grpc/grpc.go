@@ -17,21 +17,31 @@ import (
 
 type Server struct {
 	r calendar.Repository
+	w calendar.ChangeWatcher
 	event.UnimplementedEventServiceServer
 }
 
-func New(r calendar.Repository) *Server {
+func New(r calendar.Repository, w calendar.ChangeWatcher) *Server {
 	return &Server{
 		r: r,
+		w: w,
 	}
 }
 
 var _ event.EventServiceServer = (*Server)(nil)
 
 func (s *Server) CreateEventV1(ctx context.Context, req *event.CreateEventRequestV1) (*event.EventResponseV1, error) {
-	userID, err := uuid.Parse(req.GetUserId())
+	userID, err := callerID(ctx, req.GetUserId())
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+		return nil, err
+	}
+
+	if err := validateRRule(req.GetRrule()); err != nil {
+		return nil, err
+	}
+
+	if _, err := loadLocation(req.GetTimeZone()); err != nil {
+		return nil, err
 	}
 
 	e, err := s.r.CreateEvent(ctx, &calendar.Event{
@@ -39,8 +49,11 @@ func (s *Server) CreateEventV1(ctx context.Context, req *event.CreateEventReques
 		Description:          req.GetDescription(),
 		StartAt:              time.Unix(req.GetStartAt(), 0),
 		EndAt:                time.Unix(req.GetEndAt(), 0),
+		TimeZone:             req.GetTimeZone(),
 		UserID:               userID,
 		NotificationDuration: req.GetNotificationDuration(),
+		RRule:                req.GetRrule(),
+		ExDates:              timesFromUnix(req.GetExdates()),
 	})
 	if err != nil {
 		if errors.Is(err, calendar.ErrDateBusy) {
@@ -50,23 +63,17 @@ func (s *Server) CreateEventV1(ctx context.Context, req *event.CreateEventReques
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
 
+	setETag(ctx, e)
+
 	return &event.EventResponseV1{
-		Event: &event.EventV1{
-			Id:                   e.ID.String(),
-			Title:                e.Title,
-			Description:          e.Description,
-			StartAt:              e.StartAt.Unix(),
-			EndAt:                e.EndAt.Unix(),
-			UserId:               e.UserID.String(),
-			NotificationDuration: e.NotificationDuration,
-		},
+		Event: newEventV1(e),
 	}, nil
 }
 
 func (s *Server) UpdateEventV1(ctx context.Context, req *event.UpdateEventRequestV1) (*event.EventResponseV1, error) {
-	userID, err := uuid.Parse(req.GetUserId())
+	userID, err := callerID(ctx, req.GetUserId())
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+		return nil, err
 	}
 
 	ID, err := uuid.Parse(req.GetId())
@@ -74,41 +81,61 @@ func (s *Server) UpdateEventV1(ctx context.Context, req *event.UpdateEventReques
 		return nil, status.Error(codes.InvalidArgument, "invalid uuid")
 	}
 
+	if err := validateRRule(req.GetRrule()); err != nil {
+		return nil, err
+	}
+
+	if _, err := loadLocation(req.GetTimeZone()); err != nil {
+		return nil, err
+	}
+
+	version, err := expectedVersion(ctx, req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwner(ctx, userID, ID); err != nil {
+		return nil, err
+	}
+
 	e, err := s.r.UpdateEvent(ctx, ID, &calendar.Event{
 		Title:                req.GetTitle(),
 		Description:          req.GetDescription(),
 		StartAt:              time.Unix(req.GetStartAt(), 0),
 		EndAt:                time.Unix(req.GetEndAt(), 0),
+		TimeZone:             req.GetTimeZone(),
 		UserID:               userID,
 		NotificationDuration: req.GetNotificationDuration(),
+		RRule:                req.GetRrule(),
+		ExDates:              timesFromUnix(req.GetExdates()),
+		Version:              version,
 	})
 	if err != nil {
-		if errors.Is(err, calendar.ErrDateBusy) {
-			return nil, status.Error(codes.InvalidArgument, "that date is already taken by another event")
-		}
-
-		return nil, status.Error(codes.Unavailable, err.Error())
+		return nil, updateError(err)
 	}
 
+	setETag(ctx, e)
+
 	return &event.EventResponseV1{
-		Event: &event.EventV1{
-			Id:                   e.ID.String(),
-			Title:                e.Title,
-			Description:          e.Description,
-			StartAt:              e.StartAt.Unix(),
-			EndAt:                e.EndAt.Unix(),
-			UserId:               e.UserID.String(),
-			NotificationDuration: e.NotificationDuration,
-		},
+		Event: newEventV1(e),
 	}, nil
 }
 
 func (s *Server) DeleteEventV1(ctx context.Context, req *event.DeleteEventRequestV1) (*emptypb.Empty, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
 	ID, err := uuid.Parse(req.GetId())
 	if err != nil {
 		return nil, status.Error(codes.InvalidArgument, "invalid uuid")
 	}
 
+	if err := s.checkOwner(ctx, userID, ID); err != nil {
+		return nil, err
+	}
+
 	if err := s.r.DeleteEvent(ctx, ID); err != nil {
 		return nil, status.Error(codes.Unavailable, err.Error())
 	}
@@ -117,139 +144,175 @@ func (s *Server) DeleteEventV1(ctx context.Context, req *event.DeleteEventReques
 }
 
 func (s *Server) GetEventsForDayV1(ctx context.Context, req *event.GetEventsForDayRequestV1) (*event.EventsResponseV1, error) {
-	userID, err := uuid.Parse(req.GetUserId())
+	userID, err := callerID(ctx, req.GetUserId())
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+		return nil, err
 	}
 
-	date, err := time.Parse("2006-01-02", req.GetDate())
+	from, to, err := dateWindow(req.GetDate(), req.GetTimeZone(), 0, 0, 1)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid date")
+		return nil, err
 	}
 
-	nextDay := date.AddDate(0, 0, 1)
-
-	year, month, day := date.Date()
-	nYear, nMonth, nDay := nextDay.Date()
-	from := time.Date(year, month, day, 0, 0, 0, 0, date.UTC().Location())
-	to := time.Date(nYear, nMonth, nDay, 0, 0, 0, 0, nextDay.UTC().Location())
+	return s.findEventsPage(ctx, calendar.EventFilter{
+		UserID:    userID,
+		Attending: true,
+		From:      from,
+		To:        to,
+	}, req)
+}
 
-	events, err := s.r.FindEvents(ctx, calendar.EventFilter{
-		UserID: userID,
-		From:   from,
-		To:     to,
-	})
+func (s *Server) GetEventsForWeekV1(ctx context.Context, req *event.GetEventsForWeekRequestV1) (*event.EventsResponseV1, error) {
+	userID, err := callerID(ctx, req.GetUserId())
 	if err != nil {
-		return nil, status.Error(codes.Unavailable, err.Error())
+		return nil, err
 	}
 
-	result := make([]*event.EventV1, 0, len(events))
-
-	for _, e := range events {
-		result = append(result, &event.EventV1{
-			Id:                   e.ID.String(),
-			Title:                e.Title,
-			Description:          e.Description,
-			StartAt:              e.StartAt.Unix(),
-			EndAt:                e.EndAt.Unix(),
-			UserId:               e.UserID.String(),
-			NotificationDuration: e.NotificationDuration,
-		})
+	from, to, err := dateWindow(req.GetStartDate(), req.GetTimeZone(), 0, 0, 7)
+	if err != nil {
+		return nil, err
 	}
 
-	return &event.EventsResponseV1{
-		Events: result,
-	}, nil
+	return s.findEventsPage(ctx, calendar.EventFilter{
+		UserID:    userID,
+		Attending: true,
+		From:      from,
+		To:        to,
+	}, req)
 }
 
-func (s *Server) GetEventsForWeekV1(ctx context.Context, req *event.GetEventsForWeekRequestV1) (*event.EventsResponseV1, error) {
-	userID, err := uuid.Parse(req.GetUserId())
+func (s *Server) GetEventsForMonthV1(ctx context.Context, req *event.GetEventsForMonthRequestV1) (*event.EventsResponseV1, error) {
+	userID, err := callerID(ctx, req.GetUserId())
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+		return nil, err
 	}
 
-	date, err := time.Parse("2006-01-02", req.GetStartDate())
+	from, to, err := dateWindow(req.GetStartDate(), req.GetTimeZone(), 0, 1, 0)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid date")
+		return nil, err
 	}
 
-	nextWeek := date.AddDate(0, 0, 7)
+	return s.findEventsPage(ctx, calendar.EventFilter{
+		UserID:    userID,
+		Attending: true,
+		From:      from,
+		To:        to,
+	}, req)
+}
 
-	year, month, day := date.Date()
-	nYear, nMonth, nDay := nextWeek.Date()
-	from := time.Date(year, month, day, 0, 0, 0, 0, date.UTC().Location())
-	to := time.Date(nYear, nMonth, nDay, 0, 0, 0, 0, nextWeek.UTC().Location())
+// newEventV1 преобразует событие в его представление в API.
+// У вхождений повторяющегося события заполняется recurrence_id.
+func newEventV1(e *calendar.Event) *event.EventV1 {
+	res := &event.EventV1{
+		Id:                   e.ID.String(),
+		Title:                e.Title,
+		Description:          e.Description,
+		StartAt:              e.StartAt.Unix(),
+		EndAt:                e.EndAt.Unix(),
+		TimeZone:             e.TimeZone,
+		UserId:               e.UserID.String(),
+		NotificationDuration: e.NotificationDuration,
+		Rrule:                e.RRule,
+		Exdates:              timesToUnix(e.ExDates),
+		Version:              e.Version,
+	}
 
-	events, err := s.r.FindEvents(ctx, calendar.EventFilter{
-		UserID: userID,
-		From:   from,
-		To:     to,
-	})
-	if err != nil {
-		return nil, status.Error(codes.Unavailable, err.Error())
+	if e.RecurrenceID != nil {
+		res.RecurrenceId = e.RecurrenceID.Unix()
 	}
 
-	result := make([]*event.EventV1, 0, len(events))
+	if e.DeletedAt != nil {
+		res.DeletedAt = e.DeletedAt.Unix()
+	}
 
-	for _, e := range events {
-		result = append(result, &event.EventV1{
-			Id:                   e.ID.String(),
-			Title:                e.Title,
-			Description:          e.Description,
-			StartAt:              e.StartAt.Unix(),
-			EndAt:                e.EndAt.Unix(),
-			UserId:               e.UserID.String(),
-			NotificationDuration: e.NotificationDuration,
-		})
+	for _, a := range e.Attendees {
+		res.Attendees = append(res.Attendees, newAttendeeV1(&a))
 	}
 
-	return &event.EventsResponseV1{
-		Events: result,
-	}, nil
+	return res
 }
 
-func (s *Server) GetEventsForMonthV1(ctx context.Context, req *event.GetEventsForMonthRequestV1) (*event.EventsResponseV1, error) {
-	userID, err := uuid.Parse(req.GetUserId())
-	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid user id")
+// updateError преобразует ошибку обновления события в статус gRPC.
+func updateError(err error) error {
+	switch {
+	case errors.Is(err, calendar.ErrDateBusy):
+		return status.Error(codes.InvalidArgument, "that date is already taken by another event")
+	case errors.Is(err, calendar.ErrVersionConflict):
+		return status.Error(codes.Aborted, "event has been modified, reload it and retry")
+	default:
+		return status.Error(codes.Unavailable, err.Error())
+	}
+}
+
+// validateRRule проверяет правило повторения, если оно передано.
+func validateRRule(rrule string) error {
+	if rrule == "" {
+		return nil
 	}
 
-	date, err := time.Parse("2006-01-02", req.GetStartDate())
+	if _, err := calendar.ParseRRule(rrule); err != nil {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	return nil
+}
+
+// loadLocation загружает часовой пояс из запроса. Пустое имя означает UTC.
+func loadLocation(name string) (*time.Location, error) {
+	loc, err := calendar.LoadLocation(name)
 	if err != nil {
-		return nil, status.Error(codes.InvalidArgument, "invalid date")
+		return nil, status.Error(codes.InvalidArgument, "invalid time zone")
 	}
 
-	nextMonth := date.AddDate(0, 1, 0)
+	return loc, nil
+}
 
-	year, month, day := date.Date()
-	nYear, nMonth, nDay := nextMonth.Date()
-	from := time.Date(year, month, day, 0, 0, 0, 0, date.UTC().Location())
-	to := time.Date(nYear, nMonth, nDay, 0, 0, 0, 0, nextMonth.UTC().Location())
+// dateWindow возвращает промежуток от начала дня date до начала дня через years, months и days
+// в часовом поясе tz. Границы вычисляются по местному календарю, поэтому сутки
+// при переходе на летнее время и обратно длятся 23 или 25 часов.
+func dateWindow(date, tz string, years, months, days int) (from, to time.Time, err error) {
+	loc, err := loadLocation(tz)
+	if err != nil {
+		return from, to, err
+	}
 
-	events, err := s.r.FindEvents(ctx, calendar.EventFilter{
-		UserID: userID,
-		From:   from,
-		To:     to,
-	})
+	start, err := time.ParseInLocation("2006-01-02", date, loc)
 	if err != nil {
-		return nil, status.Error(codes.Unavailable, err.Error())
+		return from, to, status.Error(codes.InvalidArgument, "invalid date")
 	}
 
-	result := make([]*event.EventV1, 0, len(events))
+	year, month, day := start.Date()
 
-	for _, e := range events {
-		result = append(result, &event.EventV1{
-			Id:                   e.ID.String(),
-			Title:                e.Title,
-			Description:          e.Description,
-			StartAt:              e.StartAt.Unix(),
-			EndAt:                e.EndAt.Unix(),
-			UserId:               e.UserID.String(),
-			NotificationDuration: e.NotificationDuration,
-		})
+	from = time.Date(year, month, day, 0, 0, 0, 0, loc)
+	to = time.Date(year+years, month+time.Month(months), day+days, 0, 0, 0, 0, loc)
+
+	return from, to, nil
+}
+
+// timesFromUnix преобразует unix timestamps в даты.
+func timesFromUnix(ts []int64) []time.Time {
+	if len(ts) == 0 {
+		return nil
 	}
 
-	return &event.EventsResponseV1{
-		Events: result,
-	}, nil
+	res := make([]time.Time, 0, len(ts))
+	for _, t := range ts {
+		res = append(res, time.Unix(t, 0))
+	}
+
+	return res
+}
+
+// timesToUnix преобразует даты в unix timestamps.
+func timesToUnix(ts []time.Time) []int64 {
+	if len(ts) == 0 {
+		return nil
+	}
+
+	res := make([]int64, 0, len(ts))
+	for _, t := range ts {
+		res = append(res, t.Unix())
+	}
+
+	return res
 }
@@ -6,15 +6,23 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
 	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/mocks"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
 )
 
+// userCtx контекст запроса аутентифицированного пользователя.
+var userCtx = auth.WithUserID(context.Background(), uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"))
+
 func TestServer_CreateEventV1(t *testing.T) {
 	t.Run("base test", func(t *testing.T) {
 		m := mocks.NewRepository(t)
@@ -47,7 +55,7 @@ func TestServer_CreateEventV1(t *testing.T) {
 		}, nil).Once()
 
 		s := Server{r: m}
-		got, err := s.CreateEventV1(context.Background(), req)
+		got, err := s.CreateEventV1(userCtx, req)
 
 		require.NoError(t, err)
 		require.Equal(t, &event.EventResponseV1{
@@ -62,6 +70,108 @@ func TestServer_CreateEventV1(t *testing.T) {
 			},
 		}, got)
 	})
+
+	t.Run("recurring event", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		req := &event.CreateEventRequestV1{
+			Title:                "foo",
+			StartAt:              int64(1664643702),
+			EndAt:                int64(1664644150),
+			UserId:               "123e4567-e89b-12d3-a456-426614174000",
+			NotificationDuration: 30,
+			Rrule:                "FREQ=DAILY;COUNT=3",
+			Exdates:              []int64{1664730102},
+		}
+
+		m.On("CreateEvent", mock.Anything, &calendar.Event{
+			Title:                "foo",
+			StartAt:              time.Unix(1664643702, 0),
+			EndAt:                time.Unix(1664644150, 0),
+			UserID:               uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+			NotificationDuration: 30,
+			RRule:                "FREQ=DAILY;COUNT=3",
+			ExDates:              calendar.ExDates{time.Unix(1664730102, 0)},
+		}).Return(&calendar.Event{
+			ID:                   uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+			Title:                "foo",
+			StartAt:              time.Unix(1664643702, 0),
+			EndAt:                time.Unix(1664644150, 0),
+			UserID:               uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+			NotificationDuration: 30,
+			RRule:                "FREQ=DAILY;COUNT=3",
+			ExDates:              calendar.ExDates{time.Unix(1664730102, 0)},
+		}, nil).Once()
+
+		s := Server{r: m}
+		got, err := s.CreateEventV1(userCtx, req)
+
+		require.NoError(t, err)
+		require.Equal(t, &event.EventResponseV1{
+			Event: &event.EventV1{
+				Id:                   "ef0d2079-e9a2-4810-8cae-eb6729c50580",
+				Title:                "foo",
+				StartAt:              1664643702,
+				EndAt:                1664644150,
+				UserId:               "123e4567-e89b-12d3-a456-426614174000",
+				NotificationDuration: 30,
+				Rrule:                "FREQ=DAILY;COUNT=3",
+				Exdates:              []int64{1664730102},
+			},
+		}, got)
+	})
+
+	t.Run("invalid rrule", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		req := &event.CreateEventRequestV1{
+			Title:   "foo",
+			StartAt: int64(1664643702),
+			EndAt:   int64(1664644150),
+			UserId:  "123e4567-e89b-12d3-a456-426614174000",
+			Rrule:   "FREQ=YEARLY",
+		}
+
+		s := Server{r: m}
+		_, err := s.CreateEventV1(userCtx, req)
+
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("another user", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		req := &event.CreateEventRequestV1{
+			Title:   "foo",
+			StartAt: int64(1664643702),
+			EndAt:   int64(1664644150),
+			UserId:  "2f0d2079-e9a2-4810-8cae-eb6729c50580",
+		}
+
+		s := Server{r: m}
+		_, err := s.CreateEventV1(userCtx, req)
+
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		req := &event.CreateEventRequestV1{
+			Title:   "foo",
+			StartAt: int64(1664643702),
+			EndAt:   int64(1664644150),
+		}
+
+		s := Server{r: m}
+		_, err := s.CreateEventV1(context.Background(), req)
+
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
 }
 
 func TestServer_UpdateEventV1(t *testing.T) {
@@ -77,8 +187,16 @@ func TestServer_UpdateEventV1(t *testing.T) {
 			EndAt:                int64(1664644150),
 			UserId:               "123e4567-e89b-12d3-a456-426614174000",
 			NotificationDuration: 30,
+			Version:              1,
 		}
 
+		m.On("FindEventByID", mock.Anything, uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")).
+			Return(&calendar.Event{
+				ID:     uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				UserID: uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+			}, nil).
+			Once()
+
 		m.On("UpdateEvent", mock.Anything, uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"), &calendar.Event{
 			Title:                "foo",
 			Description:          "bar",
@@ -86,6 +204,7 @@ func TestServer_UpdateEventV1(t *testing.T) {
 			EndAt:                time.Unix(1664644150, 0),
 			UserID:               uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
 			NotificationDuration: 30,
+			Version:              1,
 		}).Return(&calendar.Event{
 			ID:                   uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
 			Title:                "foo",
@@ -94,10 +213,11 @@ func TestServer_UpdateEventV1(t *testing.T) {
 			EndAt:                time.Unix(1664644150, 0),
 			UserID:               uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
 			NotificationDuration: 30,
+			Version:              2,
 		}, nil).Once()
 
 		s := Server{r: m}
-		got, err := s.UpdateEventV1(context.Background(), req)
+		got, err := s.UpdateEventV1(userCtx, req)
 
 		require.NoError(t, err)
 		require.Equal(t, &event.EventResponseV1{
@@ -109,9 +229,108 @@ func TestServer_UpdateEventV1(t *testing.T) {
 				EndAt:                1664644150,
 				UserId:               "123e4567-e89b-12d3-a456-426614174000",
 				NotificationDuration: 30,
+				Version:              2,
 			},
 		}, got)
 	})
+
+	t.Run("event of another user", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		req := &event.UpdateEventRequestV1{
+			Id:      "ef0d2079-e9a2-4810-8cae-eb6729c50580",
+			Title:   "foo",
+			StartAt: int64(1664643702),
+			EndAt:   int64(1664644150),
+			Version: 1,
+		}
+
+		m.On("FindEventByID", mock.Anything, uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")).
+			Return(&calendar.Event{
+				ID:     uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				UserID: uuid.MustParse("2f0d2079-e9a2-4810-8cae-eb6729c50580"),
+			}, nil).
+			Once()
+
+		s := Server{r: m}
+		_, err := s.UpdateEventV1(userCtx, req)
+
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		req := &event.UpdateEventRequestV1{
+			Id:      "ef0d2079-e9a2-4810-8cae-eb6729c50580",
+			Title:   "foo",
+			StartAt: int64(1664643702),
+			EndAt:   int64(1664644150),
+			Version: 1,
+		}
+
+		m.On("FindEventByID", mock.Anything, uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")).
+			Return(nil, calendar.ErrNotFound).
+			Once()
+
+		s := Server{r: m}
+		_, err := s.UpdateEventV1(userCtx, req)
+
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+
+	t.Run("version from if-match", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		req := &event.UpdateEventRequestV1{
+			Id:      "ef0d2079-e9a2-4810-8cae-eb6729c50580",
+			Title:   "foo",
+			StartAt: int64(1664643702),
+			EndAt:   int64(1664644150),
+		}
+
+		m.On("FindEventByID", mock.Anything, uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")).
+			Return(&calendar.Event{
+				ID:     uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				UserID: uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+			}, nil).
+			Once()
+
+		m.On("UpdateEvent", mock.Anything, uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+			mock.MatchedBy(func(e *calendar.Event) bool { return e.Version == 3 })).
+			Return(nil, errors.Wrap(calendar.ErrVersionConflict, "update event")).
+			Once()
+
+		ctx := metadata.NewIncomingContext(userCtx, metadata.Pairs(IfMatchHeader, `W/"3"`))
+
+		s := Server{r: m}
+		_, err := s.UpdateEventV1(ctx, req)
+
+		require.Equal(t, codes.Aborted, status.Code(err))
+	})
+
+	t.Run("version required", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		req := &event.UpdateEventRequestV1{
+			Id:      "ef0d2079-e9a2-4810-8cae-eb6729c50580",
+			Title:   "foo",
+			StartAt: int64(1664643702),
+			EndAt:   int64(1664644150),
+		}
+
+		s := Server{r: m}
+		_, err := s.UpdateEventV1(userCtx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+
+		ctx := metadata.NewIncomingContext(userCtx, metadata.Pairs(IfMatchHeader, "*"))
+		_, err = s.UpdateEventV1(ctx, req)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
 }
 
 func TestServer_DeleteEventV1(t *testing.T) {
@@ -123,16 +342,44 @@ func TestServer_DeleteEventV1(t *testing.T) {
 			Id: "ef0d2079-e9a2-4810-8cae-eb6729c50580",
 		}
 
+		m.On("FindEventByID", mock.Anything, uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")).
+			Return(&calendar.Event{
+				ID:     uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				UserID: uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+			}, nil).
+			Once()
+
 		m.On("DeleteEvent", mock.Anything, uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")).
 			Return(nil).
 			Once()
 
 		s := Server{r: m}
-		got, err := s.DeleteEventV1(context.Background(), req)
+		got, err := s.DeleteEventV1(userCtx, req)
 
 		require.NoError(t, err)
 		require.Equal(t, &emptypb.Empty{}, got)
 	})
+
+	t.Run("event of another user", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		req := &event.DeleteEventRequestV1{
+			Id: "ef0d2079-e9a2-4810-8cae-eb6729c50580",
+		}
+
+		m.On("FindEventByID", mock.Anything, uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")).
+			Return(&calendar.Event{
+				ID:     uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				UserID: uuid.MustParse("2f0d2079-e9a2-4810-8cae-eb6729c50580"),
+			}, nil).
+			Once()
+
+		s := Server{r: m}
+		_, err := s.DeleteEventV1(userCtx, req)
+
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
 }
 
 func TestServer_GetEventsForDayV1(t *testing.T) {
@@ -158,7 +405,7 @@ func TestServer_GetEventsForDayV1(t *testing.T) {
 		}, nil).Once()
 
 		s := Server{r: m}
-		got, err := s.GetEventsForDayV1(context.Background(), req)
+		got, err := s.GetEventsForDayV1(userCtx, req)
 
 		require.NoError(t, err)
 		require.Equal(t, &event.EventsResponseV1{
@@ -177,6 +424,94 @@ func TestServer_GetEventsForDayV1(t *testing.T) {
 	})
 }
 
+func TestServer_GetEventsForDayV1_AnotherUser(t *testing.T) {
+	m := mocks.NewRepository(t)
+	defer m.AssertExpectations(t)
+
+	req := &event.GetEventsForDayRequestV1{
+		UserId: "2f0d2079-e9a2-4810-8cae-eb6729c50580",
+		Date:   "2022-10-01",
+	}
+
+	s := Server{r: m}
+	_, err := s.GetEventsForDayV1(userCtx, req)
+
+	require.Equal(t, codes.PermissionDenied, status.Code(err))
+}
+
+func TestServer_GetEventsForDayV1_TimeZone(t *testing.T) {
+	m := mocks.NewRepository(t)
+	defer m.AssertExpectations(t)
+
+	m.On("FindEvents", mock.Anything, mock.MatchedBy(func(f calendar.EventFilter) bool {
+		// сутки 2022-10-12 по Москве: с 21:00 UTC предыдущего дня
+		return f.From.Equal(time.Date(2022, 10, 11, 21, 0, 0, 0, time.UTC)) &&
+			f.To.Equal(time.Date(2022, 10, 12, 21, 0, 0, 0, time.UTC))
+	})).Return([]*calendar.Event{}, nil).Once()
+
+	s := Server{r: m}
+	_, err := s.GetEventsForDayV1(userCtx, &event.GetEventsForDayRequestV1{
+		Date:     "2022-10-12",
+		TimeZone: "Europe/Moscow",
+	})
+	require.NoError(t, err)
+
+	_, err = s.GetEventsForDayV1(userCtx, &event.GetEventsForDayRequestV1{
+		Date:     "2022-10-12",
+		TimeZone: "Europe/Unknown",
+	})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestDateWindow(t *testing.T) {
+	tests := []struct {
+		name                string
+		date, tz            string
+		years, months, days int
+		wantFrom, wantTo    time.Time
+	}{
+		{
+			name:     "utc by default",
+			date:     "2022-10-12",
+			days:     1,
+			wantFrom: time.Date(2022, 10, 12, 0, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2022, 10, 13, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "day with dst end lasts 25 hours",
+			date:     "2022-10-30",
+			tz:       "Europe/Berlin",
+			days:     1,
+			wantFrom: time.Date(2022, 10, 29, 22, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2022, 10, 30, 23, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "week with dst start",
+			date:     "2022-03-07",
+			tz:       "America/New_York",
+			days:     7,
+			wantFrom: time.Date(2022, 3, 7, 5, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2022, 3, 14, 4, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "month",
+			date:     "2022-10-01",
+			tz:       "Europe/Moscow",
+			months:   1,
+			wantFrom: time.Date(2022, 9, 30, 21, 0, 0, 0, time.UTC),
+			wantTo:   time.Date(2022, 10, 31, 21, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			from, to, err := dateWindow(tt.date, tt.tz, tt.years, tt.months, tt.days)
+			require.NoError(t, err)
+			require.True(t, tt.wantFrom.Equal(from), from)
+			require.True(t, tt.wantTo.Equal(to), to)
+		})
+	}
+}
+
 func TestServer_GetEventsForWeekV1(t *testing.T) {
 	t.Run("base test", func(t *testing.T) {
 		m := mocks.NewRepository(t)
@@ -200,7 +535,7 @@ func TestServer_GetEventsForWeekV1(t *testing.T) {
 		}, nil).Once()
 
 		s := Server{r: m}
-		got, err := s.GetEventsForWeekV1(context.Background(), req)
+		got, err := s.GetEventsForWeekV1(userCtx, req)
 
 		require.NoError(t, err)
 		require.Equal(t, &event.EventsResponseV1{
@@ -217,6 +552,70 @@ func TestServer_GetEventsForWeekV1(t *testing.T) {
 			},
 		}, got)
 	})
+
+	t.Run("recurring event occurrences", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		req := &event.GetEventsForWeekRequestV1{
+			UserId:    "123e4567-e89b-12d3-a456-426614174000",
+			StartDate: "2022-10-01",
+		}
+
+		first, second := time.Unix(1664643702, 0), time.Unix(1664816502, 0)
+
+		m.On("FindEvents", mock.Anything, mock.Anything).Return([]*calendar.Event{
+			{
+				ID:           uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				Title:        "foo",
+				StartAt:      first,
+				EndAt:        first.Add(time.Hour),
+				UserID:       uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+				RRule:        "FREQ=DAILY",
+				ExDates:      calendar.ExDates{time.Unix(1664730102, 0)},
+				RecurrenceID: &first,
+			},
+			{
+				ID:           uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				Title:        "foo",
+				StartAt:      second,
+				EndAt:        second.Add(time.Hour),
+				UserID:       uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+				RRule:        "FREQ=DAILY",
+				ExDates:      calendar.ExDates{time.Unix(1664730102, 0)},
+				RecurrenceID: &second,
+			},
+		}, nil).Once()
+
+		s := Server{r: m}
+		got, err := s.GetEventsForWeekV1(userCtx, req)
+
+		require.NoError(t, err)
+		require.Equal(t, &event.EventsResponseV1{
+			Events: []*event.EventV1{
+				{
+					Id:           "ef0d2079-e9a2-4810-8cae-eb6729c50580",
+					Title:        "foo",
+					StartAt:      1664643702,
+					EndAt:        1664647302,
+					UserId:       "123e4567-e89b-12d3-a456-426614174000",
+					Rrule:        "FREQ=DAILY",
+					Exdates:      []int64{1664730102},
+					RecurrenceId: 1664643702,
+				},
+				{
+					Id:           "ef0d2079-e9a2-4810-8cae-eb6729c50580",
+					Title:        "foo",
+					StartAt:      1664816502,
+					EndAt:        1664820102,
+					UserId:       "123e4567-e89b-12d3-a456-426614174000",
+					Rrule:        "FREQ=DAILY",
+					Exdates:      []int64{1664730102},
+					RecurrenceId: 1664816502,
+				},
+			},
+		}, got)
+	})
 }
 
 func TestServer_GetEventsForMonthV1(t *testing.T) {
@@ -242,7 +641,7 @@ func TestServer_GetEventsForMonthV1(t *testing.T) {
 		}, nil).Once()
 
 		s := Server{r: m}
-		got, err := s.GetEventsForMonthV1(context.Background(), req)
+		got, err := s.GetEventsForMonthV1(userCtx, req)
 
 		require.NoError(t, err)
 		require.Equal(t, &event.EventsResponseV1{
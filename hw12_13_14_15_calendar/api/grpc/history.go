@@ -0,0 +1,157 @@
+package grpc
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+// GetEventHistoryV1 возвращает журнал изменений события, от старых записей к новым.
+// Журнал доступен и для событий в корзине или окончательно удаленных.
+func (s *Server) GetEventHistoryV1(
+	ctx context.Context,
+	req *event.GetEventHistoryRequestV1,
+) (*event.GetEventHistoryResponseV1, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := uuid.Parse(req.GetEventId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid uuid")
+	}
+
+	revisions, err := s.r.FindEventRevisions(ctx, ID)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	if err := s.checkHistoryOwner(ctx, userID, ID, revisions); err != nil {
+		return nil, err
+	}
+
+	res := &event.GetEventHistoryResponseV1{
+		Revisions: make([]*event.RevisionV1, 0, len(revisions)),
+	}
+
+	for _, r := range revisions {
+		res.Revisions = append(res.Revisions, newRevisionV1(r))
+	}
+
+	return res, nil
+}
+
+// RevertEventV1 возвращает событие к состоянию из записи журнала изменений.
+// Возврат сохраняется как обычное изменение события: требует текущую версию события
+// и тоже попадает в журнал.
+func (s *Server) RevertEventV1(ctx context.Context, req *event.RevertEventRequestV1) (*event.EventResponseV1, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := uuid.Parse(req.GetEventId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid uuid")
+	}
+
+	revisionID, err := uuid.Parse(req.GetRevisionId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid revision uuid")
+	}
+
+	version, err := expectedVersion(ctx, req.GetVersion())
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwner(ctx, userID, ID); err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.r.FindEventRevisions(ctx, ID)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	var revision *calendar.Revision
+
+	for _, r := range revisions {
+		if r.ID == revisionID {
+			revision = r
+
+			break
+		}
+	}
+
+	if revision == nil {
+		return nil, status.Error(codes.NotFound, "revision not found")
+	}
+
+	// владелец события не меняется при возврате
+	reverted := revision.State.Event(ID)
+	reverted.UserID = userID
+	reverted.Version = version
+
+	e, err := s.r.UpdateEvent(ctx, ID, reverted)
+	if err != nil {
+		return nil, updateError(err)
+	}
+
+	setETag(ctx, e)
+
+	return &event.EventResponseV1{
+		Event: newEventV1(e),
+	}, nil
+}
+
+// checkHistoryOwner проверяет, что журнал изменений события принадлежит пользователю:
+// владельцем считается владелец события в последней записи журнала.
+// Для событий без журнала (созданных до его появления) проверяется само событие.
+func (s *Server) checkHistoryOwner(
+	ctx context.Context,
+	userID, eventID uuid.UUID,
+	revisions []*calendar.Revision,
+) error {
+	if len(revisions) == 0 {
+		return s.checkOwner(ctx, userID, eventID)
+	}
+
+	if revisions[len(revisions)-1].State.UserID != userID {
+		return status.Error(codes.PermissionDenied, "event belongs to another user")
+	}
+
+	return nil
+}
+
+// newRevisionV1 преобразует запись журнала изменений в ее представление в API.
+func newRevisionV1(r *calendar.Revision) *event.RevisionV1 {
+	res := &event.RevisionV1{
+		Id:        r.ID.String(),
+		EventId:   r.EventID.String(),
+		Action:    string(r.Action),
+		CreatedAt: r.CreatedAt.Unix(),
+		Changes:   make([]*event.FieldChangeV1, 0, len(r.Changes)),
+		Event:     newEventV1(r.State.Event(r.EventID)),
+	}
+
+	if r.ActorID != uuid.Nil {
+		res.ActorId = r.ActorID.String()
+	}
+
+	for _, c := range r.Changes {
+		res.Changes = append(res.Changes, &event.FieldChangeV1{
+			Field:    c.Field,
+			OldValue: c.Old,
+			NewValue: c.New,
+		})
+	}
+
+	return res
+}
@@ -0,0 +1,171 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/mocks"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+func TestServer_GetEventHistoryV1(t *testing.T) {
+	userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	eventID := uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")
+	revisionID := uuid.MustParse("9a1f7a0e-2f4b-4b8e-9d0e-2c6c3c1d0b7a")
+
+	t.Run("history", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		m.On("FindEventRevisions", mock.Anything, eventID).Return([]*calendar.Revision{
+			{
+				ID:        revisionID,
+				EventID:   eventID,
+				Action:    calendar.RevisionUpdated,
+				ActorID:   userID,
+				CreatedAt: time.Unix(1664643702, 0),
+				Changes:   calendar.FieldChanges{{Field: "title", Old: "foo", New: "bar"}},
+				State: calendar.EventState{
+					Title:   "bar",
+					StartAt: time.Unix(1664643702, 0),
+					EndAt:   time.Unix(1664644150, 0),
+					UserID:  userID,
+				},
+			},
+		}, nil).Once()
+
+		s := Server{r: m}
+		got, err := s.GetEventHistoryV1(userCtx, &event.GetEventHistoryRequestV1{EventId: eventID.String()})
+
+		require.NoError(t, err)
+		require.Equal(t, []*event.RevisionV1{
+			{
+				Id:        revisionID.String(),
+				EventId:   eventID.String(),
+				Action:    "updated",
+				ActorId:   userID.String(),
+				CreatedAt: 1664643702,
+				Changes:   []*event.FieldChangeV1{{Field: "title", OldValue: "foo", NewValue: "bar"}},
+				Event: &event.EventV1{
+					Id:      eventID.String(),
+					Title:   "bar",
+					StartAt: 1664643702,
+					EndAt:   1664644150,
+					UserId:  userID.String(),
+				},
+			},
+		}, got.Revisions)
+	})
+
+	t.Run("another user event", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		m.On("FindEventRevisions", mock.Anything, eventID).Return([]*calendar.Revision{
+			{ID: revisionID, EventID: eventID, State: calendar.EventState{UserID: uuid.New()}},
+		}, nil).Once()
+
+		s := Server{r: m}
+		_, err := s.GetEventHistoryV1(userCtx, &event.GetEventHistoryRequestV1{EventId: eventID.String()})
+
+		require.Equal(t, codes.PermissionDenied, status.Code(err))
+	})
+
+	t.Run("event without history", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		m.On("FindEventRevisions", mock.Anything, eventID).Return([]*calendar.Revision{}, nil).Once()
+		m.On("FindEventByID", mock.Anything, eventID).Return(nil, calendar.ErrNotFound).Once()
+
+		s := Server{r: m}
+		_, err := s.GetEventHistoryV1(userCtx, &event.GetEventHistoryRequestV1{EventId: eventID.String()})
+
+		require.Equal(t, codes.NotFound, status.Code(err))
+	})
+}
+
+func TestServer_RevertEventV1(t *testing.T) {
+	userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	eventID := uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")
+	revisionID := uuid.MustParse("9a1f7a0e-2f4b-4b8e-9d0e-2c6c3c1d0b7a")
+
+	state := calendar.EventState{
+		Title:                "foo",
+		Description:          "old description",
+		StartAt:              time.Unix(1664643702, 0),
+		EndAt:                time.Unix(1664644150, 0),
+		UserID:               userID,
+		NotificationDuration: 15,
+	}
+
+	tests := []struct {
+		name       string
+		revisionID string
+		repoErr    error
+		wantCode   codes.Code
+	}{
+		{name: "reverted", revisionID: revisionID.String(), wantCode: codes.OK},
+		{name: "invalid revision uuid", revisionID: "foo", wantCode: codes.InvalidArgument},
+		{name: "unknown revision", revisionID: uuid.NewString(), wantCode: codes.NotFound},
+		{
+			name:       "busy",
+			revisionID: revisionID.String(),
+			repoErr:    calendar.ErrDateBusy,
+			wantCode:   codes.InvalidArgument,
+		},
+		{
+			name:       "version conflict",
+			revisionID: revisionID.String(),
+			repoErr:    calendar.ErrVersionConflict,
+			wantCode:   codes.Aborted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewRepository(t)
+			defer m.AssertExpectations(t)
+
+			if tt.revisionID != "foo" {
+				m.On("FindEventByID", mock.Anything, eventID).
+					Return(&calendar.Event{ID: eventID, UserID: userID}, nil).Once()
+				m.On("FindEventRevisions", mock.Anything, eventID).
+					Return([]*calendar.Revision{{ID: revisionID, EventID: eventID, State: state}}, nil).Once()
+			}
+
+			if tt.wantCode == codes.OK || tt.repoErr != nil {
+				reverted := state.Event(eventID)
+				reverted.Version = 4
+
+				var e *calendar.Event
+				if tt.repoErr == nil {
+					e = reverted
+				}
+
+				m.On("UpdateEvent", mock.Anything, eventID, reverted).
+					Return(e, errors.Wrap(tt.repoErr, "update event")).Once()
+			}
+
+			s := Server{r: m}
+			got, err := s.RevertEventV1(userCtx, &event.RevertEventRequestV1{
+				EventId:    eventID.String(),
+				RevisionId: tt.revisionID,
+				Version:    4,
+			})
+
+			require.Equal(t, tt.wantCode, status.Code(err))
+
+			if tt.wantCode == codes.OK {
+				require.Equal(t, "old description", got.Event.Description)
+			}
+		})
+	}
+}
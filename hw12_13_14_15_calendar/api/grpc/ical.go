@@ -0,0 +1,129 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+
+	"github.com/google/uuid"
+	"google.golang.org/genproto/googleapis/api/httpbody"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/ical"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+// ExportEventsV1 выгружает события пользователя за период (включая end_date) в формате iCalendar.
+// Выгружаются и события, начавшиеся до периода или закончившиеся после него.
+// Повторяющиеся события выгружаются целой серией.
+func (s *Server) ExportEventsV1(ctx context.Context, req *event.ExportEventsRequestV1) (*httpbody.HttpBody, error) {
+	userID, err := callerID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	from, _, err := dateWindow(req.GetStartDate(), req.GetTimeZone(), 0, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	endDate, to, err := dateWindow(req.GetEndDate(), req.GetTimeZone(), 0, 0, 1)
+	if err != nil || endDate.Before(from) {
+		return nil, status.Error(codes.InvalidArgument, "invalid end date")
+	}
+
+	found, err := s.r.FindEvents(ctx, calendar.EventFilter{
+		UserID:      userID,
+		From:        from,
+		To:          to,
+		Overlapping: true,
+	})
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	events := make([]*calendar.Event, 0, len(found))
+	seen := make(map[uuid.UUID]struct{}, len(found))
+
+	for _, e := range found {
+		if _, ok := seen[e.ID]; ok {
+			continue
+		}
+
+		seen[e.ID] = struct{}{}
+
+		// вместо вхождения выгружаем серию целиком
+		if e.RecurrenceID != nil {
+			if e, err = s.r.FindEventByID(ctx, e.ID); err != nil {
+				return nil, status.Error(codes.Unavailable, err.Error())
+			}
+		}
+
+		events = append(events, e)
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, events); err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	return &httpbody.HttpBody{
+		ContentType: ical.ContentType,
+		Data:        buf.Bytes(),
+	}, nil
+}
+
+// ImportEventsV1 загружает события пользователя из календаря в формате iCalendar.
+// События, которые не удалось сохранить (например, из-за занятого времени),
+// возвращаются в conflicts, не прерывая загрузку остальных.
+func (s *Server) ImportEventsV1(ctx context.Context, req *event.ImportEventsRequestV1) (*event.ImportEventsResponseV1, error) {
+	userID, err := callerID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	vevents, err := ical.Decode(strings.NewReader(req.GetIcs()))
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	res := &event.ImportEventsResponseV1{
+		Events:    make([]*event.EventV1, 0, len(vevents)),
+		Conflicts: make([]*event.ImportConflictV1, 0),
+	}
+
+	for _, ve := range vevents {
+		if ve.Err != nil {
+			res.Conflicts = append(res.Conflicts, &event.ImportConflictV1{
+				Uid:    ve.UID,
+				Reason: ve.Err.Error(),
+			})
+
+			continue
+		}
+
+		ve.Event.UserID = userID
+
+		e, err := s.r.CreateEvent(ctx, ve.Event)
+		if err != nil {
+			if !errors.Is(err, calendar.ErrDateBusy) && !errors.Is(err, calendar.ErrInvalidRRule) {
+				return nil, status.Error(codes.Unavailable, err.Error())
+			}
+
+			res.Conflicts = append(res.Conflicts, &event.ImportConflictV1{
+				Uid:    ve.UID,
+				Title:  ve.Event.Title,
+				Reason: err.Error(),
+			})
+
+			continue
+		}
+
+		res.Events = append(res.Events, newEventV1(e))
+	}
+
+	return res, nil
+}
@@ -0,0 +1,244 @@
+package grpc
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/inmem"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/mocks"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+func TestServer_ExportEventsV1(t *testing.T) {
+	t.Run("base test", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+		seriesID := uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")
+
+		series := &calendar.Event{
+			ID:      seriesID,
+			Title:   "standup",
+			StartAt: time.Date(2022, 10, 3, 10, 0, 0, 0, time.UTC),
+			EndAt:   time.Date(2022, 10, 3, 10, 15, 0, 0, time.UTC),
+			UserID:  userID,
+			RRule:   "FREQ=DAILY",
+		}
+
+		first, second := series.StartAt.AddDate(0, 0, 7), series.StartAt.AddDate(0, 0, 8)
+		occurrences := []*calendar.Event{
+			{ID: seriesID, Title: "standup", StartAt: first, EndAt: first.Add(15 * time.Minute), RRule: "FREQ=DAILY", RecurrenceID: &first},    //nolint:lll
+			{ID: seriesID, Title: "standup", StartAt: second, EndAt: second.Add(15 * time.Minute), RRule: "FREQ=DAILY", RecurrenceID: &second}, //nolint:lll
+		}
+
+		m.On("FindEvents", mock.Anything, calendar.EventFilter{
+			UserID:      userID,
+			From:        time.Date(2022, 10, 10, 0, 0, 0, 0, time.UTC),
+			To:          time.Date(2022, 10, 12, 0, 0, 0, 0, time.UTC),
+			Overlapping: true,
+		}).Return(append(occurrences, &calendar.Event{
+			ID:      uuid.MustParse("2f0d2079-e9a2-4810-8cae-eb6729c50580"),
+			Title:   "lunch",
+			StartAt: time.Date(2022, 10, 11, 13, 0, 0, 0, time.UTC),
+			EndAt:   time.Date(2022, 10, 11, 14, 0, 0, 0, time.UTC),
+			UserID:  userID,
+		}), nil).Once()
+
+		m.On("FindEventByID", mock.Anything, seriesID).Return(series, nil).Once()
+
+		s := Server{r: m}
+		got, err := s.ExportEventsV1(userCtx, &event.ExportEventsRequestV1{
+			UserId:    userID.String(),
+			StartDate: "2022-10-10",
+			EndDate:   "2022-10-11",
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, "text/calendar; charset=utf-8", got.GetContentType())
+
+		ics := string(got.GetData())
+		require.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT"))
+		require.Contains(t, ics, "UID:ef0d2079-e9a2-4810-8cae-eb6729c50580\r\n")
+		require.Contains(t, ics, "DTSTART:20221003T100000Z\r\n")
+		require.Contains(t, ics, "RRULE:FREQ=DAILY\r\n")
+		require.Contains(t, ics, "SUMMARY:lunch\r\n")
+	})
+
+	t.Run("events crossing range boundaries", func(t *testing.T) {
+		repo := inmem.New()
+
+		userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+
+		for _, e := range []*calendar.Event{
+			{
+				Title:   "night shift",
+				StartAt: time.Date(2022, 10, 9, 22, 0, 0, 0, time.UTC),
+				EndAt:   time.Date(2022, 10, 10, 6, 0, 0, 0, time.UTC),
+				UserID:  userID,
+			},
+			{
+				Title:   "trip",
+				StartAt: time.Date(2022, 10, 11, 20, 0, 0, 0, time.UTC),
+				EndAt:   time.Date(2022, 10, 13, 8, 0, 0, 0, time.UTC),
+				UserID:  userID,
+			},
+			{
+				Title:   "before",
+				StartAt: time.Date(2022, 10, 9, 12, 0, 0, 0, time.UTC),
+				EndAt:   time.Date(2022, 10, 9, 13, 0, 0, 0, time.UTC),
+				UserID:  userID,
+			},
+		} {
+			_, err := repo.CreateEvent(userCtx, e)
+			require.NoError(t, err)
+		}
+
+		s := Server{r: repo}
+		got, err := s.ExportEventsV1(userCtx, &event.ExportEventsRequestV1{
+			UserId:    userID.String(),
+			StartDate: "2022-10-10",
+			EndDate:   "2022-10-11",
+		})
+		require.NoError(t, err)
+
+		ics := string(got.GetData())
+		require.Equal(t, 2, strings.Count(ics, "BEGIN:VEVENT"))
+		require.Contains(t, ics, "SUMMARY:night shift\r\n")
+		require.Contains(t, ics, "SUMMARY:trip\r\n")
+	})
+
+	t.Run("invalid range", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		s := Server{r: m}
+		_, err := s.ExportEventsV1(userCtx, &event.ExportEventsRequestV1{
+			UserId:    "123e4567-e89b-12d3-a456-426614174000",
+			StartDate: "2022-10-10",
+			EndDate:   "2022-10-09",
+		})
+
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
+
+func TestServer_ImportEventsV1(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:free@example.com",
+		"DTSTART:20221012T123000Z",
+		"DTEND:20221012T143000Z",
+		"SUMMARY:foo",
+		"DESCRIPTION:bar",
+		"BEGIN:VALARM",
+		"TRIGGER:-PT30M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:busy@example.com",
+		"DTSTART:20221012T130000Z",
+		"DTEND:20221012T133000Z",
+		"SUMMARY:baz",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:broken@example.com",
+		"SUMMARY:qux",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	t.Run("base test", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+
+		m.On("CreateEvent", mock.Anything, &calendar.Event{
+			UID:                  "free@example.com",
+			Title:                "foo",
+			Description:          "bar",
+			StartAt:              time.Date(2022, 10, 12, 12, 30, 0, 0, time.UTC),
+			EndAt:                time.Date(2022, 10, 12, 14, 30, 0, 0, time.UTC),
+			UserID:               userID,
+			NotificationDuration: 30,
+		}).Return(&calendar.Event{
+			ID:                   uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+			UID:                  "free@example.com",
+			Title:                "foo",
+			Description:          "bar",
+			StartAt:              time.Date(2022, 10, 12, 12, 30, 0, 0, time.UTC),
+			EndAt:                time.Date(2022, 10, 12, 14, 30, 0, 0, time.UTC),
+			UserID:               userID,
+			NotificationDuration: 30,
+		}, nil).Once()
+
+		m.On("CreateEvent", mock.Anything, mock.MatchedBy(func(e *calendar.Event) bool {
+			return e.Title == "baz"
+		})).Return(nil, errors.Wrap(calendar.ErrDateBusy, "create event")).Once()
+
+		s := Server{r: m}
+		got, err := s.ImportEventsV1(userCtx, &event.ImportEventsRequestV1{
+			UserId: userID.String(),
+			Ics:    ics,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []*event.EventV1{
+			{
+				Id:                   "ef0d2079-e9a2-4810-8cae-eb6729c50580",
+				Title:                "foo",
+				Description:          "bar",
+				StartAt:              1665577800,
+				EndAt:                1665585000,
+				UserId:               "123e4567-e89b-12d3-a456-426614174000",
+				NotificationDuration: 30,
+			},
+		}, got.GetEvents())
+
+		require.Len(t, got.GetConflicts(), 2)
+		require.Equal(t, "busy@example.com", got.GetConflicts()[0].GetUid())
+		require.Equal(t, "baz", got.GetConflicts()[0].GetTitle())
+		require.Contains(t, got.GetConflicts()[0].GetReason(), calendar.ErrDateBusy.Error())
+		require.Equal(t, "broken@example.com", got.GetConflicts()[1].GetUid())
+	})
+
+	t.Run("repository error aborts import", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		m.On("CreateEvent", mock.Anything, mock.Anything).Return(nil, errors.New("connection refused")).Once()
+
+		s := Server{r: m}
+		_, err := s.ImportEventsV1(userCtx, &event.ImportEventsRequestV1{
+			UserId: "123e4567-e89b-12d3-a456-426614174000",
+			Ics:    ics,
+		})
+
+		require.Equal(t, codes.Unavailable, status.Code(err))
+	})
+
+	t.Run("invalid calendar", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		s := Server{r: m}
+		_, err := s.ImportEventsV1(userCtx, &event.ImportEventsRequestV1{
+			UserId: "123e4567-e89b-12d3-a456-426614174000",
+			Ics:    "BEGIN:VEVENT",
+		})
+
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+}
@@ -0,0 +1,143 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+const (
+	// meetingSlotStep шаг, с которым предлагаются варианты начала встречи.
+	meetingSlotStep = 15 * time.Minute
+
+	// maxMeetingDuration максимальная длительность встречи.
+	maxMeetingDuration = 24 * time.Hour
+
+	// maxMeetingParticipants максимальное количество участников встречи.
+	maxMeetingParticipants = 50
+
+	// defaultMeetingSlots количество вариантов, если оно не передано.
+	defaultMeetingSlots = 10
+
+	// maxMeetingSlots максимальное количество вариантов.
+	maxMeetingSlots = 100
+)
+
+// FindMeetingSlotsV1 подбирает время встречи, в которое свободны все участники.
+// Варианты упорядочены по рангу (см. calendar.MeetingSlots).
+func (s *Server) FindMeetingSlotsV1(
+	ctx context.Context,
+	req *event.FindMeetingSlotsRequestV1,
+) (*event.FindMeetingSlotsResponseV1, error) {
+	if _, err := callerID(ctx, ""); err != nil {
+		return nil, err
+	}
+
+	userIDs, err := parseParticipants(req.GetUserIds())
+	if err != nil {
+		return nil, err
+	}
+
+	duration := time.Duration(req.GetDurationMinutes()) * time.Minute
+	if duration <= 0 || duration > maxMeetingDuration {
+		return nil, status.Error(codes.InvalidArgument, "invalid duration")
+	}
+
+	limit := int(req.GetMaxResults())
+
+	switch {
+	case limit == 0:
+		limit = defaultMeetingSlots
+	case limit > maxMeetingSlots:
+		limit = maxMeetingSlots
+	}
+
+	from, to, err := searchRange(req.GetStartAt(), req.GetEndAt())
+	if err != nil {
+		return nil, err
+	}
+
+	windows, err := workingWindows(req.GetWorkingHours(), req.GetTimeZone(), from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	events := make([]*calendar.Event, 0)
+
+	for _, userID := range userIDs {
+		userEvents, err := s.r.FindEvents(ctx, calendar.EventFilter{
+			UserID:      userID,
+			Attending:   true,
+			From:        from,
+			To:          to,
+			Overlapping: true,
+		})
+		if err != nil {
+			return nil, status.Error(codes.Unavailable, err.Error())
+		}
+
+		events = append(events, userEvents...)
+	}
+
+	free := calendar.FreeIntervals(calendar.BusyIntervals(events, from, to), windows, duration)
+
+	res := &event.FindMeetingSlotsResponseV1{
+		Slots: make([]*event.IntervalV1, 0, limit),
+	}
+
+	for _, slot := range calendar.MeetingSlots(free, duration, meetingSlotStep) {
+		if len(res.Slots) == limit {
+			break
+		}
+
+		// вариант проверяется по тому же правилу, что и новое событие при создании
+		err := calendar.CheckDateBusy(events, &calendar.Event{StartAt: slot.Start, EndAt: slot.End}, uuid.Nil)
+		if errors.Is(err, calendar.ErrDateBusy) {
+			continue
+		}
+
+		if err != nil {
+			return nil, status.Error(codes.Internal, err.Error())
+		}
+
+		res.Slots = append(res.Slots, &event.IntervalV1{
+			StartAt: slot.Start.Unix(),
+			EndAt:   slot.End.Unix(),
+		})
+	}
+
+	return res, nil
+}
+
+// parseParticipants разбирает идентификаторы участников встречи, отбрасывая повторы.
+func parseParticipants(ids []string) ([]uuid.UUID, error) {
+	if len(ids) == 0 || len(ids) > maxMeetingParticipants {
+		return nil, status.Error(codes.InvalidArgument, "invalid user ids")
+	}
+
+	res := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+
+	for _, id := range ids {
+		userID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, status.Error(codes.InvalidArgument, "invalid user id")
+		}
+
+		if _, ok := seen[userID]; ok {
+			continue
+		}
+
+		seen[userID] = struct{}{}
+		res = append(res, userID)
+	}
+
+	return res, nil
+}
@@ -0,0 +1,98 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/mocks"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+func TestServer_FindMeetingSlotsV1(t *testing.T) {
+	day := time.Date(2022, 10, 3, 0, 0, 0, 0, time.UTC)
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+
+	first := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	second := uuid.MustParse("2f0d2079-e9a2-4810-8cae-eb6729c50580")
+
+	t.Run("base test", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		filter := func(userID uuid.UUID) calendar.EventFilter {
+			return calendar.EventFilter{
+				UserID:      userID,
+				Attending:   true,
+				From:        time.Unix(day.Unix(), 0),
+				To:          time.Unix(day.Add(24*time.Hour).Unix(), 0),
+				Overlapping: true,
+			}
+		}
+
+		m.On("FindEvents", mock.Anything, filter(first)).Return([]*calendar.Event{
+			{StartAt: at(9, 0), EndAt: at(10, 0), UserID: first},
+			{StartAt: at(13, 0), EndAt: at(14, 0), UserID: first},
+		}, nil).Once()
+
+		m.On("FindEvents", mock.Anything, filter(second)).Return([]*calendar.Event{
+			{StartAt: at(10, 30), EndAt: at(12, 0), UserID: second},
+			{StartAt: at(15, 30), EndAt: at(18, 0), UserID: second},
+		}, nil).Once()
+
+		s := Server{r: m}
+		got, err := s.FindMeetingSlotsV1(userCtx, &event.FindMeetingSlotsRequestV1{
+			UserIds:         []string{first.String(), second.String(), first.String()},
+			DurationMinutes: 60,
+			StartAt:         day.Unix(),
+			EndAt:           day.Add(24 * time.Hour).Unix(),
+			WorkingHours:    &event.WorkingHoursV1{Start: "09:00", End: "18:00"},
+			MaxResults:      3,
+		})
+
+		require.NoError(t, err)
+		require.Equal(t, []*event.IntervalV1{
+			// 12:00 - 13:00 заполняет промежуток целиком
+			{StartAt: at(12, 0).Unix(), EndAt: at(13, 0).Unix()},
+			// 14:00 - 15:00 и 14:30 - 15:30 оставляют один обрывок в 30 минут,
+			// 14:15 - 15:15 оставил бы два обрывка и ранжируется ниже
+			{StartAt: at(14, 0).Unix(), EndAt: at(15, 0).Unix()},
+			{StartAt: at(14, 30).Unix(), EndAt: at(15, 30).Unix()},
+		}, got.Slots)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		s := Server{r: mocks.NewRepository(t)}
+
+		for _, req := range []*event.FindMeetingSlotsRequestV1{
+			{DurationMinutes: 60, StartAt: day.Unix(), EndAt: day.Add(time.Hour).Unix()},
+			{UserIds: []string{"foo"}, DurationMinutes: 60, StartAt: day.Unix(), EndAt: day.Add(time.Hour).Unix()},
+			{UserIds: []string{first.String()}, StartAt: day.Unix(), EndAt: day.Add(time.Hour).Unix()},
+			{UserIds: []string{first.String()}, DurationMinutes: 60, StartAt: day.Unix(), EndAt: day.Unix()},
+		} {
+			_, err := s.FindMeetingSlotsV1(userCtx, req)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		}
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		s := Server{r: mocks.NewRepository(t)}
+
+		_, err := s.FindMeetingSlotsV1(context.Background(), &event.FindMeetingSlotsRequestV1{
+			UserIds:         []string{first.String()},
+			DurationMinutes: 60,
+			StartAt:         day.Unix(),
+			EndAt:           day.Add(time.Hour).Unix(),
+		})
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
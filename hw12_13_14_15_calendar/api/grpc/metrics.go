@@ -0,0 +1,54 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/metrics"
+)
+
+// MetricsUnaryInterceptor считает запросы и время их обработки по методу и коду ответа.
+func MetricsUnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(
+		ctx context.Context,
+		req interface{},
+		info *grpc.UnaryServerInfo,
+		handler grpc.UnaryHandler,
+	) (interface{}, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+		observeRequest(info.FullMethod, start, err)
+
+		return resp, err
+	}
+}
+
+// MetricsStreamInterceptor аналог MetricsUnaryInterceptor для потоковых вызовов.
+// Время обработки равно времени жизни потока.
+func MetricsStreamInterceptor() grpc.StreamServerInterceptor {
+	return func(
+		srv interface{},
+		ss grpc.ServerStream,
+		info *grpc.StreamServerInfo,
+		handler grpc.StreamHandler,
+	) error {
+		start := time.Now()
+
+		err := handler(srv, ss)
+		observeRequest(info.FullMethod, start, err)
+
+		return err
+	}
+}
+
+// observeRequest записывает метрики завершенного запроса.
+func observeRequest(method string, start time.Time, err error) {
+	code := status.Code(err).String()
+
+	metrics.GRPCRequests.WithLabelValues(method, code).Inc()
+	metrics.GRPCRequestDuration.WithLabelValues(method, code).Observe(time.Since(start).Seconds())
+}
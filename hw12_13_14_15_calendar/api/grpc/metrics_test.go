@@ -0,0 +1,63 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/metrics"
+)
+
+func TestMetricsUnaryInterceptor(t *testing.T) {
+	const method = "/event.EventService/TestMetricsUnaryInterceptor"
+
+	interceptor := MetricsUnaryInterceptor()
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	tests := []struct {
+		name    string
+		err     error
+		code    codes.Code
+		wantErr bool
+	}{
+		{name: "ok", code: codes.OK},
+		{name: "not found", err: status.Error(codes.NotFound, "not found"), code: codes.NotFound, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metrics.GRPCRequests.WithLabelValues(method, tt.code.String())
+			before := testutil.ToFloat64(counter)
+
+			_, err := interceptor(context.Background(), nil, info, func(ctx context.Context, req interface{}) (interface{}, error) {
+				return nil, tt.err
+			})
+
+			require.Equal(t, tt.wantErr, err != nil)
+			require.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
+
+func TestMetricsStreamInterceptor(t *testing.T) {
+	const method = "/event.EventService/TestMetricsStreamInterceptor"
+
+	interceptor := MetricsStreamInterceptor()
+	info := &grpc.StreamServerInfo{FullMethod: method}
+
+	counter := metrics.GRPCRequests.WithLabelValues(method, codes.Canceled.String())
+	before := testutil.ToFloat64(counter)
+
+	err := interceptor(nil, nil, info, func(srv interface{}, stream grpc.ServerStream) error {
+		return status.Error(codes.Canceled, "canceled")
+	})
+
+	require.Error(t, err)
+	require.Equal(t, before+1, testutil.ToFloat64(counter))
+}
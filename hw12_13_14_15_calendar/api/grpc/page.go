@@ -0,0 +1,131 @@
+package grpc
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	"github.com/google/uuid"
+	json "github.com/json-iterator/go"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+const (
+	// defaultPageSize размер страницы, если он не передан.
+	defaultPageSize = 100
+
+	// maxPageSize максимальный размер страницы.
+	maxPageSize = 1000
+)
+
+// pageRequest запрос на получение страницы событий.
+type pageRequest interface {
+	GetPageSize() int32
+	GetPageToken() string
+	GetOrderBy() string
+}
+
+// pageToken содержимое page_token: позиция последнего события на странице.
+type pageToken struct {
+	OrderBy calendar.EventOrder `json:"o"`
+	Title   string              `json:"t,omitempty"`
+	StartAt int64               `json:"s"`
+	EndAt   int64               `json:"e"`
+	ID      uuid.UUID           `json:"i"`
+}
+
+// findEventsPage находит страницу событий по фильтру.
+func (s *Server) findEventsPage(
+	ctx context.Context,
+	filter calendar.EventFilter,
+	req pageRequest,
+) (*event.EventsResponseV1, error) {
+	order, err := calendar.ParseEventOrder(req.GetOrderBy())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid order_by")
+	}
+
+	size := int(req.GetPageSize())
+
+	switch {
+	case size < 0:
+		return nil, status.Error(codes.InvalidArgument, "invalid page_size")
+	case size == 0:
+		size = defaultPageSize
+	case size > maxPageSize:
+		size = maxPageSize
+	}
+
+	if req.GetPageToken() != "" {
+		if filter.After, err = decodePageToken(req.GetPageToken(), order); err != nil {
+			return nil, err
+		}
+	}
+
+	// запрашиваем на одно событие больше, чтобы узнать, есть ли следующая страница
+	filter.OrderBy, filter.Limit = order, size+1
+
+	events, err := s.r.FindEvents(ctx, filter)
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	res := &event.EventsResponseV1{}
+
+	if len(events) > size {
+		events = events[:size]
+		res.NextPageToken = encodePageToken(order, calendar.CursorOf(events[size-1]))
+	}
+
+	res.Events = make([]*event.EventV1, 0, len(events))
+
+	for _, e := range events {
+		res.Events = append(res.Events, newEventV1(e))
+	}
+
+	return res, nil
+}
+
+// encodePageToken формирует page_token.
+func encodePageToken(order calendar.EventOrder, c calendar.EventCursor) string {
+	t := pageToken{
+		OrderBy: order,
+		StartAt: c.StartAt.UnixNano(),
+		EndAt:   c.EndAt.UnixNano(),
+		ID:      c.ID,
+	}
+
+	if order == calendar.OrderByTitle {
+		t.Title = c.Title
+	}
+
+	bs, _ := json.Marshal(t)
+
+	return base64.RawURLEncoding.EncodeToString(bs)
+}
+
+// decodePageToken разбирает page_token. Токен действителен только для той же сортировки.
+func decodePageToken(token string, order calendar.EventOrder) (*calendar.EventCursor, error) {
+	invalid := status.Error(codes.InvalidArgument, "invalid page_token")
+
+	bs, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, invalid
+	}
+
+	t := pageToken{}
+	if err := json.Unmarshal(bs, &t); err != nil || t.OrderBy != order {
+		return nil, invalid
+	}
+
+	return &calendar.EventCursor{
+		Title:   t.Title,
+		StartAt: time.Unix(0, t.StartAt),
+		EndAt:   time.Unix(0, t.EndAt),
+		ID:      t.ID,
+	}, nil
+}
@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/mocks"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+func TestServer_GetEventsForMonthV1_Paging(t *testing.T) {
+	userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	from := time.Date(2022, 10, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2022, 11, 1, 0, 0, 0, 0, time.UTC)
+
+	events := []*calendar.Event{
+		{ID: uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"), Title: "a", StartAt: from, EndAt: from.Add(time.Hour), UserID: userID},                        //nolint:lll
+		{ID: uuid.MustParse("2f0d2079-e9a2-4810-8cae-eb6729c50580"), Title: "b", StartAt: from.Add(time.Hour), EndAt: from.Add(2 * time.Hour), UserID: userID},     //nolint:lll
+		{ID: uuid.MustParse("3f0d2079-e9a2-4810-8cae-eb6729c50580"), Title: "c", StartAt: from.Add(2 * time.Hour), EndAt: from.Add(3 * time.Hour), UserID: userID}, //nolint:lll
+	}
+
+	t.Run("next page token", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		m.On("FindEvents", mock.Anything, calendar.EventFilter{
+			UserID:    userID,
+			Attending: true,
+			From:      from,
+			To:        to,
+			OrderBy:   calendar.OrderByTitle,
+			Limit:     3,
+		}).Return(events, nil).Once()
+
+		s := Server{r: m}
+		got, err := s.GetEventsForMonthV1(userCtx, &event.GetEventsForMonthRequestV1{
+			StartDate: "2022-10-01",
+			PageSize:  2,
+			OrderBy:   "title",
+		})
+		require.NoError(t, err)
+		require.Len(t, got.Events, 2)
+		require.NotEmpty(t, got.NextPageToken)
+
+		cursor, err := decodePageToken(got.NextPageToken, calendar.OrderByTitle)
+		require.NoError(t, err)
+		require.Equal(t, "b", cursor.Title)
+		require.True(t, events[1].StartAt.Equal(cursor.StartAt))
+		require.Equal(t, events[1].ID, cursor.ID)
+
+		_, err = decodePageToken(got.NextPageToken, calendar.OrderByStartAt)
+		require.Equal(t, codes.InvalidArgument, status.Code(err))
+	})
+
+	t.Run("last page", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		token := encodePageToken(calendar.OrderByStartAt, calendar.CursorOf(events[1]))
+
+		m.On("FindEvents", mock.Anything, mock.MatchedBy(func(f calendar.EventFilter) bool {
+			return f.Limit == defaultPageSize+1 &&
+				f.OrderBy == calendar.OrderByStartAt &&
+				f.After != nil && f.After.ID == events[1].ID && f.After.StartAt.Equal(events[1].StartAt)
+		})).Return(events[2:], nil).Once()
+
+		s := Server{r: m}
+		got, err := s.GetEventsForMonthV1(userCtx, &event.GetEventsForMonthRequestV1{
+			StartDate: "2022-10-01",
+			PageToken: token,
+		})
+		require.NoError(t, err)
+		require.Len(t, got.Events, 1)
+		require.Empty(t, got.NextPageToken)
+	})
+
+	t.Run("invalid arguments", func(t *testing.T) {
+		m := mocks.NewRepository(t)
+		defer m.AssertExpectations(t)
+
+		s := Server{r: m}
+
+		for _, req := range []*event.GetEventsForMonthRequestV1{
+			{StartDate: "2022-10-01", OrderBy: "user_id"},
+			{StartDate: "2022-10-01", PageSize: -1},
+			{StartDate: "2022-10-01", PageToken: "foo"},
+		} {
+			_, err := s.GetEventsForMonthV1(userCtx, req)
+			require.Equal(t, codes.InvalidArgument, status.Code(err))
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+// versionPath путь в маске полей, который задает ожидаемую версию события, а не изменяемое поле.
+const versionPath = "version"
+
+// PatchEventV1 обновляет только поля события, перечисленные в update_mask,
+// остальные поля сохраняют текущие значения.
+// Через REST маска по умолчанию составляется из полей, переданных в теле запроса.
+func (s *Server) PatchEventV1(ctx context.Context, req *event.PatchEventRequestV1) (*event.EventResponseV1, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid uuid")
+	}
+
+	fields, err := patchFields(req.GetUpdateMask())
+	if err != nil {
+		return nil, err
+	}
+
+	src := req.GetEvent()
+
+	if err := validateRRule(src.GetRrule()); err != nil {
+		return nil, err
+	}
+
+	if _, err := loadLocation(src.GetTimeZone()); err != nil {
+		return nil, err
+	}
+
+	requested := req.GetVersion()
+	if requested == 0 {
+		requested = src.GetVersion()
+	}
+
+	version, err := expectedVersion(ctx, requested)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.checkOwner(ctx, userID, ID); err != nil {
+		return nil, err
+	}
+
+	e, err := s.r.PatchEvent(ctx, ID, &calendar.Event{
+		Title:                src.GetTitle(),
+		Description:          src.GetDescription(),
+		StartAt:              time.Unix(src.GetStartAt(), 0),
+		EndAt:                time.Unix(src.GetEndAt(), 0),
+		TimeZone:             src.GetTimeZone(),
+		NotificationDuration: src.GetNotificationDuration(),
+		RRule:                src.GetRrule(),
+		ExDates:              timesFromUnix(src.GetExdates()),
+		Version:              version,
+	}, fields)
+	if err != nil {
+		return nil, updateError(err)
+	}
+
+	setETag(ctx, e)
+
+	return &event.EventResponseV1{
+		Event: newEventV1(e),
+	}, nil
+}
+
+// patchFields преобразует маску в список изменяемых полей события.
+func patchFields(mask *fieldmaskpb.FieldMask) ([]calendar.EventField, error) {
+	fields := make([]calendar.EventField, 0, len(mask.GetPaths()))
+
+	for _, path := range mask.GetPaths() {
+		if path == versionPath {
+			continue
+		}
+
+		f, err := calendar.ParseEventField(path)
+		if err != nil {
+			return nil, status.Errorf(codes.InvalidArgument, "field %q cannot be updated", path)
+		}
+
+		fields = append(fields, f)
+	}
+
+	if len(fields) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "update mask is empty")
+	}
+
+	return fields, nil
+}
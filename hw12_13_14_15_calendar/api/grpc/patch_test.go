@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/mocks"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+func TestServer_PatchEventV1(t *testing.T) {
+	userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	eventID := uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")
+
+	tests := []struct {
+		name     string
+		paths    []string
+		version  uint64
+		repoErr  error
+		wantCode codes.Code
+	}{
+		{name: "patched", paths: []string{"description", "version"}, version: 2, wantCode: codes.OK},
+		{name: "empty mask", paths: nil, version: 2, wantCode: codes.InvalidArgument},
+		{name: "only version", paths: []string{"version"}, version: 2, wantCode: codes.InvalidArgument},
+		{name: "read-only field", paths: []string{"user_id"}, version: 2, wantCode: codes.InvalidArgument},
+		{name: "no version", paths: []string{"description"}, wantCode: codes.InvalidArgument},
+		{
+			name:     "version conflict",
+			paths:    []string{"description"},
+			version:  2,
+			repoErr:  calendar.ErrVersionConflict,
+			wantCode: codes.Aborted,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewRepository(t)
+			defer m.AssertExpectations(t)
+
+			if tt.wantCode == codes.OK || tt.repoErr != nil {
+				m.On("FindEventByID", mock.Anything, eventID).
+					Return(&calendar.Event{ID: eventID, UserID: userID, Version: 2}, nil).Once()
+
+				var e *calendar.Event
+				if tt.repoErr == nil {
+					e = &calendar.Event{
+						ID:          eventID,
+						Title:       "foo",
+						Description: "bar",
+						StartAt:     time.Unix(1664643702, 0),
+						EndAt:       time.Unix(1664644150, 0),
+						UserID:      userID,
+						Version:     3,
+					}
+				}
+
+				m.On("PatchEvent", mock.Anything, eventID, mock.MatchedBy(func(e *calendar.Event) bool {
+					return e.Description == "bar" && e.Version == 2
+				}), []calendar.EventField{calendar.FieldDescription}).
+					Return(e, errors.Wrap(tt.repoErr, "patch event")).Once()
+			}
+
+			s := Server{r: m}
+			got, err := s.PatchEventV1(userCtx, &event.PatchEventRequestV1{
+				Id:         eventID.String(),
+				Event:      &event.EventV1{Description: "bar", Version: tt.version},
+				UpdateMask: &fieldmaskpb.FieldMask{Paths: tt.paths},
+			})
+
+			require.Equal(t, tt.wantCode, status.Code(err))
+
+			if tt.wantCode == codes.OK {
+				require.Equal(t, "foo", got.Event.Title)
+				require.Equal(t, "bar", got.Event.Description)
+				require.Equal(t, uint64(3), got.Event.Version)
+			}
+		})
+	}
+}
@@ -0,0 +1,149 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/inmem"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/memqueue"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/scheduler"
+)
+
+// TestServer_WithScheduler проверяет (с флагом -race), что API читает события,
+// пока планировщик отмечает их уведомленными и перемещает в корзину в том же хранилище.
+func TestServer_WithScheduler(t *testing.T) {
+	repo := inmem.New()
+
+	ownerID, _ := auth.UserIDFromContext(userCtx)
+	attendeeID := uuid.New()
+	attendeeCtx := auth.WithUserID(context.Background(), attendeeID)
+
+	now := time.Now().Truncate(time.Minute)
+
+	// события, о которых планировщик уведомляет
+	upcoming := make([]*calendar.Event, 0, 2)
+
+	for _, e := range []*calendar.Event{
+		{Title: "single", StartAt: now.Add(10 * time.Minute), NotificationDuration: 60},
+		{Title: "series", StartAt: now.Add(20 * time.Minute), NotificationDuration: 60, RRule: "FREQ=DAILY"},
+	} {
+		e.EndAt = e.StartAt.Add(5 * time.Minute)
+		e.UserID = ownerID
+
+		created, err := repo.CreateEvent(userCtx, e)
+		require.NoError(t, err)
+
+		upcoming = append(upcoming, created)
+	}
+
+	// старые события, которые планировщик перемещает в корзину и удаляет из нее
+	for i := 0; i < 20; i++ {
+		startAt := now.AddDate(0, -3, 0).Add(time.Duration(i) * time.Hour)
+
+		_, err := repo.CreateEvent(userCtx, &calendar.Event{
+			Title:   "old",
+			StartAt: startAt,
+			EndAt:   startAt.Add(time.Minute),
+			UserID:  ownerID,
+		})
+		require.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	q := memqueue.New(memqueue.Config{Size: 1000, MaxAttempts: 1})
+
+	t.Cleanup(func() {
+		require.NoError(t, q.Close())
+	})
+
+	s := scheduler.New(repo, q, scheduler.Config{
+		Interval:        time.Millisecond,
+		EventLifeInDays: 1,
+		TrashRetention:  time.Nanosecond,
+	})
+
+	schedulerErr := make(chan error, 1)
+
+	go func() {
+		schedulerErr <- s.Start(ctx)
+	}()
+
+	srv := New(repo, nil)
+
+	var wg sync.WaitGroup
+
+	for _, e := range upcoming {
+		wg.Add(1)
+
+		go func(eventID string) {
+			defer wg.Done()
+
+			for i := 0; i < 50; i++ {
+				_, err := srv.InviteAttendeeV1(userCtx, &event.InviteAttendeeRequestV1{
+					EventId: eventID,
+					UserId:  attendeeID.String(),
+				})
+				require.NoError(t, err)
+
+				status := string(calendar.RSVPAccepted)
+				if i%2 == 1 {
+					status = string(calendar.RSVPDeclined)
+				}
+
+				_, err = srv.RespondToEventV1(attendeeCtx, &event.RespondToEventRequestV1{
+					EventId: eventID,
+					Status:  status,
+				})
+				require.NoError(t, err)
+
+				_, err = srv.RemoveAttendeeV1(userCtx, &event.RemoveAttendeeRequestV1{
+					EventId: eventID,
+					UserId:  attendeeID.String(),
+				})
+				require.NoError(t, err)
+			}
+		}(e.ID.String())
+	}
+
+	wg.Add(1)
+
+	go func() {
+		defer wg.Done()
+
+		for i := 0; i < 50; i++ {
+			_, err := srv.GetEventsForWeekV1(userCtx, &event.GetEventsForWeekRequestV1{
+				StartDate: now.Format("2006-01-02"),
+			})
+			require.NoError(t, err)
+
+			_, err = srv.GetEventsForMonthV1(userCtx, &event.GetEventsForMonthRequestV1{
+				StartDate: now.AddDate(0, -3, 0).Format("2006-01-02"),
+			})
+			require.NoError(t, err)
+
+			_, err = srv.ListTrashV1(userCtx, &event.ListTrashRequestV1{})
+			require.NoError(t, err)
+		}
+	}()
+
+	wg.Wait()
+
+	// планировщик успел уведомить о событиях
+	require.Eventually(t, func() bool {
+		events, err := repo.FindEvents(ctx, calendar.EventFilter{NotNotified: true, NotifyTime: true})
+		return err == nil && len(events) == 0
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	require.ErrorIs(t, <-schedulerErr, context.Canceled)
+}
@@ -0,0 +1,112 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/uuid"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+// ListTrashV1 возвращает события пользователя в корзине, сначала удаленные последними.
+func (s *Server) ListTrashV1(ctx context.Context, req *event.ListTrashRequestV1) (*event.EventsResponseV1, error) {
+	userID, err := callerID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+
+	events, err := s.r.FindTrash(ctx, calendar.TrashFilter{UserID: userID})
+	if err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	res := &event.EventsResponseV1{
+		Events: make([]*event.EventV1, 0, len(events)),
+	}
+
+	for _, e := range events {
+		res.Events = append(res.Events, newEventV1(e))
+	}
+
+	return res, nil
+}
+
+// RestoreEventV1 восстанавливает событие из корзины.
+// Если время события уже занято, то событие остается в корзине.
+func (s *Server) RestoreEventV1(ctx context.Context, req *event.RestoreEventRequestV1) (*event.EventResponseV1, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid uuid")
+	}
+
+	if err := s.checkTrashOwner(ctx, userID, ID); err != nil {
+		return nil, err
+	}
+
+	e, err := s.r.RestoreEvent(ctx, ID)
+	if err != nil {
+		switch {
+		case errors.Is(err, calendar.ErrNotFound):
+			return nil, status.Error(codes.NotFound, "event not found in trash")
+		case errors.Is(err, calendar.ErrDateBusy):
+			return nil, status.Error(codes.InvalidArgument, "that date is already taken by another event")
+		}
+
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &event.EventResponseV1{
+		Event: newEventV1(e),
+	}, nil
+}
+
+// PurgeEventV1 окончательно удаляет событие из корзины.
+func (s *Server) PurgeEventV1(ctx context.Context, req *event.PurgeEventRequestV1) (*emptypb.Empty, error) {
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return nil, err
+	}
+
+	ID, err := uuid.Parse(req.GetId())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid uuid")
+	}
+
+	if err := s.checkTrashOwner(ctx, userID, ID); err != nil {
+		return nil, err
+	}
+
+	if err := s.r.PurgeEvent(ctx, ID); err != nil {
+		return nil, status.Error(codes.Unavailable, err.Error())
+	}
+
+	return &emptypb.Empty{}, nil
+}
+
+// checkTrashOwner проверяет, что событие в корзине принадлежит пользователю userID.
+func (s *Server) checkTrashOwner(ctx context.Context, userID, eventID uuid.UUID) error {
+	events, err := s.r.FindTrash(ctx, calendar.TrashFilter{ID: eventID})
+	if err != nil {
+		return status.Error(codes.Unavailable, err.Error())
+	}
+
+	if len(events) == 0 {
+		return status.Error(codes.NotFound, "event not found in trash")
+	}
+
+	if events[0].UserID != userID {
+		return status.Error(codes.PermissionDenied, "event belongs to another user")
+	}
+
+	return nil
+}
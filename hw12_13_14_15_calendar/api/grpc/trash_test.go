@@ -0,0 +1,159 @@
+package grpc
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/mocks"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+func TestServer_ListTrashV1(t *testing.T) {
+	userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	eventID := uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")
+	deletedAt := time.Unix(1664643702, 0)
+
+	m := mocks.NewRepository(t)
+	defer m.AssertExpectations(t)
+
+	m.On("FindTrash", mock.Anything, calendar.TrashFilter{UserID: userID}).Return([]*calendar.Event{
+		{
+			ID:        eventID,
+			Title:     "foo",
+			StartAt:   time.Unix(1664643702, 0),
+			EndAt:     time.Unix(1664644150, 0),
+			UserID:    userID,
+			DeletedAt: &deletedAt,
+		},
+	}, nil).Once()
+
+	s := Server{r: m}
+	got, err := s.ListTrashV1(userCtx, &event.ListTrashRequestV1{})
+
+	require.NoError(t, err)
+	require.Equal(t, []*event.EventV1{
+		{
+			Id:        eventID.String(),
+			Title:     "foo",
+			StartAt:   1664643702,
+			EndAt:     1664644150,
+			UserId:    userID.String(),
+			DeletedAt: 1664643702,
+		},
+	}, got.Events)
+}
+
+func TestServer_RestoreEventV1(t *testing.T) {
+	userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	eventID := uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")
+
+	tests := []struct {
+		name     string
+		id       string
+		trash    []*calendar.Event
+		repoErr  error
+		wantCode codes.Code
+	}{
+		{
+			name:     "restored",
+			id:       eventID.String(),
+			trash:    []*calendar.Event{{ID: eventID, UserID: userID}},
+			wantCode: codes.OK,
+		},
+		{
+			name:     "invalid uuid",
+			id:       "foo",
+			wantCode: codes.InvalidArgument,
+		},
+		{
+			name:     "not in trash",
+			id:       eventID.String(),
+			wantCode: codes.NotFound,
+		},
+		{
+			name:     "another user event",
+			id:       eventID.String(),
+			trash:    []*calendar.Event{{ID: eventID, UserID: uuid.New()}},
+			wantCode: codes.PermissionDenied,
+		},
+		{
+			name:     "busy",
+			id:       eventID.String(),
+			trash:    []*calendar.Event{{ID: eventID, UserID: userID}},
+			repoErr:  calendar.ErrDateBusy,
+			wantCode: codes.InvalidArgument,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewRepository(t)
+			defer m.AssertExpectations(t)
+
+			if tt.id == eventID.String() {
+				m.On("FindTrash", mock.Anything, calendar.TrashFilter{ID: eventID}).Return(tt.trash, nil).Once()
+			}
+
+			if tt.wantCode == codes.OK || tt.repoErr != nil {
+				var e *calendar.Event
+				if tt.repoErr == nil {
+					e = &calendar.Event{ID: eventID, UserID: userID}
+				}
+
+				m.On("RestoreEvent", mock.Anything, eventID).Return(e, errors.Wrap(tt.repoErr, "restore event")).Once()
+			}
+
+			s := Server{r: m}
+			got, err := s.RestoreEventV1(userCtx, &event.RestoreEventRequestV1{Id: tt.id})
+
+			require.Equal(t, tt.wantCode, status.Code(err))
+
+			if tt.wantCode == codes.OK {
+				require.Equal(t, eventID.String(), got.Event.Id)
+			}
+		})
+	}
+}
+
+func TestServer_PurgeEventV1(t *testing.T) {
+	userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	eventID := uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")
+
+	tests := []struct {
+		name     string
+		trash    []*calendar.Event
+		wantCode codes.Code
+	}{
+		{name: "purged", trash: []*calendar.Event{{ID: eventID, UserID: userID}}, wantCode: codes.OK},
+		{name: "not in trash", wantCode: codes.NotFound},
+		{
+			name:     "another user event",
+			trash:    []*calendar.Event{{ID: eventID, UserID: uuid.New()}},
+			wantCode: codes.PermissionDenied,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewRepository(t)
+			defer m.AssertExpectations(t)
+
+			m.On("FindTrash", mock.Anything, calendar.TrashFilter{ID: eventID}).Return(tt.trash, nil).Once()
+
+			if tt.wantCode == codes.OK {
+				m.On("PurgeEvent", mock.Anything, eventID).Return(nil).Once()
+			}
+
+			s := Server{r: m}
+			_, err := s.PurgeEventV1(userCtx, &event.PurgeEventRequestV1{Id: eventID.String()})
+
+			require.Equal(t, tt.wantCode, status.Code(err))
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+const (
+	// IfMatchHeader ключ метаданных с ожидаемой версией события (аналог HTTP заголовка If-Match).
+	IfMatchHeader = "if-match"
+
+	// ETagHeader ключ метаданных ответа с версией события (аналог HTTP заголовка ETag).
+	ETagHeader = "etag"
+)
+
+// expectedVersion возвращает версию события, на основе которой клиент сделал изменения:
+// из поля запроса, а если оно не заполнено - из метаданных if-match.
+func expectedVersion(ctx context.Context, requested uint64) (uint64, error) {
+	if requested != 0 {
+		return requested, nil
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+
+	values := md.Get(IfMatchHeader)
+	if len(values) == 0 {
+		return 0, status.Error(codes.InvalidArgument, "event version is required")
+	}
+
+	version, err := parseETag(values[0])
+	if err != nil {
+		return 0, status.Error(codes.InvalidArgument, "invalid if-match")
+	}
+
+	return version, nil
+}
+
+// setETag передает версию события клиенту в метаданных ответа.
+func setETag(ctx context.Context, e *calendar.Event) {
+	// вне gRPC вызова (например, в тестах) метаданные ответа некуда передать
+	_ = grpc.SetHeader(ctx, metadata.Pairs(ETagHeader, formatETag(e.Version)))
+}
+
+// formatETag формирует ETag по версии события.
+func formatETag(version uint64) string {
+	return strconv.Quote(strconv.FormatUint(version, 10))
+}
+
+// parseETag извлекает версию события из ETag. Слабые ETag (W/"1") также принимаются.
+func parseETag(etag string) (uint64, error) {
+	etag = strings.TrimPrefix(strings.TrimSpace(etag), "W/")
+
+	unquoted, err := strconv.Unquote(etag)
+	if err != nil {
+		return 0, err
+	}
+
+	return strconv.ParseUint(unquoted, 10, 64)
+}
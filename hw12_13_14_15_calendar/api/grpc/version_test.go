@@ -0,0 +1,49 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+func TestExpectedVersion(t *testing.T) {
+	tests := []struct {
+		name      string
+		requested uint64
+		ifMatch   string
+		want      uint64
+		wantCode  codes.Code
+	}{
+		{name: "from request", requested: 2, ifMatch: `"5"`, want: 2},
+		{name: "from if-match", ifMatch: `"5"`, want: 5},
+		{name: "weak etag", ifMatch: `W/"5"`, want: 5},
+		{name: "unquoted", ifMatch: "5", wantCode: codes.InvalidArgument},
+		{name: "any", ifMatch: "*", wantCode: codes.InvalidArgument},
+		{name: "missing", wantCode: codes.InvalidArgument},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.ifMatch != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs(IfMatchHeader, tt.ifMatch))
+			}
+
+			got, err := expectedVersion(ctx, tt.requested)
+
+			require.Equal(t, tt.wantCode, status.Code(err))
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatETag(t *testing.T) {
+	require.Equal(t, `"12"`, formatETag(12))
+
+	version, err := parseETag(formatETag(12))
+	require.NoError(t, err)
+	require.Equal(t, uint64(12), version)
+}
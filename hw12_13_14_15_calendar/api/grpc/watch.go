@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"errors"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+// WatchEventsV1 передает изменения событий вызывающего пользователя:
+// событий, которыми он владеет или на которые приглашен.
+// Если передана after_revision, то сначала передаются пропущенные изменения после нее.
+// При обрыве потока клиенту следует переподключиться с ревизией последнего полученного изменения.
+func (s *Server) WatchEventsV1(req *event.WatchEventsRequestV1, stream event.EventService_WatchEventsV1Server) error {
+	ctx := stream.Context()
+
+	userID, err := callerID(ctx, "")
+	if err != nil {
+		return err
+	}
+
+	changes, err := s.w.WatchChanges(ctx, userID, req.GetAfterRevision())
+	if err != nil {
+		return watchError(err)
+	}
+
+	// заголовки отправляются сразу, чтобы клиент знал, что подписка оформлена
+	if err := stream.SendHeader(metadata.MD{}); err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case c, ok := <-changes:
+			if !ok {
+				if ctx.Err() != nil {
+					return nil
+				}
+
+				return status.Error(codes.Aborted, "watcher is too slow, resume from the last revision")
+			}
+
+			if err := stream.Send(NewEventChangeV1(c)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watchError преобразует ошибку подписки на изменения в статус gRPC.
+func watchError(err error) error {
+	if errors.Is(err, calendar.ErrRevisionExpired) {
+		return status.Error(codes.OutOfRange, "revision expired, reload events")
+	}
+
+	return status.Error(codes.Unavailable, err.Error())
+}
+
+// NewEventChangeV1 преобразует изменение события в его представление в API.
+func NewEventChangeV1(c calendar.Change) *event.EventChangeV1 {
+	return &event.EventChangeV1{
+		Revision: c.Revision,
+		Type:     string(c.Type),
+		Event:    newEventV1(c.Event),
+	}
+}
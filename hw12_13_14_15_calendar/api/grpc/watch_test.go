@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/changes"
+	eventmocks "github.com/RomanSarvarov/otus_go_home_work/calendar/mocks/proto/event"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+func TestServer_WatchEventsV1(t *testing.T) {
+	userID, ok := auth.UserIDFromContext(userCtx)
+	require.True(t, ok)
+
+	newEvent := func(ownerID uuid.UUID) *calendar.Event {
+		return &calendar.Event{ID: uuid.New(), UserID: ownerID}
+	}
+
+	t.Run("resume and live changes", func(t *testing.T) {
+		bus := changes.New(10)
+
+		created := newEvent(userID)
+		bus.PublishChange(calendar.NewChange(calendar.ChangeCreated, newEvent(userID)))
+		bus.PublishChange(calendar.NewChange(calendar.ChangeCreated, created))
+		bus.PublishChange(calendar.NewChange(calendar.ChangeCreated, newEvent(uuid.New())))
+
+		ctx, cancel := context.WithCancel(userCtx)
+		defer cancel()
+
+		sent := make(chan *event.EventChangeV1, 10)
+
+		stream := eventmocks.NewEventService_WatchEventsV1Server(t)
+		stream.On("Context").Return(ctx)
+		stream.On("SendHeader", metadata.MD{}).Return(nil).Once()
+		stream.On("Send", mock.Anything).Run(func(args mock.Arguments) {
+			sent <- args.Get(0).(*event.EventChangeV1)
+		}).Return(nil)
+
+		done := make(chan error)
+
+		s := Server{w: bus}
+
+		go func() {
+			done <- s.WatchEventsV1(&event.WatchEventsRequestV1{AfterRevision: 1}, stream)
+		}()
+
+		got := <-sent
+		require.Equal(t, uint64(2), got.Revision)
+		require.Equal(t, "created", got.Type)
+		require.Equal(t, created.ID.String(), got.Event.Id)
+
+		deleted := newEvent(userID)
+		bus.PublishChange(calendar.NewChange(calendar.ChangeDeleted, deleted))
+
+		got = <-sent
+		require.Equal(t, uint64(4), got.Revision)
+		require.Equal(t, "deleted", got.Type)
+		require.Equal(t, deleted.ID.String(), got.Event.Id)
+
+		cancel()
+		require.NoError(t, <-done)
+	})
+
+	t.Run("revision expired", func(t *testing.T) {
+		stream := eventmocks.NewEventService_WatchEventsV1Server(t)
+		stream.On("Context").Return(userCtx)
+
+		s := Server{w: changes.New(10)}
+		err := s.WatchEventsV1(&event.WatchEventsRequestV1{AfterRevision: 5}, stream)
+
+		require.Equal(t, codes.OutOfRange, status.Code(err))
+	})
+
+	t.Run("unauthenticated", func(t *testing.T) {
+		stream := eventmocks.NewEventService_WatchEventsV1Server(t)
+		stream.On("Context").Return(context.Background())
+
+		s := Server{w: changes.New(10)}
+		err := s.WatchEventsV1(&event.WatchEventsRequestV1{}, stream)
+
+		require.Equal(t, codes.Unauthenticated, status.Code(err))
+	})
+}
@@ -0,0 +1,38 @@
+package rest
+
+import (
+	"net/http"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+)
+
+// AuthMiddleware проверяет токен доступа и кладет идентификатор пользователя в контекст запроса.
+// Токен передается в заголовке "Authorization: Bearer <token>".
+// Клиенты CalDAV, которые умеют только Basic авторизацию, передают токен паролем.
+func AuthMiddleware(v *auth.Verifier, next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, req *http.Request) {
+		token, err := requestToken(req)
+		if err == nil {
+			userID, err := v.Verify(token)
+			if err == nil {
+				next.ServeHTTP(w, req.WithContext(auth.WithUserID(req.Context(), userID)))
+				return
+			}
+		}
+
+		w.Header().Add("WWW-Authenticate", `Bearer realm="calendar"`)
+		w.Header().Add("WWW-Authenticate", `Basic realm="calendar"`)
+		http.Error(w, auth.ErrUnauthenticated.Error(), http.StatusUnauthorized)
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// requestToken извлекает токен доступа из заголовков запроса.
+func requestToken(req *http.Request) (string, error) {
+	if _, password, ok := req.BasicAuth(); ok {
+		return password, nil
+	}
+
+	return auth.BearerToken(req.Header.Get("Authorization"))
+}
@@ -0,0 +1,81 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+)
+
+func TestAuthMiddleware(t *testing.T) {
+	v, err := auth.NewVerifier(auth.Config{HMACSecret: "secret"})
+	require.NoError(t, err)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.RegisteredClaims{
+		Subject:   "123e4567-e89b-12d3-a456-426614174000",
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte("secret"))
+	require.NoError(t, err)
+
+	h := AuthMiddleware(v, http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		userID, ok := auth.UserIDFromContext(req.Context())
+		require.True(t, ok)
+
+		_, _ = w.Write([]byte(userID.String()))
+	}))
+
+	tests := []struct {
+		name       string
+		setup      func(req *http.Request)
+		wantStatus int
+	}{
+		{
+			name: "bearer",
+			setup: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer "+token)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name: "basic",
+			setup: func(req *http.Request) {
+				req.SetBasicAuth("user", token)
+			},
+			wantStatus: http.StatusOK,
+		},
+		{
+			name:       "without token",
+			setup:      func(req *http.Request) {},
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name: "invalid token",
+			setup: func(req *http.Request) {
+				req.Header.Set("Authorization", "Bearer foo")
+			},
+			wantStatus: http.StatusUnauthorized,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/events/day", nil)
+			tt.setup(req)
+
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+
+			if tt.wantStatus == http.StatusOK {
+				require.Equal(t, "123e4567-e89b-12d3-a456-426614174000", rec.Body.String())
+			} else {
+				require.NotEmpty(t, rec.Header().Values("WWW-Authenticate"))
+			}
+		})
+	}
+}
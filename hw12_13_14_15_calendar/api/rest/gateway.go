@@ -0,0 +1,58 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/textproto"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	grpcapi "github.com/RomanSarvarov/otus_go_home_work/calendar/api/grpc"
+)
+
+// GatewayOptions настройки grpc-gateway для REST API:
+// заголовок If-Match передается в метаданные запроса, версия события возвращается в заголовке ETag,
+// а конфликт версий отдается статусом 412 Precondition Failed.
+func GatewayOptions() []runtime.ServeMuxOption {
+	return []runtime.ServeMuxOption{
+		runtime.WithIncomingHeaderMatcher(incomingHeaderMatcher),
+		runtime.WithOutgoingHeaderMatcher(outgoingHeaderMatcher),
+		runtime.WithErrorHandler(errorHandler),
+	}
+}
+
+// incomingHeaderMatcher передает заголовок If-Match без префикса grpcgateway-.
+func incomingHeaderMatcher(key string) (string, bool) {
+	if textproto.CanonicalMIMEHeaderKey(key) == "If-Match" {
+		return grpcapi.IfMatchHeader, true
+	}
+
+	return runtime.DefaultHeaderMatcher(key)
+}
+
+// outgoingHeaderMatcher отдает версию события заголовком ETag.
+func outgoingHeaderMatcher(key string) (string, bool) {
+	if key == grpcapi.ETagHeader {
+		return "ETag", true
+	}
+
+	return runtime.MetadataHeaderPrefix + key, true
+}
+
+// errorHandler отдает конфликт версий события (codes.Aborted) статусом 412 Precondition Failed.
+func errorHandler(
+	ctx context.Context,
+	mux *runtime.ServeMux,
+	m runtime.Marshaler,
+	w http.ResponseWriter,
+	req *http.Request,
+	err error,
+) {
+	if status.Code(err) == codes.Aborted {
+		err = &runtime.HTTPStatusError{HTTPStatus: http.StatusPreconditionFailed, Err: err}
+	}
+
+	runtime.DefaultHTTPErrorHandler(ctx, mux, m, w, req, err)
+}
@@ -0,0 +1,104 @@
+package rest
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	grpcapi "github.com/RomanSarvarov/otus_go_home_work/calendar/api/grpc"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/mocks"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+func TestGatewayOptions_Version(t *testing.T) {
+	userID := uuid.New()
+	eventID := uuid.New()
+
+	tests := []struct {
+		name     string
+		repoErr  error
+		wantCode int
+		wantETag string
+	}{
+		{name: "updated", wantCode: http.StatusOK, wantETag: `"3"`},
+		{name: "conflict", repoErr: calendar.ErrVersionConflict, wantCode: http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			m := mocks.NewRepository(t)
+			defer m.AssertExpectations(t)
+
+			m.On("FindEventByID", mock.Anything, eventID).
+				Return(&calendar.Event{ID: eventID, UserID: userID, Version: 2}, nil).
+				Once()
+
+			var updated *calendar.Event
+			if tt.repoErr == nil {
+				updated = &calendar.Event{ID: eventID, UserID: userID, Version: 3}
+			}
+
+			m.On("UpdateEvent", mock.Anything, eventID, mock.MatchedBy(func(e *calendar.Event) bool {
+				return e.Version == 2
+			})).Return(updated, errors.Wrap(tt.repoErr, "update event")).Once()
+
+			mux := runtime.NewServeMux(GatewayOptions()...)
+			err := event.RegisterEventServiceHandlerServer(context.Background(), mux, grpcapi.New(m, nil))
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, "/events/"+eventID.String(), strings.NewReader(`{"title":"foo"}`))
+			req.Header.Set("If-Match", `"2"`)
+			req = req.WithContext(auth.WithUserID(req.Context(), userID))
+
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			require.Equal(t, tt.wantCode, rec.Code)
+			require.Equal(t, tt.wantETag, rec.Header().Get("ETag"))
+		})
+	}
+}
+
+func TestGatewayOptions_Patch(t *testing.T) {
+	userID := uuid.New()
+	eventID := uuid.New()
+
+	m := mocks.NewRepository(t)
+	defer m.AssertExpectations(t)
+
+	m.On("FindEventByID", mock.Anything, eventID).
+		Return(&calendar.Event{ID: eventID, UserID: userID, Version: 2}, nil).
+		Once()
+
+	// маска составляется из полей тела запроса
+	m.On("PatchEvent", mock.Anything, eventID, mock.MatchedBy(func(e *calendar.Event) bool {
+		return e.Description == "bar" && e.Version == 2
+	}), []calendar.EventField{calendar.FieldDescription}).
+		Return(&calendar.Event{ID: eventID, UserID: userID, Description: "bar", Version: 3}, nil).
+		Once()
+
+	mux := runtime.NewServeMux(GatewayOptions()...)
+	err := event.RegisterEventServiceHandlerServer(context.Background(), mux, grpcapi.New(m, nil))
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPatch, "/events/"+eventID.String(), strings.NewReader(`{"description":"bar"}`))
+	req.Header.Set("If-Match", `"2"`)
+	req = req.WithContext(auth.WithUserID(req.Context(), userID))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, `"3"`, rec.Header().Get("ETag"))
+}
@@ -2,9 +2,12 @@ package rest
 
 import (
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/rs/zerolog/log"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/metrics"
 )
 
 func LoggingMiddleware(next http.Handler) http.Handler {
@@ -40,6 +43,27 @@ func LoggingMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(fn)
 }
 
+// MetricsMiddleware считает запросы и время их обработки по HTTP методу и коду ответа.
+func MetricsMiddleware(next http.Handler) http.Handler {
+	fn := func(w http.ResponseWriter, req *http.Request) {
+		start := time.Now()
+		wrapped := wrapResponseWriter(w)
+		next.ServeHTTP(wrapped, req)
+
+		status := wrapped.status
+		if status == 0 {
+			status = http.StatusOK
+		}
+
+		code := strconv.Itoa(status)
+
+		metrics.HTTPRequests.WithLabelValues(req.Method, code).Inc()
+		metrics.HTTPRequestDuration.WithLabelValues(req.Method, code).Observe(time.Since(start).Seconds())
+	}
+
+	return http.HandlerFunc(fn)
+}
+
 type responseWriter struct {
 	http.ResponseWriter
 	status      int
@@ -63,3 +87,10 @@ func (rw *responseWriter) WriteHeader(code int) {
 	rw.ResponseWriter.WriteHeader(code)
 	rw.wroteHeader = true
 }
+
+// Flush передает буферизованные данные клиенту, если это поддерживает исходный ResponseWriter.
+func (rw *responseWriter) Flush() {
+	if f, ok := rw.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
@@ -0,0 +1,51 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/metrics"
+)
+
+func TestMetricsMiddleware(t *testing.T) {
+	tests := []struct {
+		name    string
+		method  string
+		handler http.HandlerFunc
+		code    string
+	}{
+		{
+			name:   "implicit ok",
+			method: http.MethodGet,
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				_, _ = w.Write([]byte("ok"))
+			},
+			code: "200",
+		},
+		{
+			name:   "explicit status",
+			method: http.MethodDelete,
+			handler: func(w http.ResponseWriter, req *http.Request) {
+				w.WriteHeader(http.StatusNotFound)
+			},
+			code: "404",
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metrics.HTTPRequests.WithLabelValues(tt.method, tt.code)
+			before := testutil.ToFloat64(counter)
+
+			rec := httptest.NewRecorder()
+			MetricsMiddleware(tt.handler).ServeHTTP(rec, httptest.NewRequest(tt.method, "/events", nil))
+
+			require.Equal(t, before+1, testutil.ToFloat64(counter))
+		})
+	}
+}
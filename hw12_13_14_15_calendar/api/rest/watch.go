@@ -0,0 +1,111 @@
+package rest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	grpcapi "github.com/RomanSarvarov/otus_go_home_work/calendar/api/grpc"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+)
+
+// heartbeatInterval период отправки комментария, не дающего прокси закрыть простаивающее соединение.
+var heartbeatInterval = 15 * time.Second
+
+// WatchHandler передает изменения событий пользователя в формате Server-Sent Events.
+// Каждое изменение передается с id, равным ревизии, поэтому браузер при переподключении
+// сам передает последнюю полученную ревизию в заголовке Last-Event-ID.
+// Начальную ревизию также можно передать параметром after_revision.
+func WatchHandler(cw calendar.ChangeWatcher) http.Handler {
+	fn := func(w http.ResponseWriter, req *http.Request) {
+		if req.Method != http.MethodGet {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+
+		userID, ok := auth.UserIDFromContext(req.Context())
+		if !ok {
+			http.Error(w, auth.ErrUnauthenticated.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+			return
+		}
+
+		after, err := afterRevision(req)
+		if err != nil {
+			http.Error(w, "invalid revision", http.StatusBadRequest)
+			return
+		}
+
+		changes, err := cw.WatchChanges(req.Context(), userID, after)
+		if err != nil {
+			if errors.Is(err, calendar.ErrRevisionExpired) {
+				http.Error(w, "revision expired, reload events", http.StatusGone)
+				return
+			}
+
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(http.StatusOK)
+		flusher.Flush()
+
+		heartbeat := time.NewTicker(heartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			select {
+			case <-req.Context().Done():
+				return
+			case <-heartbeat.C:
+				if _, err := fmt.Fprint(w, ": ping\n\n"); err != nil {
+					return
+				}
+			case c, ok := <-changes:
+				// подписка закрыта из-за медленного чтения: клиент переподключится с Last-Event-ID
+				if !ok {
+					return
+				}
+
+				data, err := protojson.Marshal(grpcapi.NewEventChangeV1(c))
+				if err != nil {
+					return
+				}
+
+				if _, err := fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", c.Revision, c.Type, data); err != nil {
+					return
+				}
+			}
+
+			flusher.Flush()
+		}
+	}
+
+	return http.HandlerFunc(fn)
+}
+
+// afterRevision возвращает ревизию, после которой передаются изменения.
+func afterRevision(req *http.Request) (uint64, error) {
+	v := req.Header.Get("Last-Event-ID")
+	if v == "" {
+		v = req.URL.Query().Get("after_revision")
+	}
+
+	if v == "" {
+		return 0, nil
+	}
+
+	return strconv.ParseUint(v, 10, 64)
+}
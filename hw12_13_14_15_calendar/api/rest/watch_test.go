@@ -0,0 +1,93 @@
+package rest
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/changes"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+)
+
+func TestWatchHandler(t *testing.T) {
+	userID := uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+
+	bus := changes.New(10)
+
+	for i := 0; i < 2; i++ {
+		bus.PublishChange(calendar.NewChange(calendar.ChangeCreated, &calendar.Event{ID: uuid.New(), UserID: userID}))
+	}
+
+	srv := httptest.NewServer(LoggingMiddleware(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		WatchHandler(bus).ServeHTTP(w, req.WithContext(auth.WithUserID(req.Context(), userID)))
+	})))
+	defer srv.Close()
+
+	t.Run("resume from last event id", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+		require.NoError(t, err)
+		req.Header.Set("Last-Event-ID", "1")
+
+		res, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusOK, res.StatusCode)
+		require.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+		r := bufio.NewReader(res.Body)
+
+		readLine := func() string {
+			line, err := r.ReadString('\n')
+			require.NoError(t, err)
+
+			return strings.TrimSuffix(line, "\n")
+		}
+
+		require.Equal(t, "id: 2", readLine())
+		require.Equal(t, "event: created", readLine())
+
+		data := readLine()
+		require.True(t, strings.HasPrefix(data, "data: "))
+
+		change := new(event.EventChangeV1)
+		require.NoError(t, protojson.Unmarshal([]byte(strings.TrimPrefix(data, "data: ")), change))
+		require.Equal(t, uint64(2), change.Revision)
+		require.Equal(t, userID.String(), change.Event.UserId)
+
+		require.Equal(t, "", readLine())
+
+		bus.PublishChange(calendar.NewChange(calendar.ChangeDeleted, &calendar.Event{ID: uuid.New(), UserID: userID}))
+
+		require.Equal(t, "id: 3", readLine())
+		require.Equal(t, "event: deleted", readLine())
+	})
+
+	t.Run("revision expired", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "?after_revision=100")
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusGone, res.StatusCode)
+	})
+
+	t.Run("invalid revision", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "?after_revision=foo")
+		require.NoError(t, err)
+		defer res.Body.Close()
+
+		require.Equal(t, http.StatusBadRequest, res.StatusCode)
+	})
+}
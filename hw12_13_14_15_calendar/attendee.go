@@ -0,0 +1,84 @@
+package calendar
+
+import (
+	"github.com/google/uuid"
+)
+
+// RSVPStatus ответ участника на приглашение (PARTSTAT из RFC 5545).
+type RSVPStatus string
+
+const (
+	// RSVPNeedsAction участник еще не ответил на приглашение.
+	RSVPNeedsAction RSVPStatus = "needs-action"
+
+	// RSVPAccepted участник принял приглашение.
+	RSVPAccepted RSVPStatus = "accepted"
+
+	// RSVPDeclined участник отклонил приглашение.
+	RSVPDeclined RSVPStatus = "declined"
+
+	// RSVPTentative участник, возможно, придет.
+	RSVPTentative RSVPStatus = "tentative"
+)
+
+// ParseRSVPStatus разбирает ответ участника на приглашение.
+func ParseRSVPStatus(s string) (RSVPStatus, error) {
+	switch status := RSVPStatus(s); status {
+	case RSVPNeedsAction, RSVPAccepted, RSVPDeclined, RSVPTentative:
+		return status, nil
+	}
+
+	return "", ErrInvalidRSVPStatus
+}
+
+// Attendee участник события, приглашенный владельцем.
+type Attendee struct {
+	// EventID идентификатор события.
+	EventID uuid.UUID `db:"event_id"`
+
+	// UserID идентификатор пользователя.
+	UserID uuid.UUID `db:"user_id"`
+
+	// Status ответ на приглашение.
+	Status RSVPStatus `db:"status"`
+}
+
+// Attendee возвращает участника события или nil, если пользователь не приглашен.
+func (e *Event) Attendee(userID uuid.UUID) *Attendee {
+	for i := range e.Attendees {
+		if e.Attendees[i].UserID == userID {
+			return &e.Attendees[i]
+		}
+	}
+
+	return nil
+}
+
+// HasAccepted принял ли пользователь приглашение на событие.
+func (e *Event) HasAccepted(userID uuid.UUID) bool {
+	a := e.Attendee(userID)
+
+	return a != nil && a.Status == RSVPAccepted
+}
+
+// AcceptedAttendees возвращает идентификаторы участников, принявших приглашение.
+func (e *Event) AcceptedAttendees() []uuid.UUID {
+	res := make([]uuid.UUID, 0)
+
+	for _, a := range e.Attendees {
+		if a.Status == RSVPAccepted {
+			res = append(res, a.UserID)
+		}
+	}
+
+	return res
+}
+
+// MatchUser проверяет событие на соответствие фильтру по пользователю.
+func (filter EventFilter) MatchUser(e *Event) bool {
+	if filter.UserID == uuid.Nil || e.UserID == filter.UserID {
+		return true
+	}
+
+	return filter.Attending && e.HasAccepted(filter.UserID)
+}
@@ -0,0 +1,69 @@
+package calendar
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRSVPStatus(t *testing.T) {
+	t.Parallel()
+
+	got, err := ParseRSVPStatus("tentative")
+	require.NoError(t, err)
+	require.Equal(t, RSVPTentative, got)
+
+	_, err = ParseRSVPStatus("maybe")
+	require.ErrorIs(t, err, ErrInvalidRSVPStatus)
+}
+
+func TestEventFilter_MatchUser(t *testing.T) {
+	t.Parallel()
+
+	owner, accepted, declined := uuid.New(), uuid.New(), uuid.New()
+
+	e := &Event{
+		UserID: owner,
+		Attendees: []Attendee{
+			{UserID: accepted, Status: RSVPAccepted},
+			{UserID: declined, Status: RSVPDeclined},
+		},
+	}
+
+	require.True(t, EventFilter{}.MatchUser(e))
+	require.True(t, EventFilter{UserID: owner}.MatchUser(e))
+	require.False(t, EventFilter{UserID: accepted}.MatchUser(e))
+	require.True(t, EventFilter{UserID: accepted, Attending: true}.MatchUser(e))
+	require.False(t, EventFilter{UserID: declined, Attending: true}.MatchUser(e))
+}
+
+func TestNewNotifications(t *testing.T) {
+	t.Parallel()
+
+	owner, accepted, declined, pending := uuid.New(), uuid.New(), uuid.New(), uuid.New()
+	start := mustParseDateTime("2022-10-03 10:00:00")
+
+	e := &Event{
+		ID:      uuid.New(),
+		Title:   "foo",
+		StartAt: start,
+		UserID:  owner,
+		Attendees: []Attendee{
+			{UserID: accepted, Status: RSVPAccepted},
+			{UserID: declined, Status: RSVPDeclined},
+			{UserID: pending, Status: RSVPNeedsAction},
+		},
+	}
+
+	got := NewNotifications(e)
+
+	require.Len(t, got, 3)
+
+	for i, userID := range []uuid.UUID{owner, accepted, pending} {
+		require.NotEqual(t, uuid.Nil, got[i].ID)
+
+		want := &Notification{ID: got[i].ID, EventID: e.ID, EventTitle: "foo", EventStartAt: start, UserID: userID}
+		require.Equal(t, want, got[i])
+	}
+}
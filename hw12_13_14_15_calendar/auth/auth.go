@@ -0,0 +1,139 @@
+// Package auth проверяет подписанные токены доступа (JWT)
+// и хранит идентификатор пользователя в контексте запроса.
+//
+// Идентификатор пользователя передается в claim "sub".
+// Токен подписывается либо общим секретом (HS256, HS384, HS512),
+// либо закрытым ключом RSA (RS256, RS384, RS512) и проверяется локально.
+package auth
+
+import (
+	"context"
+	"crypto/rsa"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// ErrUnauthenticated возвращается, если токен отсутствует или недействителен.
+var ErrUnauthenticated = errors.New("unauthenticated")
+
+// bearerPrefix префикс токена в заголовке Authorization.
+const bearerPrefix = "bearer "
+
+// timeNowFunc возвращает текущее время.
+var timeNowFunc = time.Now
+
+// Config предоставляет настройки проверки токенов.
+type Config struct {
+	// HMACSecret общий секрет для токенов, подписанных HMAC.
+	HMACSecret string
+
+	// RSAPublicKeyPath путь к открытому ключу RSA в формате PEM.
+	RSAPublicKeyPath string
+}
+
+// Verifier проверяет токены доступа.
+type Verifier struct {
+	hmacKey []byte
+	rsaKey  *rsa.PublicKey
+}
+
+// NewVerifier создает Verifier. Должен быть задан хотя бы один ключ.
+func NewVerifier(cfg Config) (*Verifier, error) {
+	v := &Verifier{}
+
+	if cfg.HMACSecret != "" {
+		v.hmacKey = []byte(cfg.HMACSecret)
+	}
+
+	if cfg.RSAPublicKeyPath != "" {
+		pem, err := os.ReadFile(cfg.RSAPublicKeyPath)
+		if err != nil {
+			return nil, errors.Wrap(err, "read rsa public key")
+		}
+
+		if v.rsaKey, err = jwt.ParseRSAPublicKeyFromPEM(pem); err != nil {
+			return nil, errors.Wrap(err, "parse rsa public key")
+		}
+	}
+
+	if v.hmacKey == nil && v.rsaKey == nil {
+		return nil, errors.New("no keys for token verification")
+	}
+
+	return v, nil
+}
+
+// Verify проверяет подпись и срок действия токена
+// и возвращает идентификатор пользователя.
+func (v *Verifier) Verify(token string) (uuid.UUID, error) {
+	claims := &jwt.RegisteredClaims{}
+
+	parser := jwt.NewParser(jwt.WithoutClaimsValidation())
+
+	_, err := parser.ParseWithClaims(token, claims, v.key)
+	if err != nil {
+		return uuid.Nil, errors.Wrap(ErrUnauthenticated, err.Error())
+	}
+
+	now := timeNowFunc()
+
+	if !claims.VerifyExpiresAt(now, true) {
+		return uuid.Nil, errors.Wrap(ErrUnauthenticated, "token is expired")
+	}
+
+	if !claims.VerifyNotBefore(now, false) {
+		return uuid.Nil, errors.Wrap(ErrUnauthenticated, "token is not valid yet")
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return uuid.Nil, errors.Wrap(ErrUnauthenticated, "invalid subject")
+	}
+
+	return userID, nil
+}
+
+// key выбирает ключ проверки по алгоритму подписи токена.
+func (v *Verifier) key(t *jwt.Token) (interface{}, error) {
+	switch t.Method.(type) {
+	case *jwt.SigningMethodHMAC:
+		if v.hmacKey != nil {
+			return v.hmacKey, nil
+		}
+	case *jwt.SigningMethodRSA:
+		if v.rsaKey != nil {
+			return v.rsaKey, nil
+		}
+	}
+
+	return nil, errors.Errorf("unexpected signing method: %s", t.Header["alg"])
+}
+
+// BearerToken извлекает токен из значения заголовка Authorization.
+func BearerToken(header string) (string, error) {
+	if len(header) <= len(bearerPrefix) || !strings.EqualFold(header[:len(bearerPrefix)], bearerPrefix) {
+		return "", errors.Wrap(ErrUnauthenticated, "bearer token required")
+	}
+
+	return strings.TrimSpace(header[len(bearerPrefix):]), nil
+}
+
+// ctxKey ключ идентификатора пользователя в контексте.
+type ctxKey struct{}
+
+// WithUserID возвращает контекст с идентификатором пользователя.
+func WithUserID(ctx context.Context, userID uuid.UUID) context.Context {
+	return context.WithValue(ctx, ctxKey{}, userID)
+}
+
+// UserIDFromContext возвращает идентификатор пользователя из контекста.
+func UserIDFromContext(ctx context.Context) (uuid.UUID, bool) {
+	userID, ok := ctx.Value(ctxKey{}).(uuid.UUID)
+
+	return userID, ok
+}
@@ -0,0 +1,162 @@
+package auth
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+const userID = "123e4567-e89b-12d3-a456-426614174000"
+
+func sign(t *testing.T, method jwt.SigningMethod, key interface{}, claims jwt.Claims) string {
+	t.Helper()
+
+	token, err := jwt.NewWithClaims(method, claims).SignedString(key)
+	require.NoError(t, err)
+
+	return token
+}
+
+func claims(sub string, exp time.Duration) *jwt.RegisteredClaims {
+	return &jwt.RegisteredClaims{
+		Subject:   sub,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(exp)),
+	}
+}
+
+func TestVerifier_Verify(t *testing.T) {
+	t.Parallel()
+
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	pubDER, err := x509.MarshalPKIXPublicKey(&rsaKey.PublicKey)
+	require.NoError(t, err)
+
+	pubPath := filepath.Join(t.TempDir(), "public.pem")
+	require.NoError(t, os.WriteFile(pubPath, pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o600))
+
+	v, err := NewVerifier(Config{HMACSecret: "secret", RSAPublicKeyPath: pubPath})
+	require.NoError(t, err)
+
+	hmacOnly, err := NewVerifier(Config{HMACSecret: "secret"})
+	require.NoError(t, err)
+
+	tests := []struct {
+		name     string
+		verifier *Verifier
+		token    string
+		wantErr  bool
+	}{
+		{
+			name:     "hmac",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodHS256, []byte("secret"), claims(userID, time.Hour)),
+		},
+		{
+			name:     "rsa",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodRS256, rsaKey, claims(userID, time.Hour)),
+		},
+		{
+			name:     "rsa without rsa key",
+			verifier: hmacOnly,
+			token:    sign(t, jwt.SigningMethodRS256, rsaKey, claims(userID, time.Hour)),
+			wantErr:  true,
+		},
+		{
+			name:     "wrong secret",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodHS256, []byte("other"), claims(userID, time.Hour)),
+			wantErr:  true,
+		},
+		{
+			name:     "expired",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodHS256, []byte("secret"), claims(userID, -time.Minute)),
+			wantErr:  true,
+		},
+		{
+			name:     "without expiration",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodHS256, []byte("secret"), &jwt.RegisteredClaims{Subject: userID}),
+			wantErr:  true,
+		},
+		{
+			name:     "invalid subject",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodHS256, []byte("secret"), claims("admin", time.Hour)),
+			wantErr:  true,
+		},
+		{
+			name:     "none algorithm",
+			verifier: v,
+			token:    sign(t, jwt.SigningMethodNone, jwt.UnsafeAllowNoneSignatureType, claims(userID, time.Hour)),
+			wantErr:  true,
+		},
+		{
+			name:     "garbage",
+			verifier: v,
+			token:    "foo.bar.baz",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.verifier.Verify(tt.token)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrUnauthenticated)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, uuid.MustParse(userID), got)
+		})
+	}
+}
+
+func TestNewVerifier_WithoutKeys(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewVerifier(Config{})
+	require.Error(t, err)
+}
+
+func TestBearerToken(t *testing.T) {
+	t.Parallel()
+
+	got, err := BearerToken("Bearer abc")
+	require.NoError(t, err)
+	require.Equal(t, "abc", got)
+
+	got, err = BearerToken("bearer abc")
+	require.NoError(t, err)
+	require.Equal(t, "abc", got)
+
+	for _, header := range []string{"", "Bearer ", "Basic abc", "abc"} {
+		_, err := BearerToken(header)
+		require.ErrorIs(t, err, ErrUnauthenticated, header)
+	}
+}
+
+func TestUserIDFromContext(t *testing.T) {
+	t.Parallel()
+
+	_, ok := UserIDFromContext(context.Background())
+	require.False(t, ok)
+
+	got, ok := UserIDFromContext(WithUserID(context.Background(), uuid.MustParse(userID)))
+	require.True(t, ok)
+	require.Equal(t, uuid.MustParse(userID), got)
+}
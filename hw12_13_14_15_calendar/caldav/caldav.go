@@ -0,0 +1,753 @@
+// Package caldav реализует сервер CalDAV (RFC 4791) поверх calendar.Repository.
+//
+// Ресурсы сервера:
+//
+//	{prefix}/{user_id}/                  - принципал пользователя и коллекция его календарей;
+//	{prefix}/{user_id}/events/           - календарь пользователя;
+//	{prefix}/{user_id}/events/{uid}.ics  - событие.
+//
+// Имя ресурса события совпадает с его UID из iCalendar,
+// а для событий, созданных через API календаря, - с их идентификатором.
+//
+// Пользователь берется из контекста запроса (см. пакет auth),
+// доступ есть только к собственному календарю.
+package caldav
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha1" //nolint:gosec // используется только для ETag
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/ical"
+)
+
+const (
+	// calendarName имя календаря пользователя.
+	calendarName = "events"
+
+	// resourceExt расширение ресурсов событий.
+	resourceExt = ".ics"
+
+	// maxBodySize максимальный размер тела запроса.
+	maxBodySize = 1 << 20
+
+	// allowedMethods поддерживаемые методы.
+	allowedMethods = "OPTIONS, GET, HEAD, PUT, DELETE, PROPFIND, REPORT"
+
+	// timeRangeLayout формат времени в фильтре time-range.
+	timeRangeLayout = "20060102T150405Z"
+)
+
+// maxTime время, используемое как бесконечность в фильтре time-range.
+var maxTime = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// kind тип ресурса.
+type kind int
+
+const (
+	kindRoot kind = iota
+	kindPrincipal
+	kindCalendar
+	kindEvent
+)
+
+// target ресурс, к которому обращается запрос.
+type target struct {
+	kind   kind
+	userID uuid.UUID
+
+	// name имя ресурса события без расширения.
+	name string
+}
+
+// httpError ошибка с кодом ответа HTTP.
+type httpError struct {
+	code int
+	msg  string
+}
+
+func newHTTPError(code int, msg string) error {
+	return &httpError{code: code, msg: msg}
+}
+
+func (e *httpError) Error() string {
+	return e.msg
+}
+
+// Handler обрабатывает запросы CalDAV.
+type Handler struct {
+	r      calendar.Repository
+	prefix string
+}
+
+// New создает обработчик CalDAV, обслуживающий пути, начинающиеся с prefix.
+func New(r calendar.Repository, prefix string) *Handler {
+	return &Handler{
+		r:      r,
+		prefix: strings.TrimSuffix(prefix, "/"),
+	}
+}
+
+// ServeHTTP реализует http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	w.Header().Set("DAV", "1, 3, calendar-access")
+
+	if req.Method == http.MethodOptions {
+		w.Header().Set("Allow", allowedMethods)
+		w.WriteHeader(http.StatusOK)
+
+		return
+	}
+
+	t, err := h.parsePath(req.URL.EscapedPath())
+	if err == nil {
+		err = checkAccess(req.Context(), t)
+	}
+
+	if err == nil {
+		req.Body = http.MaxBytesReader(w, req.Body, maxBodySize)
+
+		switch req.Method {
+		case "PROPFIND":
+			err = h.propfind(w, req, t)
+		case "REPORT":
+			err = h.report(w, req, t)
+		case http.MethodGet, http.MethodHead:
+			err = h.get(w, req, t)
+		case http.MethodPut:
+			err = h.put(w, req, t)
+		case http.MethodDelete:
+			err = h.delete(w, req, t)
+		default:
+			w.Header().Set("Allow", allowedMethods)
+			err = newHTTPError(http.StatusMethodNotAllowed, "method not allowed")
+		}
+	}
+
+	if err != nil {
+		writeError(w, err)
+	}
+}
+
+// propfind обрабатывает запрос PROPFIND.
+func (h *Handler) propfind(w http.ResponseWriter, req *http.Request, t target) error {
+	body := propfindRequest{}
+
+	empty, err := decodeXML(req.Body, &body)
+	if err != nil {
+		return err
+	}
+
+	pr := propRequest{all: true}
+	if !empty {
+		pr = newPropRequest(body.AllProp != nil, body.PropName != nil, body.Prop)
+	}
+
+	depth := req.Header.Get("Depth")
+	if depth != "0" && depth != "1" && depth != "infinity" && depth != "" {
+		return newHTTPError(http.StatusBadRequest, "invalid depth")
+	}
+
+	children := depth != "0"
+	ctx := req.Context()
+
+	var responses []response
+
+	switch t.kind {
+	case kindRoot:
+		userID, _ := auth.UserIDFromContext(ctx)
+
+		responses = append(responses, newResponse(h.prefix+"/", props{
+			propResourceType:         "<D:collection/>",
+			propCurrentUserPrincipal: hrefElement(h.principalHref(userID)),
+		}, pr))
+	case kindPrincipal:
+		responses = append(responses, newResponse(h.principalHref(t.userID), h.principalProps(t.userID), pr))
+
+		if children {
+			events, err := h.userEvents(ctx, t.userID)
+			if err != nil {
+				return err
+			}
+
+			responses = append(responses, newResponse(h.calendarHref(t.userID), h.calendarProps(t.userID, events), pr))
+		}
+	case kindCalendar:
+		events, err := h.userEvents(ctx, t.userID)
+		if err != nil {
+			return err
+		}
+
+		responses = append(responses, newResponse(h.calendarHref(t.userID), h.calendarProps(t.userID, events), pr))
+
+		if children {
+			for _, e := range events {
+				responses = append(responses, newResponse(h.eventHref(e), eventProps(e), pr))
+			}
+		}
+	case kindEvent:
+		e, err := h.resolve(ctx, t)
+		if err != nil {
+			return err
+		}
+
+		responses = append(responses, newResponse(h.eventHref(e), eventProps(e), pr))
+	}
+
+	writeMultistatus(w, responses)
+
+	return nil
+}
+
+// report обрабатывает запросы REPORT calendar-query и calendar-multiget.
+func (h *Handler) report(w http.ResponseWriter, req *http.Request, t target) error {
+	if t.kind != kindCalendar {
+		return newHTTPError(http.StatusForbidden, "reports are supported only for calendar collection")
+	}
+
+	body := reportRequest{}
+
+	empty, err := decodeXML(req.Body, &body)
+	if err != nil {
+		return err
+	}
+
+	if empty {
+		return newHTTPError(http.StatusBadRequest, "report body is required")
+	}
+
+	pr := newPropRequest(body.AllProp != nil, false, body.Prop)
+	ctx := req.Context()
+
+	switch body.XMLName {
+	case reportCalendarQuery:
+		return h.calendarQuery(ctx, w, t, body, pr)
+	case reportCalendarMultiget:
+		return h.calendarMultiget(ctx, w, t, body, pr)
+	default:
+		return newHTTPError(http.StatusForbidden, "unsupported report")
+	}
+}
+
+// calendarQuery возвращает события календаря, подходящие под фильтр.
+// Поддерживается фильтр по компоненту VEVENT и его time-range.
+func (h *Handler) calendarQuery(
+	ctx context.Context,
+	w http.ResponseWriter,
+	t target,
+	body reportRequest,
+	pr propRequest,
+) error {
+	from, to := time.Time{}, maxTime
+	wantEvents := true
+
+	if body.Filter != nil {
+		f := body.Filter.CompFilter
+		if f.Name != "VCALENDAR" {
+			return newHTTPError(http.StatusBadRequest, "vcalendar comp-filter is required")
+		}
+
+		// если заданы фильтры компонентов, то события нужны, только если среди них есть VEVENT
+		wantEvents = len(f.CompFilters) == 0
+
+		for _, child := range f.CompFilters {
+			if child.Name != "VEVENT" {
+				continue
+			}
+
+			wantEvents = true
+
+			if child.TimeRange != nil {
+				var err error
+
+				if from, to, err = parseTimeRange(*child.TimeRange); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	responses := make([]response, 0)
+
+	if wantEvents {
+		events, err := h.userEvents(ctx, t.userID)
+		if err != nil {
+			return err
+		}
+
+		for _, e := range events {
+			occurrences, err := e.Occurrences(from, to)
+			if err != nil {
+				return err
+			}
+
+			if len(occurrences) > 0 {
+				responses = append(responses, newResponse(h.eventHref(e), eventProps(e), pr))
+			}
+		}
+	}
+
+	writeMultistatus(w, responses)
+
+	return nil
+}
+
+// calendarMultiget возвращает события календаря по их адресам.
+func (h *Handler) calendarMultiget(
+	ctx context.Context,
+	w http.ResponseWriter,
+	t target,
+	body reportRequest,
+	pr propRequest,
+) error {
+	responses := make([]response, 0, len(body.Hrefs))
+
+	for _, href := range body.Hrefs {
+		u, err := url.Parse(strings.TrimSpace(href))
+		if err != nil {
+			responses = append(responses, response{href: href, status: http.StatusNotFound})
+			continue
+		}
+
+		et, err := h.parsePath(u.EscapedPath())
+		if err != nil || et.kind != kindEvent || et.userID != t.userID {
+			responses = append(responses, response{href: href, status: http.StatusNotFound})
+			continue
+		}
+
+		e, err := h.resolve(ctx, et)
+		if errors.Is(err, calendar.ErrNotFound) {
+			responses = append(responses, response{href: href, status: http.StatusNotFound})
+			continue
+		}
+
+		if err != nil {
+			return err
+		}
+
+		responses = append(responses, newResponse(h.eventHref(e), eventProps(e), pr))
+	}
+
+	writeMultistatus(w, responses)
+
+	return nil
+}
+
+// get отдает событие или весь календарь в формате iCalendar.
+func (h *Handler) get(w http.ResponseWriter, req *http.Request, t target) error {
+	var (
+		events []*calendar.Event
+		tag    string
+	)
+
+	switch t.kind {
+	case kindCalendar:
+		var err error
+		if events, err = h.userEvents(req.Context(), t.userID); err != nil {
+			return err
+		}
+
+		tag = ctag(events)
+	case kindEvent:
+		e, err := h.resolve(req.Context(), t)
+		if err != nil {
+			return err
+		}
+
+		events, tag = []*calendar.Event{e}, etag(e)
+	default:
+		return newHTTPError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, events); err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", ical.ContentType)
+	w.Header().Set("ETag", tag)
+	_, _ = w.Write(buf.Bytes())
+
+	return nil
+}
+
+// put создает или обновляет событие.
+func (h *Handler) put(w http.ResponseWriter, req *http.Request, t target) error {
+	if t.kind != kindEvent {
+		return newHTTPError(http.StatusMethodNotAllowed, "method not allowed")
+	}
+
+	vevents, err := ical.Decode(req.Body)
+	if err != nil {
+		return err
+	}
+
+	if len(vevents) != 1 {
+		return newHTTPError(http.StatusForbidden, "resource must contain exactly one vevent")
+	}
+
+	if vevents[0].Err != nil {
+		return vevents[0].Err
+	}
+
+	ctx := req.Context()
+
+	existing, err := h.resolve(ctx, t)
+	if err != nil && !errors.Is(err, calendar.ErrNotFound) {
+		return err
+	}
+
+	if err := checkPreconditions(req, existing); err != nil {
+		return err
+	}
+
+	e := vevents[0].Event
+	e.UserID = t.userID
+
+	if e.UID == "" {
+		e.UID = t.name
+	}
+
+	var (
+		saved *calendar.Event
+		code  int
+	)
+
+	if existing != nil {
+		// событие не должно измениться между проверкой предусловий и сохранением
+		e.Version = existing.Version
+		saved, err = h.r.UpdateEvent(ctx, existing.ID, e)
+		code = http.StatusNoContent
+	} else {
+		if err := h.checkUIDConflict(ctx, t, e.UID); err != nil {
+			return err
+		}
+
+		saved, err = h.r.CreateEvent(ctx, e)
+		code = http.StatusCreated
+	}
+
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("ETag", etag(saved))
+	w.WriteHeader(code)
+
+	return nil
+}
+
+// delete удаляет событие.
+func (h *Handler) delete(w http.ResponseWriter, req *http.Request, t target) error {
+	if t.kind != kindEvent {
+		return newHTTPError(http.StatusForbidden, "only events can be deleted")
+	}
+
+	e, err := h.resolve(req.Context(), t)
+	if err != nil {
+		return err
+	}
+
+	if err := checkPreconditions(req, e); err != nil {
+		return err
+	}
+
+	if err := h.r.DeleteEvent(req.Context(), e.ID); err != nil {
+		return err
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+
+	return nil
+}
+
+// checkUIDConflict проверяет, что у пользователя нет другого события с таким же UID.
+func (h *Handler) checkUIDConflict(ctx context.Context, t target, uid string) error {
+	if uid == t.name {
+		return nil
+	}
+
+	events, err := h.r.FindEvents(ctx, calendar.EventFilter{UserID: t.userID, UID: uid})
+	if err != nil {
+		return err
+	}
+
+	if len(events) > 0 {
+		return newHTTPError(http.StatusForbidden, "event with the same uid already exists")
+	}
+
+	return nil
+}
+
+// resolve находит событие по имени ресурса.
+func (h *Handler) resolve(ctx context.Context, t target) (*calendar.Event, error) {
+	events, err := h.r.FindEvents(ctx, calendar.EventFilter{UserID: t.userID, UID: t.name})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(events) > 0 {
+		return events[0], nil
+	}
+
+	id, err := uuid.Parse(t.name)
+	if err != nil {
+		return nil, calendar.ErrNotFound
+	}
+
+	e, err := h.r.FindEventByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if e.UserID != t.userID || e.UID != "" {
+		return nil, calendar.ErrNotFound
+	}
+
+	return e, nil
+}
+
+// userEvents возвращает все события пользователя (повторяющиеся - целыми сериями).
+func (h *Handler) userEvents(ctx context.Context, userID uuid.UUID) ([]*calendar.Event, error) {
+	events, err := h.r.FindEvents(ctx, calendar.EventFilter{UserID: userID})
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Slice(events, func(i, j int) bool {
+		return resourceName(events[i]) < resourceName(events[j])
+	})
+
+	return events, nil
+}
+
+// checkAccess проверяет, что пользователь из контекста обращается к своему календарю.
+func checkAccess(ctx context.Context, t target) error {
+	userID, ok := auth.UserIDFromContext(ctx)
+	if !ok {
+		return newHTTPError(http.StatusUnauthorized, "unauthenticated")
+	}
+
+	if t.kind != kindRoot && t.userID != userID {
+		return newHTTPError(http.StatusForbidden, "access to calendar of another user is denied")
+	}
+
+	return nil
+}
+
+// parsePath разбирает путь запроса.
+func (h *Handler) parsePath(escapedPath string) (target, error) {
+	notFound := newHTTPError(http.StatusNotFound, "not found")
+
+	rel := strings.TrimPrefix(escapedPath, h.prefix)
+	if rel == escapedPath && h.prefix != "" {
+		return target{}, notFound
+	}
+
+	rel = strings.Trim(rel, "/")
+	if rel == "" {
+		return target{kind: kindRoot}, nil
+	}
+
+	parts := strings.Split(rel, "/")
+
+	userID, err := uuid.Parse(parts[0])
+	if err != nil {
+		return target{}, notFound
+	}
+
+	switch {
+	case len(parts) == 1:
+		return target{kind: kindPrincipal, userID: userID}, nil
+	case parts[1] != calendarName || len(parts) > 3:
+		return target{}, notFound
+	case len(parts) == 2:
+		return target{kind: kindCalendar, userID: userID}, nil
+	}
+
+	name, err := url.PathUnescape(parts[2])
+	if err != nil || !strings.HasSuffix(name, resourceExt) || name == resourceExt {
+		return target{}, notFound
+	}
+
+	return target{kind: kindEvent, userID: userID, name: strings.TrimSuffix(name, resourceExt)}, nil
+}
+
+func (h *Handler) principalHref(userID uuid.UUID) string {
+	return h.prefix + "/" + userID.String() + "/"
+}
+
+func (h *Handler) calendarHref(userID uuid.UUID) string {
+	return h.principalHref(userID) + calendarName + "/"
+}
+
+func (h *Handler) eventHref(e *calendar.Event) string {
+	return h.calendarHref(e.UserID) + url.PathEscape(resourceName(e)) + resourceExt
+}
+
+// principalProps свойства принципала.
+func (h *Handler) principalProps(userID uuid.UUID) props {
+	principal := hrefElement(h.principalHref(userID))
+
+	return props{
+		propResourceType:         "<D:collection/>" + "<D:principal/>",
+		propDisplayName:          escape(userID.String()),
+		propCurrentUserPrincipal: principal,
+		propPrincipalURL:         principal,
+		propCalendarHomeSet:      principal,
+	}
+}
+
+// calendarProps свойства календаря.
+func (h *Handler) calendarProps(userID uuid.UUID, events []*calendar.Event) props {
+	principal := hrefElement(h.principalHref(userID))
+	report := func(name string) string {
+		return "<D:supported-report><D:report><C:" + name + "/></D:report></D:supported-report>"
+	}
+
+	return props{
+		propResourceType:         "<D:collection/>" + "<C:calendar/>",
+		propDisplayName:          "Calendar",
+		propCurrentUserPrincipal: principal,
+		propOwner:                principal,
+		propSupportedComponents:  `<C:comp name="VEVENT"/>`,
+		propSupportedReportSet:   report("calendar-query") + report("calendar-multiget"),
+		propGetCTag:              escape(ctag(events)),
+	}
+}
+
+// eventProps свойства события.
+func eventProps(e *calendar.Event) props {
+	res := props{
+		propResourceType:   "",
+		propDisplayName:    escape(e.Title),
+		propGetETag:        escape(etag(e)),
+		propGetContentType: "text/calendar; charset=utf-8; component=vevent",
+	}
+
+	var buf bytes.Buffer
+	if err := ical.Encode(&buf, []*calendar.Event{e}); err == nil {
+		res[propCalendarData] = escape(buf.String())
+	}
+
+	return res
+}
+
+// resourceName возвращает имя ресурса события без расширения.
+func resourceName(e *calendar.Event) string {
+	if e.UID != "" {
+		return e.UID
+	}
+
+	return e.ID.String()
+}
+
+// etag вычисляет ETag события по его содержимому.
+func etag(e *calendar.Event) string {
+	h := sha1.New() //nolint:gosec
+
+	_, _ = fmt.Fprintf(
+		h, "%s|%s|%s|%s|%d|%d|%s|%d|%s",
+		e.ID, e.UID, e.Title, e.Description, e.StartAt.Unix(), e.EndAt.Unix(), e.TimeZone, e.NotificationDuration, e.RRule,
+	)
+
+	for _, t := range e.ExDates {
+		_, _ = fmt.Fprintf(h, "|%d", t.Unix())
+	}
+
+	return `"` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
+
+// ctag вычисляет тег календаря, меняющийся при изменении любого из его событий.
+func ctag(events []*calendar.Event) string {
+	h := sha1.New() //nolint:gosec
+
+	for _, e := range events {
+		_, _ = fmt.Fprintf(h, "%s:%s;", resourceName(e), etag(e))
+	}
+
+	return `"` + hex.EncodeToString(h.Sum(nil)) + `"`
+}
+
+// checkPreconditions проверяет заголовки If-Match и If-None-Match.
+func checkPreconditions(req *http.Request, existing *calendar.Event) error {
+	failed := newHTTPError(http.StatusPreconditionFailed, "precondition failed")
+
+	if match := req.Header.Get("If-Match"); match != "" {
+		if existing == nil || (match != "*" && !containsETag(match, etag(existing))) {
+			return failed
+		}
+	}
+
+	if noneMatch := req.Header.Get("If-None-Match"); noneMatch != "" && existing != nil {
+		if noneMatch == "*" || containsETag(noneMatch, etag(existing)) {
+			return failed
+		}
+	}
+
+	return nil
+}
+
+// containsETag проверяет, содержится ли тег в списке тегов заголовка.
+func containsETag(header, tag string) bool {
+	for _, t := range strings.Split(header, ",") {
+		if strings.TrimPrefix(strings.TrimSpace(t), "W/") == tag {
+			return true
+		}
+	}
+
+	return false
+}
+
+// parseTimeRange разбирает фильтр time-range.
+func parseTimeRange(tr timeRange) (from, to time.Time, err error) {
+	from, to = time.Time{}, maxTime
+
+	if tr.Start != "" {
+		if from, err = time.Parse(timeRangeLayout, tr.Start); err != nil {
+			return from, to, newHTTPError(http.StatusBadRequest, "invalid time-range start")
+		}
+	}
+
+	if tr.End != "" {
+		if to, err = time.Parse(timeRangeLayout, tr.End); err != nil {
+			return from, to, newHTTPError(http.StatusBadRequest, "invalid time-range end")
+		}
+	}
+
+	return from, to, nil
+}
+
+// writeError записывает ответ с ошибкой.
+func writeError(w http.ResponseWriter, err error) {
+	var httpErr *httpError
+
+	switch {
+	case errors.As(err, &httpErr):
+		http.Error(w, httpErr.msg, httpErr.code)
+	case errors.Is(err, calendar.ErrNotFound):
+		http.Error(w, "not found", http.StatusNotFound)
+	case errors.Is(err, calendar.ErrDateBusy):
+		http.Error(w, "that date is already taken by another event", http.StatusConflict)
+	case errors.Is(err, calendar.ErrVersionConflict):
+		http.Error(w, "event has been modified", http.StatusPreconditionFailed)
+	case errors.Is(err, calendar.ErrInvalidRRule), errors.Is(err, ical.ErrInvalidCalendar):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	default:
+		log.Error().Err(err).Msg("caldav")
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+	}
+}
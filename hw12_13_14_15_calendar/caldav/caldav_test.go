@@ -0,0 +1,286 @@
+package caldav
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/inmem"
+)
+
+const (
+	userID      = "123e4567-e89b-12d3-a456-426614174000"
+	otherUserID = "2f0d2079-e9a2-4810-8cae-eb6729c50580"
+)
+
+func vevent(uid, start, end, summary string) string {
+	return strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"UID:" + uid,
+		"DTSTART:" + start,
+		"DTEND:" + end,
+		"SUMMARY:" + summary,
+		"END:VEVENT",
+		"END:VCALENDAR",
+		"",
+	}, "\r\n")
+}
+
+func do(h http.Handler, method, path, body string, headers ...string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req = req.WithContext(auth.WithUserID(req.Context(), uuid.MustParse(userID)))
+
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestHandler_PutGetDelete(t *testing.T) {
+	t.Parallel()
+
+	h := New(inmem.New(), "/caldav")
+	href := "/caldav/" + userID + "/events/meeting@example.com.ics"
+
+	// создание
+	rec := do(h, http.MethodPut, href, vevent("meeting@example.com", "20221012T123000Z", "20221012T143000Z", "foo"),
+		"If-None-Match", "*")
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	tag := rec.Header().Get("ETag")
+	require.NotEmpty(t, tag)
+
+	// повторное создание запрещено
+	rec = do(h, http.MethodPut, href, vevent("meeting@example.com", "20221012T123000Z", "20221012T143000Z", "foo"),
+		"If-None-Match", "*")
+	require.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	// чтение
+	rec = do(h, http.MethodGet, href, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, tag, rec.Header().Get("ETag"))
+	require.Contains(t, rec.Body.String(), "UID:meeting@example.com\r\n")
+	require.Contains(t, rec.Body.String(), "SUMMARY:foo\r\n")
+
+	// обновление с устаревшим ETag
+	rec = do(h, http.MethodPut, href, vevent("meeting@example.com", "20221012T123000Z", "20221012T143000Z", "bar"),
+		"If-Match", `"stale"`)
+	require.Equal(t, http.StatusPreconditionFailed, rec.Code)
+
+	// обновление
+	rec = do(h, http.MethodPut, href, vevent("meeting@example.com", "20221012T123000Z", "20221012T143000Z", "bar"),
+		"If-Match", tag)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.NotEqual(t, tag, rec.Header().Get("ETag"))
+
+	tag = rec.Header().Get("ETag")
+
+	rec = do(h, http.MethodGet, href, "")
+	require.Contains(t, rec.Body.String(), "SUMMARY:bar\r\n")
+
+	// нет доступа к календарю другого пользователя
+	rec = do(h, http.MethodGet, "/caldav/"+otherUserID+"/events/meeting@example.com.ics", "")
+	require.Equal(t, http.StatusForbidden, rec.Code)
+
+	// удаление
+	rec = do(h, http.MethodDelete, href, "", "If-Match", tag)
+	require.Equal(t, http.StatusNoContent, rec.Code)
+
+	rec = do(h, http.MethodGet, href, "")
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestHandler_PutErrors(t *testing.T) {
+	t.Parallel()
+
+	h := New(inmem.New(), "/caldav")
+	base := "/caldav/" + userID + "/events/"
+
+	rec := do(h, http.MethodPut, base+"a.ics", vevent("a", "20221012T123000Z", "20221012T143000Z", "foo"))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	t.Run("date busy", func(t *testing.T) {
+		rec := do(h, http.MethodPut, base+"b.ics", vevent("b", "20221012T130000Z", "20221012T133000Z", "bar"))
+		require.Equal(t, http.StatusConflict, rec.Code)
+	})
+
+	t.Run("uid conflict", func(t *testing.T) {
+		rec := do(h, http.MethodPut, base+"c.ics", vevent("a", "20221013T130000Z", "20221013T133000Z", "bar"))
+		require.Equal(t, http.StatusForbidden, rec.Code)
+	})
+
+	t.Run("invalid calendar", func(t *testing.T) {
+		rec := do(h, http.MethodPut, base+"d.ics", "BEGIN:VEVENT")
+		require.Equal(t, http.StatusBadRequest, rec.Code)
+	})
+
+	t.Run("put to collection", func(t *testing.T) {
+		rec := do(h, http.MethodPut, base, vevent("e", "20221014T130000Z", "20221014T133000Z", "bar"))
+		require.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+	})
+
+	t.Run("unknown path", func(t *testing.T) {
+		rec := do(h, http.MethodGet, "/caldav/"+userID+"/other/", "")
+		require.Equal(t, http.StatusNotFound, rec.Code)
+	})
+}
+
+func TestHandler_Unauthenticated(t *testing.T) {
+	t.Parallel()
+
+	h := New(inmem.New(), "/caldav")
+
+	req := httptest.NewRequest("PROPFIND", "/caldav/"+userID+"/events/", nil)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestHandler_Propfind(t *testing.T) {
+	t.Parallel()
+
+	repo := inmem.New()
+	h := New(repo, "/caldav")
+
+	// событие, созданное через API, доступно по идентификатору
+	e, err := repo.CreateEvent(context.Background(), &calendar.Event{
+		Title:   "api",
+		StartAt: time.Date(2022, 10, 11, 10, 0, 0, 0, time.UTC),
+		EndAt:   time.Date(2022, 10, 11, 11, 0, 0, 0, time.UTC),
+		UserID:  uuid.MustParse(userID),
+	})
+	require.NoError(t, err)
+
+	rec := do(h, http.MethodPut, "/caldav/"+userID+"/events/a.ics",
+		vevent("a", "20221012T123000Z", "20221012T143000Z", "foo"))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	body := `<?xml version="1.0" encoding="utf-8"?>
+<D:propfind xmlns:D="DAV:" xmlns:CS="http://calendarserver.org/ns/">
+  <D:prop><D:resourcetype/><D:getetag/><CS:getctag/><D:quota-used-bytes/></D:prop>
+</D:propfind>`
+
+	t.Run("calendar depth 1", func(t *testing.T) {
+		rec := do(h, "PROPFIND", "/caldav/"+userID+"/events/", body, "Depth", "1")
+		require.Equal(t, http.StatusMultiStatus, rec.Code)
+
+		got := rec.Body.String()
+		require.Equal(t, 3, strings.Count(got, "<D:response>"))
+		require.Contains(t, got, "<D:href>/caldav/"+userID+"/events/</D:href>")
+		require.Contains(t, got, "<D:collection/><C:calendar/>")
+		require.Contains(t, got, "<CS:getctag>")
+		require.Contains(t, got, "<D:href>/caldav/"+userID+"/events/a.ics</D:href>")
+		require.Contains(t, got, "<D:href>/caldav/"+userID+"/events/"+e.ID.String()+".ics</D:href>")
+		require.Contains(t, got, "<D:quota-used-bytes/></D:prop><D:status>HTTP/1.1 404 Not Found</D:status>")
+	})
+
+	t.Run("ctag changes", func(t *testing.T) {
+		rec := do(h, "PROPFIND", "/caldav/"+userID+"/events/", body, "Depth", "0")
+		before := rec.Body.String()
+
+		rec = do(h, http.MethodPut, "/caldav/"+userID+"/events/"+e.ID.String()+".ics",
+			vevent(e.ID.String(), "20221011T100000Z", "20221011T113000Z", "api"))
+		require.Equal(t, http.StatusNoContent, rec.Code)
+
+		rec = do(h, "PROPFIND", "/caldav/"+userID+"/events/", body, "Depth", "0")
+		require.Equal(t, 1, strings.Count(rec.Body.String(), "<D:response>"))
+		require.NotEqual(t, before, rec.Body.String())
+	})
+
+	t.Run("root", func(t *testing.T) {
+		rec := do(h, "PROPFIND", "/caldav/", "", "Depth", "0")
+		require.Equal(t, http.StatusMultiStatus, rec.Code)
+		require.Contains(t, rec.Body.String(),
+			"<D:current-user-principal><D:href>/caldav/"+userID+"/</D:href></D:current-user-principal>")
+	})
+
+	t.Run("principal", func(t *testing.T) {
+		rec := do(h, "PROPFIND", "/caldav/"+userID+"/", "", "Depth", "1")
+		require.Equal(t, http.StatusMultiStatus, rec.Code)
+
+		got := rec.Body.String()
+		require.Contains(t, got, "<C:calendar-home-set><D:href>/caldav/"+userID+"/</D:href></C:calendar-home-set>")
+		require.Contains(t, got, "<D:href>/caldav/"+userID+"/events/</D:href>")
+	})
+}
+
+func TestHandler_Report(t *testing.T) {
+	t.Parallel()
+
+	h := New(inmem.New(), "/caldav")
+	base := "/caldav/" + userID + "/events/"
+
+	rec := do(h, http.MethodPut, base+"a.ics", vevent("a", "20221012T123000Z", "20221012T143000Z", "foo"))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	rec = do(h, http.MethodPut, base+"b.ics", vevent("b", "20221020T123000Z", "20221020T143000Z", "bar"))
+	require.Equal(t, http.StatusCreated, rec.Code)
+
+	t.Run("calendar-query with time-range", func(t *testing.T) {
+		body := `<?xml version="1.0" encoding="utf-8"?>
+<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <C:filter>
+    <C:comp-filter name="VCALENDAR">
+      <C:comp-filter name="VEVENT">
+        <C:time-range start="20221010T000000Z" end="20221015T000000Z"/>
+      </C:comp-filter>
+    </C:comp-filter>
+  </C:filter>
+</C:calendar-query>`
+
+		rec := do(h, "REPORT", base, body, "Depth", "1")
+		require.Equal(t, http.StatusMultiStatus, rec.Code)
+
+		got := rec.Body.String()
+		require.Equal(t, 1, strings.Count(got, "<D:response>"))
+		require.Contains(t, got, "<D:href>"+base+"a.ics</D:href>")
+		require.Contains(t, got, "SUMMARY:foo")
+	})
+
+	t.Run("calendar-query for todos", func(t *testing.T) {
+		body := `<C:calendar-query xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/></D:prop>
+  <C:filter><C:comp-filter name="VCALENDAR"><C:comp-filter name="VTODO"/></C:comp-filter></C:filter>
+</C:calendar-query>`
+
+		rec := do(h, "REPORT", base, body)
+		require.Equal(t, http.StatusMultiStatus, rec.Code)
+		require.NotContains(t, rec.Body.String(), "<D:response>")
+	})
+
+	t.Run("calendar-multiget", func(t *testing.T) {
+		body := `<C:calendar-multiget xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav">
+  <D:prop><D:getetag/><C:calendar-data/></D:prop>
+  <D:href>` + base + `b.ics</D:href>
+  <D:href>` + base + `missing.ics</D:href>
+</C:calendar-multiget>`
+
+		rec := do(h, "REPORT", base, body)
+		require.Equal(t, http.StatusMultiStatus, rec.Code)
+
+		got := rec.Body.String()
+		require.Contains(t, got, "SUMMARY:bar")
+		require.Contains(t, got, "<D:href>"+base+"missing.ics</D:href><D:status>HTTP/1.1 404 Not Found</D:status>")
+	})
+
+	t.Run("unsupported report", func(t *testing.T) {
+		rec := do(h, "REPORT", base, `<D:sync-collection xmlns:D="DAV:"/>`)
+		require.Equal(t, http.StatusForbidden, rec.Code)
+	})
+}
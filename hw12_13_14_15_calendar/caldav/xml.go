@@ -0,0 +1,263 @@
+package caldav
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	"github.com/pkg/errors"
+)
+
+// Пространства имен XML, используемые в WebDAV и CalDAV.
+const (
+	nsDAV    = "DAV:"
+	nsCalDAV = "urn:ietf:params:xml:ns:caldav"
+	nsCS     = "http://calendarserver.org/ns/"
+)
+
+// prefixes префиксы известных пространств имен в ответах.
+var prefixes = map[string]string{
+	nsDAV:    "D",
+	nsCalDAV: "C",
+	nsCS:     "CS",
+}
+
+// Свойства ресурсов.
+var (
+	propResourceType         = xml.Name{Space: nsDAV, Local: "resourcetype"}
+	propDisplayName          = xml.Name{Space: nsDAV, Local: "displayname"}
+	propGetETag              = xml.Name{Space: nsDAV, Local: "getetag"}
+	propGetContentType       = xml.Name{Space: nsDAV, Local: "getcontenttype"}
+	propCurrentUserPrincipal = xml.Name{Space: nsDAV, Local: "current-user-principal"}
+	propPrincipalURL         = xml.Name{Space: nsDAV, Local: "principal-URL"}
+	propOwner                = xml.Name{Space: nsDAV, Local: "owner"}
+	propSupportedReportSet   = xml.Name{Space: nsDAV, Local: "supported-report-set"}
+	propCalendarHomeSet      = xml.Name{Space: nsCalDAV, Local: "calendar-home-set"}
+	propCalendarData         = xml.Name{Space: nsCalDAV, Local: "calendar-data"}
+	propSupportedComponents  = xml.Name{Space: nsCalDAV, Local: "supported-calendar-component-set"}
+	propGetCTag              = xml.Name{Space: nsCS, Local: "getctag"}
+)
+
+// Отчеты (REPORT).
+var (
+	reportCalendarQuery    = xml.Name{Space: nsCalDAV, Local: "calendar-query"}
+	reportCalendarMultiget = xml.Name{Space: nsCalDAV, Local: "calendar-multiget"}
+)
+
+// propNames список запрошенных свойств.
+type propNames struct {
+	Names []struct {
+		XMLName xml.Name
+	} `xml:",any"`
+}
+
+// propfindRequest тело запроса PROPFIND.
+type propfindRequest struct {
+	XMLName  xml.Name   `xml:"DAV: propfind"`
+	AllProp  *struct{}  `xml:"DAV: allprop"`
+	PropName *struct{}  `xml:"DAV: propname"`
+	Prop     *propNames `xml:"DAV: prop"`
+}
+
+// reportRequest тело запроса REPORT (calendar-query или calendar-multiget).
+type reportRequest struct {
+	XMLName xml.Name
+	AllProp *struct{}  `xml:"DAV: allprop"`
+	Prop    *propNames `xml:"DAV: prop"`
+	Hrefs   []string   `xml:"DAV: href"`
+	Filter  *struct {
+		CompFilter compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+	} `xml:"urn:ietf:params:xml:ns:caldav filter"`
+}
+
+// compFilter фильтр по компонентам календаря.
+type compFilter struct {
+	Name        string       `xml:"name,attr"`
+	TimeRange   *timeRange   `xml:"urn:ietf:params:xml:ns:caldav time-range"`
+	CompFilters []compFilter `xml:"urn:ietf:params:xml:ns:caldav comp-filter"`
+}
+
+// timeRange фильтр по времени (значения в формате 20060102T150405Z).
+type timeRange struct {
+	Start string `xml:"start,attr"`
+	End   string `xml:"end,attr"`
+}
+
+// propRequest описывает, какие свойства запрошены.
+type propRequest struct {
+	// all запрошены все свойства (allprop или пустое тело).
+	all bool
+
+	// namesOnly запрошены только имена свойств (propname).
+	namesOnly bool
+
+	// names запрошенные свойства.
+	names []xml.Name
+}
+
+// newPropRequest формирует описание запрошенных свойств.
+func newPropRequest(allProp, propName bool, prop *propNames) propRequest {
+	if prop == nil || allProp {
+		return propRequest{all: true, namesOnly: propName}
+	}
+
+	names := make([]xml.Name, 0, len(prop.Names))
+	for _, n := range prop.Names {
+		names = append(names, n.XMLName)
+	}
+
+	return propRequest{names: names}
+}
+
+// decodeXML разбирает тело запроса. Пустое тело не является ошибкой.
+func decodeXML(r io.Reader, v interface{}) (empty bool, err error) {
+	err = xml.NewDecoder(r).Decode(v)
+	if errors.Is(err, io.EOF) {
+		return true, nil
+	}
+
+	if err != nil {
+		return false, newHTTPError(http.StatusBadRequest, "invalid xml body")
+	}
+
+	return false, nil
+}
+
+// props свойства ресурса: имя свойства - готовое XML содержимое.
+type props map[xml.Name]string
+
+// response элемент ответа multistatus.
+type response struct {
+	href    string
+	status  int
+	found   props
+	missing []xml.Name
+}
+
+// newResponse формирует ответ по ресурсу с учетом запрошенных свойств.
+func newResponse(href string, available props, req propRequest) response {
+	res := response{href: href, found: make(props)}
+
+	if req.all {
+		for name, value := range available {
+			// calendar-data не входит в allprop (RFC 4791, 9.6)
+			if name == propCalendarData {
+				continue
+			}
+
+			if req.namesOnly {
+				value = ""
+			}
+
+			res.found[name] = value
+		}
+
+		return res
+	}
+
+	for _, name := range req.names {
+		if value, ok := available[name]; ok {
+			res.found[name] = value
+		} else {
+			res.missing = append(res.missing, name)
+		}
+	}
+
+	return res
+}
+
+// writeMultistatus записывает ответ 207 Multi-Status.
+func writeMultistatus(w http.ResponseWriter, responses []response) {
+	var buf bytes.Buffer
+
+	buf.WriteString(xml.Header)
+	buf.WriteString(`<D:multistatus xmlns:D="DAV:" xmlns:C="urn:ietf:params:xml:ns:caldav" xmlns:CS="http://calendarserver.org/ns/">`) //nolint:lll
+
+	for _, r := range responses {
+		buf.WriteString("<D:response><D:href>" + escape(r.href) + "</D:href>")
+
+		if r.status != 0 {
+			buf.WriteString("<D:status>" + statusLine(r.status) + "</D:status>")
+		}
+
+		if len(r.found) > 0 {
+			names := make([]xml.Name, 0, len(r.found))
+			for name := range r.found {
+				names = append(names, name)
+			}
+
+			sortNames(names)
+
+			buf.WriteString("<D:propstat><D:prop>")
+			for _, name := range names {
+				buf.WriteString(element(name, r.found[name]))
+			}
+			buf.WriteString("</D:prop><D:status>" + statusLine(http.StatusOK) + "</D:status></D:propstat>")
+		}
+
+		if len(r.missing) > 0 {
+			buf.WriteString("<D:propstat><D:prop>")
+			for _, name := range r.missing {
+				buf.WriteString(element(name, ""))
+			}
+			buf.WriteString("</D:prop><D:status>" + statusLine(http.StatusNotFound) + "</D:status></D:propstat>")
+		}
+
+		buf.WriteString("</D:response>")
+	}
+
+	buf.WriteString("</D:multistatus>")
+
+	w.Header().Set("Content-Type", `application/xml; charset="utf-8"`)
+	w.WriteHeader(http.StatusMultiStatus)
+	_, _ = w.Write(buf.Bytes())
+}
+
+// element формирует XML элемент свойства.
+func element(name xml.Name, inner string) string {
+	tag, attrs := name.Local, ""
+
+	if prefix, ok := prefixes[name.Space]; ok {
+		tag = prefix + ":" + name.Local
+	} else if name.Space != "" {
+		attrs = ` xmlns="` + escape(name.Space) + `"`
+	}
+
+	if inner == "" {
+		return "<" + tag + attrs + "/>"
+	}
+
+	return "<" + tag + attrs + ">" + inner + "</" + tag + ">"
+}
+
+// hrefElement формирует элемент DAV:href.
+func hrefElement(href string) string {
+	return "<D:href>" + escape(href) + "</D:href>"
+}
+
+// escape экранирует текст для XML.
+func escape(s string) string {
+	var buf bytes.Buffer
+	_ = xml.EscapeText(&buf, []byte(s))
+
+	return buf.String()
+}
+
+// statusLine формирует строку статуса HTTP.
+func statusLine(code int) string {
+	return fmt.Sprintf("HTTP/1.1 %d %s", code, http.StatusText(code))
+}
+
+// sortNames сортирует имена свойств для стабильного ответа.
+func sortNames(names []xml.Name) {
+	sort.Slice(names, func(i, j int) bool {
+		if names[i].Space != names[j].Space {
+			return names[i].Space < names[j].Space
+		}
+
+		return names[i].Local < names[j].Local
+	})
+}
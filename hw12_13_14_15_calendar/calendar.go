@@ -11,15 +11,64 @@ type Repository interface {
 	// CreateEvent создать событие.
 	CreateEvent(ctx context.Context, e *Event) (*Event, error)
 
-	// UpdateEvent обновить событие.
+	// UpdateEvent обновить событие, если его версия совпадает с e.Version (иначе ErrVersionConflict).
 	UpdateEvent(ctx context.Context, id uuid.UUID, e *Event) (*Event, error)
 
-	// DeleteEvent удалить событие.
+	// PatchEvent обновить только поля fields события, если его версия совпадает с e.Version
+	// (иначе ErrVersionConflict).
+	PatchEvent(ctx context.Context, id uuid.UUID, e *Event, fields []EventField) (*Event, error)
+
+	// DeleteEvent переместить событие в корзину.
 	DeleteEvent(ctx context.Context, ids ...uuid.UUID) error
 
+	// FindTrash найти события в корзине, сначала удаленные последними.
+	FindTrash(ctx context.Context, filter TrashFilter) ([]*Event, error)
+
+	// RestoreEvent восстановить событие из корзины.
+	RestoreEvent(ctx context.Context, id uuid.UUID) (*Event, error)
+
+	// PurgeEvent окончательно удалить события из корзины.
+	PurgeEvent(ctx context.Context, ids ...uuid.UUID) error
+
 	// FindEvents найти множество событий.
 	FindEvents(ctx context.Context, filter EventFilter) ([]*Event, error)
 
 	// FindEventByID найти событие по его идентификатору.
 	FindEventByID(ctx context.Context, id uuid.UUID) (*Event, error)
+
+	// FindEventRevisions найти журнал изменений события, от старых записей к новым.
+	FindEventRevisions(ctx context.Context, eventID uuid.UUID) ([]*Revision, error)
+
+	// InviteAttendee пригласить пользователя на событие.
+	InviteAttendee(ctx context.Context, eventID, userID uuid.UUID) (*Attendee, error)
+
+	// RemoveAttendee удалить участника события.
+	RemoveAttendee(ctx context.Context, eventID, userID uuid.UUID) error
+
+	// RespondAttendee ответить на приглашение на событие.
+	RespondAttendee(ctx context.Context, eventID, userID uuid.UUID, status RSVPStatus) (*Attendee, error)
+}
+
+// Outbox декларирует контракт исходящего ящика уведомлений.
+type Outbox interface {
+	// ClaimNotifications сохранить уведомления о событиях и отметить события уведомленными.
+	ClaimNotifications(ctx context.Context, events ...*Event) error
+
+	// FindPendingNotifications найти неопубликованные уведомления.
+	FindPendingNotifications(ctx context.Context, limit int) ([]*Notification, error)
+
+	// MarkNotificationsPublished отметить уведомления опубликованными.
+	MarkNotificationsPublished(ctx context.Context, ids ...uuid.UUID) error
+
+	// IsNotificationDelivered проверить, доставлено ли уведомление.
+	IsNotificationDelivered(ctx context.Context, id uuid.UUID) (bool, error)
+
+	// AckNotification подтвердить доставку уведомления.
+	AckNotification(ctx context.Context, id uuid.UUID) error
+}
+
+// Storage декларирует контракт хранилища, реализуемый драйверами БД.
+type Storage interface {
+	Repository
+	Outbox
 }
@@ -0,0 +1,113 @@
+package calendar
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// ChangeType вид изменения события.
+type ChangeType string
+
+const (
+	// ChangeCreated событие создано.
+	ChangeCreated ChangeType = "created"
+
+	// ChangeUpdated событие изменено (в том числе состав участников или их ответы).
+	ChangeUpdated ChangeType = "updated"
+
+	// ChangeDeleted событие удалено.
+	ChangeDeleted ChangeType = "deleted"
+
+	// ChangePurged событие окончательно удалено из корзины.
+	ChangePurged ChangeType = "purged"
+)
+
+// Change изменение события.
+type Change struct {
+	// Revision порядковый номер изменения. Назначается журналом изменений хранилища,
+	// а если хранилище его не ведет - при публикации.
+	Revision uint64
+
+	// Type вид изменения.
+	Type ChangeType
+
+	// Event событие после изменения (для удаленного - перед удалением).
+	Event *Event
+
+	// UserIDs пользователи, которых касается изменение: владелец и участники события.
+	UserIDs []uuid.UUID
+}
+
+// NewChange формирует изменение события. Событие копируется, чтобы
+// последующие изменения в хранилище не затрагивали опубликованное.
+// В extra передаются пользователи, которые перестали быть участниками.
+func NewChange(t ChangeType, e *Event, extra ...uuid.UUID) Change {
+	ev := *e
+	ev.Attendees = append([]Attendee(nil), e.Attendees...)
+
+	userIDs := append([]uuid.UUID{e.UserID}, extra...)
+	for _, a := range e.Attendees {
+		userIDs = append(userIDs, a.UserID)
+	}
+
+	return Change{
+		Type:    t,
+		Event:   &ev,
+		UserIDs: userIDs,
+	}
+}
+
+// Concerns касается ли изменение пользователя.
+func (c Change) Concerns(userID uuid.UUID) bool {
+	for _, id := range c.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+
+	return false
+}
+
+// ChangePublisher декларирует контракт публикации изменений событий.
+// Публикация не должна блокировать хранилище.
+type ChangePublisher interface {
+	// PublishChange опубликовать изменение.
+	PublishChange(c Change)
+}
+
+// ChangeWatcher декларирует контракт подписки на изменения событий.
+type ChangeWatcher interface {
+	// WatchChanges подписаться на изменения событий пользователя с ревизией больше after
+	// (0 - только новые изменения). Канал закрывается при отмене контекста, а также если
+	// подписчик не успевает читать изменения - тогда следует переподключиться
+	// с последней полученной ревизией. Если изменения после after уже недоступны,
+	// то вернет ошибку ErrRevisionExpired.
+	WatchChanges(ctx context.Context, userID uuid.UUID, after uint64) (<-chan Change, error)
+}
+
+// ChangeFilter предоставляет фильтр для поиска в журнале изменений.
+type ChangeFilter struct {
+	// After искать изменения с ревизией больше After.
+	After uint64
+
+	// Until искать изменения с ревизией не больше Until (0 - без ограничения).
+	Until uint64
+
+	// UserID искать только изменения, касающиеся пользователя.
+	UserID uuid.UUID
+
+	// Limit максимальное количество изменений (0 - без ограничения).
+	Limit int
+}
+
+// ChangeLog декларирует контракт журнала изменений событий, который хранилище ведет
+// в той же транзакции, что и само изменение. Журнал общий для всех процессов,
+// работающих с хранилищем, и сохраняется между перезапусками.
+type ChangeLog interface {
+	// FindChanges найти изменения в порядке ревизий.
+	FindChanges(ctx context.Context, filter ChangeFilter) ([]Change, error)
+
+	// LastChangeRevision вернуть ревизию последнего изменения (0, если изменений нет).
+	LastChangeRevision(ctx context.Context) (uint64, error)
+}
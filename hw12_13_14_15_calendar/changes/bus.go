@@ -0,0 +1,324 @@
+// Package changes реализует шину изменений событий.
+//
+// Шина хранит ограниченную историю последних изменений, чтобы клиент после
+// переподключения мог продолжить получение с последней полученной ревизии.
+// Если хранилище ведет журнал изменений, шина получает из него изменения всех
+// процессов (см. Follow), а пропущенные изменения, которых уже нет в истории,
+// загружает из журнала. Без журнала ревизии не сохраняются между перезапусками:
+// после перезапуска клиент получит calendar.ErrRevisionExpired и должен заново загрузить события.
+package changes
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// subscriberBuffer размер буфера изменений подписчика.
+const subscriberBuffer = 64
+
+// logBacklogLimit максимальное количество пропущенных изменений, загружаемых из журнала при подписке.
+// Если пропущено больше, клиенту проще заново загрузить события.
+const logBacklogLimit = 1000
+
+// followBatch количество изменений, загружаемых из журнала за один запрос.
+const followBatch = 100
+
+// Bus шина изменений событий.
+type Bus struct {
+	mu sync.Mutex
+
+	// revision ревизия последнего опубликованного изменения.
+	revision uint64
+
+	// floor ревизия, после которой история содержит все изменения.
+	floor uint64
+
+	// history последние изменения в порядке ревизий.
+	history     []calendar.Change
+	historySize int
+
+	// log журнал изменений хранилища (nil, если хранилище его не ведет).
+	log calendar.ChangeLog
+
+	subscribers map[*subscriber]struct{}
+}
+
+// subscriber подписчик на изменения событий пользователя.
+type subscriber struct {
+	userID uuid.UUID
+	ch     chan calendar.Change
+}
+
+var (
+	_ calendar.ChangePublisher = (*Bus)(nil)
+	_ calendar.ChangeWatcher   = (*Bus)(nil)
+)
+
+// New создает шину, хранящую historySize последних изменений.
+func New(historySize int) *Bus {
+	return &Bus{
+		history:     make([]calendar.Change, 0, historySize),
+		historySize: historySize,
+		subscribers: make(map[*subscriber]struct{}),
+	}
+}
+
+// SetLog задает журнал изменений хранилища, из которого шина получает изменения (см. Follow).
+// Ревизии шины продолжают ревизии журнала. Вызывается до начала работы с шиной.
+func (b *Bus) SetLog(ctx context.Context, log calendar.ChangeLog) error {
+	revision, err := log.LastChangeRevision(ctx)
+	if err != nil {
+		return errors.Wrap(err, "set change log")
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.log = log
+	b.revision, b.floor = revision, revision
+	b.history = b.history[:0]
+
+	return nil
+}
+
+// Follow публикует новые изменения из журнала, проверяя его каждые interval,
+// пока не будет отменен контекст. Журнал задается через SetLog.
+func (b *Bus) Follow(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := b.follow(ctx); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// follow публикует изменения из журнала после последней опубликованной ревизии.
+func (b *Bus) follow(ctx context.Context) error {
+	for {
+		b.mu.Lock()
+		after := b.revision
+		b.mu.Unlock()
+
+		changes, err := b.log.FindChanges(ctx, calendar.ChangeFilter{After: after, Limit: followBatch})
+		if err != nil {
+			return errors.Wrap(err, "follow change log")
+		}
+
+		b.mu.Lock()
+
+		for _, c := range changes {
+			b.publish(c)
+		}
+
+		b.mu.Unlock()
+
+		if len(changes) < followBatch {
+			return nil
+		}
+	}
+}
+
+// PublishChange публикует изменение.
+// Изменению без ревизии назначается следующая ревизия шины. Изменение из журнала
+// публикуется с его ревизией, а уже опубликованное изменение пропускается.
+// Подписчик, буфер которого переполнен, отключается.
+func (b *Bus) PublishChange(c calendar.Change) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.publish(c)
+}
+
+// publish публикует изменение. Вызывается под блокировкой.
+func (b *Bus) publish(c calendar.Change) {
+	switch {
+	case c.Revision == 0:
+		b.revision++
+		c.Revision = b.revision
+	case c.Revision <= b.revision:
+		return
+	default:
+		b.revision = c.Revision
+	}
+
+	if b.historySize > 0 {
+		if len(b.history) == b.historySize {
+			b.floor = b.history[0].Revision
+
+			copy(b.history, b.history[1:])
+			b.history = b.history[:len(b.history)-1]
+		}
+
+		b.history = append(b.history, c)
+	} else {
+		b.floor = b.revision
+	}
+
+	for s := range b.subscribers {
+		if !c.Concerns(s.userID) {
+			continue
+		}
+
+		select {
+		case s.ch <- c:
+		default:
+			b.unsubscribe(s)
+		}
+	}
+}
+
+// WatchChanges подписывается на изменения событий пользователя с ревизией больше after.
+// Если изменений после after уже нет в истории, они загружаются из журнала.
+func (b *Bus) WatchChanges(ctx context.Context, userID uuid.UUID, after uint64) (<-chan calendar.Change, error) {
+	b.mu.Lock()
+
+	if after == 0 {
+		after = b.revision
+	}
+
+	if after >= b.floor && after <= b.revision {
+		defer b.mu.Unlock()
+
+		backlog := make([]calendar.Change, 0)
+
+		for _, c := range b.history {
+			if c.Revision > after && c.Concerns(userID) {
+				backlog = append(backlog, c)
+			}
+		}
+
+		return b.subscribe(ctx, userID, backlog).ch, nil
+	}
+
+	if b.log == nil {
+		b.mu.Unlock()
+
+		return nil, calendar.ErrRevisionExpired
+	}
+
+	// подписка оформляется до загрузки из журнала, чтобы не пропустить изменения, опубликованные во время загрузки
+	s := b.subscribe(ctx, userID, nil)
+	revision := b.revision
+
+	b.mu.Unlock()
+
+	backlog, err := b.findBacklog(ctx, userID, after, revision)
+	if err != nil {
+		b.mu.Lock()
+		b.unsubscribe(s)
+		b.mu.Unlock()
+
+		return nil, err
+	}
+
+	ch := make(chan calendar.Change)
+
+	go func() {
+		defer close(ch)
+
+		for _, c := range backlog {
+			select {
+			case ch <- c:
+			case <-ctx.Done():
+				return
+			}
+		}
+
+		for c := range s.ch {
+			if c.Revision <= after {
+				continue
+			}
+
+			select {
+			case ch <- c:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// findBacklog загружает из журнала изменения пользователя с ревизией больше after и не больше until.
+func (b *Bus) findBacklog(ctx context.Context, userID uuid.UUID, after, until uint64) ([]calendar.Change, error) {
+	// ревизия клиента может опережать шину, если он получил ее от другого процесса
+	if after > until {
+		last, err := b.log.LastChangeRevision(ctx)
+		if err != nil {
+			return nil, errors.Wrap(err, "watch changes")
+		}
+
+		if after > last {
+			return nil, calendar.ErrRevisionExpired
+		}
+
+		return nil, nil
+	}
+
+	changes, err := b.log.FindChanges(ctx, calendar.ChangeFilter{
+		After:  after,
+		Until:  until,
+		UserID: userID,
+		Limit:  logBacklogLimit + 1,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "watch changes")
+	}
+
+	if len(changes) > logBacklogLimit {
+		return nil, calendar.ErrRevisionExpired
+	}
+
+	return changes, nil
+}
+
+// subscribe добавляет подписчика, которому сначала передаются изменения backlog.
+// Подписчик отключается при отмене контекста. Вызывается под блокировкой.
+func (b *Bus) subscribe(ctx context.Context, userID uuid.UUID, backlog []calendar.Change) *subscriber {
+	s := &subscriber{
+		userID: userID,
+		ch:     make(chan calendar.Change, subscriberBuffer+len(backlog)),
+	}
+
+	for _, c := range backlog {
+		s.ch <- c
+	}
+
+	b.subscribers[s] = struct{}{}
+
+	go func() {
+		<-ctx.Done()
+
+		b.mu.Lock()
+		defer b.mu.Unlock()
+
+		b.unsubscribe(s)
+	}()
+
+	return s
+}
+
+// unsubscribe отключает подписчика. Вызывается под блокировкой.
+func (b *Bus) unsubscribe(s *subscriber) {
+	if _, ok := b.subscribers[s]; !ok {
+		return
+	}
+
+	delete(b.subscribers, s)
+	close(s.ch)
+}
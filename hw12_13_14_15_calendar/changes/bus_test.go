@@ -0,0 +1,321 @@
+package changes
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+var (
+	ownerID    = uuid.MustParse("123e4567-e89b-12d3-a456-426614174000")
+	attendeeID = uuid.MustParse("2f0d2079-e9a2-4810-8cae-eb6729c50580")
+	strangerID = uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")
+)
+
+func newChange(t calendar.ChangeType) calendar.Change {
+	return calendar.NewChange(t, &calendar.Event{
+		ID:     uuid.New(),
+		UserID: ownerID,
+		Attendees: []calendar.Attendee{
+			{UserID: attendeeID, Status: calendar.RSVPNeedsAction},
+		},
+	})
+}
+
+func receive(t *testing.T, ch <-chan calendar.Change, n int) []calendar.Change {
+	t.Helper()
+
+	res := make([]calendar.Change, 0, n)
+
+	for i := 0; i < n; i++ {
+		select {
+		case c, ok := <-ch:
+			require.True(t, ok, "channel closed")
+
+			res = append(res, c)
+		case <-time.After(time.Second):
+			require.FailNow(t, "no change received")
+		}
+	}
+
+	return res
+}
+
+// memLog журнал изменений в памяти.
+type memLog struct {
+	mu      sync.Mutex
+	changes []calendar.Change
+}
+
+// add добавляет изменение в журнал, как это делает хранилище другого процесса.
+func (l *memLog) add(c calendar.Change) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	c.Revision = uint64(len(l.changes) + 1)
+	l.changes = append(l.changes, c)
+}
+
+func (l *memLog) FindChanges(_ context.Context, filter calendar.ChangeFilter) ([]calendar.Change, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	res := make([]calendar.Change, 0)
+
+	for _, c := range l.changes {
+		if c.Revision <= filter.After || (filter.Until != 0 && c.Revision > filter.Until) {
+			continue
+		}
+
+		if filter.UserID != uuid.Nil && !c.Concerns(filter.UserID) {
+			continue
+		}
+
+		if filter.Limit > 0 && len(res) == filter.Limit {
+			break
+		}
+
+		res = append(res, c)
+	}
+
+	return res, nil
+}
+
+func (l *memLog) LastChangeRevision(context.Context) (uint64, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return uint64(len(l.changes)), nil
+}
+
+func TestBus_WatchChanges(t *testing.T) {
+	t.Run("live changes of user", func(t *testing.T) {
+		b := New(10)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		owner, err := b.WatchChanges(ctx, ownerID, 0)
+		require.NoError(t, err)
+
+		attendee, err := b.WatchChanges(ctx, attendeeID, 0)
+		require.NoError(t, err)
+
+		stranger, err := b.WatchChanges(ctx, strangerID, 0)
+		require.NoError(t, err)
+
+		b.PublishChange(newChange(calendar.ChangeCreated))
+		b.PublishChange(newChange(calendar.ChangeDeleted))
+
+		got := receive(t, owner, 2)
+		require.Equal(t, uint64(1), got[0].Revision)
+		require.Equal(t, calendar.ChangeCreated, got[0].Type)
+		require.Equal(t, uint64(2), got[1].Revision)
+		require.Equal(t, calendar.ChangeDeleted, got[1].Type)
+
+		require.Len(t, receive(t, attendee, 2), 2)
+		require.Empty(t, stranger)
+	})
+
+	t.Run("resume from revision", func(t *testing.T) {
+		b := New(10)
+
+		for i := 0; i < 3; i++ {
+			b.PublishChange(newChange(calendar.ChangeUpdated))
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch, err := b.WatchChanges(ctx, ownerID, 1)
+		require.NoError(t, err)
+
+		b.PublishChange(newChange(calendar.ChangeUpdated))
+
+		got := receive(t, ch, 3)
+		require.Equal(t, uint64(2), got[0].Revision)
+		require.Equal(t, uint64(3), got[1].Revision)
+		require.Equal(t, uint64(4), got[2].Revision)
+	})
+
+	t.Run("revision expired", func(t *testing.T) {
+		b := New(2)
+
+		for i := 0; i < 4; i++ {
+			b.PublishChange(newChange(calendar.ChangeUpdated))
+		}
+
+		_, err := b.WatchChanges(context.Background(), ownerID, 1)
+		require.ErrorIs(t, err, calendar.ErrRevisionExpired)
+
+		_, err = b.WatchChanges(context.Background(), ownerID, 5)
+		require.ErrorIs(t, err, calendar.ErrRevisionExpired)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch, err := b.WatchChanges(ctx, ownerID, 2)
+		require.NoError(t, err)
+		require.Len(t, receive(t, ch, 2), 2)
+	})
+
+	t.Run("slow subscriber is dropped", func(t *testing.T) {
+		b := New(0)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch, err := b.WatchChanges(ctx, ownerID, 0)
+		require.NoError(t, err)
+
+		for i := 0; i < subscriberBuffer+1; i++ {
+			b.PublishChange(newChange(calendar.ChangeUpdated))
+		}
+
+		require.Len(t, receive(t, ch, subscriberBuffer), subscriberBuffer)
+
+		_, ok := <-ch
+		require.False(t, ok)
+	})
+
+	t.Run("closed on cancel", func(t *testing.T) {
+		b := New(10)
+
+		ctx, cancel := context.WithCancel(context.Background())
+
+		ch, err := b.WatchChanges(ctx, ownerID, 0)
+		require.NoError(t, err)
+
+		cancel()
+
+		_, ok := <-ch
+		require.False(t, ok)
+	})
+}
+
+func TestBus_Follow(t *testing.T) {
+	t.Run("changes of other processes", func(t *testing.T) {
+		log := new(memLog)
+		log.add(newChange(calendar.ChangeCreated))
+
+		b := New(10)
+		require.NoError(t, b.SetLog(context.Background(), log))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch, err := b.WatchChanges(ctx, ownerID, 0)
+		require.NoError(t, err)
+
+		followErr := make(chan error, 1)
+
+		go func() {
+			followErr <- b.Follow(ctx, time.Millisecond)
+		}()
+
+		log.add(newChange(calendar.ChangeDeleted))
+		log.add(newChange(calendar.ChangePurged))
+
+		got := receive(t, ch, 2)
+		require.Equal(t, uint64(2), got[0].Revision)
+		require.Equal(t, calendar.ChangeDeleted, got[0].Type)
+		require.Equal(t, uint64(3), got[1].Revision)
+		require.Equal(t, calendar.ChangePurged, got[1].Type)
+
+		cancel()
+		require.ErrorIs(t, <-followErr, context.Canceled)
+	})
+
+	t.Run("more changes than batch", func(t *testing.T) {
+		log := new(memLog)
+
+		b := New(0)
+		require.NoError(t, b.SetLog(context.Background(), log))
+
+		for i := 0; i < followBatch*2+1; i++ {
+			log.add(newChange(calendar.ChangeUpdated))
+		}
+
+		require.NoError(t, b.follow(context.Background()))
+		require.Equal(t, uint64(followBatch*2+1), b.revision)
+	})
+}
+
+func TestBus_WatchChangesFromLog(t *testing.T) {
+	t.Run("resume after restart", func(t *testing.T) {
+		log := new(memLog)
+		log.add(newChange(calendar.ChangeCreated))
+		log.add(calendar.NewChange(calendar.ChangeCreated, &calendar.Event{ID: uuid.New(), UserID: strangerID}))
+		log.add(newChange(calendar.ChangeUpdated))
+
+		// шина после перезапуска не содержит изменений в истории
+		b := New(10)
+		require.NoError(t, b.SetLog(context.Background(), log))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch, err := b.WatchChanges(ctx, ownerID, 1)
+		require.NoError(t, err)
+
+		log.add(newChange(calendar.ChangeDeleted))
+		require.NoError(t, b.follow(ctx))
+
+		got := receive(t, ch, 2)
+		require.Equal(t, uint64(3), got[0].Revision)
+		require.Equal(t, uint64(4), got[1].Revision)
+		require.Equal(t, calendar.ChangeDeleted, got[1].Type)
+	})
+
+	t.Run("revision ahead of bus", func(t *testing.T) {
+		log := new(memLog)
+
+		b := New(10)
+		require.NoError(t, b.SetLog(context.Background(), log))
+
+		// клиент получил ревизию от процесса, который уже прочитал журнал
+		log.add(newChange(calendar.ChangeCreated))
+
+		_, err := b.WatchChanges(context.Background(), ownerID, 2)
+		require.ErrorIs(t, err, calendar.ErrRevisionExpired)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ch, err := b.WatchChanges(ctx, ownerID, 1)
+		require.NoError(t, err)
+
+		log.add(newChange(calendar.ChangeUpdated))
+		require.NoError(t, b.follow(ctx))
+
+		got := receive(t, ch, 1)
+		require.Equal(t, uint64(2), got[0].Revision)
+	})
+
+	t.Run("too many missed changes", func(t *testing.T) {
+		log := new(memLog)
+
+		for i := 0; i < logBacklogLimit+2; i++ {
+			log.add(newChange(calendar.ChangeUpdated))
+		}
+
+		b := New(10)
+		require.NoError(t, b.SetLog(context.Background(), log))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		_, err := b.WatchChanges(ctx, ownerID, 1)
+		require.ErrorIs(t, err, calendar.ErrRevisionExpired)
+
+		ch, err := b.WatchChanges(ctx, ownerID, 2)
+		require.NoError(t, err)
+		require.Len(t, receive(t, ch, logBacklogLimit), logBacklogLimit)
+	})
+}
@@ -8,41 +8,72 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
 	grpczerolog "github.com/philip-bui/grpc-zerolog"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/contrib/instrumentation/google.golang.org/grpc/otelgrpc"
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/rs/zerolog/log"
 	flag "github.com/spf13/pflag"
 
 	"google.golang.org/grpc"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 
 	"github.com/RomanSarvarov/otus_go_home_work/calendar"
 	grpcapi "github.com/RomanSarvarov/otus_go_home_work/calendar/api/grpc"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/api/rest"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/caldav"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/changes"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/config"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/health"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/inmem"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/metrics"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/pkg/closer"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/pkg/logging"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/postgres"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/sqlite"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/tracing"
 )
 
 // migrationsDir определяет местонахождение миграций.
 const migrationsDir = "migrations"
 
+// sqliteMigrationsDir определяет местонахождение миграций SQLite.
+const sqliteMigrationsDir = "migrations/sqlite"
+
+// caldavPrefix определяет путь, по которому доступен CalDAV.
+const caldavPrefix = "/caldav"
+
+// changeHistorySize количество последних изменений, с которых можно продолжить подписку.
+const changeHistorySize = 1000
+
+// changeFollowInterval интервал проверки журнала изменений событий.
+const changeFollowInterval = time.Second
+
+// healthCheckInterval интервал обновления статуса GRPC сервиса проверки состояния.
+const healthCheckInterval = 5 * time.Second
+
+// serviceName имя сервиса в трассах.
+const serviceName = "calendar"
+
 func main() {
 	logging.InitLogger()
 
 	log.Info().Msg("start")
 
-	cfgPath := parseFlags()
+	cfgPath, allInOne := parseFlags()
 
 	log.
 		Debug().
 		Str("cfg path", cfgPath).
+		Bool("all in one", allInOne).
 		Msg("flags parsed")
 
 	cfg, err := config.Load(cfgPath)
@@ -60,28 +91,53 @@ func main() {
 		log.Fatal().Err(err).Send()
 	}
 
-	if err := run(cfg); err != nil {
+	if err := run(cfg, allInOne); err != nil {
 		log.Fatal().Err(err).Send()
 	}
 }
 
 // run запускает приложение.
-func run(cfg *config.Config) error {
+// В режиме allInOne вместе с API в том же процессе запускаются планировщик и отправитель уведомлений.
+func run(cfg *config.Config, allInOne bool) error {
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
 	defer stop()
 	defer closer.CloseAll()
 
 	errgrp, ctx := errgroup.WithContext(ctx)
 
+	shutdownTracing, err := tracing.Init(ctx, tracing.Config{
+		ServiceName:  serviceName,
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return err
+	}
+
+	// выполняется после остановки серверов, чтобы выгрузить спаны последних запросов
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Err(err).Msg("shutdown tracing")
+		}
+	}()
+
 	log.
 		Debug().
 		Msg("start application")
 
-	var repo calendar.Repository
+	var store calendar.Storage
+
+	checker := health.New()
+
+	bus := changes.New(changeHistorySize)
 
 	switch cfg.DBDriver {
 	case inmem.Key:
-		repo = inmem.New()
+		r := inmem.New()
+		r.SetChangePublisher(bus)
+
+		store = r
 	case postgres.Key:
 		log.
 			Debug().
@@ -116,16 +172,101 @@ func run(cfg *config.Config) error {
 			return err
 		}
 
-		repo = r
+		// изменения всех процессов, включая планировщик, читаются из журнала изменений
+		if err := bus.SetLog(ctx, r); err != nil {
+			return err
+		}
+
+		errgrp.Go(func() error {
+			return bus.Follow(ctx, changeFollowInterval)
+		})
+
+		checker.Add("postgres", r.Ping)
+		checker.Add("migrations", func(ctx context.Context) error {
+			return r.CheckMigrations(ctx, migrationsDir)
+		})
+
+		store = r
+	case sqlite.Key:
+		log.
+			Debug().
+			Msg("opening sqlite")
+
+		r, err := sqlite.Open(sqlite.Config{Path: cfg.SQLite.Path})
+		if err != nil {
+			return err
+		}
+
+		closer.Add(func() error {
+			log.
+				Debug().
+				Msgf("terminating sqlite connection")
+
+			return r.Close()
+		})
+
+		log.
+			Debug().
+			Msgf("run sqlite migrations")
+
+		if err := r.Up(sqliteMigrationsDir); err != nil {
+			return err
+		}
+
+		r.SetChangePublisher(bus)
+
+		checker.Add("sqlite", r.Ping)
+		checker.Add("migrations", func(ctx context.Context) error {
+			return r.CheckMigrations(ctx, sqliteMigrationsDir)
+		})
+
+		store = r
 	default:
 		return fmt.Errorf("database driver `%s` not found", cfg.DBDriver)
 	}
 
+	repo := tracing.InstrumentRepository(cfg.DBDriver, metrics.InstrumentRepository(cfg.DBDriver, store))
+
+	verifier, err := auth.NewVerifier(auth.Config{
+		HMACSecret:       cfg.Auth.HMACSecret,
+		RSAPublicKeyPath: cfg.Auth.RSAPublicKeyPath,
+	})
+	if err != nil {
+		return errors.Wrap(err, "create token verifier")
+	}
+
 	// Start REST.
-	mux := runtime.NewServeMux()
+	mux := runtime.NewServeMux(rest.GatewayOptions()...)
+
+	httpMux := http.NewServeMux()
+	httpMux.Handle(caldavPrefix+"/", rest.AuthMiddleware(verifier, caldav.New(repo, caldavPrefix)))
+	httpMux.Handle("/events/watch", rest.AuthMiddleware(verifier, rest.WatchHandler(bus)))
+	httpMux.Handle(metrics.Path, metrics.Handler())
+	httpMux.Handle(health.LivenessPath, health.LivenessHandler())
+	httpMux.Handle(health.ReadinessPath, health.ReadinessHandler(checker))
+	httpMux.Handle("/.well-known/caldav", http.RedirectHandler(caldavPrefix+"/", http.StatusMovedPermanently))
+	httpMux.Handle("/", rest.AuthMiddleware(verifier, mux))
+
+	// спаны REST запросов, в том числе обработанных grpc-gateway, становятся родительскими для спанов хранилища
+	handler := otelhttp.NewHandler(
+		rest.MetricsMiddleware(rest.LoggingMiddleware(httpMux)),
+		"rest",
+		otelhttp.WithSpanNameFormatter(func(_ string, req *http.Request) string {
+			return "HTTP " + req.Method
+		}),
+		otelhttp.WithFilter(func(req *http.Request) bool {
+			switch req.URL.Path {
+			case metrics.Path, health.LivenessPath, health.ReadinessPath:
+				return false
+			default:
+				return true
+			}
+		}),
+	)
+
 	restSrv := &http.Server{
 		Addr:    cfg.REST.Address,
-		Handler: rest.LoggingMiddleware(mux),
+		Handler: handler,
 	}
 
 	closer.Add(func() error {
@@ -145,7 +286,7 @@ func run(cfg *config.Config) error {
 			Debug().
 			Msgf("starting REST server on: `%s`", cfg.REST.Address)
 
-		err := event.RegisterEventServiceHandlerServer(context.Background(), mux, grpcapi.New(repo))
+		err := event.RegisterEventServiceHandlerServer(context.Background(), mux, grpcapi.New(repo, bus))
 		if err != nil {
 			return errors.Wrap(err, "register event service handler server")
 		}
@@ -164,10 +305,29 @@ func run(cfg *config.Config) error {
 	}
 
 	grpcSrv := grpc.NewServer(
-		grpczerolog.UnaryInterceptor(),
+		grpc.ChainUnaryInterceptor(
+			otelgrpc.UnaryServerInterceptor(),
+			grpcapi.MetricsUnaryInterceptor(),
+			grpcapi.AuthUnaryInterceptor(verifier),
+			grpczerolog.NewUnaryServerInterceptor(),
+		),
+		grpc.ChainStreamInterceptor(
+			otelgrpc.StreamServerInterceptor(),
+			grpcapi.MetricsStreamInterceptor(),
+			grpcapi.AuthStreamInterceptor(verifier),
+		),
 	)
 
-	event.RegisterEventServiceServer(grpcSrv, grpcapi.New(repo))
+	event.RegisterEventServiceServer(grpcSrv, grpcapi.New(repo, bus))
+
+	healthSrv := grpchealth.NewServer()
+	healthpb.RegisterHealthServer(grpcSrv, healthSrv)
+
+	errgrp.Go(func() error {
+		health.WatchGRPC(ctx, checker, healthSrv, healthCheckInterval, event.EventService_ServiceDesc.ServiceName)
+
+		return nil
+	})
 
 	closer.Add(func() error {
 		log.
@@ -191,6 +351,12 @@ func run(cfg *config.Config) error {
 		return nil
 	})
 
+	if allInOne {
+		if err := startNotifications(ctx, errgrp, cfg, repo); err != nil {
+			return err
+		}
+	}
+
 	<-ctx.Done()
 
 	log.
@@ -211,10 +377,11 @@ func run(cfg *config.Config) error {
 }
 
 // parseFlags возвращает флаги запуска.
-func parseFlags() string {
+func parseFlags() (string, bool) {
 	configPath := flag.StringP("config", "C", "", "Path to configuration file")
+	allInOne := flag.Bool("all-in-one", false, "Run scheduler and sender in the same process using in-memory queue")
 
 	flag.Parse()
 
-	return *configPath
+	return *configPath, *allInOne
 }
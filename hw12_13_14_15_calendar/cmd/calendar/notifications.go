@@ -0,0 +1,90 @@
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"golang.org/x/sync/errgroup"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/config"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/memqueue"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/pkg/closer"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/scheduler"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/sender"
+)
+
+// notificationQueueSize количество уведомлений, которое очередь вмещает без блокировки планировщика.
+const notificationQueueSize = 1000
+
+// notificationRepository хранилище, общее для планировщика и отправителя.
+type notificationRepository interface {
+	scheduler.Repository
+	sender.Repository
+}
+
+// startNotifications запускает планировщик и отправитель в текущем процессе.
+// Уведомления передаются через очередь в памяти вместо Kafka.
+func startNotifications(
+	ctx context.Context,
+	errgrp *errgroup.Group,
+	cfg *config.Config,
+	repo notificationRepository,
+) error {
+	q := memqueue.New(memqueue.Config{
+		Size:        notificationQueueSize,
+		MaxAttempts: cfg.Sender.MaxAttempts,
+		RetryDelay:  cfg.Sender.RetryDelay,
+	})
+	closer.Add(q.Close)
+
+	notifier, err := sender.NewNotifier(sender.NotifierConfig{
+		Notifiers:          cfg.Sender.Notifiers,
+		FilePath:           cfg.Sender.FilePath,
+		FileMaxSize:        cfg.Sender.FileMaxSize,
+		FileMaxBackups:     cfg.Sender.FileMaxBackups,
+		WebhookURL:         cfg.Sender.WebhookURL,
+		WebhookSecret:      cfg.Sender.WebhookSecret,
+		WebhookTimeout:     cfg.Sender.WebhookTimeout,
+		WebhookMaxAttempts: cfg.Sender.WebhookMaxAttempts,
+		WebhookBaseDelay:   cfg.Sender.WebhookBaseDelay,
+		WebhookMaxDelay:    cfg.Sender.WebhookMaxDelay,
+	})
+	if err != nil {
+		return err
+	}
+	closer.Add(notifier.Close)
+
+	sch := scheduler.New(repo, q, scheduler.Config{
+		Interval:        cfg.Scheduler.Interval,
+		EventLifeInDays: cfg.Scheduler.EventLifeInDays,
+		TrashRetention:  cfg.Scheduler.TrashRetention,
+	})
+
+	s := sender.New(repo, q, q, notifier, sender.Config{
+		Threads: cfg.Sender.Threads,
+	})
+
+	errgrp.Go(func() error {
+		log.
+			Debug().
+			Msgf("start scheduler")
+
+		return sch.Start(ctx)
+	})
+
+	errgrp.Go(func() error {
+		log.
+			Debug().
+			Msgf("start sender")
+
+		// очередь закрывается при остановке приложения
+		if err := s.Start(ctx); err != nil && !errors.Is(err, memqueue.ErrClosed) {
+			return err
+		}
+
+		return nil
+	})
+
+	return nil
+}
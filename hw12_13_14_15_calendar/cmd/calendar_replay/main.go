@@ -0,0 +1,77 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	flag "github.com/spf13/pflag"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/config"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/kafka"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/pkg/logging"
+)
+
+// replayGroupSuffix суффикс группы читателя топика недоставленных уведомлений.
+const replayGroupSuffix = "-dead-letter-replay"
+
+func main() {
+	logging.InitLogger()
+
+	log.Info().Msg("start")
+
+	cfgPath, idleTimeout := parseFlags()
+
+	log.
+		Debug().
+		Str("cfg path", cfgPath).
+		Msg("flags parsed")
+
+	cfg, err := config.Load(cfgPath)
+	if err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	logConfig := logging.Config{Level: cfg.Log.Level}
+	if err := logging.Configure(logConfig); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+
+	if err := run(cfg, idleTimeout); err != nil {
+		log.Fatal().Err(err).Send()
+	}
+}
+
+// run переносит недоставленные уведомления обратно в топик рассыльщика.
+func run(cfg *config.Config, idleTimeout time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	replayed, err := kafka.ReplayDeadLetters(ctx, kafka.ReplayConfig{
+		Brokers:         cfg.Kafka.Brokers,
+		GroupID:         cfg.Kafka.GroupID + replayGroupSuffix,
+		DeadLetterTopic: cfg.Kafka.DeadLetterTopic,
+		Topic:           cfg.Kafka.SenderTopic,
+		IdleTimeout:     idleTimeout,
+	})
+
+	log.
+		Info().
+		Int("replayed", replayed).
+		Msg("dead letters replayed")
+
+	return err
+}
+
+// parseFlags возвращает флаги запуска.
+func parseFlags() (string, time.Duration) {
+	configPath := flag.StringP("config", "C", "", "Path to configuration file")
+	idleTimeout := flag.Duration("idle-timeout", 10*time.Second, "Stop after waiting this long for a new dead letter")
+
+	flag.Parse()
+
+	return *configPath, *idleTimeout
+}
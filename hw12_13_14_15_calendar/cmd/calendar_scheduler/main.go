@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -13,15 +14,29 @@ import (
 	"github.com/rs/zerolog/log"
 	flag "github.com/spf13/pflag"
 
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/config"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/health"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/inmem"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/kafka"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/metrics"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/pkg/closer"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/pkg/logging"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/postgres"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/scheduler"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/sqlite"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/tracing"
 )
 
+// migrationsDir определяет местонахождение миграций.
+const migrationsDir = "migrations"
+
+// sqliteMigrationsDir определяет местонахождение миграций SQLite.
+const sqliteMigrationsDir = "migrations/sqlite"
+
+// serviceName имя сервиса в трассах.
+const serviceName = "calendar_scheduler"
+
 func main() {
 	logging.InitLogger()
 
@@ -62,15 +77,34 @@ func run(cfg *config.Config) error {
 
 	errgrp, ctx := errgroup.WithContext(ctx)
 
+	shutdownTracing, err := tracing.Init(ctx, tracing.Config{
+		ServiceName:  serviceName,
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return err
+	}
+
+	// выполняется после остановки серверов, чтобы выгрузить спаны последних запросов
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Err(err).Msg("shutdown tracing")
+		}
+	}()
+
 	log.
 		Debug().
 		Msg("start application")
 
-	var repo scheduler.Repository
+	var store calendar.Storage
+
+	checker := health.New()
 
 	switch cfg.DBDriver {
 	case inmem.Key:
-		repo = inmem.New()
+		store = inmem.New()
 	case postgres.Key:
 		log.
 			Debug().
@@ -97,11 +131,77 @@ func run(cfg *config.Config) error {
 			return r.Close()
 		})
 
-		repo = r
+		checker.Add("postgres", r.Ping)
+		checker.Add("migrations", func(ctx context.Context) error {
+			return r.CheckMigrations(ctx, migrationsDir)
+		})
+
+		store = r
+	case sqlite.Key:
+		log.
+			Debug().
+			Msg("opening sqlite")
+
+		r, err := sqlite.Open(sqlite.Config{Path: cfg.SQLite.Path})
+		if err != nil {
+			return err
+		}
+
+		closer.Add(func() error {
+			log.
+				Debug().
+				Msgf("terminating sqlite connection")
+
+			return r.Close()
+		})
+
+		checker.Add("sqlite", r.Ping)
+		checker.Add("migrations", func(ctx context.Context) error {
+			return r.CheckMigrations(ctx, sqliteMigrationsDir)
+		})
+
+		store = r
 	default:
 		return fmt.Errorf("database driver `%s` not found", cfg.DBDriver)
 	}
 
+	repo := tracing.InstrumentRepository(cfg.DBDriver, metrics.InstrumentRepository(cfg.DBDriver, store))
+
+	checker.Add("kafka", func(ctx context.Context) error {
+		return kafka.CheckBrokers(ctx, cfg.Kafka.Brokers)
+	})
+
+	// служебный сервер отдает метрики и состояние сервиса
+	opsMux := http.NewServeMux()
+	opsMux.Handle(metrics.Path, metrics.Handler())
+	opsMux.Handle(health.LivenessPath, health.LivenessHandler())
+	opsMux.Handle(health.ReadinessPath, health.ReadinessHandler(checker))
+
+	opsSrv := &http.Server{
+		Addr:    cfg.Metrics.Address,
+		Handler: opsMux,
+	}
+
+	closer.Add(func() error {
+		log.
+			Debug().
+			Msgf("terminating metrics and health server")
+
+		return opsSrv.Close()
+	})
+
+	errgrp.Go(func() error {
+		log.
+			Debug().
+			Msgf("starting metrics and health server on: `%s`", cfg.Metrics.Address)
+
+		err := opsSrv.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+
 	w := kafka.NewWriter(&kafka.WriterConfig{
 		Brokers: cfg.Kafka.Brokers,
 		Topic:   cfg.Kafka.SenderTopic,
@@ -113,6 +213,7 @@ func run(cfg *config.Config) error {
 	sch := scheduler.New(repo, w, scheduler.Config{
 		Interval:        cfg.Scheduler.Interval,
 		EventLifeInDays: cfg.Scheduler.EventLifeInDays,
+		TrashRetention:  cfg.Scheduler.TrashRetention,
 	})
 
 	errgrp.Go(func() error {
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
@@ -13,15 +14,29 @@ import (
 	"github.com/rs/zerolog/log"
 	flag "github.com/spf13/pflag"
 
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/config"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/health"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/inmem"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/kafka"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/metrics"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/pkg/closer"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/pkg/logging"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/postgres"
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/sender"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/sqlite"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/tracing"
 )
 
+// migrationsDir определяет местонахождение миграций.
+const migrationsDir = "migrations"
+
+// sqliteMigrationsDir определяет местонахождение миграций SQLite.
+const sqliteMigrationsDir = "migrations/sqlite"
+
+// serviceName имя сервиса в трассах.
+const serviceName = "calendar_sender"
+
 func main() {
 	logging.InitLogger()
 
@@ -62,15 +77,34 @@ func run(cfg *config.Config) error {
 
 	errgrp, ctx := errgroup.WithContext(ctx)
 
+	shutdownTracing, err := tracing.Init(ctx, tracing.Config{
+		ServiceName:  serviceName,
+		Exporter:     cfg.Tracing.Exporter,
+		OTLPEndpoint: cfg.Tracing.OTLPEndpoint,
+		SampleRatio:  cfg.Tracing.SampleRatio,
+	})
+	if err != nil {
+		return err
+	}
+
+	// выполняется после остановки серверов, чтобы выгрузить спаны последних запросов
+	defer func() {
+		if err := shutdownTracing(context.Background()); err != nil {
+			log.Err(err).Msg("shutdown tracing")
+		}
+	}()
+
 	log.
 		Debug().
 		Msg("start application")
 
-	var repo sender.Repository
+	var store calendar.Storage
+
+	checker := health.New()
 
 	switch cfg.DBDriver {
 	case inmem.Key:
-		repo = inmem.New()
+		store = inmem.New()
 	case postgres.Key:
 		log.
 			Debug().
@@ -97,31 +131,134 @@ func run(cfg *config.Config) error {
 			return r.Close()
 		})
 
-		repo = r
+		checker.Add("postgres", r.Ping)
+		checker.Add("migrations", func(ctx context.Context) error {
+			return r.CheckMigrations(ctx, migrationsDir)
+		})
+
+		store = r
+	case sqlite.Key:
+		log.
+			Debug().
+			Msg("opening sqlite")
+
+		r, err := sqlite.Open(sqlite.Config{Path: cfg.SQLite.Path})
+		if err != nil {
+			return err
+		}
+
+		closer.Add(func() error {
+			log.
+				Debug().
+				Msgf("terminating sqlite connection")
+
+			return r.Close()
+		})
+
+		checker.Add("sqlite", r.Ping)
+		checker.Add("migrations", func(ctx context.Context) error {
+			return r.CheckMigrations(ctx, sqliteMigrationsDir)
+		})
+
+		store = r
 	default:
 		return fmt.Errorf("database driver `%s` not found", cfg.DBDriver)
 	}
 
-	r := kafka.NewReader(kafka.ReaderConfig{
-		Brokers: cfg.Kafka.Brokers,
-		GroupID: cfg.Kafka.GroupID,
-		Topic:   cfg.Kafka.SenderTopic,
+	repo := tracing.InstrumentRepository(cfg.DBDriver, metrics.InstrumentRepository(cfg.DBDriver, store))
+
+	checker.Add("kafka", func(ctx context.Context) error {
+		return kafka.CheckBrokers(ctx, cfg.Kafka.Brokers)
 	})
+
+	// служебный сервер отдает метрики и состояние сервиса
+	opsMux := http.NewServeMux()
+	opsMux.Handle(metrics.Path, metrics.Handler())
+	opsMux.Handle(health.LivenessPath, health.LivenessHandler())
+	opsMux.Handle(health.ReadinessPath, health.ReadinessHandler(checker))
+
+	opsSrv := &http.Server{
+		Addr:    cfg.Metrics.Address,
+		Handler: opsMux,
+	}
+
 	closer.Add(func() error {
-		return r.Close()
-	})
+		log.
+			Debug().
+			Msgf("terminating metrics and health server")
 
-	s := sender.New(repo, r, sender.Config{
-		Threads: cfg.Sender.Threads,
+		return opsSrv.Close()
 	})
 
 	errgrp.Go(func() error {
 		log.
 			Debug().
-			Msgf("start sender")
+			Msgf("starting metrics and health server on: `%s`", cfg.Metrics.Address)
+
+		err := opsSrv.ListenAndServe()
+		if err != nil && !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	})
+
+	rt := kafka.NewRetrier(kafka.RetrierConfig{
+		Brokers:         cfg.Kafka.Brokers,
+		RetryTopic:      cfg.Kafka.RetryTopic,
+		DeadLetterTopic: cfg.Kafka.DeadLetterTopic,
+		MaxAttempts:     cfg.Sender.MaxAttempts,
+		Delay:           cfg.Sender.RetryDelay,
+	})
+	closer.Add(func() error {
+		return rt.Close()
+	})
 
-		return s.Start(ctx)
+	notifier, err := sender.NewNotifier(sender.NotifierConfig{
+		Notifiers:          cfg.Sender.Notifiers,
+		FilePath:           cfg.Sender.FilePath,
+		FileMaxSize:        cfg.Sender.FileMaxSize,
+		FileMaxBackups:     cfg.Sender.FileMaxBackups,
+		WebhookURL:         cfg.Sender.WebhookURL,
+		WebhookSecret:      cfg.Sender.WebhookSecret,
+		WebhookTimeout:     cfg.Sender.WebhookTimeout,
+		WebhookMaxAttempts: cfg.Sender.WebhookMaxAttempts,
+		WebhookBaseDelay:   cfg.Sender.WebhookBaseDelay,
+		WebhookMaxDelay:    cfg.Sender.WebhookMaxDelay,
 	})
+	if err != nil {
+		return err
+	}
+	closer.Add(notifier.Close)
+
+	// уведомления читаются из основного топика и из топика отложенных
+	for _, topic := range []string{cfg.Kafka.SenderTopic, cfg.Kafka.RetryTopic} {
+		r := kafka.NewReader(kafka.ReaderConfig{
+			Brokers: cfg.Kafka.Brokers,
+			GroupID: cfg.Kafka.GroupID,
+			Topic:   topic,
+		}).WithDeadLetter(rt)
+		closer.Add(func() error {
+			return r.Close()
+		})
+
+		if err := metrics.RegisterConsumerLag(topic, r.Lag); err != nil {
+			return err
+		}
+
+		s := sender.New(repo, r, rt, notifier, sender.Config{
+			Threads: cfg.Sender.Threads,
+		})
+
+		topic := topic
+
+		errgrp.Go(func() error {
+			log.
+				Debug().
+				Msgf("start sender for topic `%s`", topic)
+
+			return s.Start(ctx)
+		})
+	}
 
 	<-ctx.Done()
 
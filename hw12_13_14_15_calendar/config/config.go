@@ -22,9 +22,18 @@ type Config struct {
 	// GRPC параметры для GRPC сервера.
 	GRPC GRPCConfig
 
+	// Metrics параметры сервера метрик.
+	Metrics MetricsConfig
+
+	// Tracing параметры трассировки.
+	Tracing TracingConfig
+
 	// PostgreSQL параметры для подключения к PostgreSQL.
 	PostgreSQL PostgreSQLConfig
 
+	// SQLite параметры для подключения к SQLite.
+	SQLite SQLiteConfig
+
 	// Kafka настройки работы с Kafka.
 	Kafka KafkaConfig
 
@@ -34,6 +43,9 @@ type Config struct {
 	// Sender настройки отправителя.
 	Sender SenderConfig
 
+	// Auth настройки проверки токенов доступа.
+	Auth AuthConfig
+
 	// DBDriver декларирует драйвер базы данных.
 	DBDriver string `env:"DB_DRIVER,required"`
 }
@@ -56,6 +68,25 @@ type GRPCConfig struct {
 	Address string `env:"GRPC_ADDRESS" envDefault:":8081"`
 }
 
+// MetricsConfig предоставляет настройки служебного сервера планировщика и отправителя,
+// который отдает метрики и состояние сервиса. API отдает их на адресе REST сервера.
+type MetricsConfig struct {
+	// Address адрес служебного сервера.
+	Address string `env:"METRICS_ADDRESS" envDefault:":9090"`
+}
+
+// TracingConfig предоставляет настройки трассировки.
+type TracingConfig struct {
+	// Exporter способ экспорта спанов: none, stdout, otlp.
+	Exporter string `env:"TRACING_EXPORTER" envDefault:"none"`
+
+	// OTLPEndpoint адрес OTLP коллектора (GRPC).
+	OTLPEndpoint string `env:"TRACING_OTLP_ENDPOINT" envDefault:"localhost:4317"`
+
+	// SampleRatio доля трассируемых запросов от 0 до 1.
+	SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+}
+
 // PostgreSQLConfig предоставляет настройки подключения к PostgreSQL.
 type PostgreSQLConfig struct {
 	// Host адрес БД.
@@ -74,6 +105,12 @@ type PostgreSQLConfig struct {
 	Database string `env:"POSTGRES_DB"`
 }
 
+// SQLiteConfig предоставляет настройки подключения к SQLite.
+type SQLiteConfig struct {
+	// Path путь к файлу БД.
+	Path string `env:"SQLITE_PATH" envDefault:"calendar.db"`
+}
+
 // KafkaConfig предоставляет настройки работы с Kafka.
 type KafkaConfig struct {
 	// GroupID адреса брокеров.
@@ -84,6 +121,12 @@ type KafkaConfig struct {
 
 	// SenderTopic название топика для планировщика.
 	SenderTopic string `env:"KAFKA_SENDER_TOPIC" envDefault:"calendar-sender-topic"`
+
+	// RetryTopic название топика отложенных уведомлений.
+	RetryTopic string `env:"KAFKA_RETRY_TOPIC" envDefault:"calendar-sender-retry-topic"`
+
+	// DeadLetterTopic название топика недоставленных уведомлений.
+	DeadLetterTopic string `env:"KAFKA_DEAD_LETTER_TOPIC" envDefault:"calendar-sender-dead-letter-topic"`
 }
 
 // SchedulerConfig предоставляет настройки планировщика.
@@ -93,12 +136,60 @@ type SchedulerConfig struct {
 
 	// EventLifeInDays количество дней, после истечения которых удалять событие.
 	EventLifeInDays uint `env:"SCHEDULER_EVENT_LIFE_IN_DAYS" envDefault:"365"`
+
+	// TrashRetention срок хранения событий в корзине, после которого они удаляются окончательно.
+	TrashRetention time.Duration `env:"SCHEDULER_TRASH_RETENTION" envDefault:"720h"`
 }
 
 // SenderConfig предоставляет настройки отправителя.
 type SenderConfig struct {
 	// Threads количество потоков (консьюмеров).
 	Threads int `env:"SENDER_THREADS" envDefault:"3"`
+
+	// MaxAttempts количество попыток доставки, после которого уведомление считается недоставленным.
+	MaxAttempts int `env:"SENDER_MAX_ATTEMPTS" envDefault:"5"`
+
+	// RetryDelay задержка перед первой повторной попыткой, каждая следующая вдвое больше.
+	RetryDelay time.Duration `env:"SENDER_RETRY_DELAY" envDefault:"1m"`
+
+	// Notifiers каналы доставки уведомлений: stdout, file, webhook.
+	Notifiers []string `env:"SENDER_NOTIFIERS" envDefault:"stdout" envSeparator:","`
+
+	// FilePath путь к файлу уведомлений (канал file).
+	FilePath string `env:"SENDER_FILE_PATH" envDefault:"notifications.log"`
+
+	// FileMaxSize размер файла уведомлений в байтах, после которого выполняется ротация.
+	FileMaxSize int64 `env:"SENDER_FILE_MAX_SIZE" envDefault:"10485760"`
+
+	// FileMaxBackups количество хранимых копий файла уведомлений.
+	FileMaxBackups int `env:"SENDER_FILE_MAX_BACKUPS" envDefault:"5"`
+
+	// WebhookURL адрес, на который отправляются уведомления (канал webhook).
+	WebhookURL string `env:"SENDER_WEBHOOK_URL"`
+
+	// WebhookSecret ключ подписи запросов HMAC-SHA256.
+	WebhookSecret string `env:"SENDER_WEBHOOK_SECRET"`
+
+	// WebhookTimeout таймаут запроса к WebhookURL.
+	WebhookTimeout time.Duration `env:"SENDER_WEBHOOK_TIMEOUT" envDefault:"5s"`
+
+	// WebhookMaxAttempts максимальное количество попыток отправки.
+	WebhookMaxAttempts int `env:"SENDER_WEBHOOK_MAX_ATTEMPTS" envDefault:"5"`
+
+	// WebhookBaseDelay задержка перед первой повторной попыткой.
+	WebhookBaseDelay time.Duration `env:"SENDER_WEBHOOK_BASE_DELAY" envDefault:"500ms"`
+
+	// WebhookMaxDelay максимальная задержка между попытками.
+	WebhookMaxDelay time.Duration `env:"SENDER_WEBHOOK_MAX_DELAY" envDefault:"30s"`
+}
+
+// AuthConfig предоставляет настройки проверки токенов доступа (JWT).
+type AuthConfig struct {
+	// HMACSecret общий секрет для токенов, подписанных HMAC.
+	HMACSecret string `env:"AUTH_HMAC_SECRET"`
+
+	// RSAPublicKeyPath путь к открытому ключу RSA (PEM) для токенов, подписанных RSA.
+	RSAPublicKeyPath string `env:"AUTH_RSA_PUBLIC_KEY_PATH"`
 }
 
 // NewConfig создает новый конфиг.
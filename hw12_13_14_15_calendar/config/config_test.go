@@ -38,6 +38,12 @@ func Test_loadConfig(t *testing.T) {
 				DBDriver: "postgres",
 				REST:     RESTConfig{Address: ":8080"},
 				GRPC:     GRPCConfig{Address: ":8081"},
+				Metrics:  MetricsConfig{Address: ":9090"},
+				Tracing: TracingConfig{
+					Exporter:     "none",
+					OTLPEndpoint: "localhost:4317",
+					SampleRatio:  1,
+				},
 				PostgreSQL: PostgreSQLConfig{
 					Host:     "",
 					Port:     0,
@@ -45,17 +51,31 @@ func Test_loadConfig(t *testing.T) {
 					Password: "",
 					Database: "",
 				},
+				SQLite: SQLiteConfig{Path: "calendar.db"},
 				Kafka: KafkaConfig{
-					Brokers:     []string{"kafka:9092"},
-					GroupID:     "calendar",
-					SenderTopic: "calendar-sender-topic",
+					Brokers:         []string{"kafka:9092"},
+					GroupID:         "calendar",
+					SenderTopic:     "calendar-sender-topic",
+					RetryTopic:      "calendar-sender-retry-topic",
+					DeadLetterTopic: "calendar-sender-dead-letter-topic",
 				},
 				Scheduler: SchedulerConfig{
 					Interval:        1 * time.Minute,
 					EventLifeInDays: 365,
+					TrashRetention:  720 * time.Hour,
 				},
 				Sender: SenderConfig{
-					Threads: 3,
+					Threads:            3,
+					MaxAttempts:        5,
+					RetryDelay:         time.Minute,
+					Notifiers:          []string{"stdout"},
+					FilePath:           "notifications.log",
+					FileMaxSize:        10485760,
+					FileMaxBackups:     5,
+					WebhookTimeout:     5 * time.Second,
+					WebhookMaxAttempts: 5,
+					WebhookBaseDelay:   500 * time.Millisecond,
+					WebhookMaxDelay:    30 * time.Second,
 				},
 			},
 		},
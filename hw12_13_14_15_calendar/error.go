@@ -7,3 +7,30 @@ var ErrNotFound = errors.New("not found")
 
 // ErrDateBusy данное время уже занято.
 var ErrDateBusy = errors.New("that date is busy")
+
+// ErrInvalidRRule некорректное правило повторения события.
+var ErrInvalidRRule = errors.New("invalid recurrence rule")
+
+// ErrInvalidOrder некорректное поле сортировки.
+var ErrInvalidOrder = errors.New("invalid order")
+
+// ErrInvalidTimeZone некорректный часовой пояс.
+var ErrInvalidTimeZone = errors.New("invalid time zone")
+
+// ErrInvalidWorkingHours некорректные рабочие часы.
+var ErrInvalidWorkingHours = errors.New("invalid working hours")
+
+// ErrInvalidRSVPStatus некорректный ответ на приглашение.
+var ErrInvalidRSVPStatus = errors.New("invalid rsvp status")
+
+// ErrInvalidAttendee пользователь не может быть участником события (например, он его владелец).
+var ErrInvalidAttendee = errors.New("invalid attendee")
+
+// ErrRevisionExpired изменения после запрошенной ревизии недоступны.
+var ErrRevisionExpired = errors.New("revision expired")
+
+// ErrVersionConflict событие было изменено после получения клиентом (версии не совпадают).
+var ErrVersionConflict = errors.New("version conflict")
+
+// ErrInvalidField некорректное поле события для частичного обновления.
+var ErrInvalidField = errors.New("invalid event field")
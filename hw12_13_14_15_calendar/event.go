@@ -11,6 +11,10 @@ type Event struct {
 	// ID уникальный идентификатор события.
 	ID uuid.UUID `db:"id"`
 
+	// UID идентификатор события в формате iCalendar (RFC 5545), заданный внешним клиентом
+	// (например, при импорте или синхронизации по CalDAV). Не изменяется после создания события.
+	UID string `db:"uid"`
+
 	// Title заголовок события.
 	Title string `db:"title"`
 
@@ -23,6 +27,10 @@ type Event struct {
 	// EndAt дата и время окончания события.
 	EndAt time.Time `db:"end_at"`
 
+	// TimeZone часовой пояс события из базы IANA (например, Europe/Moscow), пусто для UTC.
+	// Повторения события рассчитываются по местному времени этого пояса.
+	TimeZone string `db:"time_zone"`
+
 	// UserID идентификатор пользователя (владельца события).
 	UserID uuid.UUID `db:"user_id"`
 
@@ -31,6 +39,31 @@ type Event struct {
 
 	// IsNotified было ли уведомление уже выслано.
 	IsNotified bool `db:"is_notified"`
+
+	// RRule правило повторения события в формате RFC 5545 (пусто для неповторяющихся событий).
+	RRule string `db:"rrule"`
+
+	// ExDates даты начала вхождений, исключенных из повторения.
+	ExDates ExDates `db:"exdates"`
+
+	// NotifiedUntil дата начала последнего вхождения повторяющегося события,
+	// по которому уведомление уже выслано.
+	NotifiedUntil *time.Time `db:"notified_until"`
+
+	// DeletedAt дата перемещения события в корзину (nil для действующих событий).
+	DeletedAt *time.Time `db:"deleted_at"`
+
+	// Version версия события, увеличивается при каждом изменении полей события.
+	// При обновлении передается версия, на основе которой сделаны изменения:
+	// если событие успело измениться, обновление отклоняется.
+	Version uint64 `db:"version"`
+
+	// Attendees приглашенные участники события.
+	Attendees []Attendee `db:"-"`
+
+	// RecurrenceID дата начала вхождения повторяющегося события (RECURRENCE-ID из RFC 5545).
+	// Заполняется только у вхождений, полученных разворачиванием серии.
+	RecurrenceID *time.Time `db:"-"`
 }
 
 // EventFilter предоставляет фильтр для поиска.
@@ -38,6 +71,13 @@ type EventFilter struct {
 	// UserID идентификатор пользователя.
 	UserID uuid.UUID
 
+	// Attending вместе с событиями пользователя UserID искать события,
+	// приглашение на которые он принял.
+	Attending bool
+
+	// UID идентификатор события в формате iCalendar.
+	UID string
+
 	// From дата и время начала события.
 	From time.Time
 
@@ -49,4 +89,29 @@ type EventFilter struct {
 
 	// NotifyTime найти те события, по которым нужно выслать уведомление.
 	NotifyTime bool
+
+	// Overlapping искать события, пересекающиеся с промежутком From - To,
+	// а не только целиком лежащие в нем.
+	Overlapping bool
+
+	// OrderBy поле сортировки (по умолчанию OrderByStartAt).
+	OrderBy EventOrder
+
+	// After вернуть только события, идущие после указанной позиции.
+	After *EventCursor
+
+	// Limit максимальное количество событий (0 - без ограничения).
+	Limit int
+}
+
+// TrashFilter предоставляет фильтр для поиска событий в корзине.
+type TrashFilter struct {
+	// ID идентификатор события.
+	ID uuid.UUID
+
+	// UserID идентификатор пользователя (владельца события).
+	UserID uuid.UUID
+
+	// DeletedBefore только события, перемещенные в корзину раньше указанной даты.
+	DeletedBefore time.Time
 }
@@ -0,0 +1,190 @@
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// Interval промежуток времени [Start, End).
+type Interval struct {
+	// Start начало промежутка.
+	Start time.Time
+
+	// End окончание промежутка.
+	End time.Time
+}
+
+// Duration длительность промежутка.
+func (i Interval) Duration() time.Duration {
+	return i.End.Sub(i.Start)
+}
+
+// WorkingHours рабочие часы, которыми ограничивается свободное время.
+type WorkingHours struct {
+	// Start начало рабочего дня (смещение от полуночи по местному времени).
+	Start time.Duration
+
+	// End окончание рабочего дня (смещение от полуночи по местному времени).
+	End time.Duration
+
+	// Weekdays рабочие дни недели. Пустой список означает все дни.
+	Weekdays []time.Weekday
+
+	// Location часовой пояс рабочих часов. Пустое значение означает UTC.
+	Location *time.Location
+}
+
+// Validate проверяет рабочие часы на корректность.
+func (w WorkingHours) Validate() error {
+	if w.Start < 0 || w.End > 24*time.Hour || w.Start >= w.End {
+		return ErrInvalidWorkingHours
+	}
+
+	for _, d := range w.Weekdays {
+		if d < time.Sunday || d > time.Saturday {
+			return ErrInvalidWorkingHours
+		}
+	}
+
+	return nil
+}
+
+// Windows возвращает рабочие промежутки, пересекающиеся с промежутком from - to.
+// Начало и окончание рабочего дня отсчитываются по местному времени, поэтому
+// при переходе на летнее время рабочий день не сдвигается.
+func (w WorkingHours) Windows(from, to time.Time) []Interval {
+	loc := w.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	res := make([]Interval, 0)
+
+	first := from.In(loc)
+	day := time.Date(first.Year(), first.Month(), first.Day(), 0, 0, 0, 0, loc)
+
+	for ; day.Before(to); day = time.Date(day.Year(), day.Month(), day.Day()+1, 0, 0, 0, 0, loc) {
+		if !w.isWorkday(day.Weekday()) {
+			continue
+		}
+
+		window := clipInterval(Interval{
+			Start: wallClock(day, w.Start),
+			End:   wallClock(day, w.End),
+		}, from, to)
+
+		if window.Duration() > 0 {
+			res = append(res, window)
+		}
+	}
+
+	return res
+}
+
+// isWorkday является ли день недели рабочим.
+func (w WorkingHours) isWorkday(d time.Weekday) bool {
+	if len(w.Weekdays) == 0 {
+		return true
+	}
+
+	for _, wd := range w.Weekdays {
+		if wd == d {
+			return true
+		}
+	}
+
+	return false
+}
+
+// wallClock возвращает момент дня day, отстоящий от полуночи на offset по местному времени.
+func wallClock(day time.Time, offset time.Duration) time.Time {
+	return time.Date(
+		day.Year(), day.Month(), day.Day(),
+		int(offset/time.Hour), int(offset%time.Hour/time.Minute), int(offset%time.Minute/time.Second), 0,
+		day.Location(),
+	)
+}
+
+// BusyIntervals возвращает занятые событиями промежутки внутри from - to,
+// отсортированные и объединенные между собой.
+func BusyIntervals(events []*Event, from, to time.Time) []Interval {
+	intervals := make([]Interval, 0, len(events))
+
+	for _, e := range events {
+		i := clipInterval(Interval{Start: e.StartAt, End: e.EndAt}, from, to)
+		if i.Duration() > 0 {
+			intervals = append(intervals, i)
+		}
+	}
+
+	return MergeIntervals(intervals)
+}
+
+// MergeIntervals сортирует промежутки и объединяет пересекающиеся и соприкасающиеся.
+func MergeIntervals(intervals []Interval) []Interval {
+	sort.Slice(intervals, func(i, j int) bool {
+		return intervals[i].Start.Before(intervals[j].Start)
+	})
+
+	res := make([]Interval, 0, len(intervals))
+
+	for _, i := range intervals {
+		if n := len(res); n > 0 && !i.Start.After(res[n-1].End) {
+			if i.End.After(res[n-1].End) {
+				res[n-1].End = i.End
+			}
+
+			continue
+		}
+
+		res = append(res, i)
+	}
+
+	return res
+}
+
+// FreeIntervals возвращает свободные промежутки внутри окон windows,
+// не занятые промежутками busy и длительностью не меньше minLength.
+// Промежутки busy должны быть отсортированы и объединены (см. MergeIntervals).
+func FreeIntervals(busy, windows []Interval, minLength time.Duration) []Interval {
+	res := make([]Interval, 0)
+
+	for _, w := range windows {
+		start := w.Start
+
+		for _, b := range busy {
+			if !b.End.After(start) {
+				continue
+			}
+
+			if !b.Start.Before(w.End) {
+				break
+			}
+
+			if gap := (Interval{Start: start, End: b.Start}); gap.Duration() > 0 && gap.Duration() >= minLength {
+				res = append(res, gap)
+			}
+
+			start = b.End
+		}
+
+		if gap := (Interval{Start: start, End: w.End}); gap.Duration() > 0 && gap.Duration() >= minLength {
+			res = append(res, gap)
+		}
+	}
+
+	return res
+}
+
+// clipInterval обрезает промежуток по границам from - to.
+func clipInterval(i Interval, from, to time.Time) Interval {
+	if i.Start.Before(from) {
+		i.Start = from
+	}
+
+	if i.End.After(to) {
+		i.End = to
+	}
+
+	return i
+}
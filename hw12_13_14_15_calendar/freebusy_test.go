@@ -0,0 +1,123 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestBusyIntervals(t *testing.T) {
+	t.Parallel()
+
+	from := mustParseDateTime("2022-10-03 00:00:00")
+	to := from.Add(24 * time.Hour)
+
+	events := []*Event{
+		{StartAt: from.Add(13 * time.Hour), EndAt: from.Add(14 * time.Hour)},
+		{StartAt: from.Add(-time.Hour), EndAt: from.Add(time.Hour)},
+		{StartAt: from.Add(10 * time.Hour), EndAt: from.Add(12 * time.Hour)},
+		{StartAt: from.Add(11 * time.Hour), EndAt: from.Add(13 * time.Hour)},
+		{StartAt: from.Add(23 * time.Hour), EndAt: from.Add(25 * time.Hour)},
+		{StartAt: from.Add(30 * time.Hour), EndAt: from.Add(31 * time.Hour)},
+	}
+
+	require.Equal(t, []Interval{
+		{Start: from, End: from.Add(time.Hour)},
+		{Start: from.Add(10 * time.Hour), End: from.Add(14 * time.Hour)},
+		{Start: from.Add(23 * time.Hour), End: to},
+	}, BusyIntervals(events, from, to))
+}
+
+func TestFreeIntervals(t *testing.T) {
+	t.Parallel()
+
+	from := mustParseDateTime("2022-10-03 00:00:00")
+	to := from.Add(24 * time.Hour)
+
+	busy := []Interval{
+		{Start: from.Add(9 * time.Hour), End: from.Add(10 * time.Hour)},
+		{Start: from.Add(10*time.Hour + 30*time.Minute), End: from.Add(12 * time.Hour)},
+		{Start: from.Add(17 * time.Hour), End: from.Add(19 * time.Hour)},
+	}
+
+	tests := []struct {
+		name      string
+		windows   []Interval
+		minLength time.Duration
+		want      []Interval
+	}{
+		{
+			name:    "whole range",
+			windows: []Interval{{Start: from, End: to}},
+			want: []Interval{
+				{Start: from, End: from.Add(9 * time.Hour)},
+				{Start: from.Add(10 * time.Hour), End: from.Add(10*time.Hour + 30*time.Minute)},
+				{Start: from.Add(12 * time.Hour), End: from.Add(17 * time.Hour)},
+				{Start: from.Add(19 * time.Hour), End: to},
+			},
+		},
+		{
+			name:      "min length",
+			windows:   []Interval{{Start: from, End: to}},
+			minLength: time.Hour,
+			want: []Interval{
+				{Start: from, End: from.Add(9 * time.Hour)},
+				{Start: from.Add(12 * time.Hour), End: from.Add(17 * time.Hour)},
+				{Start: from.Add(19 * time.Hour), End: to},
+			},
+		},
+		{
+			name:    "working hours",
+			windows: []Interval{{Start: from.Add(9 * time.Hour), End: from.Add(18 * time.Hour)}},
+			want: []Interval{
+				{Start: from.Add(10 * time.Hour), End: from.Add(10*time.Hour + 30*time.Minute)},
+				{Start: from.Add(12 * time.Hour), End: from.Add(17 * time.Hour)},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			require.Equal(t, tt.want, FreeIntervals(busy, tt.windows, tt.minLength))
+		})
+	}
+}
+
+func TestWorkingHours_Windows(t *testing.T) {
+	t.Parallel()
+
+	loc, err := LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+
+	w := WorkingHours{
+		Start:    9 * time.Hour,
+		End:      18 * time.Hour,
+		Weekdays: []time.Weekday{time.Friday, time.Saturday, time.Monday},
+		Location: loc,
+	}
+	require.NoError(t, w.Validate())
+
+	// 2022-10-30 в Берлине переход на зимнее время, рабочий день остается 9:00 - 18:00 по местному времени
+	from := time.Date(2022, 10, 28, 12, 0, 0, 0, loc)
+	to := time.Date(2022, 11, 1, 0, 0, 0, 0, loc)
+
+	require.Equal(t, []Interval{
+		{Start: from.UTC(), End: mustParseDateTime("2022-10-28 16:00:00")},
+		{Start: mustParseDateTime("2022-10-29 07:00:00"), End: mustParseDateTime("2022-10-29 16:00:00")},
+		{Start: mustParseDateTime("2022-10-31 08:00:00"), End: mustParseDateTime("2022-10-31 17:00:00")},
+	}, utcIntervals(w.Windows(from, to)))
+
+	require.ErrorIs(t, WorkingHours{Start: 18 * time.Hour, End: 9 * time.Hour}.Validate(), ErrInvalidWorkingHours)
+	require.ErrorIs(t, WorkingHours{End: 25 * time.Hour}.Validate(), ErrInvalidWorkingHours)
+}
+
+func utcIntervals(intervals []Interval) []Interval {
+	for i := range intervals {
+		intervals[i].Start, intervals[i].End = intervals[i].Start.UTC(), intervals[i].End.UTC()
+	}
+
+	return intervals
+}
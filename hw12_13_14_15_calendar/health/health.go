@@ -0,0 +1,180 @@
+// Package health содержит проверки состояния сервиса для оркестратора.
+package health
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+const (
+	// LivenessPath путь проверки, что процесс жив.
+	LivenessPath = "/healthz"
+
+	// ReadinessPath путь проверки, что сервис готов обрабатывать запросы.
+	ReadinessPath = "/readyz"
+)
+
+// checkTimeout ограничивает время одной проверки.
+const checkTimeout = 3 * time.Second
+
+// Check проверяет доступность зависимости сервиса.
+type Check func(ctx context.Context) error
+
+// Checker проверяет готовность сервиса по набору именованных проверок.
+type Checker struct {
+	mu     sync.RWMutex
+	checks map[string]Check
+}
+
+// New создает Checker.
+func New() *Checker {
+	return &Checker{checks: make(map[string]Check)}
+}
+
+// Add добавляет проверку name.
+func (c *Checker) Add(name string, check Check) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.checks[name] = check
+}
+
+// Check выполняет все проверки параллельно и возвращает ошибки проверок по их именам.
+// Пустой результат означает готовность сервиса.
+func (c *Checker) Check(ctx context.Context) map[string]error {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	ctx, cancel := context.WithTimeout(ctx, checkTimeout)
+	defer cancel()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		failed = make(map[string]error)
+	)
+
+	for name, check := range c.checks {
+		name, check := name, check
+
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			if err := check(ctx); err != nil {
+				mu.Lock()
+				failed[name] = err
+				mu.Unlock()
+			}
+		}()
+	}
+
+	wg.Wait()
+
+	return failed
+}
+
+// names возвращает отсортированные имена проверок.
+func (c *Checker) names() []string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	names := make([]string, 0, len(c.checks))
+	for name := range c.checks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	return names
+}
+
+// LivenessHandler отвечает, что процесс жив.
+func LivenessHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		writeStatus(w, http.StatusOK, response{Status: "ok"})
+	})
+}
+
+// ReadinessHandler отвечает 200, если все проверки прошли, иначе 503 с результатами проверок.
+func ReadinessHandler(c *Checker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		failed := c.Check(req.Context())
+
+		res := response{Status: "ok", Checks: make(map[string]string)}
+		for _, name := range c.names() {
+			res.Checks[name] = "ok"
+		}
+
+		for name, err := range failed {
+			res.Checks[name] = err.Error()
+		}
+
+		code := http.StatusOK
+		if len(failed) > 0 {
+			res.Status = "unavailable"
+			code = http.StatusServiceUnavailable
+		}
+
+		writeStatus(w, code, res)
+	})
+}
+
+// WatchGRPC периодически выполняет проверки и выставляет статус GRPC сервиса
+// проверки состояния для сервисов services и сервера в целом.
+// Блокируется до отмены ctx, после чего переводит сервисы в NOT_SERVING.
+func WatchGRPC(ctx context.Context, c *Checker, srv *grpchealth.Server, interval time.Duration, services ...string) {
+	update := func() {
+		status := healthpb.HealthCheckResponse_SERVING
+		if len(c.Check(ctx)) > 0 {
+			status = healthpb.HealthCheckResponse_NOT_SERVING
+		}
+
+		srv.SetServingStatus("", status)
+		for _, service := range services {
+			srv.SetServingStatus(service, status)
+		}
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		update()
+
+		select {
+		case <-ctx.Done():
+			srv.Shutdown()
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// response тело ответа проверки состояния.
+type response struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+// writeStatus пишет ответ проверки состояния.
+func writeStatus(w http.ResponseWriter, code int, res response) {
+	bs, err := json.Marshal(res)
+	if err != nil {
+		http.Error(w, errors.Wrap(err, "marshal health response").Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	_, _ = w.Write(bs)
+}
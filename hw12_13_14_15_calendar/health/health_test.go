@@ -0,0 +1,148 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/stretchr/testify/require"
+	grpchealth "google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+func TestReadinessHandler(t *testing.T) {
+	errDown := errors.New("connection refused")
+
+	tests := []struct {
+		name       string
+		checks     map[string]Check
+		wantStatus int
+		want       response
+	}{
+		{
+			name:       "without checks",
+			wantStatus: http.StatusOK,
+			want:       response{Status: "ok"},
+		},
+		{
+			name: "all passed",
+			checks: map[string]Check{
+				"postgres": func(ctx context.Context) error { return nil },
+				"kafka":    func(ctx context.Context) error { return nil },
+			},
+			wantStatus: http.StatusOK,
+			want:       response{Status: "ok", Checks: map[string]string{"postgres": "ok", "kafka": "ok"}},
+		},
+		{
+			name: "failed",
+			checks: map[string]Check{
+				"postgres": func(ctx context.Context) error { return nil },
+				"kafka":    func(ctx context.Context) error { return errDown },
+			},
+			wantStatus: http.StatusServiceUnavailable,
+			want: response{
+				Status: "unavailable",
+				Checks: map[string]string{"postgres": "ok", "kafka": "connection refused"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			c := New()
+			for name, check := range tt.checks {
+				c.Add(name, check)
+			}
+
+			rec := httptest.NewRecorder()
+			ReadinessHandler(c).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, ReadinessPath, nil))
+
+			require.Equal(t, tt.wantStatus, rec.Code)
+
+			var got response
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &got))
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestLivenessHandler(t *testing.T) {
+	rec := httptest.NewRecorder()
+	LivenessHandler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, LivenessPath, nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+}
+
+func TestChecker_Check_Timeout(t *testing.T) {
+	c := New()
+	c.Add("slow", func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	failed := c.Check(ctx)
+	require.ErrorIs(t, failed["slow"], context.DeadlineExceeded)
+}
+
+func TestWatchGRPC(t *testing.T) {
+	const service = "event.EventService"
+
+	ready := make(chan bool, 1)
+	ready <- false
+
+	c := New()
+	c.Add("db", func(ctx context.Context) error {
+		r := <-ready
+		ready <- r
+
+		if !r {
+			return errors.New("down")
+		}
+
+		return nil
+	})
+
+	srv := grpchealth.NewServer()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		WatchGRPC(ctx, c, srv, time.Millisecond, service)
+	}()
+
+	status := func() healthpb.HealthCheckResponse_ServingStatus {
+		res, err := srv.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		if err != nil {
+			return healthpb.HealthCheckResponse_SERVICE_UNKNOWN
+		}
+
+		return res.Status
+	}
+
+	require.Eventually(t, func() bool {
+		return status() == healthpb.HealthCheckResponse_NOT_SERVING
+	}, time.Second, time.Millisecond)
+
+	<-ready
+	ready <- true
+
+	require.Eventually(t, func() bool {
+		return status() == healthpb.HealthCheckResponse_SERVING
+	}, time.Second, time.Millisecond)
+
+	cancel()
+	<-done
+
+	require.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, status())
+}
@@ -0,0 +1,691 @@
+// Package ical реализует импорт и экспорт событий в формате iCalendar (RFC 5545).
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// ContentType MIME-тип календаря.
+const ContentType = "text/calendar; charset=utf-8"
+
+const (
+	// prodID идентификатор продукта, создавшего календарь.
+	prodID = "-//RomanSarvarov//otus_go_home_work calendar//RU"
+
+	// maxLineLength максимальная длина строки в октетах (без CRLF).
+	maxLineLength = 75
+
+	// dateTimeLayout формат даты и времени в UTC.
+	dateTimeLayout = "20060102T150405Z"
+
+	// localDateTimeLayout формат локальной даты и времени.
+	localDateTimeLayout = "20060102T150405"
+
+	// dateLayout формат даты.
+	dateLayout = "20060102"
+)
+
+// ErrInvalidCalendar некорректный формат календаря.
+var ErrInvalidCalendar = errors.New("invalid icalendar")
+
+var timeNowFunc = time.Now
+
+// VEvent событие, прочитанное из календаря.
+type VEvent struct {
+	// UID идентификатор события в исходном календаре.
+	UID string
+
+	// Event событие.
+	Event *calendar.Event
+
+	// Err ошибка разбора события. Если она не пуста, то Event не заполнен.
+	Err error
+}
+
+// property свойство компонента календаря.
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// param возвращает значение параметра свойства.
+func (p property) param(name string) string {
+	return p.params[name]
+}
+
+// component компонент календаря (VCALENDAR, VEVENT, VALARM и т.д.).
+type component struct {
+	name       string
+	props      []property
+	components []*component
+}
+
+// prop возвращает первое свойство с указанным именем.
+func (c *component) prop(name string) (property, bool) {
+	for _, p := range c.props {
+		if p.name == name {
+			return p, true
+		}
+	}
+
+	return property{}, false
+}
+
+// Encode записывает события в w в формате iCalendar.
+// Для повторяющихся событий записывается вся серия.
+func Encode(w io.Writer, events []*calendar.Event) error {
+	lw := &lineWriter{w: bufio.NewWriter(w)}
+
+	lw.line("BEGIN:VCALENDAR")
+	lw.line("VERSION:2.0")
+	lw.line("PRODID:" + prodID)
+	lw.line("CALSCALE:GREGORIAN")
+
+	stamp := timeNowFunc().UTC().Format(dateTimeLayout)
+
+	// события с часовым поясом записываются в местном времени со ссылкой на VTIMEZONE,
+	// чтобы повторения рассчитывались клиентом с учетом перехода на летнее время
+	years := make(map[string]int)
+
+	for _, e := range events {
+		if e.TimeZone == "" {
+			continue
+		}
+
+		if y, ok := years[e.TimeZone]; !ok || e.StartAt.Year() < y {
+			years[e.TimeZone] = e.StartAt.Year()
+		}
+	}
+
+	writeTimeZones(lw, years)
+
+	for _, e := range events {
+		lw.line("BEGIN:VEVENT")
+		lw.line("UID:" + uid(e))
+		lw.line("DTSTAMP:" + stamp)
+		lw.line("DTSTART" + formatTime(e, e.StartAt))
+		lw.line("DTEND" + formatTime(e, e.EndAt))
+		lw.line("SUMMARY:" + escapeText(e.Title))
+
+		if e.Description != "" {
+			lw.line("DESCRIPTION:" + escapeText(e.Description))
+		}
+
+		if e.IsRecurring() {
+			lw.line("RRULE:" + strings.TrimPrefix(e.RRule, "RRULE:"))
+		}
+
+		for _, t := range e.ExDates {
+			lw.line("EXDATE" + formatTime(e, t))
+		}
+
+		if e.NotificationDuration > 0 {
+			lw.line("BEGIN:VALARM")
+			lw.line("ACTION:DISPLAY")
+			lw.line("DESCRIPTION:" + escapeText(e.Title))
+			lw.line("TRIGGER:-" + formatDuration(time.Duration(e.NotificationDuration)*time.Minute))
+			lw.line("END:VALARM")
+		}
+
+		lw.line("END:VEVENT")
+	}
+
+	lw.line("END:VCALENDAR")
+
+	return lw.flush()
+}
+
+// Decode читает события из календаря в формате iCalendar.
+// Ошибки разбора отдельных событий возвращаются в VEvent.Err, не прерывая чтение остальных.
+// Измененные вхождения серии (VEVENT с RECURRENCE-ID) возвращаются как отдельные события,
+// а в серию добавляется соответствующая исключенная дата.
+func Decode(r io.Reader) ([]VEvent, error) {
+	lines, err := unfold(r)
+	if err != nil {
+		return nil, err
+	}
+
+	root, err := parseComponents(lines)
+	if err != nil {
+		return nil, err
+	}
+
+	zones := decodeTimeZones(root)
+
+	res := make([]VEvent, 0, len(root.components))
+	series := make(map[string]*calendar.Event)
+	overrides := make(map[string][]time.Time)
+
+	for _, c := range root.components {
+		if c.name != "VEVENT" {
+			continue
+		}
+
+		var uid string
+		if p, ok := c.prop("UID"); ok {
+			uid = p.value
+		}
+
+		e, recurrenceID, err := decodeEvent(c, zones)
+		if err != nil {
+			res = append(res, VEvent{UID: uid, Err: err})
+			continue
+		}
+
+		if recurrenceID != nil {
+			overrides[uid] = append(overrides[uid], *recurrenceID)
+		} else if e.IsRecurring() {
+			series[uid] = e
+		}
+
+		e.UID = uid
+		res = append(res, VEvent{UID: uid, Event: e})
+	}
+
+	for uid, exdates := range overrides {
+		if e, ok := series[uid]; ok {
+			e.ExDates = append(e.ExDates, exdates...)
+		}
+	}
+
+	return res, nil
+}
+
+// uid возвращает идентификатор события в календаре.
+// Для событий, созданных не из iCalendar, используется их внутренний идентификатор.
+func uid(e *calendar.Event) string {
+	if e.UID != "" {
+		return e.UID
+	}
+
+	return e.ID.String()
+}
+
+// formatTime форматирует дату свойства события вместе с параметром TZID (например, ";TZID=Europe/Moscow:20221012T153000").
+func formatTime(e *calendar.Event, t time.Time) string {
+	if e.TimeZone == "" {
+		return ":" + t.UTC().Format(dateTimeLayout)
+	}
+
+	return ";TZID=" + e.TimeZone + ":" + t.In(e.Location()).Format(localDateTimeLayout)
+}
+
+// decodeEvent преобразует компонент VEVENT в событие.
+// Часовые пояса TZID ищутся в zones, а также среди поясов IANA и Windows.
+func decodeEvent(c *component, zones timeZones) (*calendar.Event, *time.Time, error) {
+	e := &calendar.Event{}
+
+	if p, ok := c.prop("SUMMARY"); ok {
+		e.Title = unescapeText(p.value)
+	}
+
+	if p, ok := c.prop("DESCRIPTION"); ok {
+		e.Description = unescapeText(p.value)
+	}
+
+	p, ok := c.prop("DTSTART")
+	if !ok {
+		return nil, nil, errors.Wrap(ErrInvalidCalendar, "dtstart is required")
+	}
+
+	startAt, allDay, err := parseTime(p, zones)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	e.StartAt = startAt
+
+	if tzid := p.param("TZID"); tzid != "" {
+		tz, _ := zones.resolve(tzid)
+		e.TimeZone = tz.name
+	}
+
+	if p, ok := c.prop("DTEND"); ok {
+		if e.EndAt, _, err = parseTime(p, zones); err != nil {
+			return nil, nil, err
+		}
+	} else if p, ok := c.prop("DURATION"); ok {
+		d, err := parseDuration(p.value)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		e.EndAt = startAt.Add(d)
+	} else if allDay {
+		e.EndAt = startAt.AddDate(0, 0, 1)
+	} else {
+		e.EndAt = startAt
+	}
+
+	if e.EndAt.Before(e.StartAt) {
+		return nil, nil, errors.Wrap(ErrInvalidCalendar, "dtend is before dtstart")
+	}
+
+	if p, ok := c.prop("RRULE"); ok {
+		e.RRule = p.value
+	}
+
+	for _, p := range c.props {
+		if p.name != "EXDATE" {
+			continue
+		}
+
+		for _, v := range strings.Split(p.value, ",") {
+			t, _, err := parseTime(property{name: p.name, params: p.params, value: v}, zones)
+			if err != nil {
+				return nil, nil, err
+			}
+
+			e.ExDates = append(e.ExDates, t)
+		}
+	}
+
+	if e.NotificationDuration, err = decodeAlarms(c, e, zones); err != nil {
+		return nil, nil, err
+	}
+
+	var recurrenceID *time.Time
+
+	if p, ok := c.prop("RECURRENCE-ID"); ok {
+		t, _, err := parseTime(p, zones)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		recurrenceID = &t
+	}
+
+	return e, recurrenceID, nil
+}
+
+// decodeAlarms возвращает, за сколько минут до начала события срабатывает самое раннее напоминание.
+func decodeAlarms(c *component, e *calendar.Event, zones timeZones) (uint32, error) {
+	var res uint32
+
+	for _, alarm := range c.components {
+		if alarm.name != "VALARM" {
+			continue
+		}
+
+		p, ok := alarm.prop("TRIGGER")
+		if !ok {
+			continue
+		}
+
+		var at time.Time
+
+		if p.param("VALUE") == "DATE-TIME" {
+			t, _, err := parseTime(p, zones)
+			if err != nil {
+				return 0, err
+			}
+
+			at = t
+		} else {
+			d, err := parseDuration(p.value)
+			if err != nil {
+				return 0, err
+			}
+
+			at = e.StartAt.Add(d)
+			if p.param("RELATED") == "END" {
+				at = e.EndAt.Add(d)
+			}
+		}
+
+		if !at.Before(e.StartAt) {
+			continue
+		}
+
+		if minutes := uint32(e.StartAt.Sub(at) / time.Minute); minutes > res {
+			res = minutes
+		}
+	}
+
+	return res, nil
+}
+
+// parseTime разбирает значение даты или даты и времени с учетом параметра TZID.
+// allDay будет true, если значение является датой без времени.
+func parseTime(p property, zones timeZones) (t time.Time, allDay bool, err error) {
+	loc := time.UTC
+
+	if tzid := p.param("TZID"); tzid != "" {
+		tz, err := zones.resolve(tzid)
+		if err != nil {
+			return t, false, err
+		}
+
+		loc = tz.loc
+	}
+
+	switch {
+	case p.param("VALUE") == "DATE" || len(p.value) == len(dateLayout):
+		t, err = time.ParseInLocation(dateLayout, p.value, loc)
+		allDay = true
+	case strings.HasSuffix(p.value, "Z"):
+		t, err = time.Parse(dateTimeLayout, p.value)
+	default:
+		t, err = time.ParseInLocation(localDateTimeLayout, p.value, loc)
+	}
+
+	if err != nil {
+		return t, false, errors.Wrapf(ErrInvalidCalendar, "invalid %s `%s`", strings.ToLower(p.name), p.value)
+	}
+
+	return t.UTC(), allDay, nil
+}
+
+// unfold читает строки календаря, объединяя перенесенные строки.
+func unfold(r io.Reader) ([]string, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	lines := make([]string, 0)
+
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if line == "" {
+			continue
+		}
+
+		if (line[0] == ' ' || line[0] == '\t') && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+
+		lines = append(lines, line)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrap(err, "read icalendar")
+	}
+
+	return lines, nil
+}
+
+// parseComponents строит дерево компонентов и возвращает компонент VCALENDAR.
+func parseComponents(lines []string) (*component, error) {
+	var (
+		root  *component
+		stack []*component
+	)
+
+	for _, line := range lines {
+		p, err := parseProperty(line)
+		if err != nil {
+			return nil, err
+		}
+
+		switch p.name {
+		case "BEGIN":
+			c := &component{name: strings.ToUpper(p.value)}
+
+			if len(stack) == 0 {
+				if c.name != "VCALENDAR" {
+					return nil, errors.Wrap(ErrInvalidCalendar, "vcalendar expected")
+				}
+
+				root = c
+			} else {
+				parent := stack[len(stack)-1]
+				parent.components = append(parent.components, c)
+			}
+
+			stack = append(stack, c)
+		case "END":
+			if len(stack) == 0 || stack[len(stack)-1].name != strings.ToUpper(p.value) {
+				return nil, errors.Wrapf(ErrInvalidCalendar, "unexpected end of `%s`", p.value)
+			}
+
+			stack = stack[:len(stack)-1]
+		default:
+			if len(stack) == 0 {
+				return nil, errors.Wrap(ErrInvalidCalendar, "property outside of vcalendar")
+			}
+
+			c := stack[len(stack)-1]
+			c.props = append(c.props, p)
+		}
+	}
+
+	if root == nil || len(stack) > 0 {
+		return nil, errors.Wrap(ErrInvalidCalendar, "unterminated vcalendar")
+	}
+
+	return root, nil
+}
+
+// parseProperty разбирает строку вида NAME;PARAM=VALUE:VALUE.
+func parseProperty(line string) (property, error) {
+	p := property{params: make(map[string]string)}
+
+	var (
+		quoted bool
+		parts  []string
+		start  int
+	)
+
+	for i, r := range line {
+		switch {
+		case r == '"':
+			quoted = !quoted
+		case r == ';' && !quoted:
+			parts = append(parts, line[start:i])
+			start = i + 1
+		case r == ':' && !quoted:
+			parts = append(parts, line[start:i])
+			p.value = line[i+1:]
+
+			p.name = strings.ToUpper(parts[0])
+			if p.name == "" {
+				return p, errors.Wrapf(ErrInvalidCalendar, "invalid line `%s`", line)
+			}
+
+			for _, param := range parts[1:] {
+				kv := strings.SplitN(param, "=", 2)
+				if len(kv) != 2 {
+					return p, errors.Wrapf(ErrInvalidCalendar, "invalid parameter `%s`", param)
+				}
+
+				p.params[strings.ToUpper(kv[0])] = strings.Trim(kv[1], `"`)
+			}
+
+			return p, nil
+		}
+	}
+
+	return p, errors.Wrapf(ErrInvalidCalendar, "invalid line `%s`", line)
+}
+
+// parseDuration разбирает продолжительность в формате RFC 5545 (например, -PT15M или P1DT2H).
+func parseDuration(s string) (time.Duration, error) {
+	invalid := errors.Wrapf(ErrInvalidCalendar, "invalid duration `%s`", s)
+
+	sign := time.Duration(1)
+
+	switch {
+	case strings.HasPrefix(s, "-"):
+		sign, s = -1, s[1:]
+	case strings.HasPrefix(s, "+"):
+		s = s[1:]
+	}
+
+	if !strings.HasPrefix(s, "P") || len(s) < 3 {
+		return 0, invalid
+	}
+
+	var (
+		res    time.Duration
+		num    int
+		digits bool
+		inTime bool
+	)
+
+	for _, r := range s[1:] {
+		if r >= '0' && r <= '9' {
+			num = num*10 + int(r-'0')
+			digits = true
+
+			continue
+		}
+
+		if r == 'T' {
+			if inTime || digits {
+				return 0, invalid
+			}
+
+			inTime = true
+
+			continue
+		}
+
+		if !digits {
+			return 0, invalid
+		}
+
+		var unit time.Duration
+
+		switch {
+		case r == 'W' && !inTime:
+			unit = 7 * 24 * time.Hour
+		case r == 'D' && !inTime:
+			unit = 24 * time.Hour
+		case r == 'H' && inTime:
+			unit = time.Hour
+		case r == 'M' && inTime:
+			unit = time.Minute
+		case r == 'S' && inTime:
+			unit = time.Second
+		default:
+			return 0, invalid
+		}
+
+		res += time.Duration(num) * unit
+		num, digits = 0, false
+	}
+
+	if digits {
+		return 0, invalid
+	}
+
+	return sign * res, nil
+}
+
+// formatDuration форматирует неотрицательную продолжительность в формате RFC 5545.
+func formatDuration(d time.Duration) string {
+	var sb strings.Builder
+
+	sb.WriteString("P")
+
+	if days := d / (24 * time.Hour); days > 0 {
+		sb.WriteString(strconv.FormatInt(int64(days), 10) + "D")
+		d -= days * 24 * time.Hour
+	}
+
+	if d == 0 {
+		if sb.Len() == 1 {
+			return "PT0S"
+		}
+
+		return sb.String()
+	}
+
+	sb.WriteString("T")
+
+	if h := d / time.Hour; h > 0 {
+		sb.WriteString(strconv.FormatInt(int64(h), 10) + "H")
+		d -= h * time.Hour
+	}
+
+	if m := d / time.Minute; m > 0 {
+		sb.WriteString(strconv.FormatInt(int64(m), 10) + "M")
+		d -= m * time.Minute
+	}
+
+	if s := d / time.Second; s > 0 {
+		sb.WriteString(strconv.FormatInt(int64(s), 10) + "S")
+	}
+
+	return sb.String()
+}
+
+// escapeText экранирует значение типа TEXT.
+func escapeText(s string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(s)
+}
+
+// unescapeText убирает экранирование из значения типа TEXT.
+func unescapeText(s string) string {
+	return strings.NewReplacer(
+		`\\`, `\`,
+		`\;`, ";",
+		`\,`, ",",
+		`\n`, "\n",
+		`\N`, "\n",
+	).Replace(s)
+}
+
+// lineWriter записывает строки календаря, перенося длинные строки.
+type lineWriter struct {
+	w   *bufio.Writer
+	err error
+}
+
+// line записывает строку, разбивая ее на части не длиннее maxLineLength октетов.
+func (lw *lineWriter) line(s string) {
+	if lw.err != nil {
+		return
+	}
+
+	limit := maxLineLength
+
+	for len(s) > limit {
+		cut := limit
+		for cut > 0 && !isRuneStart(s[cut]) {
+			cut--
+		}
+
+		lw.write(s[:cut] + "\r\n ")
+		s = s[cut:]
+		limit = maxLineLength - 1
+	}
+
+	lw.write(s + "\r\n")
+}
+
+func (lw *lineWriter) write(s string) {
+	if lw.err != nil {
+		return
+	}
+
+	_, lw.err = lw.w.WriteString(s)
+}
+
+func (lw *lineWriter) flush() error {
+	if lw.err != nil {
+		return errors.Wrap(lw.err, "write icalendar")
+	}
+
+	return errors.Wrap(lw.w.Flush(), "write icalendar")
+}
+
+// isRuneStart проверяет, является ли байт началом символа UTF-8.
+func isRuneStart(b byte) bool {
+	return b&0xC0 != 0x80
+}
@@ -0,0 +1,440 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+func TestEncode(t *testing.T) {
+	t.Parallel()
+
+	events := []*calendar.Event{
+		{
+			ID:                   uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+			Title:                "Встреча; важная, очень",
+			Description:          "строка 1\nстрока 2",
+			StartAt:              time.Date(2022, 10, 12, 12, 30, 0, 0, time.UTC),
+			EndAt:                time.Date(2022, 10, 12, 14, 30, 0, 0, time.UTC),
+			NotificationDuration: 90,
+			RRule:                "FREQ=WEEKLY;BYDAY=WE",
+			ExDates:              calendar.ExDates{time.Date(2022, 10, 19, 12, 30, 0, 0, time.UTC)},
+		},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, events))
+
+	got := buf.String()
+
+	require.True(t, strings.HasPrefix(got, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	require.True(t, strings.HasSuffix(got, "END:VEVENT\r\nEND:VCALENDAR\r\n"))
+	require.Contains(t, got, "UID:ef0d2079-e9a2-4810-8cae-eb6729c50580\r\n")
+	require.Contains(t, got, "DTSTART:20221012T123000Z\r\n")
+	require.Contains(t, got, "DTEND:20221012T143000Z\r\n")
+	require.Contains(t, got, `SUMMARY:Встреча\; важная\, очень`+"\r\n")
+	require.Contains(t, got, `DESCRIPTION:строка 1\nстрока 2`+"\r\n")
+	require.Contains(t, got, "RRULE:FREQ=WEEKLY;BYDAY=WE\r\n")
+	require.Contains(t, got, "EXDATE:20221019T123000Z\r\n")
+	require.Contains(t, got, "TRIGGER:-PT1H30M\r\n")
+
+	for _, line := range strings.Split(got, "\r\n") {
+		require.LessOrEqual(t, len(line), maxLineLength)
+	}
+}
+
+func TestEncode_FoldsLongLines(t *testing.T) {
+	t.Parallel()
+
+	title := strings.Repeat("событие ", 30)
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, []*calendar.Event{{Title: title}}))
+
+	for _, line := range strings.Split(buf.String(), "\r\n") {
+		require.LessOrEqual(t, len(line), maxLineLength)
+	}
+
+	decoded, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Len(t, decoded, 1)
+	require.Equal(t, title, decoded[0].Event.Title)
+}
+
+func TestDecode(t *testing.T) {
+	t.Parallel()
+
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"PRODID:-//Google Inc//Google Calendar 70.9054//EN",
+		"BEGIN:VEVENT",
+		"UID:first@example.com",
+		"DTSTART;TZID=Europe/Moscow:20221012T153000",
+		"DTEND;TZID=Europe/Moscow:20221012T173000",
+		"SUMMARY:Планерка",
+		"DESCRIPTION:длинное описание",
+		"  с переносом",
+		"RRULE:FREQ=WEEKLY;BYDAY=WE",
+		"EXDATE:20221019T123000Z",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"TRIGGER:-PT15M",
+		"END:VALARM",
+		"BEGIN:VALARM",
+		"ACTION:DISPLAY",
+		"TRIGGER;RELATED=END:-PT2H30M",
+		"END:VALARM",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:first@example.com",
+		"RECURRENCE-ID:20221026T123000Z",
+		"DTSTART:20221026T130000Z",
+		"DURATION:PT1H",
+		"SUMMARY:Перенесенная планерка",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:second@example.com",
+		"DTSTART;VALUE=DATE:20221014",
+		"SUMMARY:Отпуск",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:broken@example.com",
+		"SUMMARY:Без начала",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	got, err := Decode(strings.NewReader(ics))
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+
+	require.Equal(t, VEvent{
+		UID: "first@example.com",
+		Event: &calendar.Event{
+			UID:                  "first@example.com",
+			Title:                "Планерка",
+			Description:          "длинное описание с переносом",
+			StartAt:              time.Date(2022, 10, 12, 12, 30, 0, 0, time.UTC),
+			EndAt:                time.Date(2022, 10, 12, 14, 30, 0, 0, time.UTC),
+			TimeZone:             "Europe/Moscow",
+			NotificationDuration: 30,
+			RRule:                "FREQ=WEEKLY;BYDAY=WE",
+			ExDates: calendar.ExDates{
+				time.Date(2022, 10, 19, 12, 30, 0, 0, time.UTC),
+				time.Date(2022, 10, 26, 12, 30, 0, 0, time.UTC),
+			},
+		},
+	}, got[0])
+
+	require.Equal(t, VEvent{
+		UID: "first@example.com",
+		Event: &calendar.Event{
+			UID:     "first@example.com",
+			Title:   "Перенесенная планерка",
+			StartAt: time.Date(2022, 10, 26, 13, 0, 0, 0, time.UTC),
+			EndAt:   time.Date(2022, 10, 26, 14, 0, 0, 0, time.UTC),
+		},
+	}, got[1])
+
+	require.Equal(t, VEvent{
+		UID: "second@example.com",
+		Event: &calendar.Event{
+			UID:     "second@example.com",
+			Title:   "Отпуск",
+			StartAt: time.Date(2022, 10, 14, 0, 0, 0, 0, time.UTC),
+			EndAt:   time.Date(2022, 10, 15, 0, 0, 0, 0, time.UTC),
+		},
+	}, got[2])
+
+	require.Equal(t, "broken@example.com", got[3].UID)
+	require.ErrorIs(t, got[3].Err, ErrInvalidCalendar)
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name string
+		ics  string
+	}{
+		{name: "empty", ics: ""},
+		{name: "no vcalendar", ics: "BEGIN:VEVENT\r\nEND:VEVENT\r\n"},
+		{name: "unterminated", ics: "BEGIN:VCALENDAR\r\nBEGIN:VEVENT\r\n"},
+		{name: "mismatched end", ics: "BEGIN:VCALENDAR\r\nEND:VEVENT\r\n"},
+		{name: "invalid line", ics: "BEGIN:VCALENDAR\r\nfoo\r\nEND:VCALENDAR\r\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Decode(strings.NewReader(tt.ics))
+
+			require.ErrorIs(t, err, ErrInvalidCalendar)
+		})
+	}
+}
+
+func TestDecode_WindowsTimeZone(t *testing.T) {
+	t.Parallel()
+
+	// так экспортирует приглашения Outlook: TZID содержит название пояса Windows
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"PRODID:-//Microsoft Corporation//Outlook 16.0 MIMEDIR//EN",
+		"VERSION:2.0",
+		"METHOD:REQUEST",
+		"BEGIN:VTIMEZONE",
+		"TZID:Russian Standard Time",
+		"BEGIN:STANDARD",
+		"DTSTART:16010101T000000",
+		"TZOFFSETFROM:+0300",
+		"TZOFFSETTO:+0300",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:040000008200E00074C5B7101A82E00800000000",
+		"DTSTART;TZID=Russian Standard Time:20221012T153000",
+		"DTEND;TZID=Russian Standard Time:20221012T173000",
+		"RRULE:FREQ=WEEKLY;BYDAY=WE",
+		"EXDATE;TZID=Russian Standard Time:20221019T153000",
+		"SUMMARY;LANGUAGE=ru-RU:Планерка",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	got, err := Decode(strings.NewReader(ics))
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	require.NoError(t, got[0].Err)
+
+	require.Equal(t, &calendar.Event{
+		UID:      "040000008200E00074C5B7101A82E00800000000",
+		Title:    "Планерка",
+		StartAt:  time.Date(2022, 10, 12, 12, 30, 0, 0, time.UTC),
+		EndAt:    time.Date(2022, 10, 12, 14, 30, 0, 0, time.UTC),
+		TimeZone: "Europe/Moscow",
+		RRule:    "FREQ=WEEKLY;BYDAY=WE",
+		ExDates:  calendar.ExDates{time.Date(2022, 10, 19, 12, 30, 0, 0, time.UTC)},
+	}, got[0].Event)
+}
+
+func TestDecode_CustomTimeZone(t *testing.T) {
+	t.Parallel()
+
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VTIMEZONE",
+		"TZID:/mozilla.org/20050126_1/Asia/Yekaterinburg",
+		"BEGIN:STANDARD",
+		"DTSTART:19700101T000000",
+		"TZOFFSETFROM:+0500",
+		"TZOFFSETTO:+0500",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VTIMEZONE",
+		"TZID:Офис",
+		"X-LIC-LOCATION:Asia/Novosibirsk",
+		"END:VTIMEZONE",
+		"BEGIN:VTIMEZONE",
+		"TZID:Смена",
+		"BEGIN:STANDARD",
+		"DTSTART:19700101T000000",
+		"TZOFFSETFROM:+0400",
+		"TZOFFSETTO:+0400",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+		"BEGIN:VEVENT",
+		"UID:mozilla@example.com",
+		"DTSTART;TZID=/mozilla.org/20050126_1/Asia/Yekaterinburg:20221012T153000",
+		"DTEND;TZID=/mozilla.org/20050126_1/Asia/Yekaterinburg:20221012T173000",
+		"SUMMARY:Планерка",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:office@example.com",
+		"DTSTART;TZID=Офис:20221012T153000",
+		"DTEND;TZID=Офис:20221012T173000",
+		"SUMMARY:Обед",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:shift@example.com",
+		"DTSTART;TZID=Смена:20221012T153000",
+		"DTEND;TZID=Смена:20221012T173000",
+		"SUMMARY:Дежурство",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"UID:unknown@example.com",
+		"DTSTART;TZID=Неизвестный:20221012T153000",
+		"SUMMARY:Без пояса",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	got, err := Decode(strings.NewReader(ics))
+	require.NoError(t, err)
+	require.Len(t, got, 4)
+
+	require.NoError(t, got[0].Err)
+	require.Equal(t, "Asia/Yekaterinburg", got[0].Event.TimeZone)
+	require.Equal(t, time.Date(2022, 10, 12, 10, 30, 0, 0, time.UTC), got[0].Event.StartAt)
+
+	require.NoError(t, got[1].Err)
+	require.Equal(t, "Asia/Novosibirsk", got[1].Event.TimeZone)
+	require.Equal(t, time.Date(2022, 10, 12, 8, 30, 0, 0, time.UTC), got[1].Event.StartAt)
+
+	// пояс без названия IANA сохраняется постоянным смещением, а событие - в UTC
+	require.NoError(t, got[2].Err)
+	require.Empty(t, got[2].Event.TimeZone)
+	require.Equal(t, time.Date(2022, 10, 12, 11, 30, 0, 0, time.UTC), got[2].Event.StartAt)
+	require.Equal(t, time.Date(2022, 10, 12, 13, 30, 0, 0, time.UTC), got[2].Event.EndAt)
+
+	require.ErrorIs(t, got[3].Err, ErrInvalidCalendar)
+}
+
+func TestWindowsZones(t *testing.T) {
+	t.Parallel()
+
+	for windows, iana := range windowsZones {
+		_, err := calendar.LoadLocation(iana)
+		require.NoError(t, err, windows)
+	}
+}
+
+func TestEncodeDecode(t *testing.T) {
+	t.Parallel()
+
+	e := &calendar.Event{
+		ID:                   uuid.New(),
+		Title:                "foo, bar; baz\\",
+		Description:          "line\nnext",
+		StartAt:              time.Date(2022, 10, 12, 12, 30, 0, 0, time.UTC),
+		EndAt:                time.Date(2022, 10, 12, 14, 30, 0, 0, time.UTC),
+		NotificationDuration: 25 * 60,
+		RRule:                "FREQ=DAILY;COUNT=3",
+		ExDates:              calendar.ExDates{time.Date(2022, 10, 13, 12, 30, 0, 0, time.UTC)},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, []*calendar.Event{e}))
+
+	got, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+
+	require.Equal(t, e.ID.String(), got[0].UID)
+
+	e.ID, e.UID = uuid.Nil, e.ID.String()
+	require.Equal(t, e, got[0].Event)
+}
+
+func TestEncode_TimeZone(t *testing.T) {
+	t.Parallel()
+
+	e := &calendar.Event{
+		ID:       uuid.New(),
+		Title:    "standup",
+		StartAt:  time.Date(2022, 10, 28, 8, 0, 0, 0, time.UTC),
+		EndAt:    time.Date(2022, 10, 28, 8, 15, 0, 0, time.UTC),
+		TimeZone: "Europe/Berlin",
+		RRule:    "FREQ=DAILY",
+		ExDates:  calendar.ExDates{time.Date(2022, 10, 31, 9, 0, 0, 0, time.UTC)},
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, []*calendar.Event{e}))
+
+	got := buf.String()
+
+	require.Contains(t, got, strings.Join([]string{
+		"BEGIN:VTIMEZONE",
+		"TZID:Europe/Berlin",
+		"BEGIN:DAYLIGHT",
+		"DTSTART:20220327T020000",
+		"RRULE:FREQ=YEARLY;BYMONTH=3;BYDAY=-1SU",
+		"TZOFFSETFROM:+0100",
+		"TZOFFSETTO:+0200",
+		"TZNAME:CEST",
+		"END:DAYLIGHT",
+		"BEGIN:STANDARD",
+		"DTSTART:20221030T030000",
+		"RRULE:FREQ=YEARLY;BYMONTH=10;BYDAY=-1SU",
+		"TZOFFSETFROM:+0200",
+		"TZOFFSETTO:+0100",
+		"TZNAME:CET",
+		"END:STANDARD",
+		"END:VTIMEZONE",
+	}, "\r\n"))
+	require.Contains(t, got, "DTSTART;TZID=Europe/Berlin:20221028T100000\r\n")
+	require.Contains(t, got, "DTEND;TZID=Europe/Berlin:20221028T101500\r\n")
+	require.Contains(t, got, "EXDATE;TZID=Europe/Berlin:20221031T100000\r\n")
+
+	decoded, err := Decode(&buf)
+	require.NoError(t, err)
+	require.Len(t, decoded, 1)
+
+	e.ID, e.UID = uuid.Nil, e.ID.String()
+	require.Equal(t, e, decoded[0].Event)
+}
+
+func TestEncode_TimeZoneWithoutDST(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	require.NoError(t, Encode(&buf, []*calendar.Event{{
+		StartAt:  time.Date(2022, 10, 12, 12, 30, 0, 0, time.UTC),
+		EndAt:    time.Date(2022, 10, 12, 13, 30, 0, 0, time.UTC),
+		TimeZone: "Europe/Moscow",
+	}}))
+
+	got := buf.String()
+
+	require.Contains(t, got, "BEGIN:STANDARD\r\nDTSTART:19700101T000000\r\nTZOFFSETFROM:+0300\r\nTZOFFSETTO:+0300\r\n")
+	require.Contains(t, got, "DTSTART;TZID=Europe/Moscow:20221012T153000\r\n")
+}
+
+func TestParseDuration(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		value   string
+		want    time.Duration
+		wantErr bool
+	}{
+		{value: "PT15M", want: 15 * time.Minute},
+		{value: "-PT1H30M", want: -90 * time.Minute},
+		{value: "+P1D", want: 24 * time.Hour},
+		{value: "P1DT2H3M4S", want: 26*time.Hour + 3*time.Minute + 4*time.Second},
+		{value: "-P2W", want: -14 * 24 * time.Hour},
+		{value: "PT", wantErr: true},
+		{value: "P1H", wantErr: true},
+		{value: "PT5", wantErr: true},
+		{value: "15M", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseDuration(tt.value)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidCalendar)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	t.Parallel()
+
+	require.Equal(t, "PT0S", formatDuration(0))
+	require.Equal(t, "PT30M", formatDuration(30*time.Minute))
+	require.Equal(t, "P1DT1H", formatDuration(25*time.Hour))
+	require.Equal(t, "P2D", formatDuration(48*time.Hour))
+}
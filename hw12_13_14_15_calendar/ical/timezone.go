@@ -0,0 +1,305 @@
+package ical
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// weekdays обозначения дней недели в RFC 5545.
+var weekdays = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+// transition переход часового пояса на другое смещение.
+type transition struct {
+	// at момент перехода.
+	at time.Time
+
+	// from смещение до перехода в секундах.
+	from int
+
+	// to смещение после перехода в секундах.
+	to int
+
+	// name сокращенное название пояса после перехода.
+	name string
+
+	// dst является ли время после перехода летним.
+	dst bool
+}
+
+// writeTimeZones записывает компоненты VTIMEZONE для часовых поясов событий.
+// Правила переходов вычисляются по году самого раннего события в поясе.
+func writeTimeZones(lw *lineWriter, years map[string]int) {
+	names := make([]string, 0, len(years))
+	for name := range years {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		loc, err := time.LoadLocation(name)
+		if err != nil {
+			continue
+		}
+
+		writeTimeZone(lw, name, loc, years[name])
+	}
+}
+
+// writeTimeZone записывает компонент VTIMEZONE.
+// Переходы года year описываются ежегодными правилами (например, последнее воскресенье марта).
+func writeTimeZone(lw *lineWriter, name string, loc *time.Location, year int) {
+	lw.line("BEGIN:VTIMEZONE")
+	lw.line("TZID:" + name)
+
+	transitions := yearTransitions(loc, year)
+
+	if len(transitions) == 0 {
+		abbr, offset := time.Date(year, 1, 1, 0, 0, 0, 0, loc).Zone()
+
+		lw.line("BEGIN:STANDARD")
+		lw.line("DTSTART:19700101T000000")
+		lw.line("TZOFFSETFROM:" + formatOffset(offset))
+		lw.line("TZOFFSETTO:" + formatOffset(offset))
+		lw.line("TZNAME:" + abbr)
+		lw.line("END:STANDARD")
+	}
+
+	for _, t := range transitions {
+		kind := "STANDARD"
+		if t.dst {
+			kind = "DAYLIGHT"
+		}
+
+		// время начала указывается по смещению до перехода
+		local := t.at.In(time.FixedZone("", t.from))
+
+		lw.line("BEGIN:" + kind)
+		lw.line("DTSTART:" + local.Format(localDateTimeLayout))
+		lw.line("RRULE:FREQ=YEARLY;BYMONTH=" + strconv.Itoa(int(local.Month())) + ";BYDAY=" + byDay(local))
+		lw.line("TZOFFSETFROM:" + formatOffset(t.from))
+		lw.line("TZOFFSETTO:" + formatOffset(t.to))
+		lw.line("TZNAME:" + t.name)
+		lw.line("END:" + kind)
+	}
+
+	lw.line("END:VTIMEZONE")
+}
+
+// yearTransitions возвращает переходы часового пояса в течение года.
+func yearTransitions(loc *time.Location, year int) []transition {
+	res := make([]transition, 0, 2)
+
+	day := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	end := day.AddDate(1, 0, 0)
+
+	for ; day.Before(end); day = day.AddDate(0, 0, 1) {
+		next := day.AddDate(0, 0, 1)
+
+		_, from := day.In(loc).Zone()
+		_, to := next.In(loc).Zone()
+
+		if from == to {
+			continue
+		}
+
+		// ищем момент перехода с точностью до секунды
+		lo, hi := day, next
+		for hi.Sub(lo) > time.Second {
+			mid := lo.Add(hi.Sub(lo) / 2)
+
+			if _, offset := mid.In(loc).Zone(); offset == from {
+				lo = mid
+			} else {
+				hi = mid
+			}
+		}
+
+		after := hi.In(loc)
+		name, _ := after.Zone()
+
+		res = append(res, transition{at: hi, from: from, to: to, name: name, dst: after.IsDST()})
+	}
+
+	return res
+}
+
+// byDay возвращает значение BYDAY для дня недели даты внутри месяца (например, 2SU или -1SU).
+func byDay(t time.Time) string {
+	n := (t.Day()-1)/7 + 1
+
+	lastDay := time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+	if t.Day()+7 > lastDay {
+		n = -1
+	}
+
+	return strconv.Itoa(n) + weekdays[t.Weekday()]
+}
+
+// formatOffset форматирует смещение от UTC (например, +0300).
+func formatOffset(seconds int) string {
+	sign := "+"
+	if seconds < 0 {
+		sign, seconds = "-", -seconds
+	}
+
+	res := fmt.Sprintf("%s%02d%02d", sign, seconds/3600, seconds%3600/60)
+
+	if s := seconds % 60; s != 0 {
+		res += fmt.Sprintf("%02d", s)
+	}
+
+	return res
+}
+
+// timeZone часовой пояс, на который ссылается параметр TZID.
+type timeZone struct {
+	// name название пояса IANA.
+	// Пусто, если пояс описан в VTIMEZONE только постоянным смещением.
+	name string
+
+	loc *time.Location
+}
+
+// timeZones часовые пояса календаря по значению TZID.
+type timeZones map[string]timeZone
+
+// decodeTimeZones разбирает компоненты VTIMEZONE календаря.
+// Пояса, которые не удалось сопоставить с IANA, пропускаются:
+// ошибка возвращается при разборе события, которое на них ссылается.
+func decodeTimeZones(root *component) timeZones {
+	res := make(timeZones)
+
+	for _, c := range root.components {
+		if c.name != "VTIMEZONE" {
+			continue
+		}
+
+		p, ok := c.prop("TZID")
+		if !ok {
+			continue
+		}
+
+		if tz, ok := decodeTimeZone(p.value, c); ok {
+			res[p.value] = tz
+		}
+	}
+
+	return res
+}
+
+// decodeTimeZone определяет часовой пояс компонента VTIMEZONE.
+// Сначала пояс ищется по названию, затем по свойству X-LIC-LOCATION.
+// Пояс без перехода на летнее время заменяется постоянным смещением.
+func decodeTimeZone(tzid string, c *component) (timeZone, bool) {
+	if tz, ok := lookupTimeZone(tzid); ok {
+		return tz, true
+	}
+
+	if p, ok := c.prop("X-LIC-LOCATION"); ok {
+		if tz, ok := lookupTimeZone(p.value); ok {
+			return tz, true
+		}
+	}
+
+	var standard *component
+
+	for _, sub := range c.components {
+		switch sub.name {
+		case "DAYLIGHT":
+			return timeZone{}, false
+		case "STANDARD":
+			standard = sub
+		}
+	}
+
+	if standard == nil {
+		return timeZone{}, false
+	}
+
+	p, ok := standard.prop("TZOFFSETTO")
+	if !ok {
+		return timeZone{}, false
+	}
+
+	offset, err := parseOffset(p.value)
+	if err != nil {
+		return timeZone{}, false
+	}
+
+	return timeZone{loc: time.FixedZone(tzid, offset)}, true
+}
+
+// lookupTimeZone ищет пояс IANA по названию пояса IANA или Windows.
+// Для идентификаторов с префиксом (например, /mozilla.org/20050126_1/Europe/Moscow)
+// проверяются окончания пути.
+func lookupTimeZone(name string) (timeZone, bool) {
+	if iana, ok := windowsZones[name]; ok {
+		name = iana
+	}
+
+	for name != "" {
+		if loc, err := calendar.LoadLocation(name); err == nil {
+			return timeZone{name: name, loc: loc}, true
+		}
+
+		i := strings.Index(name, "/")
+		if i < 0 {
+			break
+		}
+
+		name = name[i+1:]
+	}
+
+	return timeZone{}, false
+}
+
+// resolve возвращает часовой пояс по значению TZID.
+func (z timeZones) resolve(tzid string) (timeZone, error) {
+	if tz, ok := z[tzid]; ok {
+		return tz, nil
+	}
+
+	if tz, ok := lookupTimeZone(tzid); ok {
+		return tz, nil
+	}
+
+	return timeZone{}, errors.Wrapf(ErrInvalidCalendar, "unknown time zone `%s`", tzid)
+}
+
+// parseOffset разбирает смещение от UTC (например, +0300 или -053000) в секундах.
+func parseOffset(s string) (int, error) {
+	invalid := errors.Wrapf(ErrInvalidCalendar, "invalid offset `%s`", s)
+
+	if (len(s) != 5 && len(s) != 7) || (s[0] != '+' && s[0] != '-') {
+		return 0, invalid
+	}
+
+	var res int
+
+	for i, unit := range []int{3600, 60, 1} {
+		if 1+i*2 >= len(s) {
+			break
+		}
+
+		n, err := strconv.Atoi(s[1+i*2 : 3+i*2])
+		if err != nil || n < 0 {
+			return 0, invalid
+		}
+
+		res += n * unit
+	}
+
+	if s[0] == '-' {
+		res = -res
+	}
+
+	return res, nil
+}
@@ -0,0 +1,106 @@
+package inmem
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// InviteAttendee приглашает пользователя на событие.
+// Повторное приглашение не меняет ответ участника.
+func (repo *Repository) InviteAttendee(ctx context.Context, eventID, userID uuid.UUID) (*calendar.Attendee, error) {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	e, exists := repo.events[eventID]
+	if !exists {
+		return nil, errors.Wrap(calendar.ErrNotFound, "invite attendee")
+	}
+
+	if e.UserID == userID {
+		return nil, errors.Wrap(calendar.ErrInvalidAttendee, "invite attendee")
+	}
+
+	if a := e.Attendee(userID); a != nil {
+		res := *a
+
+		return &res, nil
+	}
+
+	a := calendar.Attendee{
+		EventID: eventID,
+		UserID:  userID,
+		Status:  calendar.RSVPNeedsAction,
+	}
+
+	e.Attendees = append(e.Attendees, a)
+	repo.addAttendeeRevision(ctx, calendar.RevisionAttendeeInvited, e, nil, &a)
+	repo.publishChange(calendar.ChangeUpdated, e)
+
+	return &a, nil
+}
+
+// RemoveAttendee удаляет участника события.
+func (repo *Repository) RemoveAttendee(ctx context.Context, eventID, userID uuid.UUID) error {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	e, exists := repo.events[eventID]
+	if !exists {
+		return errors.Wrap(calendar.ErrNotFound, "remove attendee")
+	}
+
+	for i, a := range e.Attendees {
+		if a.UserID == userID {
+			removed := a
+
+			e.Attendees = append(e.Attendees[:i:i], e.Attendees[i+1:]...)
+			repo.addAttendeeRevision(ctx, calendar.RevisionAttendeeRemoved, e, &removed, nil)
+			repo.publishChange(calendar.ChangeUpdated, e, userID)
+
+			return nil
+		}
+	}
+
+	return errors.Wrap(calendar.ErrNotFound, "remove attendee")
+}
+
+// RespondAttendee сохраняет ответ участника на приглашение.
+// Принять приглашение можно только если время события у участника свободно.
+func (repo *Repository) RespondAttendee(
+	ctx context.Context,
+	eventID, userID uuid.UUID,
+	status calendar.RSVPStatus,
+) (*calendar.Attendee, error) {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	e, exists := repo.events[eventID]
+	if !exists {
+		return nil, errors.Wrap(calendar.ErrNotFound, "respond attendee")
+	}
+
+	a := e.Attendee(userID)
+	if a == nil {
+		return nil, errors.Wrap(calendar.ErrNotFound, "respond attendee")
+	}
+
+	if status == calendar.RSVPAccepted {
+		if err := repo.checkDateBusy(e, userID, eventID); err != nil {
+			return nil, errors.Wrap(err, "respond attendee")
+		}
+	}
+
+	before := *a
+	a.Status = status
+
+	res := *a
+
+	repo.addAttendeeRevision(ctx, calendar.RevisionAttendeeResponded, e, &before, &res)
+	repo.publishChange(calendar.ChangeUpdated, e)
+
+	return &res, nil
+}
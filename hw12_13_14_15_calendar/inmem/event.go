@@ -13,53 +13,172 @@ import (
 var timeNowFunc = time.Now
 
 // CreateEvent создает событие.
+// Проверка занятости и сохранение выполняются под одной блокировкой,
+// поэтому одновременно созданные события не могут пересечься.
 func (repo *Repository) CreateEvent(ctx context.Context, e *calendar.Event) (*calendar.Event, error) {
-	if err := repo.checkDateBusy(e); err != nil {
+	if err := e.Validate(); err != nil {
 		return nil, errors.Wrap(err, "create event")
 	}
 
-	e.ID = uuid.New()
-
 	repo.eventMu.Lock()
 	defer repo.eventMu.Unlock()
 
-	repo.events[e.ID] = e
+	if err := repo.checkDateBusy(e, e.UserID, uuid.Nil); err != nil {
+		return nil, errors.Wrap(err, "create event")
+	}
+
+	stored := copyEvent(e)
+	stored.ID = uuid.New()
+	stored.Version = 1
+
+	repo.events[stored.ID] = stored
+	repo.addRevision(ctx, calendar.RevisionCreated, nil, stored)
+	repo.publishChange(calendar.ChangeCreated, stored)
 
-	return e, nil
+	return copyEvent(stored), nil
 }
 
 // UpdateEvent обновляет событие.
+// Проверка занятости и сохранение выполняются под одной блокировкой.
 func (repo *Repository) UpdateEvent(ctx context.Context, id uuid.UUID, e *calendar.Event) (*calendar.Event, error) {
-	if _, err := repo.findEventByID(id); err != nil {
+	if err := e.Validate(); err != nil {
 		return nil, errors.Wrap(err, "update event")
 	}
 
-	if err := repo.checkDateBusy(e, id); err != nil {
-		return nil, errors.Wrap(err, "update event")
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	stored, exists := repo.events[id]
+	if !exists {
+		return nil, errors.Wrap(calendar.ErrNotFound, "update event")
 	}
 
+	if e.Version != stored.Version {
+		return nil, errors.Wrap(calendar.ErrVersionConflict, "update event")
+	}
+
+	updated := copyEvent(e)
+	updated.ID = id
+
+	// время должно быть свободно у владельца и у всех принявших приглашение участников
+	for _, userID := range append([]uuid.UUID{updated.UserID}, stored.AcceptedAttendees()...) {
+		if err := repo.checkDateBusy(updated, userID, id); err != nil {
+			return nil, errors.Wrap(err, "update event")
+		}
+	}
+
+	updated.UID = stored.UID
+	updated.KeepNotification(stored)
+	updated.Attendees = append([]calendar.Attendee(nil), stored.Attendees...)
+	updated.Version = stored.Version + 1
+	repo.events[id] = updated
+	repo.addRevision(ctx, calendar.RevisionUpdated, stored, updated)
+	repo.publishChange(calendar.ChangeUpdated, updated)
+
+	return copyEvent(updated), nil
+}
+
+// PatchEvent обновляет только поля fields события.
+// Проверка занятости и сохранение выполняются под одной блокировкой.
+func (repo *Repository) PatchEvent(
+	ctx context.Context,
+	id uuid.UUID,
+	e *calendar.Event,
+	fields []calendar.EventField,
+) (*calendar.Event, error) {
 	repo.eventMu.Lock()
 	defer repo.eventMu.Unlock()
 
-	repo.events[id] = e
-	repo.events[id].ID = id
+	stored, exists := repo.events[id]
+	if !exists {
+		return nil, errors.Wrap(calendar.ErrNotFound, "patch event")
+	}
+
+	if e.Version != stored.Version {
+		return nil, errors.Wrap(calendar.ErrVersionConflict, "patch event")
+	}
+
+	patched := copyEvent(stored)
+	if _, err := patched.Patch(e, fields); err != nil {
+		return nil, errors.Wrap(err, "patch event")
+	}
+
+	if err := patched.Validate(); err != nil {
+		return nil, errors.Wrap(err, "patch event")
+	}
+
+	// время должно быть свободно у владельца и у всех принявших приглашение участников
+	for _, userID := range append([]uuid.UUID{patched.UserID}, stored.AcceptedAttendees()...) {
+		if err := repo.checkDateBusy(patched, userID, id); err != nil {
+			return nil, errors.Wrap(err, "patch event")
+		}
+	}
+
+	patched.Version = stored.Version + 1
+	repo.events[id] = patched
+	repo.addRevision(ctx, calendar.RevisionUpdated, stored, patched)
+	repo.publishChange(calendar.ChangeUpdated, patched)
 
-	return e, nil
+	return copyEvent(patched), nil
 }
 
-// DeleteEvent удаляет событие.
+// DeleteEvent перемещает событие в корзину.
 func (repo *Repository) DeleteEvent(ctx context.Context, ids ...uuid.UUID) error {
 	repo.eventMu.Lock()
 	defer repo.eventMu.Unlock()
 
+	now := timeNowFunc()
+
 	for _, id := range ids {
+		e, exists := repo.events[id]
+		if !exists {
+			continue
+		}
+
+		deletedAt := now
+		e.DeletedAt = &deletedAt
+
 		delete(repo.events, id)
+		repo.trash[id] = e
+		repo.deleteEventNotifications(id)
+		repo.addRevision(ctx, calendar.RevisionDeleted, e, nil)
+		repo.publishChange(calendar.ChangeDeleted, e)
+	}
+
+	return nil
+}
+
+// MarkEventNotified отмечает, что уведомление о событии, начинающемся в startAt, выслано.
+// Для повторяющегося события отметка ставится только если вхождение позже уже отмеченного.
+func (repo *Repository) MarkEventNotified(ctx context.Context, id uuid.UUID, startAt time.Time) error {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	e, exists := repo.events[id]
+	if !exists {
+		return errors.Wrap(calendar.ErrNotFound, "mark event notified")
 	}
 
+	markEventNotified(e, startAt)
+
 	return nil
 }
 
+// markEventNotified ставит отметку об уведомлении. Вызывается под блокировкой.
+func markEventNotified(e *calendar.Event, startAt time.Time) {
+	if !e.IsRecurring() {
+		e.IsNotified = true
+
+		return
+	}
+
+	if e.NotifiedUntil == nil || startAt.After(*e.NotifiedUntil) {
+		e.NotifiedUntil = &startAt
+	}
+}
+
 // FindEvents находит события по критериям.
+// События сортируются согласно filter.OrderBy.
 func (repo *Repository) FindEvents(ctx context.Context, filter calendar.EventFilter) ([]*calendar.Event, error) {
 	repo.eventMu.Lock()
 	defer repo.eventMu.Unlock()
@@ -67,14 +186,26 @@ func (repo *Repository) FindEvents(ctx context.Context, filter calendar.EventFil
 	res := make([]*calendar.Event, 0)
 
 	for _, e := range repo.events {
+		if e.IsRecurring() {
+			occurrences, err := calendar.ExpandEvent(e, filter, timeNowFunc())
+			if err != nil {
+				return nil, errors.Wrap(err, "find events")
+			}
+
+			// вхождения разделяют с серией участников и исключенные даты
+			res = append(res, copyEvents(occurrences)...)
+
+			continue
+		}
+
 		if !passFilter(e, filter) {
 			continue
 		}
 
-		res = append(res, e)
+		res = append(res, copyEvent(e))
 	}
 
-	return res, nil
+	return calendar.PageEvents(res, filter), nil
 }
 
 // FindEventByID находит событие по ID.
@@ -97,12 +228,16 @@ func (repo *Repository) findEventByID(id uuid.UUID) (*calendar.Event, error) {
 		return nil, calendar.ErrNotFound
 	}
 
-	return event, nil
+	return copyEvent(event), nil
 }
 
 // passFilter проверяет событие на удовлетворенность условиям фильтра.
 func passFilter(e *calendar.Event, filter calendar.EventFilter) bool {
-	if filter.UserID != uuid.Nil && e.UserID != filter.UserID {
+	if !filter.MatchUser(e) {
+		return false
+	}
+
+	if filter.UID != "" && e.UID != filter.UID {
 		return false
 	}
 
@@ -124,46 +259,22 @@ func passFilter(e *calendar.Event, filter calendar.EventFilter) bool {
 		}
 	}
 
-	if !filter.From.IsZero() && e.StartAt.Before(filter.From) {
-		return false
-	}
-
-	if !filter.To.IsZero() && e.EndAt.After(filter.To) {
-		return false
-	}
-
-	return true
+	return calendar.InRange(e, filter)
 }
 
-// checkDateBusy проверка на свободное время.
+// checkDateBusy проверка на свободное время пользователя userID:
+// учитываются его события и события, приглашение на которые он принял.
+// Событие с идентификатором ignore не учитывается.
 // Если время занято, то вернет ошибку calendar.ErrDateBusy.
-func (repo *Repository) checkDateBusy(event *calendar.Event, ID ...uuid.UUID) error {
-	var ignore uuid.UUID
-	if len(ID) > 0 {
-		ignore = ID[0]
-	}
-
-	repo.eventMu.Lock()
-	defer repo.eventMu.Unlock()
+// Вызывается под блокировкой вместе с сохранением изменения.
+func (repo *Repository) checkDateBusy(event *calendar.Event, userID, ignore uuid.UUID) error {
+	events := make([]*calendar.Event, 0)
 
 	for _, e := range repo.events {
-		if e.ID == ignore {
-			continue
-		}
-
-		if e.UserID != event.UserID {
-			continue
-		}
-
-		if (e.StartAt.After(event.StartAt) || e.StartAt.Equal(event.StartAt)) &&
-			e.StartAt.Before(event.EndAt) {
-			return calendar.ErrDateBusy
-		}
-
-		if e.EndAt.After(event.StartAt) && (e.EndAt.Before(event.EndAt) || e.EndAt.Equal(event.EndAt)) {
-			return calendar.ErrDateBusy
+		if e.UserID == userID || e.HasAccepted(userID) {
+			events = append(events, e)
 		}
 	}
 
-	return nil
+	return calendar.CheckDateBusy(events, event, ignore)
 }
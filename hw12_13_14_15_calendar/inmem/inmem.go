@@ -2,6 +2,7 @@ package inmem
 
 import (
 	"sync"
+	"time"
 
 	"github.com/google/uuid"
 
@@ -16,13 +17,83 @@ type eventsMap map[uuid.UUID]*calendar.Event
 
 // Repository реализует in-memory хранилище.
 type Repository struct {
+	// eventMu защищает и события, и исходящие уведомления,
+	// так как захват уведомлений меняет их вместе.
 	eventMu sync.Mutex
 	events  eventsMap
+
+	// trash события, перемещенные в корзину.
+	trash eventsMap
+
+	// revisions журнал изменений событий.
+	revisions map[uuid.UUID][]*calendar.Revision
+
+	outbox     map[uuid.UUID]*outboxEntry
+	outboxKeys map[notificationKey]uuid.UUID
+	outboxSeq  uint64
+
+	// changes получатель изменений событий.
+	changes calendar.ChangePublisher
+}
+
+// copyEvent возвращает копию события, не разделяющую с ним участников, исключенные даты и отметки времени.
+// Хранилище сохраняет и возвращает только копии: сохраненные события меняются под eventMu,
+// а переданные вызывающему коду читаются без блокировки.
+func copyEvent(e *calendar.Event) *calendar.Event {
+	res := *e
+	res.Attendees = append([]calendar.Attendee(nil), e.Attendees...)
+	res.ExDates = append(calendar.ExDates(nil), e.ExDates...)
+	res.NotifiedUntil = copyTime(e.NotifiedUntil)
+	res.DeletedAt = copyTime(e.DeletedAt)
+	res.RecurrenceID = copyTime(e.RecurrenceID)
+
+	return &res
+}
+
+// copyEvents возвращает копии событий.
+func copyEvents(events []*calendar.Event) []*calendar.Event {
+	res := make([]*calendar.Event, 0, len(events))
+
+	for _, e := range events {
+		res = append(res, copyEvent(e))
+	}
+
+	return res
+}
+
+func copyTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+
+	res := *t
+
+	return &res
 }
 
 // New создает in-memory хранилище.
 func New() *Repository {
 	return &Repository{
-		events: make(eventsMap),
+		events:     make(eventsMap),
+		trash:      make(eventsMap),
+		revisions:  make(map[uuid.UUID][]*calendar.Revision),
+		outbox:     make(map[uuid.UUID]*outboxEntry),
+		outboxKeys: make(map[notificationKey]uuid.UUID),
 	}
 }
+
+// SetChangePublisher задает получателя изменений событий.
+// Устанавливается до начала работы с хранилищем.
+func (repo *Repository) SetChangePublisher(p calendar.ChangePublisher) {
+	repo.changes = p
+}
+
+// publishChange публикует изменение события, если задан получатель.
+// Вызывается под блокировкой, чтобы порядок ревизий совпадал с порядком изменений.
+func (repo *Repository) publishChange(t calendar.ChangeType, e *calendar.Event, extra ...uuid.UUID) {
+	if repo.changes == nil {
+		return
+	}
+
+	repo.changes.PublishChange(calendar.NewChange(t, e, extra...))
+}
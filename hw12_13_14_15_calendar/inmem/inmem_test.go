@@ -2,10 +2,24 @@ package inmem
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/repotest"
 )
 
 func TestKey(t *testing.T) {
 	require.Equal(t, "inmemory", Key)
 }
+
+func TestRepository(t *testing.T) {
+	repotest.Run(t, repotest.Driver{
+		New: func(t *testing.T) repotest.Storage {
+			return New()
+		},
+		SetNow: func(now func() time.Time) {
+			timeNowFunc = now
+		},
+	})
+}
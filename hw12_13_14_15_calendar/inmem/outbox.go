@@ -0,0 +1,155 @@
+package inmem
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// outboxEntry исходящее уведомление.
+type outboxEntry struct {
+	n calendar.Notification
+
+	// seq порядковый номер, задает порядок публикации.
+	seq uint64
+
+	published bool
+	delivered bool
+}
+
+// notificationKey однозначно определяет напоминание: пользователь, событие и его вхождение.
+type notificationKey struct {
+	eventID uuid.UUID
+	startAt int64
+	userID  uuid.UUID
+}
+
+// newNotificationKey возвращает ключ напоминания.
+func newNotificationKey(n *calendar.Notification) notificationKey {
+	return notificationKey{
+		eventID: n.EventID,
+		startAt: n.EventStartAt.UnixNano(),
+		userID:  n.UserID,
+	}
+}
+
+// ClaimNotifications сохраняет уведомления о наступивших событиях в исходящий ящик
+// и отмечает события уведомленными. Уже сохраненные напоминания повторно не добавляются.
+func (repo *Repository) ClaimNotifications(ctx context.Context, events ...*calendar.Event) error {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	for _, e := range events {
+		// событие могло быть удалено после выборки
+		stored, exists := repo.events[e.ID]
+		if !exists {
+			continue
+		}
+
+		for _, n := range calendar.NewNotifications(e) {
+			key := newNotificationKey(n)
+
+			if _, exists := repo.outboxKeys[key]; exists {
+				continue
+			}
+
+			repo.outboxSeq++
+			repo.outbox[n.ID] = &outboxEntry{n: *n, seq: repo.outboxSeq}
+			repo.outboxKeys[key] = n.ID
+		}
+
+		markEventNotified(stored, e.StartAt)
+	}
+
+	return nil
+}
+
+// FindPendingNotifications возвращает до limit неопубликованных уведомлений в порядке их сохранения.
+func (repo *Repository) FindPendingNotifications(ctx context.Context, limit int) ([]*calendar.Notification, error) {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	pending := make([]*outboxEntry, 0)
+
+	for _, entry := range repo.outbox {
+		if !entry.published {
+			pending = append(pending, entry)
+		}
+	}
+
+	sort.Slice(pending, func(i, j int) bool {
+		return pending[i].seq < pending[j].seq
+	})
+
+	if len(pending) > limit {
+		pending = pending[:limit]
+	}
+
+	res := make([]*calendar.Notification, 0, len(pending))
+
+	for _, entry := range pending {
+		n := entry.n
+		res = append(res, &n)
+	}
+
+	return res, nil
+}
+
+// MarkNotificationsPublished отмечает уведомления опубликованными в очередь.
+func (repo *Repository) MarkNotificationsPublished(ctx context.Context, ids ...uuid.UUID) error {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	for _, id := range ids {
+		// уведомление могло быть удалено вместе с событием
+		if entry, exists := repo.outbox[id]; exists {
+			entry.published = true
+		}
+	}
+
+	return nil
+}
+
+// IsNotificationDelivered проверяет, доставлено ли уведомление.
+func (repo *Repository) IsNotificationDelivered(ctx context.Context, id uuid.UUID) (bool, error) {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	entry, exists := repo.outbox[id]
+	if !exists {
+		return false, errors.Wrap(calendar.ErrNotFound, "is notification delivered")
+	}
+
+	return entry.delivered, nil
+}
+
+// AckNotification подтверждает доставку уведомления.
+func (repo *Repository) AckNotification(ctx context.Context, id uuid.UUID) error {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	entry, exists := repo.outbox[id]
+	if !exists {
+		return errors.Wrap(calendar.ErrNotFound, "ack notification")
+	}
+
+	entry.delivered = true
+
+	return nil
+}
+
+// deleteEventNotifications удаляет уведомления о событии. Вызывается под блокировкой.
+func (repo *Repository) deleteEventNotifications(eventID uuid.UUID) {
+	for id, entry := range repo.outbox {
+		if entry.n.EventID != eventID {
+			continue
+		}
+
+		delete(repo.outbox, id)
+		delete(repo.outboxKeys, newNotificationKey(&entry.n))
+	}
+}
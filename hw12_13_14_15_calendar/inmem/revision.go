@@ -0,0 +1,51 @@
+package inmem
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+)
+
+// FindEventRevisions находит журнал изменений события, от старых записей к новым.
+func (repo *Repository) FindEventRevisions(ctx context.Context, eventID uuid.UUID) ([]*calendar.Revision, error) {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	return append(make([]*calendar.Revision, 0), repo.revisions[eventID]...), nil
+}
+
+// addRevision добавляет запись в журнал изменений события.
+// Автором изменения считается пользователь из контекста запроса.
+// Вызывается под блокировкой вместе с сохранением изменения.
+func (repo *Repository) addRevision(
+	ctx context.Context,
+	action calendar.RevisionAction,
+	before, after *calendar.Event,
+) {
+	actorID, _ := auth.UserIDFromContext(ctx)
+
+	repo.saveRevision(calendar.NewRevision(action, actorID, before, after))
+}
+
+// addAttendeeRevision добавляет в журнал изменений запись об изменении участника события e.
+// Вызывается под блокировкой вместе с сохранением изменения.
+func (repo *Repository) addAttendeeRevision(
+	ctx context.Context,
+	action calendar.RevisionAction,
+	e *calendar.Event,
+	before, after *calendar.Attendee,
+) {
+	actorID, _ := auth.UserIDFromContext(ctx)
+
+	repo.saveRevision(calendar.NewAttendeeRevision(action, actorID, e, before, after))
+}
+
+// saveRevision сохраняет запись журнала изменений.
+func (repo *Repository) saveRevision(r *calendar.Revision) {
+	r.CreatedAt = timeNowFunc().UTC()
+
+	repo.revisions[r.EventID] = append(repo.revisions[r.EventID], r)
+}
@@ -0,0 +1,87 @@
+package inmem
+
+import (
+	"context"
+	"sort"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// FindTrash находит события в корзине. Сначала идут удаленные последними.
+func (repo *Repository) FindTrash(ctx context.Context, filter calendar.TrashFilter) ([]*calendar.Event, error) {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	res := make([]*calendar.Event, 0)
+
+	for _, e := range repo.trash {
+		if filter.ID != uuid.Nil && e.ID != filter.ID {
+			continue
+		}
+
+		if filter.UserID != uuid.Nil && e.UserID != filter.UserID {
+			continue
+		}
+
+		if !filter.DeletedBefore.IsZero() && !e.DeletedAt.Before(filter.DeletedBefore) {
+			continue
+		}
+
+		res = append(res, copyEvent(e))
+	}
+
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].DeletedAt.After(*res[j].DeletedAt)
+	})
+
+	return res, nil
+}
+
+// RestoreEvent восстанавливает событие из корзины.
+// Время события должно быть свободно у владельца и у принявших приглашение участников.
+func (repo *Repository) RestoreEvent(ctx context.Context, id uuid.UUID) (*calendar.Event, error) {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	e, exists := repo.trash[id]
+	if !exists {
+		return nil, errors.Wrap(calendar.ErrNotFound, "restore event")
+	}
+
+	for _, userID := range append([]uuid.UUID{e.UserID}, e.AcceptedAttendees()...) {
+		if err := repo.checkDateBusy(e, userID, id); err != nil {
+			return nil, errors.Wrap(err, "restore event")
+		}
+	}
+
+	e.DeletedAt = nil
+
+	delete(repo.trash, id)
+	repo.events[id] = e
+	repo.addRevision(ctx, calendar.RevisionRestored, e, e)
+	repo.publishChange(calendar.ChangeCreated, e)
+
+	return copyEvent(e), nil
+}
+
+// PurgeEvent окончательно удаляет события из корзины.
+func (repo *Repository) PurgeEvent(ctx context.Context, ids ...uuid.UUID) error {
+	repo.eventMu.Lock()
+	defer repo.eventMu.Unlock()
+
+	for _, id := range ids {
+		e, exists := repo.trash[id]
+		if !exists {
+			continue
+		}
+
+		delete(repo.trash, id)
+		repo.addRevision(ctx, calendar.RevisionPurged, e, nil)
+		repo.publishChange(calendar.ChangePurged, e)
+	}
+
+	return nil
+}
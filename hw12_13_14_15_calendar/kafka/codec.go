@@ -0,0 +1,160 @@
+package kafka
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	json "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	kafka "github.com/segmentio/kafka-go"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/notification"
+)
+
+const (
+	// contentTypeHeader заголовок с форматом тела сообщения.
+	contentTypeHeader = "content-type"
+
+	// schemaVersionHeader заголовок с версией схемы тела сообщения.
+	schemaVersionHeader = "schema-version"
+
+	// contentTypeProtobuf тело сообщения в формате protobuf.
+	contentTypeProtobuf = "application/x-protobuf"
+
+	// notificationSchemaV1 версия схемы notification.NotificationV1.
+	notificationSchemaV1 = "1"
+)
+
+// ErrUnsupportedSchema сообщение в неизвестном формате или неизвестной версии схемы.
+var ErrUnsupportedSchema = errors.New("unsupported message schema")
+
+// encodeNotification формирует сообщение с уведомлением в формате notification.NotificationV1.
+func encodeNotification(n *calendar.Notification) (kafka.Message, error) {
+	bs, err := proto.Marshal(&notification.NotificationV1{
+		Id:           n.ID.String(),
+		EventId:      n.EventID.String(),
+		EventTitle:   n.EventTitle,
+		EventStartAt: n.EventStartAt.Unix(),
+		UserId:       n.UserID.String(),
+		Attempts:     int32(n.Attempts),
+
+		DeliveredChannels: n.DeliveredChannels,
+	})
+	if err != nil {
+		return kafka.Message{}, errors.Wrap(err, "encode notification")
+	}
+
+	return kafka.Message{
+		Value: bs,
+		Headers: []kafka.Header{
+			{Key: contentTypeHeader, Value: []byte(contentTypeProtobuf)},
+			{Key: schemaVersionHeader, Value: []byte(notificationSchemaV1)},
+		},
+	}, nil
+}
+
+// decodeNotification разбирает уведомление из сообщения.
+// Сообщения без заголовка формата записаны прежними версиями в JSON
+// и поддерживаются на время перехода на protobuf.
+func decodeNotification(msg kafka.Message) (*calendar.Notification, error) {
+	contentType, ok := header(msg, contentTypeHeader)
+	if !ok {
+		return decodeLegacyNotification(msg)
+	}
+
+	if version, _ := header(msg, schemaVersionHeader); contentType != contentTypeProtobuf || version != notificationSchemaV1 {
+		return nil, errors.Wrapf(ErrUnsupportedSchema, "decode notification: %s version %s", contentType, version)
+	}
+
+	m := new(notification.NotificationV1)
+	if err := proto.Unmarshal(msg.Value, m); err != nil {
+		return nil, errors.Wrap(err, "decode notification")
+	}
+
+	n := &calendar.Notification{
+		EventTitle:   m.GetEventTitle(),
+		EventStartAt: time.Unix(m.GetEventStartAt(), 0),
+		Attempts:     int(m.GetAttempts()),
+
+		DeliveredChannels: m.GetDeliveredChannels(),
+	}
+
+	var err error
+
+	if n.ID, err = uuid.Parse(m.GetId()); err != nil {
+		return nil, errors.Wrap(err, "decode notification id")
+	}
+
+	if n.EventID, err = uuid.Parse(m.GetEventId()); err != nil {
+		return nil, errors.Wrap(err, "decode notification event id")
+	}
+
+	if n.UserID, err = uuid.Parse(m.GetUserId()); err != nil {
+		return nil, errors.Wrap(err, "decode notification user id")
+	}
+
+	return n, nil
+}
+
+// legacyMessage сообщение в JSON, записанное прежними версиями.
+// До исходящего ящика в очередь публиковалось само событие (calendar.Event),
+// затем уведомление (calendar.Notification) без заголовка формата.
+type legacyMessage struct {
+	ID           uuid.UUID `json:"ID"`
+	EventID      uuid.UUID `json:"EventID"`
+	EventTitle   string    `json:"EventTitle"`
+	EventStartAt time.Time `json:"EventStartAt"`
+	UserID       uuid.UUID `json:"UserID"`
+	Attempts     int       `json:"Attempts"`
+
+	// Title и StartAt поля события.
+	Title   string    `json:"Title"`
+	StartAt time.Time `json:"StartAt"`
+}
+
+// decodeLegacyNotification разбирает уведомление из сообщения в JSON.
+// Событие преобразуется в уведомление его владельца без идентификатора:
+// такому уведомлению не соответствует запись исходящего ящика.
+func decodeLegacyNotification(msg kafka.Message) (*calendar.Notification, error) {
+	m := new(legacyMessage)
+	if err := json.Unmarshal(msg.Value, m); err != nil {
+		return nil, errors.Wrap(err, "decode legacy notification")
+	}
+
+	if m.EventID != uuid.Nil {
+		return &calendar.Notification{
+			ID:           m.ID,
+			EventID:      m.EventID,
+			EventTitle:   m.EventTitle,
+			EventStartAt: m.EventStartAt,
+			UserID:       m.UserID,
+			Attempts:     m.Attempts,
+		}, nil
+	}
+
+	if m.ID == uuid.Nil {
+		return nil, errors.New("decode legacy notification: event id is empty")
+	}
+
+	return &calendar.Notification{
+		EventID:      m.ID,
+		EventTitle:   m.Title,
+		EventStartAt: m.StartAt,
+		UserID:       m.UserID,
+	}, nil
+}
+
+// schemaHeaders возвращает заголовки формата сообщения.
+func schemaHeaders(msg kafka.Message) []kafka.Header {
+	headers := make([]kafka.Header, 0, 2)
+
+	for _, h := range msg.Headers {
+		if h.Key == contentTypeHeader || h.Key == schemaVersionHeader {
+			headers = append(headers, h)
+		}
+	}
+
+	return headers
+}
@@ -0,0 +1,103 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	json "github.com/json-iterator/go"
+	kafka "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+func TestNotificationCodec(t *testing.T) {
+	n := &calendar.Notification{
+		ID:           uuid.New(),
+		EventID:      uuid.New(),
+		EventTitle:   "foo",
+		EventStartAt: time.Unix(1664791200, 0),
+		UserID:       uuid.New(),
+		Attempts:     2,
+
+		DeliveredChannels: []string{"stdout"},
+	}
+
+	t.Run("protobuf", func(t *testing.T) {
+		msg, err := encodeNotification(n)
+		require.NoError(t, err)
+
+		contentType, _ := header(msg, contentTypeHeader)
+		require.Equal(t, contentTypeProtobuf, contentType)
+
+		version, _ := header(msg, schemaVersionHeader)
+		require.Equal(t, notificationSchemaV1, version)
+
+		got, err := decodeNotification(msg)
+		require.NoError(t, err)
+		require.Equal(t, n, got)
+	})
+
+	t.Run("legacy notification json", func(t *testing.T) {
+		bs, err := json.Marshal(n)
+		require.NoError(t, err)
+
+		got, err := decodeNotification(kafka.Message{Value: bs})
+		require.NoError(t, err)
+		require.Equal(t, n.ID, got.ID)
+		require.Equal(t, n.EventID, got.EventID)
+		require.Equal(t, n.EventTitle, got.EventTitle)
+		require.True(t, n.EventStartAt.Equal(got.EventStartAt))
+		require.Equal(t, n.UserID, got.UserID)
+		require.Equal(t, n.Attempts, got.Attempts)
+	})
+
+	t.Run("legacy event json", func(t *testing.T) {
+		// сообщение, записанное kafka.Writer до перехода на уведомления
+		bs := []byte(`{"ID":"ef0d2079-e9a2-4810-8cae-eb6729c50580","Title":"foo","Description":"bar",` +
+			`"StartAt":"2022-10-03T10:00:00Z","EndAt":"2022-10-03T11:00:00Z",` +
+			`"UserID":"123e4567-e89b-12d3-a456-426614174000","NotificationDuration":15,"IsNotified":false}`)
+
+		got, err := decodeNotification(kafka.Message{Value: bs})
+		require.NoError(t, err)
+		require.Equal(t, &calendar.Notification{
+			EventID:      uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+			EventTitle:   "foo",
+			EventStartAt: time.Date(2022, 10, 3, 10, 0, 0, 0, time.UTC),
+			UserID:       uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+		}, got)
+
+		_, err = decodeNotification(kafka.Message{Value: []byte(`{"Title":"foo"}`)})
+		require.Error(t, err)
+	})
+
+	t.Run("unsupported schema version", func(t *testing.T) {
+		msg, err := encodeNotification(n)
+		require.NoError(t, err)
+
+		msg.Headers[1].Value = []byte("2")
+
+		_, err = decodeNotification(msg)
+		require.ErrorIs(t, err, ErrUnsupportedSchema)
+	})
+
+	t.Run("malformed protobuf", func(t *testing.T) {
+		msg, err := encodeNotification(n)
+		require.NoError(t, err)
+
+		msg.Value = []byte("garbage")
+
+		_, err = decodeNotification(msg)
+		require.Error(t, err)
+	})
+
+	t.Run("schema headers", func(t *testing.T) {
+		msg, err := encodeNotification(n)
+		require.NoError(t, err)
+
+		msg.Headers = append(msg.Headers, kafka.Header{Key: errorHeader, Value: []byte("boom")})
+
+		require.Equal(t, msg.Headers[:2], schemaHeaders(msg))
+	})
+}
@@ -0,0 +1,25 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// CheckBrokers проверяет, что доступен хотя бы один из брокеров.
+func CheckBrokers(ctx context.Context, brokers []string) error {
+	err := errors.New("no brokers configured")
+
+	for _, broker := range brokers {
+		conn, dialErr := kafka.DialContext(ctx, "tcp", broker)
+		if dialErr != nil {
+			err = dialErr
+			continue
+		}
+
+		return conn.Close()
+	}
+
+	return errors.Wrap(err, "kafka unreachable")
+}
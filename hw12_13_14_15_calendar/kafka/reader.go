@@ -2,8 +2,11 @@ package kafka
 
 import (
 	"context"
+	"strconv"
+	"sync"
+	"time"
 
-	json "github.com/json-iterator/go"
+	"github.com/pkg/errors"
 	kafka "github.com/segmentio/kafka-go"
 
 	"github.com/RomanSarvarov/otus_go_home_work/calendar"
@@ -11,30 +14,222 @@ import (
 
 type Reader struct {
 	r *kafka.Reader
+
+	// dead получатель сообщений, которые не удалось разобрать.
+	dead *Retrier
+
+	// offsets смещения прочитанных, но еще не обработанных сообщений.
+	offsets *offsets
 }
 
 type ReaderConfig = kafka.ReaderConfig
 
 func NewReader(cfg ReaderConfig) Reader {
+	r := kafka.NewReader(cfg)
+
 	return Reader{
-		r: kafka.NewReader(cfg),
+		r:       r,
+		offsets: newOffsets(r.CommitMessages),
 	}
 }
 
+// WithDeadLetter возвращает читателя, который помещает сообщения,
+// которые не удалось разобрать, в топик недоставленных вместо возврата ошибки.
+func (r Reader) WithDeadLetter(rt Retrier) Reader {
+	r.dead = &rt
+
+	return r
+}
+
+// Lag возвращает отставание читателя от конца топика в сообщениях.
+func (r Reader) Lag() int64 {
+	return r.r.Stats().Lag
+}
+
 func (r Reader) Close() error {
 	return r.r.Close()
 }
 
-func (r Reader) ReadEventFromQueue(ctx context.Context) (*calendar.Event, error) {
-	msg, err := r.r.ReadMessage(ctx)
+// ReadNotificationFromQueue читает уведомление из очереди.
+// Отложенное уведомление возвращается не раньше назначенного ему времени.
+// Сообщение подтверждается только вызовом CommitNotification после обработки уведомления,
+// поэтому при остановке рассыльщика до доставки оно будет прочитано снова.
+// Возвращаемый контекст содержит контекст трассировки отправителя сообщения.
+func (r Reader) ReadNotificationFromQueue(ctx context.Context) (context.Context, *calendar.Notification, error) {
+	for {
+		msg, err := r.r.FetchMessage(ctx)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		r.offsets.fetched(msg)
+
+		msgCtx := extractTrace(ctx, msg)
+
+		n, err := decodeNotification(msg)
+		if err != nil {
+			if r.dead == nil {
+				return nil, nil, err
+			}
+
+			err = r.dead.deadLetter(msgCtx, msg, 0, errors.Wrap(err, "malformed notification"))
+			if err != nil {
+				return nil, nil, err
+			}
+
+			if err := r.offsets.done(ctx, msg); err != nil {
+				return nil, nil, err
+			}
+
+			continue
+		}
+
+		if err := waitNotBefore(ctx, msg); err != nil {
+			return nil, nil, err
+		}
+
+		r.offsets.track(n, msg)
+
+		return msgCtx, n, nil
+	}
+}
+
+// CommitNotification подтверждает сообщение, из которого прочитано уведомление n.
+func (r Reader) CommitNotification(ctx context.Context, n *calendar.Notification) error {
+	return r.offsets.commit(ctx, n)
+}
+
+// offsets подтверждает смещения сообщений по порядку. Сообщения одного раздела
+// обрабатываются несколькими потоками и могут завершиться в любом порядке,
+// а подтверждение смещения означает обработку всех предыдущих сообщений раздела.
+// Поэтому смещение подтверждается только когда обработаны все прочитанные до него сообщения.
+type offsets struct {
+	mu sync.Mutex
+
+	commitFunc func(ctx context.Context, msgs ...kafka.Message) error
+
+	// messages сообщения, из которых прочитаны необработанные уведомления.
+	messages map[*calendar.Notification]kafka.Message
+
+	// partitions прочитанные сообщения каждого раздела в порядке чтения.
+	partitions map[int][]*pendingMessage
+}
+
+// pendingMessage прочитанное сообщение.
+type pendingMessage struct {
+	msg  kafka.Message
+	done bool
+}
+
+func newOffsets(commit func(ctx context.Context, msgs ...kafka.Message) error) *offsets {
+	return &offsets{
+		commitFunc: commit,
+		messages:   make(map[*calendar.Notification]kafka.Message),
+		partitions: make(map[int][]*pendingMessage),
+	}
+}
+
+// fetched запоминает прочитанное сообщение.
+func (o *offsets) fetched(msg kafka.Message) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.partitions[msg.Partition] = append(o.partitions[msg.Partition], &pendingMessage{msg: msg})
+}
+
+// track связывает уведомление с сообщением, из которого оно прочитано.
+func (o *offsets) track(n *calendar.Notification, msg kafka.Message) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	o.messages[n] = msg
+}
+
+// commit отмечает обработанным сообщение уведомления n.
+func (o *offsets) commit(ctx context.Context, n *calendar.Notification) error {
+	o.mu.Lock()
+	msg, ok := o.messages[n]
+	delete(o.messages, n)
+	o.mu.Unlock()
+
+	if !ok {
+		return errors.New("commit notification: notification was not read by this reader")
+	}
+
+	return o.done(ctx, msg)
+}
+
+// done отмечает сообщение обработанным и подтверждает смещение
+// последнего сообщения раздела, до которого обработаны все прочитанные.
+func (o *offsets) done(ctx context.Context, msg kafka.Message) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	pending := o.partitions[msg.Partition]
+
+	for _, p := range pending {
+		if p.msg.Offset == msg.Offset {
+			p.done = true
+
+			break
+		}
+	}
+
+	var (
+		last      kafka.Message
+		committed int
+	)
+
+	for committed < len(pending) && pending[committed].done {
+		last = pending[committed].msg
+		committed++
+	}
+
+	if committed == 0 {
+		return nil
+	}
+
+	if err := o.commitFunc(ctx, last); err != nil {
+		return errors.Wrap(err, "commit messages")
+	}
+
+	o.partitions[msg.Partition] = pending[committed:]
+
+	return nil
+}
+
+// waitNotBefore ожидает наступления времени, раньше которого сообщение не обрабатывается.
+// Сообщения раздела обрабатываются по порядку, поэтому сообщение с большей задержкой
+// задерживает следующие за ним, но ни одно не обрабатывается раньше своего срока.
+func waitNotBefore(ctx context.Context, msg kafka.Message) error {
+	notBefore, ok := header(msg, notBeforeHeader)
+	if !ok {
+		return nil
+	}
+
+	ns, err := strconv.ParseInt(notBefore, 10, 64)
 	if err != nil {
-		return nil, err
+		return nil
 	}
 
-	event := new(calendar.Event)
-	if err := json.Unmarshal(msg.Value, event); err != nil {
-		return nil, err
+	timer := time.NewTimer(time.Until(time.Unix(0, ns)))
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// header возвращает значение заголовка сообщения.
+func header(msg kafka.Message, key string) (string, bool) {
+	for _, h := range msg.Headers {
+		if h.Key == key {
+			return string(h.Value), true
+		}
 	}
 
-	return event, nil
+	return "", false
 }
@@ -0,0 +1,83 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"testing"
+	"time"
+
+	kafka "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+func TestWaitNotBefore(t *testing.T) {
+	notBefore := func(d time.Duration) kafka.Message {
+		return kafka.Message{Headers: []kafka.Header{
+			{Key: notBeforeHeader, Value: []byte(strconv.FormatInt(time.Now().Add(d).UnixNano(), 10))},
+		}}
+	}
+
+	t.Run("without header", func(t *testing.T) {
+		require.NoError(t, waitNotBefore(context.Background(), kafka.Message{}))
+	})
+
+	t.Run("waits until not before", func(t *testing.T) {
+		start := time.Now()
+
+		require.NoError(t, waitNotBefore(context.Background(), notBefore(20*time.Millisecond)))
+		require.GreaterOrEqual(t, time.Since(start), 20*time.Millisecond)
+	})
+
+	t.Run("past time", func(t *testing.T) {
+		require.NoError(t, waitNotBefore(context.Background(), notBefore(-time.Hour)))
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		require.ErrorIs(t, waitNotBefore(ctx, notBefore(time.Hour)), context.Canceled)
+	})
+}
+
+func TestOffsets(t *testing.T) {
+	ctx := context.Background()
+
+	var committed []kafka.Message
+
+	o := newOffsets(func(_ context.Context, msgs ...kafka.Message) error {
+		committed = append(committed, msgs...)
+		return nil
+	})
+
+	msgs := []kafka.Message{
+		{Partition: 0, Offset: 10},
+		{Partition: 0, Offset: 11},
+		{Partition: 1, Offset: 5},
+	}
+
+	notifications := make([]*calendar.Notification, 0, len(msgs))
+
+	for _, msg := range msgs {
+		n := &calendar.Notification{}
+		o.fetched(msg)
+		o.track(n, msg)
+		notifications = append(notifications, n)
+	}
+
+	// уведомление, прочитанное первым, еще доставляется (или рассыльщик упал до доставки):
+	// смещение следующего сообщения раздела не подтверждается, иначе первое было бы потеряно
+	require.NoError(t, o.commit(ctx, notifications[1]))
+	require.Empty(t, committed)
+
+	// другие разделы подтверждаются независимо
+	require.NoError(t, o.commit(ctx, notifications[2]))
+	require.Equal(t, []kafka.Message{msgs[2]}, committed)
+
+	require.NoError(t, o.commit(ctx, notifications[0]))
+	require.Equal(t, []kafka.Message{msgs[2], msgs[1]}, committed)
+
+	require.Error(t, o.commit(ctx, notifications[0]))
+}
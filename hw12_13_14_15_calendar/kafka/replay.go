@@ -0,0 +1,89 @@
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	kafka "github.com/segmentio/kafka-go"
+)
+
+// ReplayConfig настройки повторной отправки недоставленных уведомлений.
+type ReplayConfig struct {
+	Brokers []string
+
+	// GroupID группа читателя топика недоставленных. Смещения группы сохраняются,
+	// поэтому каждое сообщение переносится один раз.
+	GroupID string
+
+	// DeadLetterTopic топик недоставленных уведомлений.
+	DeadLetterTopic string
+
+	// Topic топик, в который переносятся уведомления.
+	Topic string
+
+	// IdleTimeout время ожидания нового сообщения, после которого перенос завершается.
+	IdleTimeout time.Duration
+}
+
+// ReplayDeadLetters переносит уведомления из топика недоставленных в топик рассыльщика,
+// сбрасывая счетчик попыток. Сообщения, которые не удалось разобрать, пропускаются.
+// Возвращает количество перенесенных уведомлений.
+func ReplayDeadLetters(ctx context.Context, cfg ReplayConfig) (int, error) {
+	r := kafka.NewReader(kafka.ReaderConfig{
+		Brokers: cfg.Brokers,
+		GroupID: cfg.GroupID,
+		Topic:   cfg.DeadLetterTopic,
+	})
+	defer r.Close()
+
+	w := newWriter(cfg.Brokers, cfg.Topic)
+	defer w.Close()
+
+	replayed := 0
+
+	for {
+		msg, err := fetchMessage(ctx, r, cfg.IdleTimeout)
+		if errors.Is(err, context.DeadlineExceeded) && ctx.Err() == nil {
+			return replayed, nil
+		}
+
+		if err != nil {
+			return replayed, errors.Wrap(err, "replay dead letters")
+		}
+
+		n, err := decodeNotification(msg)
+		if err != nil {
+			log.Warn().Err(err).Int64("offset", msg.Offset).Msg("skip malformed dead letter")
+		} else {
+			n.Attempts = 0
+
+			out, err := encodeNotification(n)
+			if err != nil {
+				return replayed, errors.Wrap(err, "replay dead letters")
+			}
+
+			// повторная отправка продолжает трассу исходного уведомления
+			injectTrace(extractTrace(ctx, msg), &out)
+
+			if err := w.WriteMessages(ctx, out); err != nil {
+				return replayed, errors.Wrap(err, "replay dead letters")
+			}
+
+			replayed++
+		}
+
+		if err := r.CommitMessages(ctx, msg); err != nil {
+			return replayed, errors.Wrap(err, "replay dead letters")
+		}
+	}
+}
+
+// fetchMessage читает сообщение, ожидая его не дольше timeout.
+func fetchMessage(ctx context.Context, r *kafka.Reader, timeout time.Duration) (kafka.Message, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	return r.FetchMessage(ctx)
+}
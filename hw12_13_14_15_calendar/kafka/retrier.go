@@ -0,0 +1,115 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog/log"
+	kafka "github.com/segmentio/kafka-go"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+const (
+	// notBeforeHeader заголовок со временем (unix, наносекунды), раньше которого сообщение не обрабатывается.
+	notBeforeHeader = "not-before"
+
+	// errorHeader заголовок с ошибкой последней попытки обработки.
+	errorHeader = "error"
+
+	// attemptsHeader заголовок с количеством попыток обработки.
+	attemptsHeader = "attempts"
+)
+
+// RetrierConfig настройки повторной обработки уведомлений.
+type RetrierConfig struct {
+	Brokers []string
+
+	// RetryTopic топик отложенных уведомлений.
+	RetryTopic string
+
+	// DeadLetterTopic топик уведомлений, которые не удалось доставить.
+	DeadLetterTopic string
+
+	// MaxAttempts количество попыток, после которого уведомление считается недоставленным.
+	MaxAttempts int
+
+	// Delay задержка перед первой повторной попыткой, каждая следующая вдвое больше.
+	Delay time.Duration
+}
+
+// Retrier откладывает повторную обработку уведомлений, а после исчерпания
+// попыток помещает их вместе с ошибкой в топик недоставленных (dead-letter).
+type Retrier struct {
+	retry *kafka.Writer
+	dead  *kafka.Writer
+	cfg   RetrierConfig
+}
+
+// NewRetrier создает Retrier.
+func NewRetrier(cfg RetrierConfig) Retrier {
+	return Retrier{
+		retry: newWriter(cfg.Brokers, cfg.RetryTopic),
+		dead:  newWriter(cfg.Brokers, cfg.DeadLetterTopic),
+		cfg:   cfg,
+	}
+}
+
+// Close закрывает соединения.
+func (r Retrier) Close() error {
+	if err := r.retry.Close(); err != nil {
+		return err
+	}
+
+	return r.dead.Close()
+}
+
+// RetryNotification откладывает уведомление, обработка которого завершилась ошибкой cause.
+func (r Retrier) RetryNotification(ctx context.Context, n *calendar.Notification, cause error) error {
+	retried := *n
+	retried.Attempts++
+
+	msg, err := encodeNotification(&retried)
+	if err != nil {
+		return err
+	}
+
+	if retried.Attempts >= r.cfg.MaxAttempts {
+		return r.deadLetter(ctx, msg, retried.Attempts, cause)
+	}
+
+	delay := r.cfg.Delay << (retried.Attempts - 1)
+	notBefore := time.Now().Add(delay).UnixNano()
+
+	msg.Headers = append(msg.Headers,
+		kafka.Header{Key: notBeforeHeader, Value: []byte(strconv.FormatInt(notBefore, 10))},
+		kafka.Header{Key: errorHeader, Value: []byte(cause.Error())},
+	)
+
+	injectTrace(ctx, &msg)
+
+	return r.retry.WriteMessages(ctx, msg)
+}
+
+// deadLetter помещает сообщение в топик недоставленных.
+// Заголовки формата исходного сообщения сохраняются, чтобы его можно было разобрать при переотправке.
+func (r Retrier) deadLetter(ctx context.Context, msg kafka.Message, attempts int, cause error) error {
+	log.Error().
+		Err(cause).
+		Int("attempts", attempts).
+		Str("topic", r.cfg.DeadLetterTopic).
+		Msg("notification moved to dead-letter topic")
+
+	dead := kafka.Message{
+		Value: msg.Value,
+		Headers: append(schemaHeaders(msg),
+			kafka.Header{Key: attemptsHeader, Value: []byte(strconv.Itoa(attempts))},
+			kafka.Header{Key: errorHeader, Value: []byte(cause.Error())},
+		),
+	}
+
+	injectTrace(ctx, &dead)
+
+	return r.dead.WriteMessages(ctx, dead)
+}
@@ -0,0 +1,53 @@
+package kafka
+
+import (
+	"context"
+
+	kafka "github.com/segmentio/kafka-go"
+	"go.opentelemetry.io/otel"
+)
+
+// headerCarrier позволяет передавать контекст трассировки в заголовках сообщения.
+type headerCarrier struct {
+	msg *kafka.Message
+}
+
+// Get возвращает значение заголовка key.
+func (c headerCarrier) Get(key string) string {
+	v, _ := header(*c.msg, key)
+
+	return v
+}
+
+// Set задает значение заголовка key, заменяя прежнее.
+func (c headerCarrier) Set(key, value string) {
+	for i, h := range c.msg.Headers {
+		if h.Key == key {
+			c.msg.Headers[i].Value = []byte(value)
+
+			return
+		}
+	}
+
+	c.msg.Headers = append(c.msg.Headers, kafka.Header{Key: key, Value: []byte(value)})
+}
+
+// Keys возвращает ключи заголовков.
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, h := range c.msg.Headers {
+		keys = append(keys, h.Key)
+	}
+
+	return keys
+}
+
+// injectTrace записывает контекст трассировки ctx в заголовки сообщения.
+func injectTrace(ctx context.Context, msg *kafka.Message) {
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier{msg: msg})
+}
+
+// extractTrace возвращает ctx с контекстом трассировки из заголовков сообщения.
+func extractTrace(ctx context.Context, msg kafka.Message) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, headerCarrier{msg: &msg})
+}
@@ -0,0 +1,37 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	kafka "github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracePropagation(t *testing.T) {
+	prev := otel.GetTextMapPropagator()
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTextMapPropagator(prev)
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+
+	msg := kafka.Message{Headers: []kafka.Header{{Key: contentTypeHeader, Value: []byte(contentTypeProtobuf)}}}
+
+	injectTrace(trace.ContextWithSpanContext(context.Background(), sc), &msg)
+	injectTrace(trace.ContextWithSpanContext(context.Background(), sc), &msg)
+
+	require.Len(t, msg.Headers, 2)
+	require.Contains(t, headerCarrier{msg: &msg}.Keys(), "traceparent")
+
+	got := trace.SpanContextFromContext(extractTrace(context.Background(), msg))
+	require.Equal(t, sc.TraceID(), got.TraceID())
+	require.Equal(t, sc.SpanID(), got.SpanID())
+	require.True(t, got.IsRemote())
+}
@@ -3,7 +3,6 @@ package kafka
 import (
 	"context"
 
-	json "github.com/json-iterator/go"
 	kafka "github.com/segmentio/kafka-go"
 
 	"github.com/RomanSarvarov/otus_go_home_work/calendar"
@@ -20,10 +19,15 @@ type WriterConfig struct {
 
 func NewWriter(cfg *WriterConfig) Writer {
 	return Writer{
-		w: &kafka.Writer{
-			Addr:  kafka.TCP(cfg.Brokers...),
-			Topic: cfg.Topic,
-		},
+		w: newWriter(cfg.Brokers, cfg.Topic),
+	}
+}
+
+// newWriter создает писателя в топик.
+func newWriter(brokers []string, topic string) *kafka.Writer {
+	return &kafka.Writer{
+		Addr:  kafka.TCP(brokers...),
+		Topic: topic,
 	}
 }
 
@@ -31,17 +35,17 @@ func (w Writer) Close() error {
 	return w.w.Close()
 }
 
-func (w Writer) SendEventToQueue(ctx context.Context, events ...*calendar.Event) error {
-	messages := make([]kafka.Message, 0, len(events))
-	for _, e := range events {
-		bs, err := json.Marshal(e)
+func (w Writer) SendNotificationToQueue(ctx context.Context, notifications ...*calendar.Notification) error {
+	messages := make([]kafka.Message, 0, len(notifications))
+	for _, n := range notifications {
+		msg, err := encodeNotification(n)
 		if err != nil {
 			return err
 		}
 
-		messages = append(messages, kafka.Message{
-			Value: bs,
-		})
+		injectTrace(ctx, &msg)
+
+		messages = append(messages, msg)
 	}
 
 	return w.w.WriteMessages(ctx, messages...)
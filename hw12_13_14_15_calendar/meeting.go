@@ -0,0 +1,73 @@
+package calendar
+
+import (
+	"sort"
+	"time"
+)
+
+// MeetingSlots возвращает варианты времени встречи длительностью duration
+// внутри свободных промежутков free (см. FreeIntervals).
+// Варианты начинаются на границе свободного промежутка или кратно step.
+//
+// Варианты ранжируются: сначала те, что оставляют меньше обрывков свободного
+// времени, в которые уже не поместится встреча такой же длительности
+// (например, встреча сразу после другой лучше встречи с 10-минутным зазором),
+// при равенстве - более ранние.
+func MeetingSlots(free []Interval, duration, step time.Duration) []Interval {
+	type candidate struct {
+		slot      Interval
+		fragments int
+	}
+
+	candidates := make([]candidate, 0)
+
+	for _, gap := range free {
+		if gap.Duration() < duration {
+			continue
+		}
+
+		last := gap.End.Add(-duration)
+
+		starts := []time.Time{gap.Start}
+		for t := gap.Start.Truncate(step).Add(step); t.Before(last); t = t.Add(step) {
+			starts = append(starts, t)
+		}
+
+		if last.After(gap.Start) {
+			starts = append(starts, last)
+		}
+
+		for _, start := range starts {
+			slot := Interval{Start: start, End: start.Add(duration)}
+
+			candidates = append(candidates, candidate{
+				slot:      slot,
+				fragments: fragment(start.Sub(gap.Start), duration) + fragment(gap.End.Sub(slot.End), duration),
+			})
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].fragments != candidates[j].fragments {
+			return candidates[i].fragments < candidates[j].fragments
+		}
+
+		return candidates[i].slot.Start.Before(candidates[j].slot.Start)
+	})
+
+	res := make([]Interval, 0, len(candidates))
+	for _, c := range candidates {
+		res = append(res, c.slot)
+	}
+
+	return res
+}
+
+// fragment возвращает 1, если остаток свободного времени слишком мал для еще одной встречи.
+func fragment(rest, duration time.Duration) int {
+	if rest > 0 && rest < duration {
+		return 1
+	}
+
+	return 0
+}
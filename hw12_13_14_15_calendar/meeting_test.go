@@ -0,0 +1,52 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMeetingSlots(t *testing.T) {
+	t.Parallel()
+
+	day := mustParseDateTime("2022-10-03 00:00:00")
+	at := func(h, m int) time.Time {
+		return day.Add(time.Duration(h)*time.Hour + time.Duration(m)*time.Minute)
+	}
+
+	free := []Interval{
+		// слишком короткий промежуток
+		{Start: at(9, 0), End: at(9, 20)},
+		// помещается одна встреча с остатком 10 минут
+		{Start: at(10, 10), End: at(11, 20)},
+		// помещаются ровно две встречи
+		{Start: at(14, 0), End: at(16, 0)},
+	}
+
+	require.Equal(t, []Interval{
+		{Start: at(14, 0), End: at(15, 0)},
+		{Start: at(15, 0), End: at(16, 0)},
+		{Start: at(10, 10), End: at(11, 10)},
+		{Start: at(10, 20), End: at(11, 20)},
+		{Start: at(14, 30), End: at(15, 30)},
+	}, MeetingSlots(free, time.Hour, 30*time.Minute))
+}
+
+func TestCheckDateBusy(t *testing.T) {
+	t.Parallel()
+
+	start := mustParseDateTime("2022-10-03 10:00:00")
+
+	busy := &Event{ID: uuid.New(), StartAt: start, EndAt: start.Add(time.Hour)}
+	daily := &Event{ID: uuid.New(), StartAt: start.Add(-48 * time.Hour), EndAt: start.Add(-47 * time.Hour), RRule: "FREQ=DAILY"}
+
+	adjacent := &Event{StartAt: start.Add(time.Hour), EndAt: start.Add(2 * time.Hour)}
+	overlapping := &Event{StartAt: start.Add(30 * time.Minute), EndAt: start.Add(2 * time.Hour)}
+
+	require.NoError(t, CheckDateBusy([]*Event{busy}, adjacent, uuid.Nil))
+	require.ErrorIs(t, CheckDateBusy([]*Event{busy}, overlapping, uuid.Nil), ErrDateBusy)
+	require.NoError(t, CheckDateBusy([]*Event{busy}, overlapping, busy.ID))
+	require.ErrorIs(t, CheckDateBusy([]*Event{busy, daily}, overlapping, busy.ID), ErrDateBusy)
+}
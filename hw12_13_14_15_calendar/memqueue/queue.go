@@ -0,0 +1,169 @@
+// Package memqueue реализует очередь уведомлений в памяти процесса.
+// Используется для локальной разработки, когда планировщик и отправитель
+// работают в одном процессе и Kafka не нужна.
+package memqueue
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// ErrClosed очередь закрыта.
+var ErrClosed = errors.New("queue closed")
+
+// Config настройки очереди.
+type Config struct {
+	// Size количество уведомлений, которое очередь вмещает без блокировки отправителя.
+	Size int
+
+	// MaxAttempts количество попыток, после которого уведомление считается недоставленным.
+	MaxAttempts int
+
+	// RetryDelay задержка перед первой повторной попыткой, каждая следующая вдвое больше.
+	RetryDelay time.Duration
+}
+
+// item уведомление в очереди вместе с контекстом трассировки отправителя.
+type item struct {
+	n  *calendar.Notification
+	sc trace.SpanContext
+}
+
+// Queue очередь уведомлений в памяти.
+// Реализует брокеры планировщика и отправителя, а также повторную обработку уведомлений.
+type Queue struct {
+	ch  chan item
+	cfg Config
+
+	mu         sync.Mutex
+	done       chan struct{}
+	closed     bool
+	deadLetter []*calendar.Notification
+}
+
+// New создает Queue.
+func New(cfg Config) *Queue {
+	return &Queue{
+		ch:   make(chan item, cfg.Size),
+		cfg:  cfg,
+		done: make(chan struct{}),
+	}
+}
+
+// Close закрывает очередь. Отложенные уведомления отбрасываются.
+func (q *Queue) Close() error {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if !q.closed {
+		q.closed = true
+		close(q.done)
+	}
+
+	return nil
+}
+
+// SendNotificationToQueue помещает уведомления в очередь.
+// Если очередь заполнена, ожидает освобождения места.
+func (q *Queue) SendNotificationToQueue(ctx context.Context, notifications ...*calendar.Notification) error {
+	for _, n := range notifications {
+		if err := q.push(ctx, ctx, n); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReadNotificationFromQueue читает уведомление из очереди.
+// Возвращаемый контекст содержит контекст трассировки отправителя уведомления.
+func (q *Queue) ReadNotificationFromQueue(ctx context.Context) (context.Context, *calendar.Notification, error) {
+	select {
+	case it := <-q.ch:
+		return trace.ContextWithRemoteSpanContext(ctx, it.sc), it.n, nil
+	case <-q.done:
+		return nil, nil, ErrClosed
+	case <-ctx.Done():
+		return nil, nil, ctx.Err()
+	}
+}
+
+// CommitNotification подтверждает обработку уведомления. Очередь в памяти не переживает
+// перезапуск процесса, поэтому подтверждать нечего: недоставленные уведомления
+// повторно публикует планировщик из исходящего ящика.
+func (q *Queue) CommitNotification(ctx context.Context, n *calendar.Notification) error {
+	return nil
+}
+
+// RetryNotification откладывает уведомление, обработка которого завершилась ошибкой cause.
+// После исчерпания попыток уведомление сохраняется среди недоставленных.
+func (q *Queue) RetryNotification(ctx context.Context, n *calendar.Notification, cause error) error {
+	retried := *n
+	retried.Attempts++
+
+	if retried.Attempts >= q.cfg.MaxAttempts {
+		log.Error().
+			Err(cause).
+			Str("notification_id", retried.ID.String()).
+			Int("attempts", retried.Attempts).
+			Msg("notification moved to dead letters")
+
+		q.mu.Lock()
+		q.deadLetter = append(q.deadLetter, &retried)
+		q.mu.Unlock()
+
+		return nil
+	}
+
+	delay := q.cfg.RetryDelay << (retried.Attempts - 1)
+
+	go func() {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-timer.C:
+		case <-q.done:
+			return
+		}
+
+		// ожидание не ограничено контекстом вызывающего: он может завершиться раньше, чем подойдет время повтора
+		if err := q.push(context.Background(), ctx, &retried); err != nil && !errors.Is(err, ErrClosed) {
+			log.Err(err).Str("notification_id", retried.ID.String()).Msg("retry notification")
+		}
+	}()
+
+	return nil
+}
+
+// DeadLetters возвращает недоставленные уведомления.
+func (q *Queue) DeadLetters() []*calendar.Notification {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	res := make([]*calendar.Notification, len(q.deadLetter))
+	copy(res, q.deadLetter)
+
+	return res
+}
+
+// push помещает копию уведомления в очередь вместе с контекстом трассировки traceCtx.
+func (q *Queue) push(ctx, traceCtx context.Context, n *calendar.Notification) error {
+	c := *n
+
+	select {
+	case q.ch <- item{n: &c, sc: trace.SpanContextFromContext(traceCtx)}:
+		return nil
+	case <-q.done:
+		return ErrClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,113 @@
+package memqueue
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+	cause := errors.New("boom")
+
+	t.Run("send and read", func(t *testing.T) {
+		q := New(Config{Size: 2})
+		defer q.Close()
+
+		n1 := &calendar.Notification{ID: uuid.New()}
+		n2 := &calendar.Notification{ID: uuid.New()}
+
+		require.NoError(t, q.SendNotificationToQueue(ctx, n1, n2))
+
+		_, got, err := q.ReadNotificationFromQueue(ctx)
+		require.NoError(t, err)
+		require.Equal(t, n1, got)
+		require.NotSame(t, n1, got)
+
+		_, got, err = q.ReadNotificationFromQueue(ctx)
+		require.NoError(t, err)
+		require.Equal(t, n2, got)
+	})
+
+	t.Run("propagates trace context", func(t *testing.T) {
+		q := New(Config{Size: 1})
+		defer q.Close()
+
+		sc := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{1},
+			SpanID:     trace.SpanID{2},
+			TraceFlags: trace.FlagsSampled,
+		})
+
+		require.NoError(t, q.SendNotificationToQueue(trace.ContextWithSpanContext(ctx, sc), &calendar.Notification{}))
+
+		msgCtx, _, err := q.ReadNotificationFromQueue(ctx)
+		require.NoError(t, err)
+
+		got := trace.SpanContextFromContext(msgCtx)
+		require.Equal(t, sc.TraceID(), got.TraceID())
+		require.Equal(t, sc.SpanID(), got.SpanID())
+		require.True(t, got.IsRemote())
+	})
+
+	t.Run("send blocks when full", func(t *testing.T) {
+		q := New(Config{Size: 1})
+		defer q.Close()
+
+		require.NoError(t, q.SendNotificationToQueue(ctx, &calendar.Notification{}))
+
+		ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+		defer cancel()
+
+		require.ErrorIs(t, q.SendNotificationToQueue(ctx, &calendar.Notification{}), context.DeadlineExceeded)
+	})
+
+	t.Run("read closed", func(t *testing.T) {
+		q := New(Config{})
+		require.NoError(t, q.Close())
+		require.NoError(t, q.Close())
+
+		_, _, err := q.ReadNotificationFromQueue(ctx)
+		require.ErrorIs(t, err, ErrClosed)
+	})
+
+	t.Run("retry", func(t *testing.T) {
+		q := New(Config{Size: 1, MaxAttempts: 3, RetryDelay: time.Millisecond})
+		defer q.Close()
+
+		n := &calendar.Notification{ID: uuid.New()}
+
+		require.NoError(t, q.RetryNotification(ctx, n, cause))
+
+		ctx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+
+		_, got, err := q.ReadNotificationFromQueue(ctx)
+		require.NoError(t, err)
+		require.Equal(t, n.ID, got.ID)
+		require.Equal(t, 1, got.Attempts)
+		require.Equal(t, 0, n.Attempts)
+		require.Empty(t, q.DeadLetters())
+	})
+
+	t.Run("dead letter", func(t *testing.T) {
+		q := New(Config{Size: 1, MaxAttempts: 3, RetryDelay: time.Millisecond})
+		defer q.Close()
+
+		n := &calendar.Notification{ID: uuid.New(), Attempts: 2}
+
+		require.NoError(t, q.RetryNotification(ctx, n, cause))
+
+		dead := q.DeadLetters()
+		require.Len(t, dead, 1)
+		require.Equal(t, n.ID, dead[0].ID)
+		require.Equal(t, 3, dead[0].Attempts)
+	})
+}
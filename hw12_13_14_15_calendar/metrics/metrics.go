@@ -0,0 +1,124 @@
+// Package metrics содержит метрики Prometheus сервиса.
+package metrics
+
+import (
+	"net/http"
+
+	"github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Path путь, по которому отдаются метрики.
+const Path = "/metrics"
+
+// namespace префикс метрик сервиса.
+const namespace = "calendar"
+
+var (
+	// GRPCRequests количество GRPC запросов по методу и коду ответа.
+	GRPCRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "requests_total",
+		Help:      "Number of handled GRPC requests.",
+	}, []string{"method", "code"})
+
+	// GRPCRequestDuration время обработки GRPC запросов по методу и коду ответа.
+	GRPCRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "grpc",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of GRPC requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	// HTTPRequests количество HTTP запросов по методу и коду ответа.
+	HTTPRequests = promauto.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "requests_total",
+		Help:      "Number of handled HTTP requests.",
+	}, []string{"method", "code"})
+
+	// HTTPRequestDuration время обработки HTTP запросов по методу и коду ответа.
+	HTTPRequestDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "http",
+		Name:      "request_duration_seconds",
+		Help:      "Duration of HTTP requests.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "code"})
+
+	// RepositoryCallDuration время вызовов хранилища по драйверу и методу.
+	RepositoryCallDuration = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "repository",
+		Name:      "call_duration_seconds",
+		Help:      "Duration of repository calls.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"driver", "method"})
+
+	// SchedulerTickDuration время обработки тика планировщика.
+	SchedulerTickDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "tick_duration_seconds",
+		Help:      "Duration of scheduler ticks.",
+		Buckets:   prometheus.DefBuckets,
+	})
+
+	// SchedulerEventsEnqueued количество событий, уведомления о которых поставлены в очередь.
+	SchedulerEventsEnqueued = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "events_enqueued_total",
+		Help:      "Number of events whose notifications were enqueued.",
+	})
+
+	// SchedulerNotificationsPublished количество уведомлений, опубликованных в очередь.
+	SchedulerNotificationsPublished = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "scheduler",
+		Name:      "notifications_published_total",
+		Help:      "Number of notifications published to the queue.",
+	})
+
+	// SenderProcessed количество обработанных отправителем уведомлений.
+	SenderProcessed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sender",
+		Name:      "notifications_processed_total",
+		Help:      "Number of notifications processed by sender.",
+	})
+
+	// SenderFailed количество уведомлений, доставка которых завершилась ошибкой.
+	SenderFailed = promauto.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Subsystem: "sender",
+		Name:      "notifications_failed_total",
+		Help:      "Number of notifications whose delivery failed.",
+	})
+)
+
+// Handler возвращает обработчик, отдающий метрики.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
+
+// RegisterConsumerLag регистрирует метрику отставания консьюмера топика.
+// Значение запрашивается у lag при каждом сборе метрик.
+func RegisterConsumerLag(topic string, lag func() int64) error {
+	g := prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+		Namespace:   namespace,
+		Subsystem:   "kafka",
+		Name:        "consumer_lag",
+		Help:        "Number of messages the consumer lags behind the topic.",
+		ConstLabels: prometheus.Labels{"topic": topic},
+	}, func() float64 {
+		return float64(lag())
+	})
+
+	return errors.Wrap(prometheus.Register(g), "register consumer lag")
+}
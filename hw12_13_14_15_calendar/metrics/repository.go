@@ -0,0 +1,162 @@
+package metrics
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// InstrumentRepository оборачивает хранилище, измеряя время вызовов его методов.
+func InstrumentRepository(driver string, r calendar.Storage) calendar.Storage {
+	return instrumentedRepository{r: r, driver: driver}
+}
+
+// instrumentedRepository хранилище, измеряющее время вызовов.
+type instrumentedRepository struct {
+	r      calendar.Storage
+	driver string
+}
+
+// observe записывает время вызова метода, начатого в start.
+func (repo instrumentedRepository) observe(method string, start time.Time) {
+	RepositoryCallDuration.WithLabelValues(repo.driver, method).Observe(time.Since(start).Seconds())
+}
+
+func (repo instrumentedRepository) CreateEvent(ctx context.Context, e *calendar.Event) (*calendar.Event, error) {
+	defer repo.observe("CreateEvent", time.Now())
+
+	return repo.r.CreateEvent(ctx, e)
+}
+
+func (repo instrumentedRepository) UpdateEvent(
+	ctx context.Context,
+	id uuid.UUID,
+	e *calendar.Event,
+) (*calendar.Event, error) {
+	defer repo.observe("UpdateEvent", time.Now())
+
+	return repo.r.UpdateEvent(ctx, id, e)
+}
+
+func (repo instrumentedRepository) PatchEvent(
+	ctx context.Context,
+	id uuid.UUID,
+	e *calendar.Event,
+	fields []calendar.EventField,
+) (*calendar.Event, error) {
+	defer repo.observe("PatchEvent", time.Now())
+
+	return repo.r.PatchEvent(ctx, id, e, fields)
+}
+
+func (repo instrumentedRepository) DeleteEvent(ctx context.Context, ids ...uuid.UUID) error {
+	defer repo.observe("DeleteEvent", time.Now())
+
+	return repo.r.DeleteEvent(ctx, ids...)
+}
+
+func (repo instrumentedRepository) FindTrash(
+	ctx context.Context,
+	filter calendar.TrashFilter,
+) ([]*calendar.Event, error) {
+	defer repo.observe("FindTrash", time.Now())
+
+	return repo.r.FindTrash(ctx, filter)
+}
+
+func (repo instrumentedRepository) RestoreEvent(ctx context.Context, id uuid.UUID) (*calendar.Event, error) {
+	defer repo.observe("RestoreEvent", time.Now())
+
+	return repo.r.RestoreEvent(ctx, id)
+}
+
+func (repo instrumentedRepository) PurgeEvent(ctx context.Context, ids ...uuid.UUID) error {
+	defer repo.observe("PurgeEvent", time.Now())
+
+	return repo.r.PurgeEvent(ctx, ids...)
+}
+
+func (repo instrumentedRepository) FindEvents(
+	ctx context.Context,
+	filter calendar.EventFilter,
+) ([]*calendar.Event, error) {
+	defer repo.observe("FindEvents", time.Now())
+
+	return repo.r.FindEvents(ctx, filter)
+}
+
+func (repo instrumentedRepository) FindEventByID(ctx context.Context, id uuid.UUID) (*calendar.Event, error) {
+	defer repo.observe("FindEventByID", time.Now())
+
+	return repo.r.FindEventByID(ctx, id)
+}
+
+func (repo instrumentedRepository) FindEventRevisions(
+	ctx context.Context,
+	eventID uuid.UUID,
+) ([]*calendar.Revision, error) {
+	defer repo.observe("FindEventRevisions", time.Now())
+
+	return repo.r.FindEventRevisions(ctx, eventID)
+}
+
+func (repo instrumentedRepository) InviteAttendee(
+	ctx context.Context,
+	eventID, userID uuid.UUID,
+) (*calendar.Attendee, error) {
+	defer repo.observe("InviteAttendee", time.Now())
+
+	return repo.r.InviteAttendee(ctx, eventID, userID)
+}
+
+func (repo instrumentedRepository) RemoveAttendee(ctx context.Context, eventID, userID uuid.UUID) error {
+	defer repo.observe("RemoveAttendee", time.Now())
+
+	return repo.r.RemoveAttendee(ctx, eventID, userID)
+}
+
+func (repo instrumentedRepository) RespondAttendee(
+	ctx context.Context,
+	eventID, userID uuid.UUID,
+	status calendar.RSVPStatus,
+) (*calendar.Attendee, error) {
+	defer repo.observe("RespondAttendee", time.Now())
+
+	return repo.r.RespondAttendee(ctx, eventID, userID, status)
+}
+
+func (repo instrumentedRepository) ClaimNotifications(ctx context.Context, events ...*calendar.Event) error {
+	defer repo.observe("ClaimNotifications", time.Now())
+
+	return repo.r.ClaimNotifications(ctx, events...)
+}
+
+func (repo instrumentedRepository) FindPendingNotifications(
+	ctx context.Context,
+	limit int,
+) ([]*calendar.Notification, error) {
+	defer repo.observe("FindPendingNotifications", time.Now())
+
+	return repo.r.FindPendingNotifications(ctx, limit)
+}
+
+func (repo instrumentedRepository) MarkNotificationsPublished(ctx context.Context, ids ...uuid.UUID) error {
+	defer repo.observe("MarkNotificationsPublished", time.Now())
+
+	return repo.r.MarkNotificationsPublished(ctx, ids...)
+}
+
+func (repo instrumentedRepository) IsNotificationDelivered(ctx context.Context, id uuid.UUID) (bool, error) {
+	defer repo.observe("IsNotificationDelivered", time.Now())
+
+	return repo.r.IsNotificationDelivered(ctx, id)
+}
+
+func (repo instrumentedRepository) AckNotification(ctx context.Context, id uuid.UUID) error {
+	defer repo.observe("AckNotification", time.Now())
+
+	return repo.r.AckNotification(ctx, id)
+}
@@ -0,0 +1,47 @@
+package metrics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/inmem"
+)
+
+func TestInstrumentRepository(t *testing.T) {
+	ctx := context.Background()
+	// уникальное имя хранилища создает новые ряды метрики при каждом запуске теста
+	repo := InstrumentRepository("test-"+uuid.NewString(), inmem.New())
+
+	before := testutil.CollectAndCount(RepositoryCallDuration)
+
+	e, err := repo.CreateEvent(ctx, &calendar.Event{
+		Title:   "foo",
+		StartAt: mustParseDateTime("2022-10-03 10:00:00"),
+		EndAt:   mustParseDateTime("2022-10-03 11:00:00"),
+		UserID:  uuid.New(),
+	})
+	require.NoError(t, err)
+
+	got, err := repo.FindEventByID(ctx, e.ID)
+	require.NoError(t, err)
+	require.Equal(t, e.ID, got.ID)
+
+	_, err = repo.FindEventByID(ctx, uuid.New())
+	require.ErrorIs(t, err, calendar.ErrNotFound)
+
+	require.Equal(t, before+2, testutil.CollectAndCount(RepositoryCallDuration))
+}
+
+func mustParseDateTime(str string) time.Time {
+	dt, err := time.Parse("2006-01-02 15:04:05", str)
+	if err != nil {
+		panic(err)
+	}
+	return dt
+}
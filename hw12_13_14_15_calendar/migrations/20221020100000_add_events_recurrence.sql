@@ -0,0 +1,15 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table events
+    add rrule          text not null default '',
+    add exdates        text not null default '',
+    add notified_until timestamp;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table events
+    drop column rrule,
+    drop column exdates,
+    drop column notified_until;
+-- +goose StatementEnd
@@ -0,0 +1,16 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table events
+    add uid text not null default '';
+
+create index events_user_id_uid_index
+    on events (user_id, uid);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop index events_user_id_uid_index;
+
+alter table events
+    drop column uid;
+-- +goose StatementEnd
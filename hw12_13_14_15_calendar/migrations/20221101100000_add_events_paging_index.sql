@@ -0,0 +1,10 @@
+-- +goose Up
+-- +goose StatementBegin
+create index events_user_id_start_at_id_index
+    on events (user_id, start_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+drop index events_user_id_start_at_id_index;
+-- +goose StatementEnd
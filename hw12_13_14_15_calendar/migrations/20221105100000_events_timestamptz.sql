@@ -0,0 +1,17 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table events
+    alter column start_at type timestamptz using start_at at time zone 'UTC',
+    alter column end_at type timestamptz using end_at at time zone 'UTC',
+    alter column notified_until type timestamptz using notified_until at time zone 'UTC',
+    add time_zone text not null default '';
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table events
+    alter column start_at type timestamp using start_at at time zone 'UTC',
+    alter column end_at type timestamp using end_at at time zone 'UTC',
+    alter column notified_until type timestamp using notified_until at time zone 'UTC',
+    drop column time_zone;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+create table event_attendees
+(
+    event_id uuid not null
+        constraint event_attendees_event_id_fk
+            references events
+            on delete cascade,
+    user_id  uuid not null,
+    status   text not null default 'needs-action',
+    constraint event_attendees_pk
+        primary key (event_id, user_id)
+);
+
+alter table event_attendees
+    owner to calendar;
+
+create index event_attendees_user_id_status_index
+    on event_attendees (user_id, status);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS event_attendees;
+-- +goose StatementEnd
@@ -0,0 +1,33 @@
+-- +goose Up
+-- +goose StatementBegin
+create table notification_outbox
+(
+    id             uuid        not null
+        constraint notification_outbox_pk
+            primary key,
+    event_id       uuid        not null
+        constraint notification_outbox_event_id_fk
+            references events
+            on delete cascade,
+    event_title    text        not null,
+    event_start_at timestamptz not null,
+    user_id        uuid        not null,
+    created_at     timestamptz not null default now(),
+    published_at   timestamptz,
+    delivered_at   timestamptz,
+    constraint notification_outbox_reminder_uindex
+        unique (event_id, event_start_at, user_id)
+);
+
+alter table notification_outbox
+    owner to calendar;
+
+create index notification_outbox_pending_index
+    on notification_outbox (created_at)
+    where published_at is null;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_outbox;
+-- +goose StatementEnd
@@ -0,0 +1,49 @@
+-- +goose Up
+-- +goose StatementBegin
+create extension if not exists btree_gist;
+-- +goose StatementEnd
+
+-- события, сохраненные до появления ограничения, могут пересекаться.
+-- какое из них оставить, решает владелец, поэтому миграция не меняет данные,
+-- а завершается ошибкой со списком пересечений: после их устранения миграцию нужно запустить снова
+-- +goose StatementBegin
+do $$
+declare
+    conflicts text;
+    total     bigint;
+begin
+    select count(*),
+           string_agg(format('user %s: %s and %s', a.user_id, a.id, b.id), E'\n')
+    into total, conflicts
+    from events a
+             join events b
+                  on b.user_id = a.user_id
+                      and b.id > a.id
+                      and b.rrule = ''
+                      and b.start_at < b.end_at
+                      and tstzrange(a.start_at, a.end_at) && tstzrange(b.start_at, b.end_at)
+    where a.rrule = ''
+      and a.start_at < a.end_at;
+
+    if total > 0 then
+        raise exception 'cannot add events_user_id_period_excl: % pairs of overlapping events', total
+            using detail = conflicts,
+                hint = 'move or delete one event of each pair and run the migration again';
+    end if;
+end
+$$;
+-- +goose StatementEnd
+
+-- +goose StatementBegin
+-- повторяющиеся события и принятые приглашения проверяются приложением под блокировкой пользователя
+alter table events
+    add constraint events_user_id_period_excl
+        exclude using gist (user_id with =, tstzrange(start_at, end_at) with &&)
+        where (rrule = '' and start_at < end_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table events
+    drop constraint events_user_id_period_excl;
+-- +goose StatementEnd
@@ -0,0 +1,37 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table events
+    add column deleted_at timestamptz;
+
+create index events_deleted_at_index
+    on events (deleted_at)
+    where deleted_at is not null;
+
+-- события в корзине не занимают время пользователя
+alter table events
+    drop constraint events_user_id_period_excl;
+
+alter table events
+    add constraint events_user_id_period_excl
+        exclude using gist (user_id with =, tstzrange(start_at, end_at) with &&)
+        where (rrule = '' and start_at < end_at and deleted_at is null);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+delete from events
+    where deleted_at is not null;
+
+alter table events
+    drop constraint events_user_id_period_excl;
+
+alter table events
+    add constraint events_user_id_period_excl
+        exclude using gist (user_id with =, tstzrange(start_at, end_at) with &&)
+        where (rrule = '' and start_at < end_at);
+
+drop index events_deleted_at_index;
+
+alter table events
+    drop column deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,28 @@
+-- +goose Up
+-- +goose StatementBegin
+-- журнал только дополняется и не ссылается на events,
+-- чтобы история сохранялась после окончательного удаления события
+create table event_revisions
+(
+    id         uuid        not null
+        constraint event_revisions_pk
+            primary key,
+    event_id   uuid        not null,
+    action     text        not null,
+    actor_id   uuid        not null,
+    created_at timestamptz not null,
+    changes    jsonb       not null default '[]',
+    state      jsonb       not null
+);
+
+alter table event_revisions
+    owner to calendar;
+
+create index event_revisions_event_id_created_at_index
+    on event_revisions (event_id, created_at);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS event_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,23 @@
+-- +goose Up
+-- +goose StatementBegin
+-- журнал изменений для подписчиков: записывается в транзакции изменения,
+-- ревизия назначается в порядке фиксации транзакций
+create table event_changes
+(
+    revision   bigserial   not null
+        constraint event_changes_pk
+            primary key,
+    type       text        not null,
+    event      jsonb       not null,
+    user_ids   uuid[]      not null,
+    created_at timestamptz not null default now()
+);
+
+alter table event_changes
+    owner to calendar;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS event_changes;
+-- +goose StatementEnd
@@ -0,0 +1,38 @@
+-- +goose Up
+-- +goose StatementBegin
+create table events
+(
+    id                    text      not null
+        constraint events_pk
+            primary key,
+    uid                   text      not null default '',
+    title                 text      not null,
+    description           text,
+    start_at              timestamp not null,
+    end_at                timestamp not null,
+    time_zone             text      not null default '',
+    user_id               text      not null,
+    notification_duration integer,
+    is_notified           boolean,
+    rrule                 text      not null default '',
+    exdates               text      not null default '',
+    notified_until        timestamp
+);
+
+create index events_user_id_start_at_end_at_index
+    on events (user_id, start_at, end_at);
+
+create index events_user_id_notification_duration_is_notified_index
+    on events (user_id, notification_duration, is_notified);
+
+create index events_user_id_uid_index
+    on events (user_id, uid);
+
+create index events_user_id_start_at_id_index
+    on events (user_id, start_at, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS events;
+-- +goose StatementEnd
@@ -0,0 +1,30 @@
+-- +goose Up
+-- +goose StatementBegin
+create table notification_outbox
+(
+    id             text      not null
+        constraint notification_outbox_pk
+            primary key,
+    event_id       text      not null
+        constraint notification_outbox_event_id_fk
+            references events
+            on delete cascade,
+    event_title    text      not null,
+    event_start_at timestamp not null,
+    user_id        text      not null,
+    created_at     timestamp not null default current_timestamp,
+    published_at   timestamp,
+    delivered_at   timestamp,
+    constraint notification_outbox_reminder_uindex
+        unique (event_id, event_start_at, user_id)
+);
+
+create index notification_outbox_pending_index
+    on notification_outbox (created_at)
+    where published_at is null;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS notification_outbox;
+-- +goose StatementEnd
@@ -0,0 +1,20 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table events
+    add column deleted_at timestamp;
+
+create index events_deleted_at_index
+    on events (deleted_at)
+    where deleted_at is not null;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+delete from events
+    where deleted_at is not null;
+
+drop index events_deleted_at_index;
+
+alter table events
+    drop column deleted_at;
+-- +goose StatementEnd
@@ -0,0 +1,25 @@
+-- +goose Up
+-- +goose StatementBegin
+-- журнал только дополняется и не ссылается на events,
+-- чтобы история сохранялась после окончательного удаления события
+create table event_revisions
+(
+    id         text      not null
+        constraint event_revisions_pk
+            primary key,
+    event_id   text      not null,
+    action     text      not null,
+    actor_id   text      not null,
+    created_at timestamp not null,
+    changes    text      not null default '[]',
+    state      text      not null
+);
+
+create index event_revisions_event_id_index
+    on event_revisions (event_id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS event_revisions;
+-- +goose StatementEnd
@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+alter table events
+    add column version bigint not null default 1;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+alter table events
+    drop column version;
+-- +goose StatementEnd
@@ -6,11 +6,10 @@ import (
 	context "context"
 
 	event "github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
-
-	grpc "google.golang.org/grpc"
-
 	mock "github.com/stretchr/testify/mock"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
+	grpc "google.golang.org/grpc"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // EventServiceClient is an autogenerated mock type for the EventServiceClient type
@@ -78,6 +77,96 @@ func (_m *EventServiceClient) DeleteEventV1(ctx context.Context, in *event.Delet
 	return r0, r1
 }
 
+// ExportEventsV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) ExportEventsV1(ctx context.Context, in *event.ExportEventsRequestV1, opts ...grpc.CallOption) (*httpbody.HttpBody, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *httpbody.HttpBody
+	if rf, ok := ret.Get(0).(func(context.Context, *event.ExportEventsRequestV1, ...grpc.CallOption) *httpbody.HttpBody); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*httpbody.HttpBody)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.ExportEventsRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindMeetingSlotsV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) FindMeetingSlotsV1(ctx context.Context, in *event.FindMeetingSlotsRequestV1, opts ...grpc.CallOption) (*event.FindMeetingSlotsResponseV1, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *event.FindMeetingSlotsResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.FindMeetingSlotsRequestV1, ...grpc.CallOption) *event.FindMeetingSlotsResponseV1); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.FindMeetingSlotsResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.FindMeetingSlotsRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEventHistoryV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) GetEventHistoryV1(ctx context.Context, in *event.GetEventHistoryRequestV1, opts ...grpc.CallOption) (*event.GetEventHistoryResponseV1, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *event.GetEventHistoryResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.GetEventHistoryRequestV1, ...grpc.CallOption) *event.GetEventHistoryResponseV1); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.GetEventHistoryResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.GetEventHistoryRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEventsForDayV1 provides a mock function with given fields: ctx, in, opts
 func (_m *EventServiceClient) GetEventsForDayV1(ctx context.Context, in *event.GetEventsForDayRequestV1, opts ...grpc.CallOption) (*event.EventsResponseV1, error) {
 	_va := make([]interface{}, len(opts))
@@ -168,6 +257,306 @@ func (_m *EventServiceClient) GetEventsForWeekV1(ctx context.Context, in *event.
 	return r0, r1
 }
 
+// GetFreeBusyV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) GetFreeBusyV1(ctx context.Context, in *event.GetFreeBusyRequestV1, opts ...grpc.CallOption) (*event.GetFreeBusyResponseV1, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *event.GetFreeBusyResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.GetFreeBusyRequestV1, ...grpc.CallOption) *event.GetFreeBusyResponseV1); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.GetFreeBusyResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.GetFreeBusyRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportEventsV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) ImportEventsV1(ctx context.Context, in *event.ImportEventsRequestV1, opts ...grpc.CallOption) (*event.ImportEventsResponseV1, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *event.ImportEventsResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.ImportEventsRequestV1, ...grpc.CallOption) *event.ImportEventsResponseV1); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.ImportEventsResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.ImportEventsRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InviteAttendeeV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) InviteAttendeeV1(ctx context.Context, in *event.InviteAttendeeRequestV1, opts ...grpc.CallOption) (*event.AttendeeResponseV1, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *event.AttendeeResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.InviteAttendeeRequestV1, ...grpc.CallOption) *event.AttendeeResponseV1); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.AttendeeResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.InviteAttendeeRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTrashV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) ListTrashV1(ctx context.Context, in *event.ListTrashRequestV1, opts ...grpc.CallOption) (*event.EventsResponseV1, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *event.EventsResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.ListTrashRequestV1, ...grpc.CallOption) *event.EventsResponseV1); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.EventsResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.ListTrashRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchEventV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) PatchEventV1(ctx context.Context, in *event.PatchEventRequestV1, opts ...grpc.CallOption) (*event.EventResponseV1, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *event.EventResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.PatchEventRequestV1, ...grpc.CallOption) *event.EventResponseV1); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.EventResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.PatchEventRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeEventV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) PurgeEventV1(ctx context.Context, in *event.PurgeEventRequestV1, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *event.PurgeEventRequestV1, ...grpc.CallOption) *emptypb.Empty); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.PurgeEventRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveAttendeeV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) RemoveAttendeeV1(ctx context.Context, in *event.RemoveAttendeeRequestV1, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *event.RemoveAttendeeRequestV1, ...grpc.CallOption) *emptypb.Empty); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.RemoveAttendeeRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RespondToEventV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) RespondToEventV1(ctx context.Context, in *event.RespondToEventRequestV1, opts ...grpc.CallOption) (*event.AttendeeResponseV1, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *event.AttendeeResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.RespondToEventRequestV1, ...grpc.CallOption) *event.AttendeeResponseV1); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.AttendeeResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.RespondToEventRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreEventV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) RestoreEventV1(ctx context.Context, in *event.RestoreEventRequestV1, opts ...grpc.CallOption) (*event.EventResponseV1, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *event.EventResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.RestoreEventRequestV1, ...grpc.CallOption) *event.EventResponseV1); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.EventResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.RestoreEventRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevertEventV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) RevertEventV1(ctx context.Context, in *event.RevertEventRequestV1, opts ...grpc.CallOption) (*event.EventResponseV1, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 *event.EventResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.RevertEventRequestV1, ...grpc.CallOption) *event.EventResponseV1); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.EventResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.RevertEventRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateEventV1 provides a mock function with given fields: ctx, in, opts
 func (_m *EventServiceClient) UpdateEventV1(ctx context.Context, in *event.UpdateEventRequestV1, opts ...grpc.CallOption) (*event.EventResponseV1, error) {
 	_va := make([]interface{}, len(opts))
@@ -198,6 +587,36 @@ func (_m *EventServiceClient) UpdateEventV1(ctx context.Context, in *event.Updat
 	return r0, r1
 }
 
+// WatchEventsV1 provides a mock function with given fields: ctx, in, opts
+func (_m *EventServiceClient) WatchEventsV1(ctx context.Context, in *event.WatchEventsRequestV1, opts ...grpc.CallOption) (event.EventService_WatchEventsV1Client, error) {
+	_va := make([]interface{}, len(opts))
+	for _i := range opts {
+		_va[_i] = opts[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx, in)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 event.EventService_WatchEventsV1Client
+	if rf, ok := ret.Get(0).(func(context.Context, *event.WatchEventsRequestV1, ...grpc.CallOption) event.EventService_WatchEventsV1Client); ok {
+		r0 = rf(ctx, in, opts...)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(event.EventService_WatchEventsV1Client)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.WatchEventsRequestV1, ...grpc.CallOption) error); ok {
+		r1 = rf(ctx, in, opts...)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 type mockConstructorTestingTNewEventServiceClient interface {
 	mock.TestingT
 	Cleanup(func())
@@ -6,9 +6,9 @@ import (
 	context "context"
 
 	event "github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
-	emptypb "google.golang.org/protobuf/types/known/emptypb"
-
 	mock "github.com/stretchr/testify/mock"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
 )
 
 // EventServiceServer is an autogenerated mock type for the EventServiceServer type
@@ -62,6 +62,75 @@ func (_m *EventServiceServer) DeleteEventV1(_a0 context.Context, _a1 *event.Dele
 	return r0, r1
 }
 
+// ExportEventsV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) ExportEventsV1(_a0 context.Context, _a1 *event.ExportEventsRequestV1) (*httpbody.HttpBody, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *httpbody.HttpBody
+	if rf, ok := ret.Get(0).(func(context.Context, *event.ExportEventsRequestV1) *httpbody.HttpBody); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*httpbody.HttpBody)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.ExportEventsRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindMeetingSlotsV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) FindMeetingSlotsV1(_a0 context.Context, _a1 *event.FindMeetingSlotsRequestV1) (*event.FindMeetingSlotsResponseV1, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *event.FindMeetingSlotsResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.FindMeetingSlotsRequestV1) *event.FindMeetingSlotsResponseV1); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.FindMeetingSlotsResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.FindMeetingSlotsRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetEventHistoryV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) GetEventHistoryV1(_a0 context.Context, _a1 *event.GetEventHistoryRequestV1) (*event.GetEventHistoryResponseV1, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *event.GetEventHistoryResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.GetEventHistoryRequestV1) *event.GetEventHistoryResponseV1); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.GetEventHistoryResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.GetEventHistoryRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetEventsForDayV1 provides a mock function with given fields: _a0, _a1
 func (_m *EventServiceServer) GetEventsForDayV1(_a0 context.Context, _a1 *event.GetEventsForDayRequestV1) (*event.EventsResponseV1, error) {
 	ret := _m.Called(_a0, _a1)
@@ -131,6 +200,236 @@ func (_m *EventServiceServer) GetEventsForWeekV1(_a0 context.Context, _a1 *event
 	return r0, r1
 }
 
+// GetFreeBusyV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) GetFreeBusyV1(_a0 context.Context, _a1 *event.GetFreeBusyRequestV1) (*event.GetFreeBusyResponseV1, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *event.GetFreeBusyResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.GetFreeBusyRequestV1) *event.GetFreeBusyResponseV1); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.GetFreeBusyResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.GetFreeBusyRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ImportEventsV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) ImportEventsV1(_a0 context.Context, _a1 *event.ImportEventsRequestV1) (*event.ImportEventsResponseV1, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *event.ImportEventsResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.ImportEventsRequestV1) *event.ImportEventsResponseV1); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.ImportEventsResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.ImportEventsRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InviteAttendeeV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) InviteAttendeeV1(_a0 context.Context, _a1 *event.InviteAttendeeRequestV1) (*event.AttendeeResponseV1, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *event.AttendeeResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.InviteAttendeeRequestV1) *event.AttendeeResponseV1); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.AttendeeResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.InviteAttendeeRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListTrashV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) ListTrashV1(_a0 context.Context, _a1 *event.ListTrashRequestV1) (*event.EventsResponseV1, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *event.EventsResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.ListTrashRequestV1) *event.EventsResponseV1); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.EventsResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.ListTrashRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchEventV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) PatchEventV1(_a0 context.Context, _a1 *event.PatchEventRequestV1) (*event.EventResponseV1, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *event.EventResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.PatchEventRequestV1) *event.EventResponseV1); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.EventResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.PatchEventRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeEventV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) PurgeEventV1(_a0 context.Context, _a1 *event.PurgeEventRequestV1) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *event.PurgeEventRequestV1) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.PurgeEventRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveAttendeeV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) RemoveAttendeeV1(_a0 context.Context, _a1 *event.RemoveAttendeeRequestV1) (*emptypb.Empty, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *emptypb.Empty
+	if rf, ok := ret.Get(0).(func(context.Context, *event.RemoveAttendeeRequestV1) *emptypb.Empty); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*emptypb.Empty)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.RemoveAttendeeRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RespondToEventV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) RespondToEventV1(_a0 context.Context, _a1 *event.RespondToEventRequestV1) (*event.AttendeeResponseV1, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *event.AttendeeResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.RespondToEventRequestV1) *event.AttendeeResponseV1); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.AttendeeResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.RespondToEventRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreEventV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) RestoreEventV1(_a0 context.Context, _a1 *event.RestoreEventRequestV1) (*event.EventResponseV1, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *event.EventResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.RestoreEventRequestV1) *event.EventResponseV1); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.EventResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.RestoreEventRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RevertEventV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) RevertEventV1(_a0 context.Context, _a1 *event.RevertEventRequestV1) (*event.EventResponseV1, error) {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 *event.EventResponseV1
+	if rf, ok := ret.Get(0).(func(context.Context, *event.RevertEventRequestV1) *event.EventResponseV1); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*event.EventResponseV1)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, *event.RevertEventRequestV1) error); ok {
+		r1 = rf(_a0, _a1)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateEventV1 provides a mock function with given fields: _a0, _a1
 func (_m *EventServiceServer) UpdateEventV1(_a0 context.Context, _a1 *event.UpdateEventRequestV1) (*event.EventResponseV1, error) {
 	ret := _m.Called(_a0, _a1)
@@ -154,6 +453,20 @@ func (_m *EventServiceServer) UpdateEventV1(_a0 context.Context, _a1 *event.Upda
 	return r0, r1
 }
 
+// WatchEventsV1 provides a mock function with given fields: _a0, _a1
+func (_m *EventServiceServer) WatchEventsV1(_a0 *event.WatchEventsRequestV1, _a1 event.EventService_WatchEventsV1Server) error {
+	ret := _m.Called(_a0, _a1)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(*event.WatchEventsRequestV1, event.EventService_WatchEventsV1Server) error); ok {
+		r0 = rf(_a0, _a1)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // mustEmbedUnimplementedEventServiceServer provides a mock function with given fields:
 func (_m *EventServiceServer) mustEmbedUnimplementedEventServiceServer() {
 	_m.Called()
@@ -6,10 +6,8 @@ import (
 	context "context"
 
 	calendar "github.com/RomanSarvarov/otus_go_home_work/calendar"
-
-	mock "github.com/stretchr/testify/mock"
-
 	uuid "github.com/google/uuid"
+	mock "github.com/stretchr/testify/mock"
 )
 
 // Repository is an autogenerated mock type for the Repository type
@@ -84,6 +82,29 @@ func (_m *Repository) FindEventByID(ctx context.Context, id uuid.UUID) (*calenda
 	return r0, r1
 }
 
+// FindEventRevisions provides a mock function with given fields: ctx, eventID
+func (_m *Repository) FindEventRevisions(ctx context.Context, eventID uuid.UUID) ([]*calendar.Revision, error) {
+	ret := _m.Called(ctx, eventID)
+
+	var r0 []*calendar.Revision
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) []*calendar.Revision); ok {
+		r0 = rf(ctx, eventID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*calendar.Revision)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, eventID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // FindEvents provides a mock function with given fields: ctx, filter
 func (_m *Repository) FindEvents(ctx context.Context, filter calendar.EventFilter) ([]*calendar.Event, error) {
 	ret := _m.Called(ctx, filter)
@@ -107,6 +128,156 @@ func (_m *Repository) FindEvents(ctx context.Context, filter calendar.EventFilte
 	return r0, r1
 }
 
+// FindTrash provides a mock function with given fields: ctx, filter
+func (_m *Repository) FindTrash(ctx context.Context, filter calendar.TrashFilter) ([]*calendar.Event, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*calendar.Event
+	if rf, ok := ret.Get(0).(func(context.Context, calendar.TrashFilter) []*calendar.Event); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*calendar.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, calendar.TrashFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// InviteAttendee provides a mock function with given fields: ctx, eventID, userID
+func (_m *Repository) InviteAttendee(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) (*calendar.Attendee, error) {
+	ret := _m.Called(ctx, eventID, userID)
+
+	var r0 *calendar.Attendee
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) *calendar.Attendee); ok {
+		r0 = rf(ctx, eventID, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*calendar.Attendee)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r1 = rf(ctx, eventID, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PatchEvent provides a mock function with given fields: ctx, id, e, fields
+func (_m *Repository) PatchEvent(ctx context.Context, id uuid.UUID, e *calendar.Event, fields []calendar.EventField) (*calendar.Event, error) {
+	ret := _m.Called(ctx, id, e, fields)
+
+	var r0 *calendar.Event
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *calendar.Event, []calendar.EventField) *calendar.Event); ok {
+		r0 = rf(ctx, id, e, fields)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*calendar.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *calendar.Event, []calendar.EventField) error); ok {
+		r1 = rf(ctx, id, e, fields)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// PurgeEvent provides a mock function with given fields: ctx, ids
+func (_m *Repository) PurgeEvent(ctx context.Context, ids ...uuid.UUID) error {
+	_va := make([]interface{}, len(ids))
+	for _i := range ids {
+		_va[_i] = ids[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...uuid.UUID) error); ok {
+		r0 = rf(ctx, ids...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RemoveAttendee provides a mock function with given fields: ctx, eventID, userID
+func (_m *Repository) RemoveAttendee(ctx context.Context, eventID uuid.UUID, userID uuid.UUID) error {
+	ret := _m.Called(ctx, eventID, userID)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID) error); ok {
+		r0 = rf(ctx, eventID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RespondAttendee provides a mock function with given fields: ctx, eventID, userID, status
+func (_m *Repository) RespondAttendee(ctx context.Context, eventID uuid.UUID, userID uuid.UUID, status calendar.RSVPStatus) (*calendar.Attendee, error) {
+	ret := _m.Called(ctx, eventID, userID, status)
+
+	var r0 *calendar.Attendee
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, uuid.UUID, calendar.RSVPStatus) *calendar.Attendee); ok {
+		r0 = rf(ctx, eventID, userID, status)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*calendar.Attendee)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, uuid.UUID, calendar.RSVPStatus) error); ok {
+		r1 = rf(ctx, eventID, userID, status)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RestoreEvent provides a mock function with given fields: ctx, id
+func (_m *Repository) RestoreEvent(ctx context.Context, id uuid.UUID) (*calendar.Event, error) {
+	ret := _m.Called(ctx, id)
+
+	var r0 *calendar.Event
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) *calendar.Event); ok {
+		r0 = rf(ctx, id)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*calendar.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UpdateEvent provides a mock function with given fields: ctx, id, e
 func (_m *Repository) UpdateEvent(ctx context.Context, id uuid.UUID, e *calendar.Event) (*calendar.Event, error) {
 	ret := _m.Called(ctx, id, e)
@@ -6,7 +6,6 @@ import (
 	context "context"
 
 	calendar "github.com/RomanSarvarov/otus_go_home_work/calendar"
-
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -15,11 +14,11 @@ type Broker struct {
 	mock.Mock
 }
 
-// SendEventToQueue provides a mock function with given fields: ctx, events
-func (_m *Broker) SendEventToQueue(ctx context.Context, events ...*calendar.Event) error {
-	_va := make([]interface{}, len(events))
-	for _i := range events {
-		_va[_i] = events[_i]
+// SendNotificationToQueue provides a mock function with given fields: ctx, notifications
+func (_m *Broker) SendNotificationToQueue(ctx context.Context, notifications ...*calendar.Notification) error {
+	_va := make([]interface{}, len(notifications))
+	for _i := range notifications {
+		_va[_i] = notifications[_i]
 	}
 	var _ca []interface{}
 	_ca = append(_ca, ctx)
@@ -27,8 +26,8 @@ func (_m *Broker) SendEventToQueue(ctx context.Context, events ...*calendar.Even
 	ret := _m.Called(_ca...)
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, ...*calendar.Event) error); ok {
-		r0 = rf(ctx, events...)
+	if rf, ok := ret.Get(0).(func(context.Context, ...*calendar.Notification) error); ok {
+		r0 = rf(ctx, notifications...)
 	} else {
 		r0 = ret.Error(0)
 	}
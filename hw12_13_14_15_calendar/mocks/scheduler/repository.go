@@ -6,7 +6,6 @@ import (
 	context "context"
 
 	calendar "github.com/RomanSarvarov/otus_go_home_work/calendar"
-
 	mock "github.com/stretchr/testify/mock"
 
 	uuid "github.com/google/uuid"
@@ -17,6 +16,27 @@ type Repository struct {
 	mock.Mock
 }
 
+// ClaimNotifications provides a mock function with given fields: ctx, events
+func (_m *Repository) ClaimNotifications(ctx context.Context, events ...*calendar.Event) error {
+	_va := make([]interface{}, len(events))
+	for _i := range events {
+		_va[_i] = events[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...*calendar.Event) error); ok {
+		r0 = rf(ctx, events...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteEvent provides a mock function with given fields: ctx, ids
 func (_m *Repository) DeleteEvent(ctx context.Context, ids ...uuid.UUID) error {
 	_va := make([]interface{}, len(ids))
@@ -61,6 +81,94 @@ func (_m *Repository) FindEvents(ctx context.Context, filter calendar.EventFilte
 	return r0, r1
 }
 
+// FindPendingNotifications provides a mock function with given fields: ctx, limit
+func (_m *Repository) FindPendingNotifications(ctx context.Context, limit int) ([]*calendar.Notification, error) {
+	ret := _m.Called(ctx, limit)
+
+	var r0 []*calendar.Notification
+	if rf, ok := ret.Get(0).(func(context.Context, int) []*calendar.Notification); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*calendar.Notification)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// FindTrash provides a mock function with given fields: ctx, filter
+func (_m *Repository) FindTrash(ctx context.Context, filter calendar.TrashFilter) ([]*calendar.Event, error) {
+	ret := _m.Called(ctx, filter)
+
+	var r0 []*calendar.Event
+	if rf, ok := ret.Get(0).(func(context.Context, calendar.TrashFilter) []*calendar.Event); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*calendar.Event)
+		}
+	}
+
+	var r1 error
+	if rf, ok := ret.Get(1).(func(context.Context, calendar.TrashFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkNotificationsPublished provides a mock function with given fields: ctx, ids
+func (_m *Repository) MarkNotificationsPublished(ctx context.Context, ids ...uuid.UUID) error {
+	_va := make([]interface{}, len(ids))
+	for _i := range ids {
+		_va[_i] = ids[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...uuid.UUID) error); ok {
+		r0 = rf(ctx, ids...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// PurgeEvent provides a mock function with given fields: ctx, ids
+func (_m *Repository) PurgeEvent(ctx context.Context, ids ...uuid.UUID) error {
+	_va := make([]interface{}, len(ids))
+	for _i := range ids {
+		_va[_i] = ids[_i]
+	}
+	var _ca []interface{}
+	_ca = append(_ca, ctx)
+	_ca = append(_ca, _va...)
+	ret := _m.Called(_ca...)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, ...uuid.UUID) error); ok {
+		r0 = rf(ctx, ids...)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewRepository interface {
 	mock.TestingT
 	Cleanup(func())
@@ -6,7 +6,6 @@ import (
 	context "context"
 
 	calendar "github.com/RomanSarvarov/otus_go_home_work/calendar"
-
 	mock "github.com/stretchr/testify/mock"
 )
 
@@ -15,27 +14,50 @@ type Broker struct {
 	mock.Mock
 }
 
-// ReadEventFromQueue provides a mock function with given fields: ctx
-func (_m *Broker) ReadEventFromQueue(ctx context.Context) (*calendar.Event, error) {
+// CommitNotification provides a mock function with given fields: ctx, n
+func (_m *Broker) CommitNotification(ctx context.Context, n *calendar.Notification) error {
+	ret := _m.Called(ctx, n)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, *calendar.Notification) error); ok {
+		r0 = rf(ctx, n)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReadNotificationFromQueue provides a mock function with given fields: ctx
+func (_m *Broker) ReadNotificationFromQueue(ctx context.Context) (context.Context, *calendar.Notification, error) {
 	ret := _m.Called(ctx)
 
-	var r0 *calendar.Event
-	if rf, ok := ret.Get(0).(func(context.Context) *calendar.Event); ok {
+	var r0 context.Context
+	if rf, ok := ret.Get(0).(func(context.Context) context.Context); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*calendar.Event)
+			r0 = ret.Get(0).(context.Context)
 		}
 	}
 
-	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context) error); ok {
+	var r1 *calendar.Notification
+	if rf, ok := ret.Get(1).(func(context.Context) *calendar.Notification); ok {
 		r1 = rf(ctx)
 	} else {
-		r1 = ret.Error(1)
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(*calendar.Notification)
+		}
+	}
+
+	var r2 error
+	if rf, ok := ret.Get(2).(func(context.Context) error); ok {
+		r2 = rf(ctx)
+	} else {
+		r2 = ret.Error(2)
 	}
 
-	return r0, r1
+	return r0, r1, r2
 }
 
 type mockConstructorTestingTNewBroker interface {
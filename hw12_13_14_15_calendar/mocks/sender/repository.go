@@ -5,8 +5,6 @@ package mocks
 import (
 	context "context"
 
-	calendar "github.com/RomanSarvarov/otus_go_home_work/calendar"
-
 	mock "github.com/stretchr/testify/mock"
 
 	uuid "github.com/google/uuid"
@@ -17,22 +15,34 @@ type Repository struct {
 	mock.Mock
 }
 
-// UpdateEvent provides a mock function with given fields: ctx, id, e
-func (_m *Repository) UpdateEvent(ctx context.Context, id uuid.UUID, e *calendar.Event) (*calendar.Event, error) {
-	ret := _m.Called(ctx, id, e)
+// AckNotification provides a mock function with given fields: ctx, id
+func (_m *Repository) AckNotification(ctx context.Context, id uuid.UUID) error {
+	ret := _m.Called(ctx, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) error); ok {
+		r0 = rf(ctx, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// IsNotificationDelivered provides a mock function with given fields: ctx, id
+func (_m *Repository) IsNotificationDelivered(ctx context.Context, id uuid.UUID) (bool, error) {
+	ret := _m.Called(ctx, id)
 
-	var r0 *calendar.Event
-	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID, *calendar.Event) *calendar.Event); ok {
-		r0 = rf(ctx, id, e)
+	var r0 bool
+	if rf, ok := ret.Get(0).(func(context.Context, uuid.UUID) bool); ok {
+		r0 = rf(ctx, id)
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(*calendar.Event)
-		}
+		r0 = ret.Get(0).(bool)
 	}
 
 	var r1 error
-	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID, *calendar.Event) error); ok {
-		r1 = rf(ctx, id, e)
+	if rf, ok := ret.Get(1).(func(context.Context, uuid.UUID) error); ok {
+		r1 = rf(ctx, id)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -6,18 +6,55 @@ import (
 	"github.com/google/uuid"
 )
 
-// Notification (уведомление) - временная сущность,
-// в БД не хранится, складывается в очередь для рассыльщика.
+// Notification (уведомление) о начале события.
+// Планировщик сохраняет уведомления в исходящий ящик (outbox) хранилища,
+// откуда они публикуются в очередь для рассыльщика.
 type Notification struct {
+	// ID идентификатор уведомления.
+	ID uuid.UUID `db:"id"`
+
 	// EventID идентификатор события.
-	EventID uuid.UUID
+	EventID uuid.UUID `db:"event_id"`
 
 	// EventTitle заголовок события.
-	EventTitle string
+	EventTitle string `db:"event_title"`
 
-	// EventStartAt дата и время начала события.
-	EventStartAt time.Time
+	// EventStartAt дата и время начала события (для повторяющегося - вхождения).
+	EventStartAt time.Time `db:"event_start_at"`
 
 	// UserID пользователь, кому отправить уведомление.
-	UserID uuid.UUID
+	UserID uuid.UUID `db:"user_id"`
+
+	// Attempts количество неудачных попыток доставки.
+	Attempts int `db:"-"`
+
+	// DeliveredChannels каналы, в которые уведомление доставлено при прошлых попытках.
+	// При повторной попытке уведомление отправляется только в остальные каналы.
+	DeliveredChannels []string `db:"-"`
+}
+
+// NewNotifications формирует уведомления о начале события для владельца
+// и каждого участника, не отклонившего приглашение.
+func NewNotifications(e *Event) []*Notification {
+	recipients := []uuid.UUID{e.UserID}
+
+	for _, a := range e.Attendees {
+		if a.Status != RSVPDeclined {
+			recipients = append(recipients, a.UserID)
+		}
+	}
+
+	res := make([]*Notification, 0, len(recipients))
+
+	for _, userID := range recipients {
+		res = append(res, &Notification{
+			ID:           uuid.New(),
+			EventID:      e.ID,
+			EventTitle:   e.Title,
+			EventStartAt: e.StartAt,
+			UserID:       userID,
+		})
+	}
+
+	return res
 }
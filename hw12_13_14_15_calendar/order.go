@@ -0,0 +1,126 @@
+package calendar
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EventOrder поле, по которому сортируются найденные события.
+type EventOrder string
+
+const (
+	// OrderByStartAt сортировка по дате начала (по умолчанию).
+	OrderByStartAt EventOrder = "start_at"
+
+	// OrderByEndAt сортировка по дате окончания.
+	OrderByEndAt EventOrder = "end_at"
+
+	// OrderByTitle сортировка по заголовку.
+	OrderByTitle EventOrder = "title"
+)
+
+// ParseEventOrder разбирает поле сортировки. Пустая строка означает сортировку по умолчанию.
+func ParseEventOrder(s string) (EventOrder, error) {
+	switch o := EventOrder(s); o {
+	case "":
+		return OrderByStartAt, nil
+	case OrderByStartAt, OrderByEndAt, OrderByTitle:
+		return o, nil
+	}
+
+	return "", ErrInvalidOrder
+}
+
+// EventCursor позиция события в отсортированном списке (для постраничной выборки).
+// Вхождения одной серии различаются датой начала, поэтому набор
+// (поле сортировки, дата начала, идентификатор) однозначно определяет позицию.
+type EventCursor struct {
+	// Title заголовок события.
+	Title string
+
+	// StartAt дата и время начала события (вхождения).
+	StartAt time.Time
+
+	// EndAt дата и время окончания события (вхождения).
+	EndAt time.Time
+
+	// ID идентификатор события.
+	ID uuid.UUID
+}
+
+// CursorOf возвращает позицию события.
+func CursorOf(e *Event) EventCursor {
+	return EventCursor{
+		Title:   e.Title,
+		StartAt: e.StartAt,
+		EndAt:   e.EndAt,
+		ID:      e.ID,
+	}
+}
+
+// Compare сравнивает позиции событий при заданной сортировке.
+// Возвращает -1, если a раньше b, 1, если позже, и 0, если позиции совпадают.
+func (o EventOrder) Compare(a, b EventCursor) int {
+	var c int
+
+	switch o {
+	case OrderByEndAt:
+		c = compareTime(a.EndAt, b.EndAt)
+	case OrderByTitle:
+		// побайтовое сравнение совпадает с COLLATE "C" в PostgreSQL
+		c = strings.Compare(a.Title, b.Title)
+	case OrderByStartAt:
+	}
+
+	if c != 0 {
+		return c
+	}
+
+	if c = compareTime(a.StartAt, b.StartAt); c != 0 {
+		return c
+	}
+
+	return bytes.Compare(a.ID[:], b.ID[:])
+}
+
+// PageEvents сортирует события согласно фильтру и оставляет только
+// события, идущие после filter.After, в количестве не больше filter.Limit.
+func PageEvents(events []*Event, filter EventFilter) []*Event {
+	order := filter.OrderBy
+	if order == "" {
+		order = OrderByStartAt
+	}
+
+	sort.SliceStable(events, func(i, j int) bool {
+		return order.Compare(CursorOf(events[i]), CursorOf(events[j])) < 0
+	})
+
+	if filter.After != nil {
+		i := sort.Search(len(events), func(i int) bool {
+			return order.Compare(CursorOf(events[i]), *filter.After) > 0
+		})
+		events = events[i:]
+	}
+
+	if filter.Limit > 0 && len(events) > filter.Limit {
+		events = events[:filter.Limit]
+	}
+
+	return events
+}
+
+// compareTime сравнивает даты.
+func compareTime(a, b time.Time) int {
+	switch {
+	case a.Before(b):
+		return -1
+	case a.After(b):
+		return 1
+	}
+
+	return 0
+}
@@ -0,0 +1,95 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseEventOrder(t *testing.T) {
+	t.Parallel()
+
+	got, err := ParseEventOrder("")
+	require.NoError(t, err)
+	require.Equal(t, OrderByStartAt, got)
+
+	got, err = ParseEventOrder("title")
+	require.NoError(t, err)
+	require.Equal(t, OrderByTitle, got)
+
+	_, err = ParseEventOrder("user_id")
+	require.ErrorIs(t, err, ErrInvalidOrder)
+}
+
+func TestPageEvents(t *testing.T) {
+	t.Parallel()
+
+	start := mustParseDateTime("2022-10-03 10:00:00")
+	seriesID := uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580")
+
+	first := &Event{ID: seriesID, Title: "b", StartAt: start, EndAt: start.Add(3 * time.Hour)}
+	second := &Event{ID: seriesID, Title: "b", StartAt: start.Add(24 * time.Hour), EndAt: start.Add(25 * time.Hour)}
+	third := &Event{
+		ID:      uuid.MustParse("2f0d2079-e9a2-4810-8cae-eb6729c50580"),
+		Title:   "a",
+		StartAt: start,
+		EndAt:   start.Add(time.Hour),
+	}
+	fourth := &Event{
+		ID:      uuid.MustParse("3f0d2079-e9a2-4810-8cae-eb6729c50580"),
+		Title:   "c",
+		StartAt: start.Add(time.Hour),
+		EndAt:   start.Add(2 * time.Hour),
+	}
+
+	events := func() []*Event {
+		return []*Event{second, fourth, first, third}
+	}
+
+	tests := []struct {
+		name   string
+		filter EventFilter
+		want   []*Event
+	}{
+		{
+			name:   "start_at by default, ties by id",
+			filter: EventFilter{},
+			want:   []*Event{third, first, fourth, second},
+		},
+		{
+			name:   "end_at",
+			filter: EventFilter{OrderBy: OrderByEndAt},
+			want:   []*Event{third, fourth, first, second},
+		},
+		{
+			name:   "title, ties by start_at",
+			filter: EventFilter{OrderBy: OrderByTitle},
+			want:   []*Event{third, first, second, fourth},
+		},
+		{
+			name:   "limit",
+			filter: EventFilter{Limit: 2},
+			want:   []*Event{third, first},
+		},
+		{
+			name:   "after cursor",
+			filter: EventFilter{After: &EventCursor{StartAt: first.StartAt, ID: first.ID}, Limit: 2},
+			want:   []*Event{fourth, second},
+		},
+		{
+			name: "after cursor of occurrence",
+			filter: EventFilter{
+				OrderBy: OrderByTitle,
+				After:   &EventCursor{Title: "b", StartAt: first.StartAt, ID: seriesID},
+			},
+			want: []*Event{second, fourth},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			require.Equal(t, tt.want, PageEvents(events(), tt.filter))
+		})
+	}
+}
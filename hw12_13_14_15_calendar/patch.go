@@ -0,0 +1,132 @@
+package calendar
+
+// EventField поле события, которое можно изменить частичным обновлением.
+// Значение совпадает с именем столбца в хранилище.
+type EventField string
+
+const (
+	// FieldTitle заголовок.
+	FieldTitle EventField = "title"
+
+	// FieldDescription описание.
+	FieldDescription EventField = "description"
+
+	// FieldStartAt дата и время начала.
+	FieldStartAt EventField = "start_at"
+
+	// FieldEndAt дата и время окончания.
+	FieldEndAt EventField = "end_at"
+
+	// FieldTimeZone часовой пояс.
+	FieldTimeZone EventField = "time_zone"
+
+	// FieldNotificationDuration за сколько минут уведомить о начале.
+	FieldNotificationDuration EventField = "notification_duration"
+
+	// FieldRRule правило повторения.
+	FieldRRule EventField = "rrule"
+
+	// FieldExDates исключенные из повторения даты.
+	FieldExDates EventField = "exdates"
+
+	// FieldIsNotified отметка об отправленном уведомлении. Не изменяется пользователем,
+	// а сбрасывается частичным обновлением полей, от которых зависит время уведомления.
+	FieldIsNotified EventField = "is_notified"
+
+	// FieldNotifiedUntil дата последнего вхождения, о котором отправлено уведомление.
+	// Сбрасывается вместе с FieldIsNotified.
+	FieldNotifiedUntil EventField = "notified_until"
+)
+
+// EventFields поля события, которые можно изменить частичным обновлением, в порядке столбцов хранилища.
+var EventFields = []EventField{
+	FieldTitle,
+	FieldDescription,
+	FieldStartAt,
+	FieldEndAt,
+	FieldTimeZone,
+	FieldNotificationDuration,
+	FieldRRule,
+	FieldExDates,
+}
+
+// PatchColumns столбцы хранилища, которые может изменить частичное обновление, в постоянном порядке.
+var PatchColumns = append(append([]EventField(nil), EventFields...), FieldIsNotified, FieldNotifiedUntil)
+
+// ParseEventField разбирает имя поля события.
+func ParseEventField(s string) (EventField, error) {
+	for _, f := range EventFields {
+		if string(f) == s {
+			return f, nil
+		}
+	}
+
+	return "", ErrInvalidField
+}
+
+// Patch копирует в событие значения полей fields из src и возвращает их
+// для сохранения в хранилище. Остальные поля события не меняются.
+// Если изменяется поле, от которого зависит время уведомления, отметка об уведомлении
+// сбрасывается и тоже возвращается для сохранения, чтобы о событии уведомили по новому времени.
+func (e *Event) Patch(src *Event, fields []EventField) (map[EventField]interface{}, error) {
+	values := make(map[EventField]interface{}, len(fields))
+	before := *e
+
+	for _, f := range fields {
+		switch f {
+		case FieldTitle:
+			e.Title = src.Title
+			values[f] = e.Title
+		case FieldDescription:
+			e.Description = src.Description
+			values[f] = e.Description
+		case FieldStartAt:
+			e.StartAt = src.StartAt
+			values[f] = e.StartAt.UTC()
+		case FieldEndAt:
+			e.EndAt = src.EndAt
+			values[f] = e.EndAt.UTC()
+		case FieldTimeZone:
+			e.TimeZone = src.TimeZone
+			values[f] = e.TimeZone
+		case FieldNotificationDuration:
+			e.NotificationDuration = src.NotificationDuration
+			values[f] = e.NotificationDuration
+		case FieldRRule:
+			e.RRule = src.RRule
+			values[f] = e.RRule
+		case FieldExDates:
+			e.ExDates = append(ExDates(nil), src.ExDates...)
+			values[f] = e.ExDates
+		default:
+			return nil, ErrInvalidField
+		}
+	}
+
+	if e.KeepNotification(&before) {
+		values[FieldIsNotified] = e.IsNotified
+		values[FieldNotifiedUntil] = e.NotifiedUntil
+	}
+
+	return values, nil
+}
+
+// KeepNotification переносит в новую версию события отметку об уведомлении из сохраненной версии stored.
+// Если изменилось время начала, часовой пояс, правило повторения или время уведомления,
+// отметка сбрасывается, чтобы о событии уведомили по новому времени. Тогда возвращается true.
+func (e *Event) KeepNotification(stored *Event) bool {
+	if !e.StartAt.Equal(stored.StartAt) ||
+		e.TimeZone != stored.TimeZone ||
+		e.NotificationDuration != stored.NotificationDuration ||
+		e.RRule != stored.RRule {
+		e.IsNotified = false
+		e.NotifiedUntil = nil
+
+		return true
+	}
+
+	e.IsNotified = stored.IsNotified
+	e.NotifiedUntil = stored.NotifiedUntil
+
+	return false
+}
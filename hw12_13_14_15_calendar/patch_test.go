@@ -0,0 +1,76 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEvent_Patch(t *testing.T) {
+	t.Parallel()
+
+	startAt := time.Date(2022, 10, 3, 10, 0, 0, 0, time.FixedZone("MSK", 3*60*60))
+	notifiedUntil := startAt.Add(-24 * time.Hour)
+
+	e := &Event{
+		Title:                "foo",
+		Description:          "bar",
+		StartAt:              startAt,
+		EndAt:                startAt.Add(time.Hour),
+		NotificationDuration: 15,
+		IsNotified:           true,
+		NotifiedUntil:        &notifiedUntil,
+	}
+
+	// поле, не влияющее на время уведомления, не сбрасывает отметку об уведомлении
+	values, err := e.Patch(&Event{Description: "qux"}, []EventField{FieldDescription})
+
+	require.NoError(t, err)
+	require.Equal(t, map[EventField]interface{}{FieldDescription: "qux"}, values)
+	require.True(t, e.IsNotified)
+	require.Equal(t, &notifiedUntil, e.NotifiedUntil)
+
+	values, err = e.Patch(&Event{
+		Title:   "baz",
+		StartAt: startAt.Add(time.Hour),
+	}, []EventField{FieldTitle, FieldStartAt, FieldTitle})
+
+	require.NoError(t, err)
+	require.Equal(t, map[EventField]interface{}{
+		FieldTitle:         "baz",
+		FieldStartAt:       startAt.Add(time.Hour).UTC(),
+		FieldIsNotified:    false,
+		FieldNotifiedUntil: (*time.Time)(nil),
+	}, values)
+
+	require.Equal(t, "baz", e.Title)
+	require.Equal(t, startAt.Add(time.Hour), e.StartAt)
+
+	// о событии уведомят по новому времени
+	require.False(t, e.IsNotified)
+	require.Nil(t, e.NotifiedUntil)
+
+	// поля вне маски не меняются
+	require.Equal(t, "qux", e.Description)
+	require.Equal(t, startAt.Add(time.Hour), e.EndAt)
+	require.Equal(t, uint32(15), e.NotificationDuration)
+
+	_, err = e.Patch(&Event{}, []EventField{"user_id"})
+	require.ErrorIs(t, err, ErrInvalidField)
+}
+
+func TestParseEventField(t *testing.T) {
+	t.Parallel()
+
+	for _, f := range EventFields {
+		got, err := ParseEventField(string(f))
+		require.NoError(t, err)
+		require.Equal(t, f, got)
+	}
+
+	for _, s := range []string{"", "id", "user_id", "is_notified", "notified_until", "version"} {
+		_, err := ParseEventField(s)
+		require.ErrorIs(t, err, ErrInvalidField, s)
+	}
+}
@@ -0,0 +1,142 @@
+package postgres
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// changeLogLock ключ блокировки журнала изменений.
+// Лежит вне диапазона hashtext, которым блокируется время пользователей.
+const changeLogLock int64 = 1 << 40
+
+var _ calendar.ChangeLog = (*Repository)(nil)
+
+// change запись журнала изменений.
+type change struct {
+	Revision uint64              `db:"revision"`
+	Type     calendar.ChangeType `db:"type"`
+	Event    []byte              `db:"event"`
+	UserIDs  pq.StringArray      `db:"user_ids"`
+}
+
+// FindChanges найти изменения в журнале в порядке ревизий.
+func (repo *Repository) FindChanges(ctx context.Context, filter calendar.ChangeFilter) ([]calendar.Change, error) {
+	where, args := []string{"revision > $1"}, []interface{}{filter.After}
+
+	if filter.Until != 0 {
+		args = append(args, filter.Until)
+		where = append(where, "revision <= $"+strconv.Itoa(len(args)))
+	}
+
+	if filter.UserID != uuid.Nil {
+		args = append(args, filter.UserID)
+		where = append(where, "$"+strconv.Itoa(len(args))+" = ANY(user_ids)")
+	}
+
+	query := `SELECT revision, type, event, user_ids FROM event_changes WHERE ` +
+		strings.Join(where, " AND ") + ` ORDER BY revision`
+
+	if filter.Limit > 0 {
+		args = append(args, filter.Limit)
+		query += ` LIMIT $` + strconv.Itoa(len(args))
+	}
+
+	rows := make([]change, 0)
+
+	if err := repo.db.SelectContext(ctx, &rows, query, args...); err != nil {
+		return nil, errors.Wrap(err, "find changes")
+	}
+
+	res := make([]calendar.Change, 0, len(rows))
+
+	for _, row := range rows {
+		c := calendar.Change{
+			Revision: row.Revision,
+			Type:     row.Type,
+			Event:    new(calendar.Event),
+			UserIDs:  make([]uuid.UUID, 0, len(row.UserIDs)),
+		}
+
+		if err := json.Unmarshal(row.Event, c.Event); err != nil {
+			return nil, errors.Wrap(err, "find changes")
+		}
+
+		for _, s := range row.UserIDs {
+			id, err := uuid.Parse(s)
+			if err != nil {
+				return nil, errors.Wrap(err, "find changes")
+			}
+
+			c.UserIDs = append(c.UserIDs, id)
+		}
+
+		res = append(res, c)
+	}
+
+	return res, nil
+}
+
+// LastChangeRevision вернуть ревизию последнего изменения в журнале.
+func (repo *Repository) LastChangeRevision(ctx context.Context) (uint64, error) {
+	var revision uint64
+
+	err := repo.db.GetContext(ctx, &revision, `SELECT COALESCE(MAX(revision), 0) FROM event_changes`)
+
+	return revision, errors.Wrap(err, "last change revision")
+}
+
+// addChange добавить изменение события в журнал изменений в рамках транзакции изменения.
+// Вызывается последним перед фиксацией: журнал блокируется до конца транзакции, поэтому ревизии
+// назначаются в порядке фиксации и читатель журнала не пропускает изменения.
+func addChange(
+	ctx context.Context,
+	tx *sqlx.Tx,
+	t calendar.ChangeType,
+	e *calendar.Event,
+	extra ...uuid.UUID,
+) (calendar.Change, error) {
+	c := calendar.NewChange(t, e, extra...)
+
+	event, err := json.Marshal(c.Event)
+	if err != nil {
+		return c, errors.Wrap(err, "add change")
+	}
+
+	userIDs := make(pq.StringArray, 0, len(c.UserIDs))
+	for _, id := range c.UserIDs {
+		userIDs = append(userIDs, id.String())
+	}
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock($1)`, changeLogLock); err != nil {
+		return c, errors.Wrap(err, "add change")
+	}
+
+	err = tx.GetContext(
+		ctx,
+		&c.Revision,
+		`INSERT INTO event_changes (type, event, user_ids) VALUES ($1, $2, $3) RETURNING revision`,
+		c.Type, event, userIDs,
+	)
+
+	return c, errors.Wrap(err, "add change")
+}
+
+// addEventChange добавить в журнал изменений изменение события id (например, состава участников),
+// загрузив его состояние в рамках транзакции изменения.
+func addEventChange(ctx context.Context, tx *sqlx.Tx, id uuid.UUID, extra ...uuid.UUID) (calendar.Change, error) {
+	e, err := findEventByID(ctx, tx, id)
+	if err != nil {
+		return calendar.Change{}, errors.Wrap(err, "add change")
+	}
+
+	return addChange(ctx, tx, calendar.ChangeUpdated, e, extra...)
+}
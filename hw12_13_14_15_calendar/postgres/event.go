@@ -3,84 +3,391 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
+	"github.com/lib/pq"
 	"github.com/pkg/errors"
 
 	"github.com/RomanSarvarov/otus_go_home_work/calendar"
 )
 
+// timeNowFunc возвращает текущее время.
+var timeNowFunc = time.Now
+
 // CreateEvent создать событие.
+// Проверка занятости и сохранение выполняются в одной транзакции под блокировкой владельца.
 func (repo *Repository) CreateEvent(ctx context.Context, e *calendar.Event) (*calendar.Event, error) {
-	if err := repo.checkDateBusy(ctx, e); err != nil {
+	if err := e.Validate(); err != nil {
+		return nil, errors.Wrap(err, "create event")
+	}
+
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "create event")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := lockUsers(ctx, tx, e.UserID); err != nil {
+		return nil, errors.Wrap(err, "create event")
+	}
+
+	if err := checkDateBusy(ctx, tx, e, e.UserID, uuid.Nil); err != nil {
 		return nil, errors.Wrap(err, "create event")
 	}
 
 	e.ID = uuid.New()
 
 	event := new(calendar.Event)
-	err := repo.db.QueryRowxContext(
+	err = tx.QueryRowxContext(
 		ctx,
-		`INSERT INTO events (id, title, description, start_at, end_at, user_id, notification_duration, is_notified) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING *;`, //nolint:lll
+		`INSERT INTO events (id, title, description, start_at, end_at, user_id, notification_duration, is_notified, rrule, exdates, notified_until, uid, time_zone) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13) RETURNING *;`, //nolint:lll
 		e.ID, e.Title, e.Description, e.StartAt, e.EndAt, e.UserID, e.NotificationDuration, e.IsNotified,
+		e.RRule, e.ExDates, e.NotifiedUntil, e.UID, e.TimeZone,
 	).StructScan(event)
+	if err != nil {
+		return nil, errors.Wrap(dateBusyError(err), "create event")
+	}
+
+	if err := addRevision(ctx, tx, calendar.RevisionCreated, nil, event); err != nil {
+		return nil, errors.Wrap(err, "create event")
+	}
+
+	change, err := addChange(ctx, tx, calendar.ChangeCreated, event)
 	if err != nil {
 		return nil, errors.Wrap(err, "create event")
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(dateBusyError(err), "create event")
+	}
+
+	repo.publishChanges(change)
+
 	return event, nil
 }
 
 // UpdateEvent обновить событие.
+// Событие блокируется до конца транзакции, поэтому список участников не меняется во время проверки,
+// а проверка занятости и сохранение выполняются под блокировкой владельца и участников.
 func (repo *Repository) UpdateEvent(ctx context.Context, id uuid.UUID, e *calendar.Event) (*calendar.Event, error) {
-	if _, err := repo.findEventByID(ctx, id); err != nil {
+	if err := e.Validate(); err != nil {
+		return nil, errors.Wrap(err, "update event")
+	}
+
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "update event")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := lockEvent(ctx, tx, id); err != nil {
+		return nil, errors.Wrap(err, "update event")
+	}
+
+	stored, err := findEventByID(ctx, tx, id)
+	if err != nil {
 		return nil, errors.Wrap(err, "update event")
 	}
 
-	if err := repo.checkDateBusy(ctx, e); err != nil {
+	if e.Version != stored.Version {
+		return nil, errors.Wrap(calendar.ErrVersionConflict, "update event")
+	}
+
+	e.ID = id
+
+	// время должно быть свободно у владельца и у всех принявших приглашение участников
+	users := append([]uuid.UUID{e.UserID}, stored.AcceptedAttendees()...)
+
+	if err := lockUsers(ctx, tx, users...); err != nil {
 		return nil, errors.Wrap(err, "update event")
 	}
 
+	for _, userID := range users {
+		if err := checkDateBusy(ctx, tx, e, userID, id); err != nil {
+			return nil, errors.Wrap(err, "update event")
+		}
+	}
+
+	e.KeepNotification(stored)
+
 	event := new(calendar.Event)
-	err := repo.db.QueryRowxContext(
+	err = tx.QueryRowxContext(
 		ctx,
-		`UPDATE events SET title = $1, description = $2, start_at = $3, end_at = $4, user_id = $5, notification_duration = $6, is_notified = $7 WHERE id = $8 RETURNING *;`, //nolint:lll
-		e.Title, e.Description, e.StartAt, e.EndAt, e.UserID, e.NotificationDuration, e.IsNotified, id,
+		`UPDATE events SET title = $1, description = $2, start_at = $3, end_at = $4, user_id = $5, notification_duration = $6, is_notified = $7, rrule = $8, exdates = $9, time_zone = $10, notified_until = $11, version = version + 1 WHERE id = $12 AND deleted_at IS NULL RETURNING *;`, //nolint:lll
+		e.Title, e.Description, e.StartAt, e.EndAt, e.UserID, e.NotificationDuration, e.IsNotified,
+		e.RRule, e.ExDates, e.TimeZone, e.NotifiedUntil, id,
 	).StructScan(event)
+	if err != nil {
+		return nil, errors.Wrap(dateBusyError(err), "update event")
+	}
+
+	if err := addRevision(ctx, tx, calendar.RevisionUpdated, stored, event); err != nil {
+		return nil, errors.Wrap(err, "update event")
+	}
+
+	event.Attendees = stored.Attendees
+
+	change, err := addChange(ctx, tx, calendar.ChangeUpdated, event)
 	if err != nil {
 		return nil, errors.Wrap(err, "update event")
 	}
 
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(dateBusyError(err), "update event")
+	}
+
+	repo.publishChanges(change)
+
+	return event, nil
+}
+
+// PatchEvent обновить только поля fields события: в запросе изменяются только соответствующие столбцы.
+// Событие блокируется до конца транзакции, как и при полном обновлении.
+func (repo *Repository) PatchEvent(
+	ctx context.Context,
+	id uuid.UUID,
+	e *calendar.Event,
+	fields []calendar.EventField,
+) (*calendar.Event, error) {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "patch event")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	if err := lockEvent(ctx, tx, id); err != nil {
+		return nil, errors.Wrap(err, "patch event")
+	}
+
+	stored, err := findEventByID(ctx, tx, id)
+	if err != nil {
+		return nil, errors.Wrap(err, "patch event")
+	}
+
+	if e.Version != stored.Version {
+		return nil, errors.Wrap(calendar.ErrVersionConflict, "patch event")
+	}
+
+	patched := *stored
+
+	values, err := patched.Patch(e, fields)
+	if err != nil {
+		return nil, errors.Wrap(err, "patch event")
+	}
+
+	if err := patched.Validate(); err != nil {
+		return nil, errors.Wrap(err, "patch event")
+	}
+
+	// время должно быть свободно у владельца и у всех принявших приглашение участников
+	users := append([]uuid.UUID{patched.UserID}, stored.AcceptedAttendees()...)
+
+	if err := lockUsers(ctx, tx, users...); err != nil {
+		return nil, errors.Wrap(err, "patch event")
+	}
+
+	for _, userID := range users {
+		if err := checkDateBusy(ctx, tx, &patched, userID, id); err != nil {
+			return nil, errors.Wrap(err, "patch event")
+		}
+	}
+
+	set := make([]string, 0, len(values)+1)
+	args := make([]interface{}, 0, len(values)+1)
+
+	// столбцы перечисляются в постоянном порядке, а не в порядке обхода map
+	for _, f := range calendar.PatchColumns {
+		if v, ok := values[f]; ok {
+			args = append(args, v)
+			set = append(set, string(f)+" = $"+strconv.Itoa(len(args)))
+		}
+	}
+
+	set = append(set, "version = version + 1")
+	args = append(args, id)
+
+	event := new(calendar.Event)
+	err = tx.QueryRowxContext(
+		ctx,
+		`UPDATE events SET `+strings.Join(set, ", ")+
+			` WHERE id = $`+strconv.Itoa(len(args))+` AND deleted_at IS NULL RETURNING *;`,
+		args...,
+	).StructScan(event)
+	if err != nil {
+		return nil, errors.Wrap(dateBusyError(err), "patch event")
+	}
+
+	if err := addRevision(ctx, tx, calendar.RevisionUpdated, stored, event); err != nil {
+		return nil, errors.Wrap(err, "patch event")
+	}
+
+	event.Attendees = stored.Attendees
+
+	change, err := addChange(ctx, tx, calendar.ChangeUpdated, event)
+	if err != nil {
+		return nil, errors.Wrap(err, "patch event")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(dateBusyError(err), "patch event")
+	}
+
+	repo.publishChanges(change)
+
 	return event, nil
 }
 
-// DeleteEvent удалить событие.
+// MarkEventNotified отметить, что уведомление о событии, начинающемся в startAt, выслано.
+// Для повторяющегося события отметка ставится только если вхождение позже уже отмеченного.
+func (repo *Repository) MarkEventNotified(ctx context.Context, id uuid.UUID, startAt time.Time) error {
+	return markEventNotified(ctx, repo.db, id, startAt)
+}
+
+// markEventNotified отметить событие уведомленным в рамках db (соединения или транзакции).
+func markEventNotified(ctx context.Context, db sqlx.ExecerContext, id uuid.UUID, startAt time.Time) error {
+	res, err := db.ExecContext(
+		ctx,
+		`UPDATE events
+		SET is_notified    = is_notified OR rrule = '',
+		    notified_until = CASE WHEN rrule = '' THEN notified_until ELSE GREATEST(notified_until, $2) END
+		WHERE id = $1 AND deleted_at IS NULL`,
+		id, startAt.UTC(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "mark event notified")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "mark event notified")
+	}
+
+	if affected == 0 {
+		return errors.Wrap(calendar.ErrNotFound, "mark event notified")
+	}
+
+	return nil
+}
+
+// DeleteEvent переместить событие в корзину.
+// Неотправленные напоминания о событии удаляются вместе с перемещением.
 func (repo *Repository) DeleteEvent(ctx context.Context, ids ...uuid.UUID) error {
-	query, args, err := sqlx.In(`DELETE FROM events WHERE id IN (?)`, ids)
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "delete event error")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query, args, err := sqlx.In(
+		`UPDATE events SET deleted_at = ? WHERE id IN (?) AND deleted_at IS NULL RETURNING *`,
+		timeNowFunc().UTC(), ids,
+	)
 	if err != nil {
 		return err
 	}
 
-	query = repo.db.Rebind(query)
+	deleted := make([]*calendar.Event, 0, len(ids))
 
-	_, err = repo.db.ExecContext(ctx, query, args...)
+	if err := tx.SelectContext(ctx, &deleted, tx.Rebind(query), args...); err != nil {
+		return errors.Wrap(err, "delete event error")
+	}
+
+	query, args, err = sqlx.In(`DELETE FROM notification_outbox WHERE event_id IN (?)`, ids)
 	if err != nil {
+		return err
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+		return errors.Wrap(err, "delete event error")
+	}
+
+	for _, e := range deleted {
+		if err := addRevision(ctx, tx, calendar.RevisionDeleted, e, nil); err != nil {
+			return errors.Wrap(err, "delete event error")
+		}
+	}
+
+	if err := loadAttendees(ctx, tx, deleted); err != nil {
+		return errors.Wrap(err, "delete event error")
+	}
+
+	changes := make([]calendar.Change, 0, len(deleted))
+
+	for _, e := range deleted {
+		change, err := addChange(ctx, tx, calendar.ChangeDeleted, e)
+		if err != nil {
+			return errors.Wrap(err, "delete event error")
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err := tx.Commit(); err != nil {
 		return errors.Wrap(err, "delete event error")
 	}
 
+	repo.publishChanges(changes...)
+
 	return nil
 }
 
+// exclusionViolation код ошибки PostgreSQL при нарушении ограничения исключения.
+const exclusionViolation = "23P01"
+
 // FindEvents найти множество событий.
+// Неповторяющиеся события выбираются запросом с сортировкой и постраничной выборкой по ключу,
+// повторяющиеся события разворачиваются во вхождения после выборки и объединяются с ними.
 func (repo *Repository) FindEvents(ctx context.Context, filter calendar.EventFilter) ([]*calendar.Event, error) {
-	where, args, counter := []string{"1 = 1"}, []interface{}{}, 1
+	now := timeNowFunc().UTC()
+
+	single, err := repo.findSingleEvents(ctx, filter, now)
+	if err != nil {
+		return nil, errors.Wrap(err, "find events")
+	}
+
+	series, err := repo.findRecurringEvents(ctx, filter)
+	if err != nil {
+		return nil, errors.Wrap(err, "find events")
+	}
+
+	if err := loadAttendees(ctx, repo.db, append(single, series...)); err != nil {
+		return nil, errors.Wrap(err, "find events")
+	}
+
+	events := single
+
+	for _, e := range series {
+		occurrences, err := calendar.ExpandEvent(e, filter, now)
+		if err != nil {
+			return nil, errors.Wrap(err, "find events")
+		}
+
+		events = append(events, occurrences...)
+	}
+
+	return calendar.PageEvents(events, filter), nil
+}
+
+// findSingleEvents найти неповторяющиеся события.
+func (repo *Repository) findSingleEvents(
+	ctx context.Context,
+	filter calendar.EventFilter,
+	now time.Time,
+) ([]*calendar.Event, error) {
+	where, args, counter := []string{"rrule = ''", "deleted_at IS NULL"}, []interface{}{}, 1
 
 	if filter.UserID != uuid.Nil {
-		where, args = append(where, "user_id = $"+strconv.Itoa(counter)), append(args, filter.UserID)
+		where, args = append(where, userCondition(filter, counter)), append(args, filter.UserID)
+		counter++
+	}
+
+	if filter.UID != "" {
+		where, args = append(where, "uid = $"+strconv.Itoa(counter)), append(args, filter.UID)
 		counter++
 	}
 
@@ -90,8 +397,6 @@ func (repo *Repository) FindEvents(ctx context.Context, filter calendar.EventFil
 	}
 
 	if filter.NotifyTime {
-		now := time.Now()
-
 		where, args = append(where, "start_at >= $"+strconv.Itoa(counter)), append(args, now)
 		counter++
 
@@ -103,35 +408,116 @@ func (repo *Repository) FindEvents(ctx context.Context, filter calendar.EventFil
 		counter++
 	}
 
-	if filter.UserID != uuid.Nil {
-		where, args = append(where, "user_id = $"+strconv.Itoa(counter)), append(args, filter.UserID)
-		counter++
+	fromCond, toCond := "start_at >= $", "end_at <= $"
+	if filter.Overlapping {
+		fromCond, toCond = "end_at > $", "start_at < $"
 	}
 
 	if !filter.From.IsZero() {
-		where, args = append(where, "start_at >= $"+strconv.Itoa(counter)), append(args, filter.From)
+		where, args = append(where, fromCond+strconv.Itoa(counter)), append(args, filter.From.UTC())
 		counter++
 	}
 
 	if !filter.To.IsZero() {
-		where, args = append(where, `end_at <= $`+strconv.Itoa(counter)), append(args, filter.To)
+		where, args = append(where, toCond+strconv.Itoa(counter)), append(args, filter.To.UTC())
+		counter++
+	}
+
+	columns, values := orderColumns(filter.OrderBy), orderValues(filter.OrderBy, filter.After)
+
+	if filter.After != nil {
+		placeholders := make([]string, 0, len(values))
+		for _, v := range values {
+			placeholders, args = append(placeholders, "$"+strconv.Itoa(counter)), append(args, v)
+			counter++
+		}
+
+		where = append(where, "("+strings.Join(columns, ", ")+") > ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	query := `SELECT * FROM events WHERE ` + strings.Join(where, " AND ") +
+		` ORDER BY ` + strings.Join(columns, ", ")
+
+	if filter.Limit > 0 {
+		query, args = query+" LIMIT $"+strconv.Itoa(counter), append(args, filter.Limit)
+	}
+
+	events := make([]*calendar.Event, 0)
+
+	if err := repo.db.SelectContext(ctx, &events, query, args...); err != nil {
+		return nil, err
+	}
+
+	return events, nil
+}
+
+// findRecurringEvents найти повторяющиеся события.
+// Условия по времени к ним применяются после разворачивания во вхождения.
+func (repo *Repository) findRecurringEvents(ctx context.Context, filter calendar.EventFilter) ([]*calendar.Event, error) {
+	where, args, counter := []string{"rrule != ''", "deleted_at IS NULL"}, []interface{}{}, 1
+
+	if filter.UserID != uuid.Nil {
+		where, args = append(where, userCondition(filter, counter)), append(args, filter.UserID)
+		counter++
+	}
+
+	if filter.UID != "" {
+		where, args = append(where, "uid = $"+strconv.Itoa(counter)), append(args, filter.UID)
 		counter++ //nolint:ineffassign,wastedassign
 	}
 
 	events := make([]*calendar.Event, 0)
 
-	err := repo.db.SelectContext(ctx, &events, `
-		SELECT * FROM events
-		WHERE `+strings.Join(where, " AND "),
-		args...,
-	)
+	err := repo.db.SelectContext(ctx, &events, `SELECT * FROM events WHERE `+strings.Join(where, " AND "), args...)
 	if err != nil {
-		return nil, errors.Wrap(err, "find events")
+		return nil, err
 	}
 
 	return events, nil
 }
 
+// userCondition возвращает условие отбора событий пользователя с номером параметра n.
+func userCondition(filter calendar.EventFilter, n int) string {
+	param := "$" + strconv.Itoa(n)
+
+	if !filter.Attending {
+		return "user_id = " + param
+	}
+
+	return "(user_id = " + param + " OR id IN (SELECT event_id FROM event_attendees WHERE user_id = " + param +
+		" AND status = '" + string(calendar.RSVPAccepted) + "'))"
+}
+
+// orderColumns возвращает колонки сортировки, соответствующие calendar.EventOrder.Compare.
+func orderColumns(order calendar.EventOrder) []string {
+	switch order {
+	case calendar.OrderByEndAt:
+		return []string{"end_at", "start_at", "id"}
+	case calendar.OrderByTitle:
+		return []string{`title COLLATE "C"`, "start_at", "id"}
+	case calendar.OrderByStartAt:
+	}
+
+	return []string{"start_at", "id"}
+}
+
+// orderValues возвращает значения колонок сортировки для позиции.
+func orderValues(order calendar.EventOrder, after *calendar.EventCursor) []interface{} {
+	if after == nil {
+		return nil
+	}
+
+	switch order {
+	case calendar.OrderByEndAt:
+		return []interface{}{after.EndAt.UTC(), after.StartAt.UTC(), after.ID}
+	case calendar.OrderByTitle:
+		return []interface{}{after.Title, after.StartAt.UTC(), after.ID}
+	case calendar.OrderByStartAt:
+	}
+
+	return []interface{}{after.StartAt.UTC(), after.ID}
+}
+
 // FindEventByID найти событие по его идентификатору.
 func (repo *Repository) FindEventByID(ctx context.Context, id uuid.UUID) (*calendar.Event, error) {
 	event, err := repo.findEventByID(ctx, id)
@@ -144,9 +530,14 @@ func (repo *Repository) FindEventByID(ctx context.Context, id uuid.UUID) (*calen
 
 // findEventByID найти событие по его идентификатору.
 func (repo *Repository) findEventByID(ctx context.Context, id uuid.UUID) (*calendar.Event, error) {
+	return findEventByID(ctx, repo.db, id)
+}
+
+// findEventByID найти событие по его идентификатору в рамках q (соединения или транзакции).
+func findEventByID(ctx context.Context, q sqlx.QueryerContext, id uuid.UUID) (*calendar.Event, error) {
 	event := new(calendar.Event)
 
-	err := repo.db.GetContext(ctx, event, `SELECT * FROM events WHERE id=$1`, id)
+	err := sqlx.GetContext(ctx, q, event, `SELECT * FROM events WHERE id = $1 AND deleted_at IS NULL`, id)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			err = calendar.ErrNotFound
@@ -155,31 +546,101 @@ func (repo *Repository) findEventByID(ctx context.Context, id uuid.UUID) (*calen
 		return nil, err
 	}
 
+	if err := loadAttendees(ctx, q, []*calendar.Event{event}); err != nil {
+		return nil, err
+	}
+
 	return event, nil
 }
 
-// checkDateBusy проверка на свободное время.
+// lockEvent блокировать событие до конца транзакции.
+func lockEvent(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
+	var locked uuid.UUID
+
+	err := tx.GetContext(ctx, &locked, `SELECT id FROM events WHERE id = $1 AND deleted_at IS NULL FOR UPDATE`, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return calendar.ErrNotFound
+	}
+
+	return err
+}
+
+// lockUsers блокировать время пользователей до конца транзакции.
+// Ограничение events_user_id_period_excl не учитывает повторяющиеся события и принятые приглашения,
+// поэтому проверка занятости и сохранение изменения выполняются под рекомендательной блокировкой.
+// Блокировки берутся в одном порядке, чтобы транзакции не ждали друг друга по кругу.
+func lockUsers(ctx context.Context, tx *sqlx.Tx, ids ...uuid.UUID) error {
+	keys := make([]string, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+
+	for _, id := range ids {
+		if _, ok := seen[id]; ok {
+			continue
+		}
+
+		seen[id] = struct{}{}
+		keys = append(keys, id.String())
+	}
+
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext($1))`, key); err != nil {
+			return errors.Wrap(err, "lock users")
+		}
+	}
+
+	return nil
+}
+
+// dateBusyError заменяет нарушение ограничения пересечения событий ошибкой calendar.ErrDateBusy.
+func dateBusyError(err error) error {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == exclusionViolation {
+		return calendar.ErrDateBusy
+	}
+
+	return err
+}
+
+// checkDateBusy проверка на свободное время пользователя userID:
+// учитываются его события и события, приглашение на которые он принял.
+// Событие с идентификатором ignore не учитывается.
 // Если время занято, то вернет ошибку calendar.ErrDateBusy.
-func (repo *Repository) checkDateBusy(ctx context.Context, event *calendar.Event) error {
+func checkDateBusy(
+	ctx context.Context,
+	q sqlx.QueryerContext,
+	event *calendar.Event,
+	userID, ignore uuid.UUID,
+) error {
+	to := event.EndAt
+	if event.IsRecurring() {
+		to = event.StartAt.Add(calendar.OverlapHorizon)
+	}
+
 	query := `
-			SELECT count(*) AS count
+			SELECT *
 			FROM events
-			WHERE user_id = $1
+			WHERE ` + userCondition(calendar.EventFilter{Attending: true}, 1) + `
 			  AND id != $2
-			  AND ((start_at >= $3 AND start_at < $4) OR (end_at > $3 AND end_at <= $4))
+			  AND deleted_at IS NULL
+			  AND start_at < $4
+			  AND (end_at > $3 OR rrule != '')
 		`
 
-	var count int
-	err := repo.db.QueryRowContext(
-		ctx, query, event.UserID, event.ID,
-		event.StartAt, event.EndAt,
-	).Scan(&count)
+	events := make([]*calendar.Event, 0)
+
+	err := sqlx.SelectContext(ctx, q, &events, query, userID, ignore, event.StartAt.UTC(), to.UTC())
 	if err != nil {
 		return errors.Wrap(err, "check date busy")
 	}
 
-	if count > 0 {
-		return calendar.ErrDateBusy
+	if err := calendar.CheckDateBusy(events, event, ignore); err != nil {
+		if errors.Is(err, calendar.ErrDateBusy) {
+			return err
+		}
+
+		return errors.Wrap(err, "check date busy")
 	}
 
 	return nil
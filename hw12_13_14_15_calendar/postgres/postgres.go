@@ -1,12 +1,15 @@
 package postgres
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/jmoiron/sqlx"
 	_ "github.com/lib/pq" // postgres support
 	"github.com/pkg/errors"
 	goose "github.com/pressly/goose/v3"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
 )
 
 // Key обозначает ключ postgres БД драйвера.
@@ -24,6 +27,9 @@ type Config struct {
 // Repository является абстракцией к БД PostgreSQL.
 type Repository struct {
 	db *sqlx.DB
+
+	// changes получатель изменений событий.
+	changes calendar.ChangePublisher
 }
 
 // Open открывает соединение к БД.
@@ -46,6 +52,60 @@ func (repo *Repository) Up(dir string) error {
 	return goose.Up(repo.db.DB, dir)
 }
 
+// Ping проверяет соединение с БД.
+func (repo *Repository) Ping(ctx context.Context) error {
+	return errors.Wrap(repo.db.PingContext(ctx), "ping postgreSQL")
+}
+
+// CheckMigrations проверяет, что к БД применены все миграции из dir.
+// Версия БД определяется так же, как это делает goose: по последней записи каждой версии.
+func (repo *Repository) CheckMigrations(ctx context.Context, dir string) error {
+	migrations, err := goose.CollectMigrations(dir, 0, goose.MaxVersion)
+	if err != nil {
+		return errors.Wrap(err, "collect migrations")
+	}
+
+	latest, err := migrations.Last()
+	if err != nil {
+		return errors.Wrap(err, "collect migrations")
+	}
+
+	var current int64
+
+	err = repo.db.GetContext(ctx, &current, `
+		SELECT COALESCE(MAX(version_id), 0)
+		FROM goose_db_version
+		WHERE is_applied AND id IN (SELECT MAX(id) FROM goose_db_version GROUP BY version_id)
+	`)
+	if err != nil {
+		return errors.Wrap(err, "get database version")
+	}
+
+	if current < latest.Version {
+		return errors.Errorf("migrations pending: database version %d, latest %d", current, latest.Version)
+	}
+
+	return nil
+}
+
+// SetChangePublisher задает получателя изменений событий, записанных этим процессом в журнал изменений.
+// Изменения всех процессов читаются из журнала (см. FindChanges).
+// Устанавливается до начала работы с хранилищем.
+func (repo *Repository) SetChangePublisher(p calendar.ChangePublisher) {
+	repo.changes = p
+}
+
+// publishChanges передает изменения, записанные в журнал, получателю, если он задан.
+func (repo *Repository) publishChanges(changes ...calendar.Change) {
+	if repo.changes == nil {
+		return
+	}
+
+	for _, c := range changes {
+		repo.changes.PublishChange(c)
+	}
+}
+
 // dsn формирует DSN строку из конфига.
 func dsn(cfg Config) string {
 	return fmt.Sprintf(
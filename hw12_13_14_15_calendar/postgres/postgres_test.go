@@ -0,0 +1,167 @@
+//go:build integration
+// +build integration
+
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	goose "github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/repotest"
+)
+
+// migrationsDir определяет местонахождение миграций PostgreSQL.
+const migrationsDir = "../migrations"
+
+// testSchema схема, в которой выполняются тесты хранилища,
+// чтобы не затрагивать данные запущенных сервисов.
+const testSchema = "repository_test"
+
+// migrateOnce выполняет миграции схемы один раз на запуск тестов.
+var migrateOnce sync.Once
+
+// newRepository открывает хранилище в схеме testSchema и очищает ее таблицы.
+// Подключение задается теми же переменными окружения, что и для интеграционных тестов.
+func newRepository(t *testing.T) *Repository {
+	t.Helper()
+
+	vars := make(map[string]string, 4)
+
+	for _, name := range []string{"POSTGRES_ADDRESS", "POSTGRES_USER", "POSTGRES_PASSWORD", "POSTGRES_DB"} {
+		vars[name] = os.Getenv(name)
+		require.NotEmpty(t, vars[name], name)
+	}
+
+	db, err := sqlx.Connect("postgres", fmt.Sprintf(
+		"postgresql://%s:%s@%s/%s?sslmode=disable&search_path=%s,public",
+		vars["POSTGRES_USER"],
+		vars["POSTGRES_PASSWORD"],
+		vars["POSTGRES_ADDRESS"],
+		vars["POSTGRES_DB"],
+		testSchema,
+	))
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, db.Close())
+	})
+
+	migrateOnce.Do(func() {
+		// расширение общее для всей БД, поэтому создается в public, где его ожидают миграции сервисов
+		_, err = db.Exec(`CREATE EXTENSION IF NOT EXISTS btree_gist SCHEMA public`)
+		require.NoError(t, err)
+
+		_, err = db.Exec(`CREATE SCHEMA IF NOT EXISTS ` + testSchema)
+		require.NoError(t, err)
+
+		require.NoError(t, goose.Up(db.DB, migrationsDir))
+	})
+
+	_, err = db.Exec(`TRUNCATE TABLE events, event_attendees, notification_outbox, event_revisions, event_changes CASCADE`)
+	require.NoError(t, err)
+
+	return &Repository{db: db}
+}
+
+func TestRepository(t *testing.T) {
+	repotest.Run(t, repotest.Driver{
+		New: func(t *testing.T) repotest.Storage {
+			return newRepository(t)
+		},
+		SetNow: func(now func() time.Time) {
+			timeNowFunc = now
+		},
+	})
+}
+
+// TestRepository_FindChanges проверяет, что изменения записываются в журнал без получателя изменений,
+// как у отдельно запущенного планировщика, и читаются из журнала другим процессом.
+func TestRepository_FindChanges(t *testing.T) {
+	ctx := context.Background()
+
+	repo := newRepository(t)
+
+	// журнал читает другое подключение, как другой процесс
+	reader := newRepository(t)
+
+	ownerID := uuid.New()
+	guestID := uuid.New()
+
+	last, err := repo.LastChangeRevision(ctx)
+	require.NoError(t, err)
+
+	e, err := repo.CreateEvent(ctx, &calendar.Event{
+		Title:   "foo",
+		StartAt: time.Date(2022, 12, 5, 10, 0, 0, 0, time.UTC),
+		EndAt:   time.Date(2022, 12, 5, 11, 0, 0, 0, time.UTC),
+		UserID:  ownerID,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.InviteAttendee(ctx, e.ID, guestID)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteEvent(ctx, e.ID))
+	require.NoError(t, repo.PurgeEvent(ctx, e.ID))
+
+	changes, err := reader.FindChanges(ctx, calendar.ChangeFilter{After: last})
+	require.NoError(t, err)
+	require.Len(t, changes, 4)
+
+	wantTypes := []calendar.ChangeType{
+		calendar.ChangeCreated,
+		calendar.ChangeUpdated,
+		calendar.ChangeDeleted,
+		calendar.ChangePurged,
+	}
+
+	for i, c := range changes {
+		require.Equal(t, wantTypes[i], c.Type)
+		require.Equal(t, e.ID, c.Event.ID)
+		require.True(t, c.Concerns(ownerID))
+		require.Greater(t, c.Revision, last)
+
+		if i > 0 {
+			require.Greater(t, c.Revision, changes[i-1].Revision)
+		}
+	}
+
+	require.Equal(t, "foo", changes[3].Event.Title)
+	require.Len(t, changes[3].Event.Attendees, 1)
+
+	revision, err := reader.LastChangeRevision(ctx)
+	require.NoError(t, err)
+	require.Equal(t, changes[3].Revision, revision)
+
+	t.Run("user", func(t *testing.T) {
+		guest, err := reader.FindChanges(ctx, calendar.ChangeFilter{After: last, UserID: guestID})
+		require.NoError(t, err)
+		require.Len(t, guest, 3)
+		require.Equal(t, changes[1].Revision, guest[0].Revision)
+
+		stranger, err := reader.FindChanges(ctx, calendar.ChangeFilter{After: last, UserID: uuid.New()})
+		require.NoError(t, err)
+		require.Empty(t, stranger)
+	})
+
+	t.Run("range", func(t *testing.T) {
+		got, err := reader.FindChanges(ctx, calendar.ChangeFilter{
+			After: changes[0].Revision,
+			Until: changes[2].Revision,
+			Limit: 1,
+		})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		require.Equal(t, changes[1].Revision, got[0].Revision)
+	})
+}
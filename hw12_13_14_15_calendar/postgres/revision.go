@@ -0,0 +1,68 @@
+package postgres
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+)
+
+// FindEventRevisions найти журнал изменений события, от старых записей к новым.
+func (repo *Repository) FindEventRevisions(ctx context.Context, eventID uuid.UUID) ([]*calendar.Revision, error) {
+	revisions := make([]*calendar.Revision, 0)
+
+	err := repo.db.SelectContext(
+		ctx,
+		&revisions,
+		`SELECT * FROM event_revisions WHERE event_id = $1 ORDER BY created_at, id`,
+		eventID,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "find event revisions")
+	}
+
+	return revisions, nil
+}
+
+// addRevision добавить запись в журнал изменений события в рамках транзакции изменения.
+// Автором изменения считается пользователь из контекста запроса.
+func addRevision(
+	ctx context.Context,
+	db sqlx.ExecerContext,
+	action calendar.RevisionAction,
+	before, after *calendar.Event,
+) error {
+	actorID, _ := auth.UserIDFromContext(ctx)
+
+	return saveRevision(ctx, db, calendar.NewRevision(action, actorID, before, after))
+}
+
+// addAttendeeRevision добавить в журнал изменений запись об изменении участника события e
+// в рамках транзакции изменения. Автором изменения считается пользователь из контекста запроса.
+func addAttendeeRevision(
+	ctx context.Context,
+	db sqlx.ExecerContext,
+	action calendar.RevisionAction,
+	e *calendar.Event,
+	before, after *calendar.Attendee,
+) error {
+	actorID, _ := auth.UserIDFromContext(ctx)
+
+	return saveRevision(ctx, db, calendar.NewAttendeeRevision(action, actorID, e, before, after))
+}
+
+// saveRevision сохранить запись журнала изменений.
+func saveRevision(ctx context.Context, db sqlx.ExecerContext, r *calendar.Revision) error {
+	_, err := db.ExecContext(
+		ctx,
+		`INSERT INTO event_revisions (id, event_id, action, actor_id, created_at, changes, state)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+		r.ID, r.EventID, r.Action, r.ActorID, r.CreatedAt, r.Changes, r.State,
+	)
+
+	return errors.Wrap(err, "add revision")
+}
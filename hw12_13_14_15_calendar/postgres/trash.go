@@ -0,0 +1,180 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// FindTrash найти события в корзине. Сначала идут удаленные последними.
+func (repo *Repository) FindTrash(ctx context.Context, filter calendar.TrashFilter) ([]*calendar.Event, error) {
+	where, args, counter := []string{"deleted_at IS NOT NULL"}, []interface{}{}, 1
+
+	if filter.ID != uuid.Nil {
+		where, args = append(where, "id = $"+strconv.Itoa(counter)), append(args, filter.ID)
+		counter++
+	}
+
+	if filter.UserID != uuid.Nil {
+		where, args = append(where, "user_id = $"+strconv.Itoa(counter)), append(args, filter.UserID)
+		counter++
+	}
+
+	if !filter.DeletedBefore.IsZero() {
+		where, args = append(where, "deleted_at < $"+strconv.Itoa(counter)), append(args, filter.DeletedBefore.UTC())
+		counter++ //nolint:ineffassign,wastedassign
+	}
+
+	events := make([]*calendar.Event, 0)
+
+	err := repo.db.SelectContext(
+		ctx,
+		&events,
+		`SELECT * FROM events WHERE `+strings.Join(where, " AND ")+` ORDER BY deleted_at DESC, id`,
+		args...,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "find trash")
+	}
+
+	if err := loadAttendees(ctx, repo.db, events); err != nil {
+		return nil, errors.Wrap(err, "find trash")
+	}
+
+	return events, nil
+}
+
+// RestoreEvent восстановить событие из корзины.
+// Время события должно быть свободно у владельца и у принявших приглашение участников,
+// проверка и восстановление выполняются в одной транзакции под их блокировкой.
+func (repo *Repository) RestoreEvent(ctx context.Context, id uuid.UUID) (*calendar.Event, error) {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "restore event")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	stored := new(calendar.Event)
+
+	err = tx.GetContext(ctx, stored, `SELECT * FROM events WHERE id = $1 AND deleted_at IS NOT NULL FOR UPDATE`, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = calendar.ErrNotFound
+		}
+
+		return nil, errors.Wrap(err, "restore event")
+	}
+
+	if err := loadAttendees(ctx, tx, []*calendar.Event{stored}); err != nil {
+		return nil, errors.Wrap(err, "restore event")
+	}
+
+	users := append([]uuid.UUID{stored.UserID}, stored.AcceptedAttendees()...)
+
+	if err := lockUsers(ctx, tx, users...); err != nil {
+		return nil, errors.Wrap(err, "restore event")
+	}
+
+	for _, userID := range users {
+		if err := checkDateBusy(ctx, tx, stored, userID, id); err != nil {
+			return nil, errors.Wrap(err, "restore event")
+		}
+	}
+
+	event := new(calendar.Event)
+
+	err = tx.QueryRowxContext(ctx, `UPDATE events SET deleted_at = NULL WHERE id = $1 RETURNING *`, id).StructScan(event)
+	if err != nil {
+		return nil, errors.Wrap(dateBusyError(err), "restore event")
+	}
+
+	if err := addRevision(ctx, tx, calendar.RevisionRestored, event, event); err != nil {
+		return nil, errors.Wrap(err, "restore event")
+	}
+
+	event.Attendees = stored.Attendees
+
+	change, err := addChange(ctx, tx, calendar.ChangeCreated, event)
+	if err != nil {
+		return nil, errors.Wrap(err, "restore event")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(dateBusyError(err), "restore event")
+	}
+
+	repo.publishChanges(change)
+
+	return event, nil
+}
+
+// PurgeEvent окончательно удалить события из корзины.
+// Участники и уведомления удаляются каскадно, журнал изменений сохраняется.
+func (repo *Repository) PurgeEvent(ctx context.Context, ids ...uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "purge event")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	query, args, err := sqlx.In(`SELECT * FROM events WHERE id IN (?) AND deleted_at IS NOT NULL FOR UPDATE`, ids)
+	if err != nil {
+		return errors.Wrap(err, "purge event")
+	}
+
+	purged := make([]*calendar.Event, 0, len(ids))
+
+	if err := tx.SelectContext(ctx, &purged, tx.Rebind(query), args...); err != nil {
+		return errors.Wrap(err, "purge event")
+	}
+
+	// участники загружаются до удаления, чтобы об удалении узнали и они
+	if err := loadAttendees(ctx, tx, purged); err != nil {
+		return errors.Wrap(err, "purge event")
+	}
+
+	query, args, err = sqlx.In(`DELETE FROM events WHERE id IN (?) AND deleted_at IS NOT NULL`, ids)
+	if err != nil {
+		return errors.Wrap(err, "purge event")
+	}
+
+	if _, err := tx.ExecContext(ctx, tx.Rebind(query), args...); err != nil {
+		return errors.Wrap(err, "purge event")
+	}
+
+	for _, e := range purged {
+		if err := addRevision(ctx, tx, calendar.RevisionPurged, e, nil); err != nil {
+			return errors.Wrap(err, "purge event")
+		}
+	}
+
+	changes := make([]calendar.Change, 0, len(purged))
+
+	for _, e := range purged {
+		change, err := addChange(ctx, tx, calendar.ChangePurged, e)
+		if err != nil {
+			return errors.Wrap(err, "purge event")
+		}
+
+		changes = append(changes, change)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "purge event")
+	}
+
+	repo.publishChanges(changes...)
+
+	return nil
+}
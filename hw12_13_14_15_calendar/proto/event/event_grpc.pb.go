@@ -8,6 +8,7 @@ package event
 
 import (
 	context "context"
+	httpbody "google.golang.org/genproto/googleapis/api/httpbody"
 	grpc "google.golang.org/grpc"
 	codes "google.golang.org/grpc/codes"
 	status "google.golang.org/grpc/status"
@@ -25,10 +26,24 @@ const _ = grpc.SupportPackageIsVersion7
 type EventServiceClient interface {
 	CreateEventV1(ctx context.Context, in *CreateEventRequestV1, opts ...grpc.CallOption) (*EventResponseV1, error)
 	UpdateEventV1(ctx context.Context, in *UpdateEventRequestV1, opts ...grpc.CallOption) (*EventResponseV1, error)
+	PatchEventV1(ctx context.Context, in *PatchEventRequestV1, opts ...grpc.CallOption) (*EventResponseV1, error)
 	DeleteEventV1(ctx context.Context, in *DeleteEventRequestV1, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListTrashV1(ctx context.Context, in *ListTrashRequestV1, opts ...grpc.CallOption) (*EventsResponseV1, error)
+	RestoreEventV1(ctx context.Context, in *RestoreEventRequestV1, opts ...grpc.CallOption) (*EventResponseV1, error)
+	PurgeEventV1(ctx context.Context, in *PurgeEventRequestV1, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	GetEventHistoryV1(ctx context.Context, in *GetEventHistoryRequestV1, opts ...grpc.CallOption) (*GetEventHistoryResponseV1, error)
+	RevertEventV1(ctx context.Context, in *RevertEventRequestV1, opts ...grpc.CallOption) (*EventResponseV1, error)
 	GetEventsForDayV1(ctx context.Context, in *GetEventsForDayRequestV1, opts ...grpc.CallOption) (*EventsResponseV1, error)
 	GetEventsForWeekV1(ctx context.Context, in *GetEventsForWeekRequestV1, opts ...grpc.CallOption) (*EventsResponseV1, error)
 	GetEventsForMonthV1(ctx context.Context, in *GetEventsForMonthRequestV1, opts ...grpc.CallOption) (*EventsResponseV1, error)
+	ExportEventsV1(ctx context.Context, in *ExportEventsRequestV1, opts ...grpc.CallOption) (*httpbody.HttpBody, error)
+	ImportEventsV1(ctx context.Context, in *ImportEventsRequestV1, opts ...grpc.CallOption) (*ImportEventsResponseV1, error)
+	GetFreeBusyV1(ctx context.Context, in *GetFreeBusyRequestV1, opts ...grpc.CallOption) (*GetFreeBusyResponseV1, error)
+	FindMeetingSlotsV1(ctx context.Context, in *FindMeetingSlotsRequestV1, opts ...grpc.CallOption) (*FindMeetingSlotsResponseV1, error)
+	InviteAttendeeV1(ctx context.Context, in *InviteAttendeeRequestV1, opts ...grpc.CallOption) (*AttendeeResponseV1, error)
+	RemoveAttendeeV1(ctx context.Context, in *RemoveAttendeeRequestV1, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RespondToEventV1(ctx context.Context, in *RespondToEventRequestV1, opts ...grpc.CallOption) (*AttendeeResponseV1, error)
+	WatchEventsV1(ctx context.Context, in *WatchEventsRequestV1, opts ...grpc.CallOption) (EventService_WatchEventsV1Client, error)
 }
 
 type eventServiceClient struct {
@@ -57,6 +72,15 @@ func (c *eventServiceClient) UpdateEventV1(ctx context.Context, in *UpdateEventR
 	return out, nil
 }
 
+func (c *eventServiceClient) PatchEventV1(ctx context.Context, in *PatchEventRequestV1, opts ...grpc.CallOption) (*EventResponseV1, error) {
+	out := new(EventResponseV1)
+	err := c.cc.Invoke(ctx, "/event.EventService/PatchEventV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *eventServiceClient) DeleteEventV1(ctx context.Context, in *DeleteEventRequestV1, opts ...grpc.CallOption) (*emptypb.Empty, error) {
 	out := new(emptypb.Empty)
 	err := c.cc.Invoke(ctx, "/event.EventService/DeleteEventV1", in, out, opts...)
@@ -66,6 +90,51 @@ func (c *eventServiceClient) DeleteEventV1(ctx context.Context, in *DeleteEventR
 	return out, nil
 }
 
+func (c *eventServiceClient) ListTrashV1(ctx context.Context, in *ListTrashRequestV1, opts ...grpc.CallOption) (*EventsResponseV1, error) {
+	out := new(EventsResponseV1)
+	err := c.cc.Invoke(ctx, "/event.EventService/ListTrashV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) RestoreEventV1(ctx context.Context, in *RestoreEventRequestV1, opts ...grpc.CallOption) (*EventResponseV1, error) {
+	out := new(EventResponseV1)
+	err := c.cc.Invoke(ctx, "/event.EventService/RestoreEventV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) PurgeEventV1(ctx context.Context, in *PurgeEventRequestV1, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/event.EventService/PurgeEventV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) GetEventHistoryV1(ctx context.Context, in *GetEventHistoryRequestV1, opts ...grpc.CallOption) (*GetEventHistoryResponseV1, error) {
+	out := new(GetEventHistoryResponseV1)
+	err := c.cc.Invoke(ctx, "/event.EventService/GetEventHistoryV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) RevertEventV1(ctx context.Context, in *RevertEventRequestV1, opts ...grpc.CallOption) (*EventResponseV1, error) {
+	out := new(EventResponseV1)
+	err := c.cc.Invoke(ctx, "/event.EventService/RevertEventV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *eventServiceClient) GetEventsForDayV1(ctx context.Context, in *GetEventsForDayRequestV1, opts ...grpc.CallOption) (*EventsResponseV1, error) {
 	out := new(EventsResponseV1)
 	err := c.cc.Invoke(ctx, "/event.EventService/GetEventsForDayV1", in, out, opts...)
@@ -93,16 +162,125 @@ func (c *eventServiceClient) GetEventsForMonthV1(ctx context.Context, in *GetEve
 	return out, nil
 }
 
+func (c *eventServiceClient) ExportEventsV1(ctx context.Context, in *ExportEventsRequestV1, opts ...grpc.CallOption) (*httpbody.HttpBody, error) {
+	out := new(httpbody.HttpBody)
+	err := c.cc.Invoke(ctx, "/event.EventService/ExportEventsV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) ImportEventsV1(ctx context.Context, in *ImportEventsRequestV1, opts ...grpc.CallOption) (*ImportEventsResponseV1, error) {
+	out := new(ImportEventsResponseV1)
+	err := c.cc.Invoke(ctx, "/event.EventService/ImportEventsV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) GetFreeBusyV1(ctx context.Context, in *GetFreeBusyRequestV1, opts ...grpc.CallOption) (*GetFreeBusyResponseV1, error) {
+	out := new(GetFreeBusyResponseV1)
+	err := c.cc.Invoke(ctx, "/event.EventService/GetFreeBusyV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) FindMeetingSlotsV1(ctx context.Context, in *FindMeetingSlotsRequestV1, opts ...grpc.CallOption) (*FindMeetingSlotsResponseV1, error) {
+	out := new(FindMeetingSlotsResponseV1)
+	err := c.cc.Invoke(ctx, "/event.EventService/FindMeetingSlotsV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) InviteAttendeeV1(ctx context.Context, in *InviteAttendeeRequestV1, opts ...grpc.CallOption) (*AttendeeResponseV1, error) {
+	out := new(AttendeeResponseV1)
+	err := c.cc.Invoke(ctx, "/event.EventService/InviteAttendeeV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) RemoveAttendeeV1(ctx context.Context, in *RemoveAttendeeRequestV1, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, "/event.EventService/RemoveAttendeeV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) RespondToEventV1(ctx context.Context, in *RespondToEventRequestV1, opts ...grpc.CallOption) (*AttendeeResponseV1, error) {
+	out := new(AttendeeResponseV1)
+	err := c.cc.Invoke(ctx, "/event.EventService/RespondToEventV1", in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *eventServiceClient) WatchEventsV1(ctx context.Context, in *WatchEventsRequestV1, opts ...grpc.CallOption) (EventService_WatchEventsV1Client, error) {
+	stream, err := c.cc.NewStream(ctx, &EventService_ServiceDesc.Streams[0], "/event.EventService/WatchEventsV1", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &eventServiceWatchEventsV1Client{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type EventService_WatchEventsV1Client interface {
+	Recv() (*EventChangeV1, error)
+	grpc.ClientStream
+}
+
+type eventServiceWatchEventsV1Client struct {
+	grpc.ClientStream
+}
+
+func (x *eventServiceWatchEventsV1Client) Recv() (*EventChangeV1, error) {
+	m := new(EventChangeV1)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // EventServiceServer is the server API for EventService service.
 // All implementations must embed UnimplementedEventServiceServer
 // for forward compatibility
 type EventServiceServer interface {
 	CreateEventV1(context.Context, *CreateEventRequestV1) (*EventResponseV1, error)
 	UpdateEventV1(context.Context, *UpdateEventRequestV1) (*EventResponseV1, error)
+	PatchEventV1(context.Context, *PatchEventRequestV1) (*EventResponseV1, error)
 	DeleteEventV1(context.Context, *DeleteEventRequestV1) (*emptypb.Empty, error)
+	ListTrashV1(context.Context, *ListTrashRequestV1) (*EventsResponseV1, error)
+	RestoreEventV1(context.Context, *RestoreEventRequestV1) (*EventResponseV1, error)
+	PurgeEventV1(context.Context, *PurgeEventRequestV1) (*emptypb.Empty, error)
+	GetEventHistoryV1(context.Context, *GetEventHistoryRequestV1) (*GetEventHistoryResponseV1, error)
+	RevertEventV1(context.Context, *RevertEventRequestV1) (*EventResponseV1, error)
 	GetEventsForDayV1(context.Context, *GetEventsForDayRequestV1) (*EventsResponseV1, error)
 	GetEventsForWeekV1(context.Context, *GetEventsForWeekRequestV1) (*EventsResponseV1, error)
 	GetEventsForMonthV1(context.Context, *GetEventsForMonthRequestV1) (*EventsResponseV1, error)
+	ExportEventsV1(context.Context, *ExportEventsRequestV1) (*httpbody.HttpBody, error)
+	ImportEventsV1(context.Context, *ImportEventsRequestV1) (*ImportEventsResponseV1, error)
+	GetFreeBusyV1(context.Context, *GetFreeBusyRequestV1) (*GetFreeBusyResponseV1, error)
+	FindMeetingSlotsV1(context.Context, *FindMeetingSlotsRequestV1) (*FindMeetingSlotsResponseV1, error)
+	InviteAttendeeV1(context.Context, *InviteAttendeeRequestV1) (*AttendeeResponseV1, error)
+	RemoveAttendeeV1(context.Context, *RemoveAttendeeRequestV1) (*emptypb.Empty, error)
+	RespondToEventV1(context.Context, *RespondToEventRequestV1) (*AttendeeResponseV1, error)
+	WatchEventsV1(*WatchEventsRequestV1, EventService_WatchEventsV1Server) error
 	mustEmbedUnimplementedEventServiceServer()
 }
 
@@ -116,9 +294,27 @@ func (UnimplementedEventServiceServer) CreateEventV1(context.Context, *CreateEve
 func (UnimplementedEventServiceServer) UpdateEventV1(context.Context, *UpdateEventRequestV1) (*EventResponseV1, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method UpdateEventV1 not implemented")
 }
+func (UnimplementedEventServiceServer) PatchEventV1(context.Context, *PatchEventRequestV1) (*EventResponseV1, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PatchEventV1 not implemented")
+}
 func (UnimplementedEventServiceServer) DeleteEventV1(context.Context, *DeleteEventRequestV1) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteEventV1 not implemented")
 }
+func (UnimplementedEventServiceServer) ListTrashV1(context.Context, *ListTrashRequestV1) (*EventsResponseV1, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrashV1 not implemented")
+}
+func (UnimplementedEventServiceServer) RestoreEventV1(context.Context, *RestoreEventRequestV1) (*EventResponseV1, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreEventV1 not implemented")
+}
+func (UnimplementedEventServiceServer) PurgeEventV1(context.Context, *PurgeEventRequestV1) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PurgeEventV1 not implemented")
+}
+func (UnimplementedEventServiceServer) GetEventHistoryV1(context.Context, *GetEventHistoryRequestV1) (*GetEventHistoryResponseV1, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetEventHistoryV1 not implemented")
+}
+func (UnimplementedEventServiceServer) RevertEventV1(context.Context, *RevertEventRequestV1) (*EventResponseV1, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RevertEventV1 not implemented")
+}
 func (UnimplementedEventServiceServer) GetEventsForDayV1(context.Context, *GetEventsForDayRequestV1) (*EventsResponseV1, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEventsForDayV1 not implemented")
 }
@@ -128,6 +324,30 @@ func (UnimplementedEventServiceServer) GetEventsForWeekV1(context.Context, *GetE
 func (UnimplementedEventServiceServer) GetEventsForMonthV1(context.Context, *GetEventsForMonthRequestV1) (*EventsResponseV1, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetEventsForMonthV1 not implemented")
 }
+func (UnimplementedEventServiceServer) ExportEventsV1(context.Context, *ExportEventsRequestV1) (*httpbody.HttpBody, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ExportEventsV1 not implemented")
+}
+func (UnimplementedEventServiceServer) ImportEventsV1(context.Context, *ImportEventsRequestV1) (*ImportEventsResponseV1, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportEventsV1 not implemented")
+}
+func (UnimplementedEventServiceServer) GetFreeBusyV1(context.Context, *GetFreeBusyRequestV1) (*GetFreeBusyResponseV1, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetFreeBusyV1 not implemented")
+}
+func (UnimplementedEventServiceServer) FindMeetingSlotsV1(context.Context, *FindMeetingSlotsRequestV1) (*FindMeetingSlotsResponseV1, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method FindMeetingSlotsV1 not implemented")
+}
+func (UnimplementedEventServiceServer) InviteAttendeeV1(context.Context, *InviteAttendeeRequestV1) (*AttendeeResponseV1, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method InviteAttendeeV1 not implemented")
+}
+func (UnimplementedEventServiceServer) RemoveAttendeeV1(context.Context, *RemoveAttendeeRequestV1) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveAttendeeV1 not implemented")
+}
+func (UnimplementedEventServiceServer) RespondToEventV1(context.Context, *RespondToEventRequestV1) (*AttendeeResponseV1, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RespondToEventV1 not implemented")
+}
+func (UnimplementedEventServiceServer) WatchEventsV1(*WatchEventsRequestV1, EventService_WatchEventsV1Server) error {
+	return status.Errorf(codes.Unimplemented, "method WatchEventsV1 not implemented")
+}
 func (UnimplementedEventServiceServer) mustEmbedUnimplementedEventServiceServer() {}
 
 // UnsafeEventServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -177,6 +397,24 @@ func _EventService_UpdateEventV1_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EventService_PatchEventV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PatchEventRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).PatchEventV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/PatchEventV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).PatchEventV1(ctx, req.(*PatchEventRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _EventService_DeleteEventV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(DeleteEventRequestV1)
 	if err := dec(in); err != nil {
@@ -195,6 +433,96 @@ func _EventService_DeleteEventV1_Handler(srv interface{}, ctx context.Context, d
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EventService_ListTrashV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListTrashRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).ListTrashV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/ListTrashV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).ListTrashV1(ctx, req.(*ListTrashRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_RestoreEventV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreEventRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).RestoreEventV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/RestoreEventV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).RestoreEventV1(ctx, req.(*RestoreEventRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_PurgeEventV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(PurgeEventRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).PurgeEventV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/PurgeEventV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).PurgeEventV1(ctx, req.(*PurgeEventRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_GetEventHistoryV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetEventHistoryRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).GetEventHistoryV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/GetEventHistoryV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).GetEventHistoryV1(ctx, req.(*GetEventHistoryRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_RevertEventV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevertEventRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).RevertEventV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/RevertEventV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).RevertEventV1(ctx, req.(*RevertEventRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _EventService_GetEventsForDayV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetEventsForDayRequestV1)
 	if err := dec(in); err != nil {
@@ -249,6 +577,153 @@ func _EventService_GetEventsForMonthV1_Handler(srv interface{}, ctx context.Cont
 	return interceptor(ctx, in, info, handler)
 }
 
+func _EventService_ExportEventsV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ExportEventsRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).ExportEventsV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/ExportEventsV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).ExportEventsV1(ctx, req.(*ExportEventsRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_ImportEventsV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportEventsRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).ImportEventsV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/ImportEventsV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).ImportEventsV1(ctx, req.(*ImportEventsRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_GetFreeBusyV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetFreeBusyRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).GetFreeBusyV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/GetFreeBusyV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).GetFreeBusyV1(ctx, req.(*GetFreeBusyRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_FindMeetingSlotsV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(FindMeetingSlotsRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).FindMeetingSlotsV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/FindMeetingSlotsV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).FindMeetingSlotsV1(ctx, req.(*FindMeetingSlotsRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_InviteAttendeeV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InviteAttendeeRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).InviteAttendeeV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/InviteAttendeeV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).InviteAttendeeV1(ctx, req.(*InviteAttendeeRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_RemoveAttendeeV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveAttendeeRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).RemoveAttendeeV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/RemoveAttendeeV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).RemoveAttendeeV1(ctx, req.(*RemoveAttendeeRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_RespondToEventV1_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RespondToEventRequestV1)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(EventServiceServer).RespondToEventV1(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: "/event.EventService/RespondToEventV1",
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(EventServiceServer).RespondToEventV1(ctx, req.(*RespondToEventRequestV1))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _EventService_WatchEventsV1_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchEventsRequestV1)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(EventServiceServer).WatchEventsV1(m, &eventServiceWatchEventsV1Server{stream})
+}
+
+type EventService_WatchEventsV1Server interface {
+	Send(*EventChangeV1) error
+	grpc.ServerStream
+}
+
+type eventServiceWatchEventsV1Server struct {
+	grpc.ServerStream
+}
+
+func (x *eventServiceWatchEventsV1Server) Send(m *EventChangeV1) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // EventService_ServiceDesc is the grpc.ServiceDesc for EventService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -264,10 +739,34 @@ var EventService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateEventV1",
 			Handler:    _EventService_UpdateEventV1_Handler,
 		},
+		{
+			MethodName: "PatchEventV1",
+			Handler:    _EventService_PatchEventV1_Handler,
+		},
 		{
 			MethodName: "DeleteEventV1",
 			Handler:    _EventService_DeleteEventV1_Handler,
 		},
+		{
+			MethodName: "ListTrashV1",
+			Handler:    _EventService_ListTrashV1_Handler,
+		},
+		{
+			MethodName: "RestoreEventV1",
+			Handler:    _EventService_RestoreEventV1_Handler,
+		},
+		{
+			MethodName: "PurgeEventV1",
+			Handler:    _EventService_PurgeEventV1_Handler,
+		},
+		{
+			MethodName: "GetEventHistoryV1",
+			Handler:    _EventService_GetEventHistoryV1_Handler,
+		},
+		{
+			MethodName: "RevertEventV1",
+			Handler:    _EventService_RevertEventV1_Handler,
+		},
 		{
 			MethodName: "GetEventsForDayV1",
 			Handler:    _EventService_GetEventsForDayV1_Handler,
@@ -280,7 +779,41 @@ var EventService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetEventsForMonthV1",
 			Handler:    _EventService_GetEventsForMonthV1_Handler,
 		},
+		{
+			MethodName: "ExportEventsV1",
+			Handler:    _EventService_ExportEventsV1_Handler,
+		},
+		{
+			MethodName: "ImportEventsV1",
+			Handler:    _EventService_ImportEventsV1_Handler,
+		},
+		{
+			MethodName: "GetFreeBusyV1",
+			Handler:    _EventService_GetFreeBusyV1_Handler,
+		},
+		{
+			MethodName: "FindMeetingSlotsV1",
+			Handler:    _EventService_FindMeetingSlotsV1_Handler,
+		},
+		{
+			MethodName: "InviteAttendeeV1",
+			Handler:    _EventService_InviteAttendeeV1_Handler,
+		},
+		{
+			MethodName: "RemoveAttendeeV1",
+			Handler:    _EventService_RemoveAttendeeV1_Handler,
+		},
+		{
+			MethodName: "RespondToEventV1",
+			Handler:    _EventService_RespondToEventV1_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchEventsV1",
+			Handler:       _EventService_WatchEventsV1_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "event/event.proto",
 }
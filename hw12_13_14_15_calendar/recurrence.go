@@ -0,0 +1,352 @@
+package calendar
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"strings"
+	"time"
+	_ "time/tzdata" // база часовых поясов для образов без tzdata
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// OverlapHorizon глубина, на которую проверяются пересечения повторяющихся событий.
+const OverlapHorizon = 366 * 24 * time.Hour
+
+// exDateLayout формат хранения исключенных дат (как в EXDATE из RFC 5545).
+const exDateLayout = "20060102T150405Z"
+
+// maxTime дата, используемая как бесконечность при разворачивании правил.
+var maxTime = time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC)
+
+// ExDates список дат начала вхождений, исключенных из повторения.
+type ExDates []time.Time
+
+// Contains проверяет, исключено ли вхождение с указанной датой начала.
+func (d ExDates) Contains(t time.Time) bool {
+	for _, ex := range d {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Value реализует driver.Valuer.
+func (d ExDates) Value() (driver.Value, error) {
+	res := make([]string, 0, len(d))
+	for _, t := range d {
+		res = append(res, t.UTC().Format(exDateLayout))
+	}
+
+	return strings.Join(res, ","), nil
+}
+
+// Scan реализует sql.Scanner.
+func (d *ExDates) Scan(src interface{}) error {
+	var s string
+
+	switch v := src.(type) {
+	case nil:
+	case string:
+		s = v
+	case []byte:
+		s = string(v)
+	default:
+		return fmt.Errorf("cannot scan %T into ExDates", src)
+	}
+
+	*d = nil
+
+	if s == "" {
+		return nil
+	}
+
+	for _, part := range strings.Split(s, ",") {
+		t, err := time.Parse(exDateLayout, part)
+		if err != nil {
+			return errors.Wrap(err, "scan exdates")
+		}
+
+		*d = append(*d, t)
+	}
+
+	return nil
+}
+
+// LoadLocation загружает часовой пояс по имени из базы IANA. Пустое имя означает UTC.
+func LoadLocation(name string) (*time.Location, error) {
+	// "Local" зависит от окружения сервера, поэтому не допускается
+	if name == "Local" {
+		return nil, ErrInvalidTimeZone
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, errors.Wrap(ErrInvalidTimeZone, err.Error())
+	}
+
+	return loc, nil
+}
+
+// Location возвращает часовой пояс события.
+func (e *Event) Location() *time.Location {
+	loc, err := LoadLocation(e.TimeZone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
+
+// Validate проверяет корректность события перед сохранением.
+func (e *Event) Validate() error {
+	if _, err := LoadLocation(e.TimeZone); err != nil {
+		return err
+	}
+
+	if !e.IsRecurring() {
+		return nil
+	}
+
+	if _, err := ParseRRule(e.RRule); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// IsRecurring является ли событие повторяющимся.
+func (e *Event) IsRecurring() bool {
+	return e.RRule != ""
+}
+
+// Occurrences возвращает вхождения события, которые заканчиваются не раньше from
+// и начинаются не позже to. Для неповторяющегося события вхождением является оно само.
+func (e *Event) Occurrences(from, to time.Time) ([]*Event, error) {
+	if !e.IsRecurring() {
+		if e.EndAt.Before(from) || e.StartAt.After(to) {
+			return nil, nil
+		}
+
+		return []*Event{e}, nil
+	}
+
+	rule, err := ParseRRule(e.RRule)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := e.EndAt.Sub(e.StartAt)
+	res := make([]*Event, 0)
+
+	rule.each(e.StartAt.In(e.Location()), to, func(start time.Time) {
+		if start.Add(duration).Before(from) || e.ExDates.Contains(start) {
+			return
+		}
+
+		recurrenceID := start
+
+		o := *e
+		o.StartAt, o.EndAt = start, start.Add(duration)
+		o.RecurrenceID = &recurrenceID
+		res = append(res, &o)
+	})
+
+	return res, nil
+}
+
+// lastOccurrence возвращает даты начала и окончания последнего вхождения события.
+// Если повторения бесконечны, то finite будет false.
+func (e *Event) lastOccurrence() (startAt, endAt time.Time, finite bool, err error) {
+	if !e.IsRecurring() {
+		return e.StartAt, e.EndAt, true, nil
+	}
+
+	rule, err := ParseRRule(e.RRule)
+	if err != nil {
+		return startAt, endAt, false, err
+	}
+
+	if rule.Count == 0 && rule.Until.IsZero() {
+		return startAt, endAt, false, nil
+	}
+
+	startAt = e.StartAt
+	rule.each(e.StartAt.In(e.Location()), maxTime, func(start time.Time) {
+		if !e.ExDates.Contains(start) {
+			startAt = start
+		}
+	})
+
+	return startAt, startAt.Add(e.EndAt.Sub(e.StartAt)), true, nil
+}
+
+// ExpandEvent возвращает вхождения повторяющегося события, удовлетворяющие фильтру.
+// Если в фильтре не задан полный промежуток времени (From и To) и не требуется
+// поиск по времени уведомления, то фильтр применяется ко всей серии, и возвращается само событие.
+func ExpandEvent(e *Event, filter EventFilter, now time.Time) ([]*Event, error) {
+	if !filter.MatchUser(e) {
+		return nil, nil
+	}
+
+	if filter.UID != "" && e.UID != filter.UID {
+		return nil, nil
+	}
+
+	if !filter.NotifyTime && (filter.From.IsZero() || filter.To.IsZero()) {
+		ok, err := matchSeries(e, filter)
+		if err != nil || !ok {
+			return nil, err
+		}
+
+		return []*Event{e}, nil
+	}
+
+	from, to := filter.From, filter.To
+
+	if filter.NotifyTime {
+		if from.Before(now) {
+			from = now
+		}
+
+		notifyTo := now.Add(time.Duration(int64(e.NotificationDuration)) * time.Minute)
+		if to.IsZero() || notifyTo.Before(to) {
+			to = notifyTo
+		}
+	}
+
+	occurrences, err := e.Occurrences(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*Event, 0, len(occurrences))
+
+	for _, o := range occurrences {
+		if passOccurrence(o, filter, now) {
+			res = append(res, o)
+		}
+	}
+
+	return res, nil
+}
+
+// Overlaps проверяет, пересекаются ли события с учетом их повторений.
+// Повторения проверяются на глубину OverlapHorizon.
+func Overlaps(a, b *Event) (bool, error) {
+	from := a.StartAt
+	if b.StartAt.After(from) {
+		from = b.StartAt
+	}
+
+	to := from.Add(OverlapHorizon)
+	for _, e := range []*Event{a, b} {
+		if !e.IsRecurring() && e.EndAt.Before(to) {
+			to = e.EndAt
+		}
+	}
+
+	as, err := a.Occurrences(from, to)
+	if err != nil {
+		return false, err
+	}
+
+	bs, err := b.Occurrences(from, to)
+	if err != nil {
+		return false, err
+	}
+
+	for i, j := 0, 0; i < len(as) && j < len(bs); {
+		x, y := as[i], bs[j]
+
+		if x.StartAt.Before(y.EndAt) && y.StartAt.Before(x.EndAt) {
+			return true, nil
+		}
+
+		if x.EndAt.Before(y.EndAt) {
+			i++
+		} else {
+			j++
+		}
+	}
+
+	return false, nil
+}
+
+// CheckDateBusy проверяет, не пересекается ли событие e с событиями events.
+// Событие с идентификатором ignore (например, само обновляемое событие) не учитывается.
+// Если время занято, то вернет ошибку ErrDateBusy.
+func CheckDateBusy(events []*Event, e *Event, ignore uuid.UUID) error {
+	for _, other := range events {
+		if ignore != uuid.Nil && other.ID == ignore {
+			continue
+		}
+
+		overlaps, err := Overlaps(other, e)
+		if err != nil {
+			return err
+		}
+
+		if overlaps {
+			return ErrDateBusy
+		}
+	}
+
+	return nil
+}
+
+// matchSeries проверяет, удовлетворяет ли серия вхождений фильтру по времени.
+func matchSeries(e *Event, filter EventFilter) (bool, error) {
+	lastStartAt, lastEndAt, finite, err := e.lastOccurrence()
+	if err != nil {
+		return false, err
+	}
+
+	if !filter.From.IsZero() && finite && lastStartAt.Before(filter.From) {
+		return false, nil
+	}
+
+	if !filter.To.IsZero() && (!finite || lastEndAt.After(filter.To)) {
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// passOccurrence проверяет вхождение на удовлетворенность условиям фильтра.
+func passOccurrence(o *Event, filter EventFilter, now time.Time) bool {
+	if filter.NotNotified && o.NotifiedUntil != nil && !o.StartAt.After(*o.NotifiedUntil) {
+		return false
+	}
+
+	if filter.NotifyTime {
+		notifyAt := o.StartAt.Add(-time.Duration(int64(o.NotificationDuration)) * time.Minute)
+
+		if o.StartAt.Before(now) || now.Before(notifyAt) {
+			return false
+		}
+	}
+
+	return InRange(o, filter)
+}
+
+// InRange проверяет, попадает ли событие в промежуток времени фильтра.
+func InRange(e *Event, filter EventFilter) bool {
+	if filter.Overlapping {
+		return (filter.From.IsZero() || e.EndAt.After(filter.From)) &&
+			(filter.To.IsZero() || e.StartAt.Before(filter.To))
+	}
+
+	if !filter.From.IsZero() && e.StartAt.Before(filter.From) {
+		return false
+	}
+
+	if !filter.To.IsZero() && e.EndAt.After(filter.To) {
+		return false
+	}
+
+	return true
+}
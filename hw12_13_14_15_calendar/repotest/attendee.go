@@ -0,0 +1,110 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+func testAttendees(t *testing.T, d Driver) {
+	ctx := context.Background()
+	repo := d.New(t)
+
+	owner, guest := uuid.New(), uuid.New()
+	startAt := mustParseDateTime("2022-10-03 10:00:00")
+
+	meeting, err := repo.CreateEvent(ctx, &calendar.Event{
+		Title:   "meeting",
+		StartAt: startAt,
+		EndAt:   startAt.Add(time.Hour),
+		UserID:  owner,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.InviteAttendee(ctx, meeting.ID, owner)
+	require.ErrorIs(t, err, calendar.ErrInvalidAttendee)
+
+	a, err := repo.InviteAttendee(ctx, meeting.ID, guest)
+	require.NoError(t, err)
+	require.Equal(t, calendar.RSVPNeedsAction, a.Status)
+
+	filter := calendar.EventFilter{UserID: guest, Attending: true}
+
+	// пока приглашение не принято, событие не попадает в календарь участника
+	events, err := repo.FindEvents(ctx, filter)
+	require.NoError(t, err)
+	require.Empty(t, events)
+
+	a, err = repo.RespondAttendee(ctx, meeting.ID, guest, calendar.RSVPAccepted)
+	require.NoError(t, err)
+	require.Equal(t, calendar.RSVPAccepted, a.Status)
+
+	// повторное приглашение не сбрасывает ответ
+	a, err = repo.InviteAttendee(ctx, meeting.ID, guest)
+	require.NoError(t, err)
+	require.Equal(t, calendar.RSVPAccepted, a.Status)
+
+	events, err = repo.FindEvents(ctx, filter)
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+	require.Equal(t, meeting.ID, events[0].ID)
+
+	// принятое приглашение занимает время участника
+	_, err = repo.CreateEvent(ctx, &calendar.Event{
+		StartAt: startAt.Add(30 * time.Minute),
+		EndAt:   startAt.Add(2 * time.Hour),
+		UserID:  guest,
+	})
+	require.ErrorIs(t, err, calendar.ErrDateBusy)
+
+	// и участвует в проверке при переносе события владельцем
+	_, err = repo.CreateEvent(ctx, &calendar.Event{
+		StartAt: startAt.Add(24 * time.Hour),
+		EndAt:   startAt.Add(25 * time.Hour),
+		UserID:  guest,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.UpdateEvent(ctx, meeting.ID, &calendar.Event{
+		Version: 1,
+		StartAt: startAt.Add(24 * time.Hour),
+		EndAt:   startAt.Add(25 * time.Hour),
+		UserID:  owner,
+	})
+	require.ErrorIs(t, err, calendar.ErrDateBusy)
+
+	require.NoError(t, repo.RemoveAttendee(ctx, meeting.ID, guest))
+	require.ErrorIs(t, repo.RemoveAttendee(ctx, meeting.ID, guest), calendar.ErrNotFound)
+
+	_, err = repo.RespondAttendee(ctx, meeting.ID, guest, calendar.RSVPDeclined)
+	require.ErrorIs(t, err, calendar.ErrNotFound)
+}
+
+func testRespondAttendeeBusy(t *testing.T, d Driver) {
+	ctx := context.Background()
+	repo := d.New(t)
+
+	owner, guest := uuid.New(), uuid.New()
+	startAt := mustParseDateTime("2022-10-03 10:00:00")
+
+	meeting, err := repo.CreateEvent(ctx, &calendar.Event{StartAt: startAt, EndAt: startAt.Add(time.Hour), UserID: owner})
+	require.NoError(t, err)
+
+	_, err = repo.CreateEvent(ctx, &calendar.Event{StartAt: startAt, EndAt: startAt.Add(time.Hour), UserID: guest})
+	require.NoError(t, err)
+
+	_, err = repo.InviteAttendee(ctx, meeting.ID, guest)
+	require.NoError(t, err)
+
+	_, err = repo.RespondAttendee(ctx, meeting.ID, guest, calendar.RSVPAccepted)
+	require.ErrorIs(t, err, calendar.ErrDateBusy)
+
+	a, err := repo.RespondAttendee(ctx, meeting.ID, guest, calendar.RSVPTentative)
+	require.NoError(t, err)
+	require.Equal(t, calendar.RSVPTentative, a.Status)
+}
@@ -0,0 +1,67 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// changeRecorder запоминает опубликованные изменения.
+type changeRecorder []calendar.Change
+
+func (r *changeRecorder) PublishChange(c calendar.Change) {
+	*r = append(*r, c)
+}
+
+func testPublishChange(t *testing.T, d Driver) {
+	ctx := context.Background()
+
+	changes := new(changeRecorder)
+
+	repo := d.New(t)
+	repo.SetChangePublisher(changes)
+
+	ownerID := uuid.New()
+	guestID := uuid.New()
+
+	e, err := repo.CreateEvent(ctx, &calendar.Event{
+		Title:   "foo",
+		StartAt: mustParseDateTime("2022-12-05 10:00:00"),
+		EndAt:   mustParseDateTime("2022-12-05 11:00:00"),
+		UserID:  ownerID,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.InviteAttendee(ctx, e.ID, guestID)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.RemoveAttendee(ctx, e.ID, guestID))
+	require.NoError(t, repo.DeleteEvent(ctx, e.ID, uuid.New()))
+	require.NoError(t, repo.PurgeEvent(ctx, e.ID))
+
+	require.Len(t, *changes, 5)
+
+	wantTypes := []calendar.ChangeType{
+		calendar.ChangeCreated,
+		calendar.ChangeUpdated,
+		calendar.ChangeUpdated,
+		calendar.ChangeDeleted,
+		calendar.ChangePurged,
+	}
+
+	for i, c := range *changes {
+		require.Equal(t, wantTypes[i], c.Type)
+		require.Equal(t, e.ID, c.Event.ID)
+		require.True(t, c.Concerns(ownerID))
+	}
+
+	// приглашенный получает изменение о приглашении и об исключении
+	require.True(t, (*changes)[1].Concerns(guestID))
+	require.True(t, (*changes)[2].Concerns(guestID))
+	require.False(t, (*changes)[3].Concerns(guestID))
+	require.False(t, (*changes)[4].Concerns(guestID))
+}
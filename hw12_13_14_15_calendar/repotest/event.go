@@ -0,0 +1,1029 @@
+package repotest
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+//nolint:goconst
+func testCreateEvent(t *testing.T, d Driver) {
+	t.Run("success", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		id := uuid.New()
+		title := "foo"
+		descr := "bar"
+		userID := uuid.New()
+		startAt := mustParseDateTime("2022-12-05 10:00:00")
+		endAt := mustParseDateTime("2022-12-05 10:00:30")
+		notificationDuration := (10 * time.Hour).Minutes()
+
+		event, err := repo.CreateEvent(ctx, &calendar.Event{
+			ID:                   id,
+			Title:                title,
+			Description:          descr,
+			StartAt:              startAt,
+			EndAt:                endAt,
+			UserID:               userID,
+			NotificationDuration: uint32(notificationDuration),
+		})
+
+		require.NoError(t, err)
+		require.NotNil(t, event)
+
+		// check fields
+		require.NotEqual(t, id, event.ID)
+		require.Equal(t, title, event.Title)
+		require.Equal(t, descr, event.Description)
+		require.Equal(t, startAt, event.StartAt)
+		require.Equal(t, endAt, event.EndAt)
+		require.Equal(t, userID, event.UserID)
+		require.Equal(t, uint32(notificationDuration), event.NotificationDuration)
+
+		// check storage
+		require.Equal(t, 1, countEvents(t, repo))
+
+		event, err = repo.FindEventByID(ctx, event.ID)
+		require.NotNil(t, event)
+		require.NoError(t, err)
+
+		event, err = repo.FindEventByID(ctx, id)
+		require.Nil(t, event)
+		require.ErrorIs(t, err, calendar.ErrNotFound)
+	})
+}
+
+func testCreateEventConcurrent(t *testing.T, d Driver) {
+	ctx := context.Background()
+	repo := d.New(t)
+
+	userID := uuid.New()
+	startAt := mustParseDateTime("2022-12-05 10:00:00")
+
+	const workers = 200
+
+	var (
+		wg      sync.WaitGroup
+		created int32
+	)
+
+	// все создания начинаются одновременно
+	start := make(chan struct{})
+
+	// события сдвинуты на 10 минут и длятся час, поэтому каждое пересекается с соседними
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			<-start
+
+			from := startAt.Add(time.Duration(i%10) * 10 * time.Minute)
+
+			_, err := repo.CreateEvent(ctx, &calendar.Event{
+				StartAt: from,
+				EndAt:   from.Add(time.Hour),
+				UserID:  userID,
+			})
+			if err == nil {
+				atomic.AddInt32(&created, 1)
+
+				return
+			}
+
+			require.ErrorIs(t, err, calendar.ErrDateBusy)
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+
+	events, err := repo.FindEvents(ctx, calendar.EventFilter{UserID: userID})
+	require.NoError(t, err)
+	require.Len(t, events, int(created))
+	require.NotZero(t, created)
+
+	for i, a := range events {
+		for _, b := range events[i+1:] {
+			overlaps, err := calendar.Overlaps(a, b)
+			require.NoError(t, err)
+			require.False(t, overlaps, "events %s and %s overlap", a.StartAt, b.StartAt)
+		}
+	}
+}
+
+func testUpdateEvent(t *testing.T, d Driver) {
+	t.Run("success", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		event, err := repo.CreateEvent(ctx, &calendar.Event{})
+		require.NoError(t, err)
+
+		id := event.ID
+		title := "foo"
+		descr := "bar"
+		userID := uuid.New()
+		startAt := mustParseDateTime("2022-12-05 10:00:00")
+		endAt := mustParseDateTime("2022-12-05 10:00:30")
+		notificationDuration := (10 * time.Hour).Minutes()
+
+		event, err = repo.UpdateEvent(ctx, id, &calendar.Event{
+			Version:              1,
+			Title:                title,
+			Description:          descr,
+			StartAt:              startAt,
+			EndAt:                endAt,
+			UserID:               userID,
+			NotificationDuration: uint32(notificationDuration),
+		})
+
+		require.NoError(t, err)
+		require.NotNil(t, event)
+
+		// check fields
+		require.Equal(t, id, event.ID)
+		require.Equal(t, title, event.Title)
+		require.Equal(t, descr, event.Description)
+		require.Equal(t, startAt, event.StartAt)
+		require.Equal(t, endAt, event.EndAt)
+		require.Equal(t, userID, event.UserID)
+		require.Equal(t, uint32(notificationDuration), event.NotificationDuration)
+		require.Equal(t, uint64(2), event.Version)
+
+		// check storage
+		require.Equal(t, 1, countEvents(t, repo))
+	})
+
+	t.Run("version conflict", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		event, err := repo.CreateEvent(ctx, &calendar.Event{Title: "foo"})
+		require.NoError(t, err)
+		require.Equal(t, uint64(1), event.Version)
+
+		_, err = repo.UpdateEvent(ctx, event.ID, &calendar.Event{Title: "bar", Version: 1})
+		require.NoError(t, err)
+
+		// изменение на основе устаревшей версии отклоняется
+		_, err = repo.UpdateEvent(ctx, event.ID, &calendar.Event{Title: "baz", Version: 1})
+		require.ErrorIs(t, err, calendar.ErrVersionConflict)
+
+		stored, err := repo.FindEventByID(ctx, event.ID)
+		require.NoError(t, err)
+		require.Equal(t, "bar", stored.Title)
+		require.Equal(t, uint64(2), stored.Version)
+	})
+
+	t.Run("resets notification", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		userID := uuid.New()
+		startAt := mustParseDateTime("2022-12-05 10:00:00")
+		notifiedUntil := startAt.AddDate(0, 0, 1)
+
+		src := calendar.Event{
+			StartAt:              startAt,
+			EndAt:                startAt.Add(time.Hour),
+			UserID:               userID,
+			NotificationDuration: 15,
+			RRule:                "FREQ=DAILY;COUNT=5",
+		}
+
+		series, err := repo.CreateEvent(ctx, &src)
+		require.NoError(t, err)
+
+		// изменение, не влияющее на время уведомления, сохраняет отметку
+		require.NoError(t, repo.MarkEventNotified(ctx, series.ID, notifiedUntil))
+
+		update := src
+		update.Title = "standup"
+		update.Version = series.Version
+
+		updated, err := repo.UpdateEvent(ctx, series.ID, &update)
+		require.NoError(t, err)
+		require.NotNil(t, updated.NotifiedUntil)
+		require.Equal(t, notifiedUntil, *updated.NotifiedUntil)
+
+		for name, change := range map[string]func(e *calendar.Event){
+			"start_at":              func(e *calendar.Event) { e.StartAt = e.StartAt.Add(-30 * time.Minute) },
+			"time_zone":             func(e *calendar.Event) { e.TimeZone = "Europe/Moscow" },
+			"notification_duration": func(e *calendar.Event) { e.NotificationDuration = 30 },
+			"rrule":                 func(e *calendar.Event) { e.RRule = "FREQ=DAILY;COUNT=3" },
+		} {
+			require.NoError(t, repo.MarkEventNotified(ctx, series.ID, notifiedUntil))
+
+			update := *updated
+			update.Attendees = nil
+			change(&update)
+
+			updated, err = repo.UpdateEvent(ctx, series.ID, &update)
+			require.NoError(t, err, name)
+			require.False(t, updated.IsNotified, name)
+			require.Nil(t, updated.NotifiedUntil, name)
+
+			stored, err := repo.FindEventByID(ctx, series.ID)
+			require.NoError(t, err)
+			require.False(t, stored.IsNotified, name)
+			require.Nil(t, stored.NotifiedUntil, name)
+		}
+	})
+
+	t.Run("keeps notification of single event", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		src := calendar.Event{
+			StartAt:              mustParseDateTime("2022-12-05 10:00:00"),
+			EndAt:                mustParseDateTime("2022-12-05 11:00:00"),
+			UserID:               uuid.New(),
+			NotificationDuration: 15,
+		}
+
+		event, err := repo.CreateEvent(ctx, &src)
+		require.NoError(t, err)
+		require.NoError(t, repo.MarkEventNotified(ctx, event.ID, event.StartAt))
+
+		// клиент не передает отметку об уведомлении, но о событии уже уведомили
+		update := src
+		update.Title = "standup"
+		update.Version = event.Version
+
+		updated, err := repo.UpdateEvent(ctx, event.ID, &update)
+		require.NoError(t, err)
+		require.True(t, updated.IsNotified)
+
+		stored, err := repo.FindEventByID(ctx, event.ID)
+		require.NoError(t, err)
+		require.True(t, stored.IsNotified)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		id := uuid.New()
+
+		event, err := repo.UpdateEvent(ctx, id, &calendar.Event{})
+
+		require.ErrorIs(t, err, calendar.ErrNotFound)
+		require.Nil(t, event)
+
+		// check storage
+		require.Zero(t, countEvents(t, repo))
+	})
+}
+
+func testPatchEvent(t *testing.T, d Driver) {
+	t.Run("success", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		userID := uuid.New()
+		startAt := mustParseDateTime("2022-12-05 10:00:00")
+
+		event, err := repo.CreateEvent(ctx, &calendar.Event{
+			Title:                "foo",
+			Description:          "bar",
+			StartAt:              startAt,
+			EndAt:                startAt.Add(time.Hour),
+			UserID:               userID,
+			NotificationDuration: 15,
+		})
+		require.NoError(t, err)
+
+		event, err = repo.PatchEvent(ctx, event.ID, &calendar.Event{
+			Title:   "baz",
+			EndAt:   startAt.Add(2 * time.Hour),
+			Version: 1,
+		}, []calendar.EventField{calendar.FieldTitle, calendar.FieldEndAt})
+
+		require.NoError(t, err)
+		require.Equal(t, "baz", event.Title)
+		require.Equal(t, startAt.Add(2*time.Hour), event.EndAt)
+		require.Equal(t, uint64(2), event.Version)
+
+		// поля вне маски сохраняют значения
+		require.Equal(t, "bar", event.Description)
+		require.Equal(t, startAt, event.StartAt)
+		require.Equal(t, userID, event.UserID)
+		require.Equal(t, uint32(15), event.NotificationDuration)
+
+		stored, err := repo.FindEventByID(ctx, event.ID)
+		require.NoError(t, err)
+		require.Equal(t, "baz", stored.Title)
+		require.Equal(t, "bar", stored.Description)
+	})
+
+	t.Run("resets notification", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		startAt := mustParseDateTime("2022-12-05 10:00:00")
+
+		series, err := repo.CreateEvent(ctx, &calendar.Event{
+			StartAt:              startAt,
+			EndAt:                startAt.Add(time.Hour),
+			UserID:               uuid.New(),
+			NotificationDuration: 15,
+			RRule:                "FREQ=DAILY;COUNT=5",
+		})
+		require.NoError(t, err)
+
+		notifiedUntil := startAt.AddDate(0, 0, 1)
+		require.NoError(t, repo.MarkEventNotified(ctx, series.ID, notifiedUntil))
+
+		// поле, не влияющее на время уведомления, сохраняет отметку
+		patched, err := repo.PatchEvent(ctx, series.ID, &calendar.Event{
+			Title:   "standup",
+			Version: series.Version,
+		}, []calendar.EventField{calendar.FieldTitle})
+		require.NoError(t, err)
+		require.NotNil(t, patched.NotifiedUntil)
+		require.Equal(t, notifiedUntil, *patched.NotifiedUntil)
+
+		for _, f := range []calendar.EventField{
+			calendar.FieldStartAt,
+			calendar.FieldNotificationDuration,
+			calendar.FieldRRule,
+		} {
+			require.NoError(t, repo.MarkEventNotified(ctx, series.ID, notifiedUntil))
+
+			patched, err = repo.PatchEvent(ctx, series.ID, &calendar.Event{
+				StartAt:              startAt.Add(time.Hour),
+				NotificationDuration: 30,
+				RRule:                "FREQ=DAILY;COUNT=3",
+				Version:              patched.Version,
+			}, []calendar.EventField{f})
+			require.NoError(t, err, f)
+			require.False(t, patched.IsNotified, f)
+			require.Nil(t, patched.NotifiedUntil, f)
+
+			stored, err := repo.FindEventByID(ctx, series.ID)
+			require.NoError(t, err)
+			require.False(t, stored.IsNotified, f)
+			require.Nil(t, stored.NotifiedUntil, f)
+		}
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		userID := uuid.New()
+		startAt := mustParseDateTime("2022-12-05 10:00:00")
+
+		event, err := repo.CreateEvent(ctx, &calendar.Event{
+			StartAt: startAt,
+			EndAt:   startAt.Add(time.Hour),
+			UserID:  userID,
+		})
+		require.NoError(t, err)
+
+		_, err = repo.CreateEvent(ctx, &calendar.Event{
+			StartAt: startAt.Add(2 * time.Hour),
+			EndAt:   startAt.Add(3 * time.Hour),
+			UserID:  userID,
+		})
+		require.NoError(t, err)
+
+		_, err = repo.PatchEvent(ctx, uuid.New(), &calendar.Event{}, []calendar.EventField{calendar.FieldTitle})
+		require.ErrorIs(t, err, calendar.ErrNotFound)
+
+		_, err = repo.PatchEvent(ctx, event.ID, &calendar.Event{Version: 2}, []calendar.EventField{calendar.FieldTitle})
+		require.ErrorIs(t, err, calendar.ErrVersionConflict)
+
+		_, err = repo.PatchEvent(ctx, event.ID, &calendar.Event{Version: 1}, []calendar.EventField{"user_id"})
+		require.ErrorIs(t, err, calendar.ErrInvalidField)
+
+		_, err = repo.PatchEvent(ctx, event.ID, &calendar.Event{
+			EndAt:   startAt.Add(150 * time.Minute),
+			Version: 1,
+		}, []calendar.EventField{calendar.FieldEndAt})
+		require.ErrorIs(t, err, calendar.ErrDateBusy)
+
+		stored, err := repo.FindEventByID(ctx, event.ID)
+		require.NoError(t, err)
+		require.Equal(t, startAt.Add(time.Hour), stored.EndAt)
+		require.Equal(t, uint64(1), stored.Version)
+	})
+}
+
+func testDeleteEvent(t *testing.T, d Driver) {
+	t.Run("success", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		event, err := repo.CreateEvent(ctx, &calendar.Event{})
+		require.NoError(t, err)
+
+		id := event.ID
+
+		err = repo.DeleteEvent(ctx, id)
+		require.NoError(t, err)
+
+		// check storage
+		require.Zero(t, countEvents(t, repo))
+
+		trash, err := repo.FindTrash(ctx, calendar.TrashFilter{})
+		require.NoError(t, err)
+		require.Len(t, trash, 1)
+		require.Equal(t, id, trash[0].ID)
+		require.NotNil(t, trash[0].DeletedAt)
+	})
+
+	t.Run("multiple", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		event1, err := repo.CreateEvent(ctx, &calendar.Event{})
+		require.NoError(t, err)
+
+		event2, err := repo.CreateEvent(ctx, &calendar.Event{})
+		require.NoError(t, err)
+
+		err = repo.DeleteEvent(ctx, event1.ID, event2.ID)
+		require.NoError(t, err)
+
+		// check storage
+		require.Zero(t, countEvents(t, repo))
+
+		trash, err := repo.FindTrash(ctx, calendar.TrashFilter{})
+		require.NoError(t, err)
+		require.Len(t, trash, 2)
+	})
+}
+
+//nolint:funlen
+func testFindEvents(t *testing.T, d Driver) {
+	t.Run("format", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		title := "foo"
+		descr := "bar"
+		userID := uuid.New()
+		startAt := mustParseDateTime("2022-12-05 10:00:00")
+		endAt := mustParseDateTime("2022-12-05 10:00:30")
+		notificationDuration := (10 * time.Hour).Minutes()
+
+		event, err := repo.CreateEvent(ctx, &calendar.Event{
+			Title:                title,
+			Description:          descr,
+			StartAt:              startAt,
+			EndAt:                endAt,
+			UserID:               userID,
+			NotificationDuration: uint32(notificationDuration),
+		})
+		require.NoError(t, err)
+
+		events, err := repo.FindEvents(ctx, calendar.EventFilter{})
+
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+
+		// check fields
+		require.Equal(t, event.ID, events[0].ID)
+		require.Equal(t, title, events[0].Title)
+		require.Equal(t, descr, events[0].Description)
+		require.Equal(t, startAt, events[0].StartAt)
+		require.Equal(t, endAt, events[0].EndAt)
+		require.Equal(t, userID, events[0].UserID)
+		require.Equal(t, uint32(notificationDuration), events[0].NotificationDuration)
+	})
+
+	t.Run("filter", func(t *testing.T) {
+		type args struct {
+			event  calendar.Event
+			filter calendar.EventFilter
+		}
+		tests := []struct {
+			name  string
+			args  args
+			found bool
+		}{
+			// user id filter
+			{
+				name: "user id match",
+				args: args{
+					event: calendar.Event{
+						UserID: uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+					},
+					filter: calendar.EventFilter{
+						UserID: uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+					},
+				},
+				found: true,
+			},
+			{
+				name: "user id skip",
+				args: args{
+					event: calendar.Event{
+						UserID: uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+					},
+					filter: calendar.EventFilter{
+						UserID: uuid.MustParse("2f0d2079-e9a2-4810-8cae-eb6729c50580"),
+					},
+				},
+				found: false,
+			},
+
+			// from filter
+			{
+				name: "from match (equal)",
+				args: args{
+					event: calendar.Event{
+						StartAt: mustParseDateTime("2022-05-10 15:20:30"),
+					},
+					filter: calendar.EventFilter{
+						From: mustParseDateTime("2022-05-10 15:20:30"),
+					},
+				},
+				found: true,
+			},
+			{
+				name: "from match (greater)",
+				args: args{
+					event: calendar.Event{
+						StartAt: mustParseDateTime("2022-05-10 15:20:40"),
+					},
+					filter: calendar.EventFilter{
+						From: mustParseDateTime("2022-05-10 15:20:30"),
+					},
+				},
+				found: true,
+			},
+			{
+				name: "from skip",
+				args: args{
+					event: calendar.Event{
+						StartAt: mustParseDateTime("2022-05-10 15:20:30"),
+					},
+					filter: calendar.EventFilter{
+						From: mustParseDateTime("2022-05-10 15:20:31"),
+					},
+				},
+				found: false,
+			},
+
+			// to filter
+			{
+				name: "to match (equal)",
+				args: args{
+					event: calendar.Event{
+						StartAt: mustParseDateTime("2022-05-10 15:20:30"),
+						EndAt:   mustParseDateTime("2022-05-10 15:50:30"),
+					},
+					filter: calendar.EventFilter{
+						To: mustParseDateTime("2022-05-10 15:50:30"),
+					},
+				},
+				found: true,
+			},
+			{
+				name: "to match (less)",
+				args: args{
+					event: calendar.Event{
+						StartAt: mustParseDateTime("2022-05-10 15:20:30"),
+						EndAt:   mustParseDateTime("2022-05-10 15:50:20"),
+					},
+					filter: calendar.EventFilter{
+						To: mustParseDateTime("2022-05-10 15:50:30"),
+					},
+				},
+				found: true,
+			},
+			{
+				name: "to skip",
+				args: args{
+					event: calendar.Event{
+						StartAt: mustParseDateTime("2022-05-10 15:20:30"),
+						EndAt:   mustParseDateTime("2022-05-10 15:50:30"),
+					},
+					filter: calendar.EventFilter{
+						To: mustParseDateTime("2022-05-10 15:50:29"),
+					},
+				},
+				found: false,
+			},
+
+			// overlapping filter
+			{
+				name: "overlapping match (starts before)",
+				args: args{
+					event: calendar.Event{
+						StartAt: mustParseDateTime("2022-05-10 15:00:00"),
+						EndAt:   mustParseDateTime("2022-05-10 16:00:00"),
+					},
+					filter: calendar.EventFilter{
+						From:        mustParseDateTime("2022-05-10 15:30:00"),
+						To:          mustParseDateTime("2022-05-10 17:00:00"),
+						Overlapping: true,
+					},
+				},
+				found: true,
+			},
+			{
+				name: "overlapping skip (touches)",
+				args: args{
+					event: calendar.Event{
+						StartAt: mustParseDateTime("2022-05-10 15:00:00"),
+						EndAt:   mustParseDateTime("2022-05-10 15:30:00"),
+					},
+					filter: calendar.EventFilter{
+						From:        mustParseDateTime("2022-05-10 15:30:00"),
+						To:          mustParseDateTime("2022-05-10 17:00:00"),
+						Overlapping: true,
+					},
+				},
+				found: false,
+			},
+
+			// is notified filter
+			{
+				name: "is notified match",
+				args: args{
+					event: calendar.Event{
+						IsNotified: false,
+					},
+					filter: calendar.EventFilter{
+						NotNotified: true,
+					},
+				},
+				found: true,
+			},
+			{
+				name: "is notified skip",
+				args: args{
+					event: calendar.Event{
+						IsNotified: true,
+					},
+					filter: calendar.EventFilter{
+						NotNotified: true,
+					},
+				},
+				found: false,
+			},
+
+			// notify time
+			{
+				name: "notify time match eq",
+				args: args{
+					event: calendar.Event{
+						StartAt:              mustParseDateTime("2022-05-10 16:00:00"),
+						NotificationDuration: 30,
+					},
+					filter: calendar.EventFilter{
+						NotifyTime: true,
+					},
+				},
+				found: true,
+			},
+			{
+				name: "notify time match gr",
+				args: args{
+					event: calendar.Event{
+						StartAt:              mustParseDateTime("2022-05-10 15:59:59"),
+						NotificationDuration: 30,
+					},
+					filter: calendar.EventFilter{
+						NotifyTime: true,
+					},
+				},
+				found: true,
+			},
+			{
+				name: "notify time skip (already started)",
+				args: args{
+					event: calendar.Event{
+						StartAt:              mustParseDateTime("2022-05-10 16:00:01"),
+						NotificationDuration: 30,
+					},
+					filter: calendar.EventFilter{
+						NotifyTime: true,
+					},
+				},
+				found: false,
+			},
+			{
+				name: "notify time skip (not yet)",
+				args: args{
+					event: calendar.Event{
+						StartAt:              mustParseDateTime("2022-05-10 16:00:00"),
+						NotificationDuration: 29,
+					},
+					filter: calendar.EventFilter{
+						NotifyTime: true,
+					},
+				},
+				found: false,
+			},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				d.setNow(t, func() time.Time {
+					return mustParseDateTime("2022-05-10 15:30:00")
+				})
+
+				ctx := context.Background()
+				repo := d.New(t)
+
+				_, err := repo.CreateEvent(ctx, &tt.args.event)
+				require.NoError(t, err)
+
+				events, err := repo.FindEvents(ctx, tt.args.filter)
+				require.NoError(t, err)
+
+				if tt.found {
+					require.Len(t, events, 1)
+				} else {
+					require.Empty(t, events)
+				}
+			})
+		}
+	})
+}
+
+func testFindEventsRecurring(t *testing.T, d Driver) {
+	ctx := context.Background()
+	repo := d.New(t)
+
+	userID := uuid.New()
+
+	series, err := repo.CreateEvent(ctx, &calendar.Event{
+		Title:                "standup",
+		StartAt:              mustParseDateTime("2022-10-03 10:00:00"),
+		EndAt:                mustParseDateTime("2022-10-03 10:15:00"),
+		UserID:               userID,
+		NotificationDuration: 30,
+		RRule:                "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=6",
+		ExDates:              calendar.ExDates{mustParseDateTime("2022-10-10 10:00:00")},
+	})
+	require.NoError(t, err)
+
+	t.Run("range expands occurrences", func(t *testing.T) {
+		events, err := repo.FindEvents(ctx, calendar.EventFilter{
+			UserID: userID,
+			From:   mustParseDateTime("2022-10-05 00:00:00"),
+			To:     mustParseDateTime("2022-10-13 00:00:00"),
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 2)
+
+		starts := []time.Time{events[0].StartAt, events[1].StartAt}
+		require.ElementsMatch(t, []time.Time{
+			mustParseDateTime("2022-10-05 10:00:00"),
+			mustParseDateTime("2022-10-12 10:00:00"),
+		}, starts)
+
+		for _, e := range events {
+			require.Equal(t, series.ID, e.ID)
+			require.NotNil(t, e.RecurrenceID)
+			require.Equal(t, e.StartAt, *e.RecurrenceID)
+		}
+	})
+
+	t.Run("notify time selects occurrence", func(t *testing.T) {
+		d.setNow(t, func() time.Time {
+			return mustParseDateTime("2022-10-12 09:45:00")
+		})
+
+		filter := calendar.EventFilter{
+			NotNotified: true,
+			NotifyTime:  true,
+		}
+
+		events, err := repo.FindEvents(ctx, filter)
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+		require.Equal(t, mustParseDateTime("2022-10-12 10:00:00"), events[0].StartAt)
+
+		err = repo.MarkEventNotified(ctx, series.ID, events[0].StartAt)
+		require.NoError(t, err)
+
+		events, err = repo.FindEvents(ctx, filter)
+		require.NoError(t, err)
+		require.Empty(t, events)
+
+		// отметка о более раннем вхождении не откатывает прогресс
+		err = repo.MarkEventNotified(ctx, series.ID, mustParseDateTime("2022-10-05 10:00:00"))
+		require.NoError(t, err)
+
+		events, err = repo.FindEvents(ctx, filter)
+		require.NoError(t, err)
+		require.Empty(t, events)
+	})
+
+	t.Run("series end filter", func(t *testing.T) {
+		events, err := repo.FindEvents(ctx, calendar.EventFilter{
+			To: mustParseDateTime("2022-10-19 00:00:00"),
+		})
+		require.NoError(t, err)
+		require.Empty(t, events)
+
+		events, err = repo.FindEvents(ctx, calendar.EventFilter{
+			To: mustParseDateTime("2022-10-20 00:00:00"),
+		})
+		require.NoError(t, err)
+		require.Len(t, events, 1)
+	})
+
+	t.Run("date busy by occurrence", func(t *testing.T) {
+		_, err := repo.CreateEvent(ctx, &calendar.Event{
+			StartAt: mustParseDateTime("2022-10-17 09:30:00"),
+			EndAt:   mustParseDateTime("2022-10-17 10:05:00"),
+			UserID:  userID,
+		})
+		require.ErrorIs(t, err, calendar.ErrDateBusy)
+
+		_, err = repo.CreateEvent(ctx, &calendar.Event{
+			StartAt: mustParseDateTime("2022-10-10 09:30:00"),
+			EndAt:   mustParseDateTime("2022-10-10 10:05:00"),
+			UserID:  userID,
+		})
+		require.NoError(t, err)
+	})
+
+	t.Run("update keeps notification progress", func(t *testing.T) {
+		err := repo.MarkEventNotified(ctx, series.ID, mustParseDateTime("2022-10-12 10:00:00"))
+		require.NoError(t, err)
+
+		updated, err := repo.UpdateEvent(ctx, series.ID, &calendar.Event{
+			Version:              1,
+			Title:                "daily",
+			StartAt:              series.StartAt,
+			EndAt:                series.EndAt,
+			UserID:               userID,
+			NotificationDuration: series.NotificationDuration,
+			RRule:                series.RRule,
+			ExDates:              series.ExDates,
+		})
+		require.NoError(t, err)
+		require.NotNil(t, updated.NotifiedUntil)
+		require.Equal(t, mustParseDateTime("2022-10-12 10:00:00"), *updated.NotifiedUntil)
+	})
+
+	t.Run("invalid rule", func(t *testing.T) {
+		_, err := repo.CreateEvent(ctx, &calendar.Event{
+			UserID: uuid.New(),
+			RRule:  "FREQ=SOMETIMES",
+		})
+		require.ErrorIs(t, err, calendar.ErrInvalidRRule)
+
+		_, err = repo.UpdateEvent(ctx, series.ID, &calendar.Event{
+			UserID: userID,
+			RRule:  "FREQ=DAILY;COUNT=-1",
+		})
+		require.ErrorIs(t, err, calendar.ErrInvalidRRule)
+	})
+}
+
+func testFindEventsPaging(t *testing.T, d Driver) {
+	ctx := context.Background()
+	repo := d.New(t)
+
+	userID := uuid.New()
+
+	_, err := repo.CreateEvent(ctx, &calendar.Event{
+		Title:   "standup",
+		StartAt: mustParseDateTime("2022-10-03 10:00:00"),
+		EndAt:   mustParseDateTime("2022-10-03 10:15:00"),
+		UserID:  userID,
+		RRule:   "FREQ=DAILY;COUNT=5",
+	})
+	require.NoError(t, err)
+
+	for _, day := range []string{"2022-10-04", "2022-10-02", "2022-10-06"} {
+		_, err := repo.CreateEvent(ctx, &calendar.Event{
+			Title:   "lunch " + day,
+			StartAt: mustParseDateTime(day + " 13:00:00"),
+			EndAt:   mustParseDateTime(day + " 14:00:00"),
+			UserID:  userID,
+		})
+		require.NoError(t, err)
+	}
+
+	filter := calendar.EventFilter{
+		UserID: userID,
+		From:   mustParseDateTime("2022-10-01 00:00:00"),
+		To:     mustParseDateTime("2022-10-31 00:00:00"),
+		Limit:  3,
+	}
+
+	starts := make([]time.Time, 0)
+
+	for {
+		events, err := repo.FindEvents(ctx, filter)
+		require.NoError(t, err)
+
+		for _, e := range events {
+			starts = append(starts, e.StartAt)
+		}
+
+		if len(events) < filter.Limit {
+			break
+		}
+
+		cursor := calendar.CursorOf(events[len(events)-1])
+		filter.After = &cursor
+	}
+
+	require.Equal(t, []time.Time{
+		mustParseDateTime("2022-10-02 13:00:00"),
+		mustParseDateTime("2022-10-03 10:00:00"),
+		mustParseDateTime("2022-10-04 10:00:00"),
+		mustParseDateTime("2022-10-04 13:00:00"),
+		mustParseDateTime("2022-10-05 10:00:00"),
+		mustParseDateTime("2022-10-06 10:00:00"),
+		mustParseDateTime("2022-10-06 13:00:00"),
+		mustParseDateTime("2022-10-07 10:00:00"),
+	}, starts)
+}
+
+func testMarkEventNotified(t *testing.T, d Driver) {
+	t.Run("single event", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		event, err := repo.CreateEvent(ctx, &calendar.Event{})
+		require.NoError(t, err)
+
+		err = repo.MarkEventNotified(ctx, event.ID, event.StartAt)
+		require.NoError(t, err)
+
+		event, err = repo.FindEventByID(ctx, event.ID)
+		require.NoError(t, err)
+		require.True(t, event.IsNotified)
+		require.Nil(t, event.NotifiedUntil)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		err := repo.MarkEventNotified(ctx, uuid.New(), time.Now())
+		require.ErrorIs(t, err, calendar.ErrNotFound)
+	})
+}
+
+func testFindEventByID(t *testing.T, d Driver) {
+	t.Run("success", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		title := "foo"
+		descr := "bar"
+		userID := uuid.New()
+		startAt := mustParseDateTime("2022-12-05 10:00:00")
+		endAt := mustParseDateTime("2022-12-05 10:00:30")
+		notificationDuration := (10 * time.Hour).Minutes()
+
+		event, err := repo.CreateEvent(ctx, &calendar.Event{
+			Title:                title,
+			Description:          descr,
+			StartAt:              startAt,
+			EndAt:                endAt,
+			UserID:               userID,
+			NotificationDuration: uint32(notificationDuration),
+		})
+		require.NoError(t, err)
+		id := event.ID
+
+		event, err = repo.FindEventByID(ctx, id)
+
+		require.NoError(t, err)
+
+		// check fields
+		require.Equal(t, id, id)
+		require.Equal(t, title, event.Title)
+		require.Equal(t, descr, event.Description)
+		require.Equal(t, startAt, event.StartAt)
+		require.Equal(t, endAt, event.EndAt)
+		require.Equal(t, userID, event.UserID)
+		require.Equal(t, uint32(notificationDuration), event.NotificationDuration)
+	})
+
+	t.Run("not found", func(t *testing.T) {
+		ctx := context.Background()
+		repo := d.New(t)
+
+		id := uuid.New()
+
+		event, err := repo.FindEventByID(ctx, id)
+
+		require.Nil(t, event)
+		require.ErrorIs(t, err, calendar.ErrNotFound)
+	})
+}
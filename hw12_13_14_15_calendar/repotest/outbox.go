@@ -0,0 +1,82 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+func testOutbox(t *testing.T, d Driver) {
+	ctx := context.Background()
+	repo := d.New(t)
+
+	owner, guest := uuid.New(), uuid.New()
+	startAt := mustParseDateTime("2022-10-03 10:00:00")
+
+	e, err := repo.CreateEvent(ctx, &calendar.Event{
+		Title:   "meeting",
+		StartAt: startAt,
+		EndAt:   startAt.Add(time.Hour),
+		UserID:  owner,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.InviteAttendee(ctx, e.ID, guest)
+	require.NoError(t, err)
+
+	e, err = repo.FindEventByID(ctx, e.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.ClaimNotifications(ctx, e))
+
+	stored, err := repo.FindEventByID(ctx, e.ID)
+	require.NoError(t, err)
+	require.True(t, stored.IsNotified)
+
+	// повторный захват того же напоминания не создает новых уведомлений
+	require.NoError(t, repo.ClaimNotifications(ctx, e))
+
+	pending, err := repo.FindPendingNotifications(ctx, 10)
+	require.NoError(t, err)
+	require.Len(t, pending, 2)
+	require.Equal(t, owner, pending[0].UserID)
+	require.Equal(t, guest, pending[1].UserID)
+
+	limited, err := repo.FindPendingNotifications(ctx, 1)
+	require.NoError(t, err)
+	require.Equal(t, pending[:1], limited)
+
+	require.NoError(t, repo.MarkNotificationsPublished(ctx, pending[0].ID))
+
+	left, err := repo.FindPendingNotifications(ctx, 10)
+	require.NoError(t, err)
+	require.Equal(t, pending[1:], left)
+
+	delivered, err := repo.IsNotificationDelivered(ctx, pending[0].ID)
+	require.NoError(t, err)
+	require.False(t, delivered)
+
+	require.NoError(t, repo.AckNotification(ctx, pending[0].ID))
+
+	delivered, err = repo.IsNotificationDelivered(ctx, pending[0].ID)
+	require.NoError(t, err)
+	require.True(t, delivered)
+
+	// уведомления удаляются вместе с событием
+	require.NoError(t, repo.DeleteEvent(ctx, e.ID))
+
+	_, err = repo.IsNotificationDelivered(ctx, pending[0].ID)
+	require.ErrorIs(t, err, calendar.ErrNotFound)
+
+	require.ErrorIs(t, repo.AckNotification(ctx, pending[1].ID), calendar.ErrNotFound)
+	require.NoError(t, repo.ClaimNotifications(ctx, e))
+
+	left, err = repo.FindPendingNotifications(ctx, 10)
+	require.NoError(t, err)
+	require.Empty(t, left)
+}
@@ -0,0 +1,98 @@
+// Package repotest содержит тесты контракта хранилища событий.
+// Каждый драйвер БД запускает их из своих тестов через Run,
+// поэтому поведение драйверов проверяется одними и теми же сценариями.
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// Storage хранилище, проверяемое тестами контракта.
+type Storage interface {
+	calendar.Storage
+
+	// MarkEventNotified отметить уведомление о событии (вхождении, начинающемся в startAt) отправленным.
+	MarkEventNotified(ctx context.Context, id uuid.UUID, startAt time.Time) error
+
+	// SetChangePublisher задать получателя изменений событий.
+	SetChangePublisher(p calendar.ChangePublisher)
+}
+
+// Driver описывает проверяемый драйвер.
+type Driver struct {
+	// New создает пустое хранилище.
+	New func(t *testing.T) Storage
+
+	// SetNow подменяет функцию текущего времени драйвера.
+	SetNow func(now func() time.Time)
+}
+
+// Run выполняет тесты контракта хранилища для драйвера d.
+// Тесты выполняются последовательно, так как подменяют текущее время драйвера,
+// а хранилища некоторых драйверов используют общую БД.
+func Run(t *testing.T, d Driver) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, d Driver)
+	}{
+		{name: "CreateEvent", run: testCreateEvent},
+		{name: "CreateEvent_Concurrent", run: testCreateEventConcurrent},
+		{name: "UpdateEvent", run: testUpdateEvent},
+		{name: "PatchEvent", run: testPatchEvent},
+		{name: "DeleteEvent", run: testDeleteEvent},
+		{name: "FindEvents", run: testFindEvents},
+		{name: "FindEvents_Recurring", run: testFindEventsRecurring},
+		{name: "FindEvents_Paging", run: testFindEventsPaging},
+		{name: "MarkEventNotified", run: testMarkEventNotified},
+		{name: "FindEventByID", run: testFindEventByID},
+		{name: "Attendees", run: testAttendees},
+		{name: "RespondAttendee_Busy", run: testRespondAttendeeBusy},
+		{name: "Outbox", run: testOutbox},
+		{name: "FindEventRevisions", run: testFindEventRevisions},
+		{name: "AttendeeRevisions", run: testAttendeeRevisions},
+		{name: "Trash", run: testTrash},
+		{name: "FindTrash", run: testFindTrash},
+		{name: "PublishChange", run: testPublishChange},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, d)
+		})
+	}
+}
+
+// setNow подменяет текущее время драйвера до завершения теста t.
+func (d Driver) setNow(t *testing.T, now func() time.Time) {
+	t.Helper()
+
+	d.SetNow(now)
+
+	t.Cleanup(func() {
+		d.SetNow(time.Now)
+	})
+}
+
+// countEvents возвращает количество сохраненных событий вне корзины.
+func countEvents(t *testing.T, repo Storage) int {
+	t.Helper()
+
+	events, err := repo.FindEvents(context.Background(), calendar.EventFilter{})
+	require.NoError(t, err)
+
+	return len(events)
+}
+
+func mustParseDateTime(str string) time.Time {
+	dt, err := time.Parse("2006-01-02 15:04:05", str)
+	if err != nil {
+		panic(err)
+	}
+	return dt
+}
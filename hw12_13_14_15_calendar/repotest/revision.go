@@ -0,0 +1,127 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/auth"
+)
+
+func testFindEventRevisions(t *testing.T, d Driver) {
+	userID := uuid.New()
+	ctx := auth.WithUserID(context.Background(), userID)
+	repo := d.New(t)
+
+	startAt := mustParseDateTime("2022-10-03 10:00:00")
+
+	created, err := repo.CreateEvent(ctx, &calendar.Event{
+		Title:   "foo",
+		StartAt: startAt,
+		EndAt:   startAt.Add(time.Hour),
+		UserID:  userID,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.UpdateEvent(ctx, created.ID, &calendar.Event{
+		Version: 1,
+		Title:   "bar",
+		StartAt: startAt.Add(time.Hour),
+		EndAt:   startAt.Add(2 * time.Hour),
+		UserID:  userID,
+	})
+	require.NoError(t, err)
+
+	// удаление планировщиком выполняется без пользователя
+	require.NoError(t, repo.DeleteEvent(context.Background(), created.ID))
+	require.NoError(t, repo.PurgeEvent(ctx, created.ID))
+
+	// журнал сохраняется после окончательного удаления
+	revisions, err := repo.FindEventRevisions(ctx, created.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 4)
+
+	require.Equal(t, calendar.RevisionCreated, revisions[0].Action)
+	require.Equal(t, userID, revisions[0].ActorID)
+	require.Equal(t, "foo", revisions[0].State.Title)
+
+	require.Equal(t, calendar.RevisionUpdated, revisions[1].Action)
+	require.Equal(t, calendar.FieldChanges{
+		{Field: "title", Old: "foo", New: "bar"},
+		{Field: "start_at", Old: "2022-10-03T10:00:00Z", New: "2022-10-03T11:00:00Z"},
+		{Field: "end_at", Old: "2022-10-03T11:00:00Z", New: "2022-10-03T12:00:00Z"},
+	}, revisions[1].Changes)
+
+	require.Equal(t, calendar.RevisionDeleted, revisions[2].Action)
+	require.Equal(t, uuid.Nil, revisions[2].ActorID)
+	require.Equal(t, "bar", revisions[2].State.Title)
+
+	require.Equal(t, calendar.RevisionPurged, revisions[3].Action)
+
+	revisions, err = repo.FindEventRevisions(ctx, uuid.New())
+	require.NoError(t, err)
+	require.Empty(t, revisions)
+}
+
+func testAttendeeRevisions(t *testing.T, d Driver) {
+	ownerID, guestID := uuid.New(), uuid.New()
+	ownerCtx := auth.WithUserID(context.Background(), ownerID)
+	guestCtx := auth.WithUserID(context.Background(), guestID)
+	repo := d.New(t)
+
+	startAt := mustParseDateTime("2022-10-03 10:00:00")
+
+	e, err := repo.CreateEvent(ownerCtx, &calendar.Event{
+		Title:   "foo",
+		StartAt: startAt,
+		EndAt:   startAt.Add(time.Hour),
+		UserID:  ownerID,
+	})
+	require.NoError(t, err)
+
+	_, err = repo.InviteAttendee(ownerCtx, e.ID, guestID)
+	require.NoError(t, err)
+
+	// повторное приглашение ничего не меняет и не попадает в журнал
+	_, err = repo.InviteAttendee(ownerCtx, e.ID, guestID)
+	require.NoError(t, err)
+
+	_, err = repo.RespondAttendee(guestCtx, e.ID, guestID, calendar.RSVPAccepted)
+	require.NoError(t, err)
+
+	require.NoError(t, repo.RemoveAttendee(ownerCtx, e.ID, guestID))
+
+	// неудачные изменения не попадают в журнал
+	require.ErrorIs(t, repo.RemoveAttendee(ownerCtx, e.ID, guestID), calendar.ErrNotFound)
+
+	_, err = repo.RespondAttendee(guestCtx, e.ID, guestID, calendar.RSVPDeclined)
+	require.ErrorIs(t, err, calendar.ErrNotFound)
+
+	revisions, err := repo.FindEventRevisions(ownerCtx, e.ID)
+	require.NoError(t, err)
+	require.Len(t, revisions, 4)
+
+	guest := guestID.String()
+
+	require.Equal(t, calendar.RevisionAttendeeInvited, revisions[1].Action)
+	require.Equal(t, ownerID, revisions[1].ActorID)
+	require.Equal(t, calendar.FieldChanges{
+		{Field: "attendees", New: guest + " needs-action"},
+	}, revisions[1].Changes)
+	require.Equal(t, "foo", revisions[1].State.Title)
+
+	require.Equal(t, calendar.RevisionAttendeeResponded, revisions[2].Action)
+	require.Equal(t, guestID, revisions[2].ActorID)
+	require.Equal(t, calendar.FieldChanges{
+		{Field: "attendees", Old: guest + " needs-action", New: guest + " accepted"},
+	}, revisions[2].Changes)
+
+	require.Equal(t, calendar.RevisionAttendeeRemoved, revisions[3].Action)
+	require.Equal(t, calendar.FieldChanges{
+		{Field: "attendees", Old: guest + " accepted"},
+	}, revisions[3].Changes)
+}
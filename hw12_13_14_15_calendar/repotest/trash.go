@@ -0,0 +1,136 @@
+package repotest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+func testTrash(t *testing.T, d Driver) {
+	ctx := context.Background()
+	repo := d.New(t)
+
+	userID := uuid.New()
+	startAt := mustParseDateTime("2022-10-03 10:00:00")
+	newEvent := func() *calendar.Event {
+		return &calendar.Event{StartAt: startAt, EndAt: startAt.Add(time.Hour), UserID: userID}
+	}
+
+	trashed, err := repo.CreateEvent(ctx, newEvent())
+	require.NoError(t, err)
+
+	require.NoError(t, repo.DeleteEvent(ctx, trashed.ID))
+
+	// событие в корзине не находится и не занимает время
+	found, err := repo.FindEvents(ctx, calendar.EventFilter{UserID: userID})
+	require.NoError(t, err)
+	require.Empty(t, found)
+
+	_, err = repo.FindEventByID(ctx, trashed.ID)
+	require.ErrorIs(t, err, calendar.ErrNotFound)
+
+	replacement, err := repo.CreateEvent(ctx, newEvent())
+	require.NoError(t, err)
+
+	// время занято другим событием
+	_, err = repo.RestoreEvent(ctx, trashed.ID)
+	require.ErrorIs(t, err, calendar.ErrDateBusy)
+
+	require.NoError(t, repo.DeleteEvent(ctx, replacement.ID))
+
+	trash, err := repo.FindTrash(ctx, calendar.TrashFilter{UserID: userID})
+	require.NoError(t, err)
+	require.Len(t, trash, 2)
+
+	trash, err = repo.FindTrash(ctx, calendar.TrashFilter{UserID: uuid.New()})
+	require.NoError(t, err)
+	require.Empty(t, trash)
+
+	restored, err := repo.RestoreEvent(ctx, trashed.ID)
+	require.NoError(t, err)
+	require.Equal(t, trashed.ID, restored.ID)
+	require.Nil(t, restored.DeletedAt)
+
+	_, err = repo.RestoreEvent(ctx, trashed.ID)
+	require.ErrorIs(t, err, calendar.ErrNotFound)
+
+	found, err = repo.FindEvents(ctx, calendar.EventFilter{UserID: userID})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	require.Equal(t, trashed.ID, found[0].ID)
+
+	// окончательно удаляются только события из корзины
+	require.NoError(t, repo.PurgeEvent(ctx, trashed.ID, replacement.ID))
+
+	trash, err = repo.FindTrash(ctx, calendar.TrashFilter{})
+	require.NoError(t, err)
+	require.Empty(t, trash)
+
+	_, err = repo.FindEventByID(ctx, trashed.ID)
+	require.NoError(t, err)
+}
+
+func testFindTrash(t *testing.T, d Driver) {
+	ctx := context.Background()
+	repo := d.New(t)
+
+	userID := uuid.New()
+	deletedAt := mustParseDateTime("2022-10-01 10:00:00")
+
+	events := make([]*calendar.Event, 0, 3)
+
+	for i := 0; i < 3; i++ {
+		e, err := repo.CreateEvent(ctx, &calendar.Event{UserID: userID})
+		require.NoError(t, err)
+
+		d.setNow(t, func() time.Time {
+			return deletedAt.AddDate(0, 0, i)
+		})
+
+		require.NoError(t, repo.DeleteEvent(ctx, e.ID))
+
+		events = append(events, e)
+	}
+
+	d.setNow(t, time.Now)
+
+	tests := []struct {
+		name   string
+		filter calendar.TrashFilter
+		want   []uuid.UUID
+	}{
+		{
+			name:   "latest first",
+			filter: calendar.TrashFilter{UserID: userID},
+			want:   []uuid.UUID{events[2].ID, events[1].ID, events[0].ID},
+		},
+		{
+			name:   "by id",
+			filter: calendar.TrashFilter{ID: events[1].ID},
+			want:   []uuid.UUID{events[1].ID},
+		},
+		{
+			name:   "deleted before",
+			filter: calendar.TrashFilter{DeletedBefore: deletedAt.AddDate(0, 0, 2)},
+			want:   []uuid.UUID{events[1].ID, events[0].ID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trash, err := repo.FindTrash(ctx, tt.filter)
+			require.NoError(t, err)
+
+			ids := make([]uuid.UUID, 0, len(trash))
+			for _, e := range trash {
+				ids = append(ids, e.ID)
+			}
+
+			require.Equal(t, tt.want, ids)
+		})
+	}
+}
@@ -0,0 +1,285 @@
+package calendar
+
+import (
+	"database/sql/driver"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+)
+
+// RevisionAction вид изменения события в журнале изменений.
+type RevisionAction string
+
+const (
+	// RevisionCreated событие создано.
+	RevisionCreated RevisionAction = "created"
+
+	// RevisionUpdated событие изменено.
+	RevisionUpdated RevisionAction = "updated"
+
+	// RevisionDeleted событие перемещено в корзину.
+	RevisionDeleted RevisionAction = "deleted"
+
+	// RevisionRestored событие восстановлено из корзины.
+	RevisionRestored RevisionAction = "restored"
+
+	// RevisionPurged событие окончательно удалено из корзины.
+	RevisionPurged RevisionAction = "purged"
+
+	// RevisionAttendeeInvited пользователь приглашен на событие.
+	RevisionAttendeeInvited RevisionAction = "attendee_invited"
+
+	// RevisionAttendeeRemoved участник удален из события.
+	RevisionAttendeeRemoved RevisionAction = "attendee_removed"
+
+	// RevisionAttendeeResponded участник ответил на приглашение.
+	RevisionAttendeeResponded RevisionAction = "attendee_responded"
+)
+
+// Revision запись журнала изменений события. Записи только добавляются
+// и сохраняются даже после окончательного удаления события.
+type Revision struct {
+	// ID идентификатор записи.
+	ID uuid.UUID `db:"id"`
+
+	// EventID идентификатор события.
+	EventID uuid.UUID `db:"event_id"`
+
+	// Action вид изменения.
+	Action RevisionAction `db:"action"`
+
+	// ActorID пользователь, выполнивший изменение (uuid.Nil - система, например планировщик).
+	ActorID uuid.UUID `db:"actor_id"`
+
+	// CreatedAt дата изменения.
+	CreatedAt time.Time `db:"created_at"`
+
+	// Changes измененные поля события.
+	Changes FieldChanges `db:"changes"`
+
+	// State состояние события после изменения (для удаления - перед удалением).
+	State EventState `db:"state"`
+}
+
+// NewRevision формирует запись журнала об изменении события из состояния before в after.
+// Для созданного события before равен nil, для удаленного - after.
+func NewRevision(action RevisionAction, actorID uuid.UUID, before, after *Event) *Revision {
+	r := &Revision{
+		ID:        uuid.New(),
+		Action:    action,
+		ActorID:   actorID,
+		CreatedAt: time.Now().UTC(),
+		Changes:   FieldChanges{},
+	}
+
+	var from EventState
+
+	if before != nil {
+		from = NewEventState(before)
+		r.EventID, r.State = before.ID, from
+	}
+
+	if after != nil {
+		r.EventID, r.State = after.ID, NewEventState(after)
+		r.Changes = from.Diff(r.State)
+	}
+
+	return r
+}
+
+// NewAttendeeRevision формирует запись журнала об изменении участника события e из состояния before в after.
+// Для приглашенного участника before равен nil, для удаленного - after.
+func NewAttendeeRevision(action RevisionAction, actorID uuid.UUID, e *Event, before, after *Attendee) *Revision {
+	return &Revision{
+		ID:        uuid.New(),
+		EventID:   e.ID,
+		Action:    action,
+		ActorID:   actorID,
+		CreatedAt: time.Now().UTC(),
+		Changes: FieldChanges{
+			{Field: "attendees", Old: formatAttendee(before), New: formatAttendee(after)},
+		},
+		State: NewEventState(e),
+	}
+}
+
+// EventState редактируемые пользователем поля события, сохраняемые в журнале изменений.
+type EventState struct {
+	Title                string    `json:"title"`
+	Description          string    `json:"description"`
+	StartAt              time.Time `json:"start_at"`
+	EndAt                time.Time `json:"end_at"`
+	TimeZone             string    `json:"time_zone"`
+	UserID               uuid.UUID `json:"user_id"`
+	NotificationDuration uint32    `json:"notification_duration"`
+	RRule                string    `json:"rrule"`
+	ExDates              ExDates   `json:"exdates"`
+}
+
+// NewEventState возвращает состояние события.
+func NewEventState(e *Event) EventState {
+	return EventState{
+		Title:                e.Title,
+		Description:          e.Description,
+		StartAt:              e.StartAt.UTC(),
+		EndAt:                e.EndAt.UTC(),
+		TimeZone:             e.TimeZone,
+		UserID:               e.UserID,
+		NotificationDuration: e.NotificationDuration,
+		RRule:                e.RRule,
+		ExDates:              append(ExDates(nil), e.ExDates...),
+	}
+}
+
+// Event возвращает событие с идентификатором id в этом состоянии.
+func (s EventState) Event(id uuid.UUID) *Event {
+	return &Event{
+		ID:                   id,
+		Title:                s.Title,
+		Description:          s.Description,
+		StartAt:              s.StartAt,
+		EndAt:                s.EndAt,
+		TimeZone:             s.TimeZone,
+		UserID:               s.UserID,
+		NotificationDuration: s.NotificationDuration,
+		RRule:                s.RRule,
+		ExDates:              append(ExDates(nil), s.ExDates...),
+	}
+}
+
+// Diff возвращает поля, отличающиеся в состоянии to.
+func (s EventState) Diff(to EventState) FieldChanges {
+	res := make(FieldChanges, 0)
+
+	add := func(field, from, to string) {
+		if from != to {
+			res = append(res, FieldChange{Field: field, Old: from, New: to})
+		}
+	}
+
+	add("title", s.Title, to.Title)
+	add("description", s.Description, to.Description)
+	add("start_at", formatTime(s.StartAt), formatTime(to.StartAt))
+	add("end_at", formatTime(s.EndAt), formatTime(to.EndAt))
+	add("time_zone", s.TimeZone, to.TimeZone)
+	add("user_id", formatUserID(s.UserID), formatUserID(to.UserID))
+	add("notification_duration", formatDuration(s.NotificationDuration), formatDuration(to.NotificationDuration))
+	add("rrule", s.RRule, to.RRule)
+	add("exdates", formatExDates(s.ExDates), formatExDates(to.ExDates))
+
+	return res
+}
+
+// Value реализует driver.Valuer.
+func (s EventState) Value() (driver.Value, error) {
+	return valueJSON(s)
+}
+
+// Scan реализует sql.Scanner.
+func (s *EventState) Scan(src interface{}) error {
+	return scanJSON(src, s)
+}
+
+// FieldChange изменение поля события. Значения приводятся к строкам,
+// пустая строка означает отсутствие значения.
+type FieldChange struct {
+	// Field имя поля.
+	Field string `json:"field"`
+
+	// Old значение до изменения.
+	Old string `json:"old,omitempty"`
+
+	// New значение после изменения.
+	New string `json:"new,omitempty"`
+}
+
+// FieldChanges список измененных полей события.
+type FieldChanges []FieldChange
+
+// Value реализует driver.Valuer.
+func (c FieldChanges) Value() (driver.Value, error) {
+	if c == nil {
+		c = FieldChanges{}
+	}
+
+	return valueJSON(c)
+}
+
+// Scan реализует sql.Scanner.
+func (c *FieldChanges) Scan(src interface{}) error {
+	return scanJSON(src, c)
+}
+
+// valueJSON приводит значение к JSON для сохранения в столбец.
+// Значение передается строкой, так как байты драйвер PostgreSQL передает как bytea.
+func valueJSON(v interface{}) (driver.Value, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	return string(b), nil
+}
+
+// scanJSON разбирает значение столбца в формате JSON.
+func scanJSON(src interface{}, dst interface{}) error {
+	var b []byte
+
+	switch v := src.(type) {
+	case string:
+		b = []byte(v)
+	case []byte:
+		b = v
+	default:
+		return fmt.Errorf("cannot scan %T into %T", src, dst)
+	}
+
+	return errors.Wrap(json.Unmarshal(b, dst), "scan json")
+}
+
+// formatTime форматирует дату для журнала изменений.
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// formatUserID форматирует идентификатор пользователя для журнала изменений.
+func formatUserID(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+
+	return id.String()
+}
+
+// formatDuration форматирует длительность напоминания для журнала изменений.
+func formatDuration(d uint32) string {
+	if d == 0 {
+		return ""
+	}
+
+	return strconv.FormatUint(uint64(d), 10)
+}
+
+// formatAttendee форматирует участника события для журнала изменений.
+func formatAttendee(a *Attendee) string {
+	if a == nil {
+		return ""
+	}
+
+	return a.UserID.String() + " " + string(a.Status)
+}
+
+// formatExDates форматирует исключенные даты для журнала изменений.
+func formatExDates(d ExDates) string {
+	v, _ := d.Value()
+
+	return v.(string)
+}
@@ -0,0 +1,119 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewRevision(t *testing.T) {
+	t.Parallel()
+
+	actorID := uuid.New()
+	startAt := time.Date(2022, 10, 3, 10, 0, 0, 0, time.UTC)
+
+	before := &Event{
+		ID:          uuid.New(),
+		Title:       "foo",
+		Description: "bar",
+		StartAt:     startAt,
+		EndAt:       startAt.Add(time.Hour),
+		UserID:      actorID,
+	}
+
+	after := *before
+	after.Title = "baz"
+	after.StartAt = startAt.Add(time.Hour)
+	after.EndAt = startAt.Add(2 * time.Hour)
+
+	t.Run("created", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewRevision(RevisionCreated, actorID, nil, before)
+
+		require.Equal(t, before.ID, r.EventID)
+		require.Equal(t, actorID, r.ActorID)
+		require.Equal(t, FieldChanges{
+			{Field: "title", New: "foo"},
+			{Field: "description", New: "bar"},
+			{Field: "start_at", New: "2022-10-03T10:00:00Z"},
+			{Field: "end_at", New: "2022-10-03T11:00:00Z"},
+			{Field: "user_id", New: actorID.String()},
+		}, r.Changes)
+		require.Equal(t, NewEventState(before), r.State)
+	})
+
+	t.Run("updated", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewRevision(RevisionUpdated, actorID, before, &after)
+
+		require.Equal(t, FieldChanges{
+			{Field: "title", Old: "foo", New: "baz"},
+			{Field: "start_at", Old: "2022-10-03T10:00:00Z", New: "2022-10-03T11:00:00Z"},
+			{Field: "end_at", Old: "2022-10-03T11:00:00Z", New: "2022-10-03T12:00:00Z"},
+		}, r.Changes)
+		require.Equal(t, "baz", r.State.Title)
+	})
+
+	t.Run("deleted by system", func(t *testing.T) {
+		t.Parallel()
+
+		r := NewRevision(RevisionDeleted, uuid.Nil, before, nil)
+
+		require.Equal(t, before.ID, r.EventID)
+		require.Equal(t, uuid.Nil, r.ActorID)
+		require.Empty(t, r.Changes)
+		require.Equal(t, "foo", r.State.Title)
+	})
+}
+
+func TestNewAttendeeRevision(t *testing.T) {
+	t.Parallel()
+
+	actorID := uuid.New()
+	e := &Event{ID: uuid.New(), Title: "foo", UserID: uuid.New()}
+
+	invited := &Attendee{EventID: e.ID, UserID: actorID, Status: RSVPNeedsAction}
+	accepted := &Attendee{EventID: e.ID, UserID: actorID, Status: RSVPAccepted}
+
+	r := NewAttendeeRevision(RevisionAttendeeResponded, actorID, e, invited, accepted)
+
+	require.Equal(t, e.ID, r.EventID)
+	require.Equal(t, RevisionAttendeeResponded, r.Action)
+	require.Equal(t, actorID, r.ActorID)
+	require.Equal(t, FieldChanges{
+		{Field: "attendees", Old: actorID.String() + " needs-action", New: actorID.String() + " accepted"},
+	}, r.Changes)
+	require.Equal(t, NewEventState(e), r.State)
+
+	r = NewAttendeeRevision(RevisionAttendeeRemoved, e.UserID, e, accepted, nil)
+
+	require.Equal(t, FieldChanges{{Field: "attendees", Old: actorID.String() + " accepted"}}, r.Changes)
+}
+
+func TestEventState_Scan(t *testing.T) {
+	t.Parallel()
+
+	state := EventState{
+		Title:   "foo",
+		StartAt: time.Date(2022, 10, 3, 10, 0, 0, 0, time.UTC),
+		EndAt:   time.Date(2022, 10, 3, 11, 0, 0, 0, time.UTC),
+		UserID:  uuid.New(),
+		RRule:   "FREQ=DAILY",
+		ExDates: ExDates{time.Date(2022, 10, 4, 10, 0, 0, 0, time.UTC)},
+	}
+
+	v, err := state.Value()
+	require.NoError(t, err)
+
+	var got EventState
+	require.NoError(t, got.Scan(v))
+	require.Equal(t, state, got)
+
+	e := got.Event(uuid.Nil)
+	require.Equal(t, "FREQ=DAILY", e.RRule)
+	require.Len(t, e.ExDates, 1)
+}
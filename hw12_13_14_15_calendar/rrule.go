@@ -0,0 +1,345 @@
+package calendar
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Frequency частота повторения события.
+type Frequency string
+
+const (
+	// FrequencyDaily ежедневное повторение.
+	FrequencyDaily Frequency = "DAILY"
+
+	// FrequencyWeekly еженедельное повторение.
+	FrequencyWeekly Frequency = "WEEKLY"
+
+	// FrequencyMonthly ежемесячное повторение.
+	FrequencyMonthly Frequency = "MONTHLY"
+)
+
+// maxPeriods ограничивает количество перебираемых периодов при разворачивании правила.
+const maxPeriods = 100000
+
+// rruleDateLayouts допустимые форматы даты в параметре UNTIL.
+var rruleDateLayouts = []string{"20060102T150405Z", "20060102T150405", "20060102"}
+
+// weekdays соответствие обозначений дней недели из RFC 5545.
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// WeekdayNum день недели с необязательным порядковым номером в месяце (например, 1MO или -1FR).
+type WeekdayNum struct {
+	// Weekday день недели.
+	Weekday time.Weekday
+
+	// N порядковый номер дня недели в месяце (0 - каждый).
+	N int
+}
+
+// RRule правило повторения события (RFC 5545).
+type RRule struct {
+	// Freq частота повторения.
+	Freq Frequency
+
+	// Interval интервал между повторениями (в единицах Freq).
+	Interval int
+
+	// ByDay дни недели, по которым повторяется событие.
+	ByDay []WeekdayNum
+
+	// Count максимальное количество повторений.
+	Count int
+
+	// Until дата и время, после которых повторения прекращаются.
+	Until time.Time
+}
+
+// ParseRRule разбирает правило повторения из строки вида FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10.
+func ParseRRule(s string) (RRule, error) {
+	r := RRule{Interval: 1}
+
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return r, errors.Wrap(ErrInvalidRRule, "empty rule")
+	}
+
+	for _, part := range strings.Split(s, ";") {
+		kv := strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return r, errors.Wrapf(ErrInvalidRRule, "invalid part `%s`", part)
+		}
+
+		key, value := strings.ToUpper(kv[0]), strings.ToUpper(kv[1])
+
+		var err error
+
+		switch key {
+		case "FREQ":
+			r.Freq = Frequency(value)
+			if r.Freq != FrequencyDaily && r.Freq != FrequencyWeekly && r.Freq != FrequencyMonthly {
+				return r, errors.Wrapf(ErrInvalidRRule, "unsupported frequency `%s`", value)
+			}
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err != nil || r.Interval < 1 {
+				return r, errors.Wrapf(ErrInvalidRRule, "invalid interval `%s`", value)
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err != nil || r.Count < 1 {
+				return r, errors.Wrapf(ErrInvalidRRule, "invalid count `%s`", value)
+			}
+		case "UNTIL":
+			r.Until, err = parseRRuleDate(value)
+			if err != nil {
+				return r, err
+			}
+		case "BYDAY":
+			r.ByDay, err = parseByDay(value)
+			if err != nil {
+				return r, err
+			}
+		case "WKST":
+		default:
+			return r, errors.Wrapf(ErrInvalidRRule, "unsupported part `%s`", key)
+		}
+	}
+
+	if r.Freq == "" {
+		return r, errors.Wrap(ErrInvalidRRule, "frequency is required")
+	}
+
+	if r.Count > 0 && !r.Until.IsZero() {
+		return r, errors.Wrap(ErrInvalidRRule, "count and until are mutually exclusive")
+	}
+
+	for _, wd := range r.ByDay {
+		if wd.N != 0 && r.Freq != FrequencyMonthly {
+			return r, errors.Wrap(ErrInvalidRRule, "numeric byday is allowed only for monthly frequency")
+		}
+	}
+
+	return r, nil
+}
+
+// String возвращает правило в формате RFC 5545.
+func (r RRule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+
+	if len(r.ByDay) > 0 {
+		days := make([]string, 0, len(r.ByDay))
+
+		for _, wd := range r.ByDay {
+			day := strings.ToUpper(wd.Weekday.String()[:2])
+			if wd.N != 0 {
+				day = strconv.Itoa(wd.N) + day
+			}
+
+			days = append(days, day)
+		}
+
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format(rruleDateLayouts[0]))
+	}
+
+	return strings.Join(parts, ";")
+}
+
+// each вызывает fn для каждого начала вхождения не позднее limit.
+// Первым вхождением всегда считается dtstart.
+func (r RRule) each(dtstart, limit time.Time, fn func(start time.Time)) {
+	if !r.Until.IsZero() && r.Until.Before(limit) {
+		limit = r.Until
+	}
+
+	count := 0
+	emit := func(start time.Time) bool {
+		if start.After(limit) {
+			return false
+		}
+
+		count++
+		fn(start)
+
+		return r.Count == 0 || count < r.Count
+	}
+
+	if !emit(dtstart) {
+		return
+	}
+
+	for n := 0; n < maxPeriods; n++ {
+		periodStart, candidates := r.period(dtstart, n)
+		if periodStart.After(limit) {
+			return
+		}
+
+		for _, c := range candidates {
+			if !c.After(dtstart) {
+				continue
+			}
+
+			if !emit(c) {
+				return
+			}
+		}
+	}
+}
+
+// period возвращает начало периода с указанным номером и вхождения в нем в порядке возрастания.
+func (r RRule) period(dtstart time.Time, n int) (time.Time, []time.Time) {
+	step := n * r.Interval
+
+	switch r.Freq {
+	case FrequencyDaily:
+		start := dtstart.AddDate(0, 0, step)
+
+		return start, []time.Time{start}
+	case FrequencyWeekly:
+		weekStart := dtstart.AddDate(0, 0, -mondayOffset(dtstart.Weekday())+7*step)
+		if len(r.ByDay) == 0 {
+			return weekStart, []time.Time{dtstart.AddDate(0, 0, 7*step)}
+		}
+
+		res := make([]time.Time, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			res = append(res, weekStart.AddDate(0, 0, mondayOffset(wd.Weekday)))
+		}
+
+		return weekStart, sortUnique(res)
+	default:
+		y, m, _ := dtstart.Date()
+		h, min, sec := dtstart.Clock()
+		monthStart := time.Date(y, m+time.Month(step), 1, h, min, sec, dtstart.Nanosecond(), dtstart.Location())
+
+		if len(r.ByDay) == 0 {
+			day := dtstart.Day()
+			if day > daysIn(monthStart) {
+				return monthStart, nil
+			}
+
+			return monthStart, []time.Time{monthStart.AddDate(0, 0, day-1)}
+		}
+
+		res := make([]time.Time, 0, len(r.ByDay))
+		for _, wd := range r.ByDay {
+			res = append(res, weekdaysInMonth(monthStart, wd)...)
+		}
+
+		return monthStart, sortUnique(res)
+	}
+}
+
+// parseRRuleDate разбирает дату из правила повторения.
+func parseRRuleDate(value string) (time.Time, error) {
+	for _, layout := range rruleDateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, nil
+		}
+	}
+
+	return time.Time{}, errors.Wrapf(ErrInvalidRRule, "invalid until `%s`", value)
+}
+
+// parseByDay разбирает список дней недели.
+func parseByDay(value string) ([]WeekdayNum, error) {
+	res := make([]WeekdayNum, 0)
+
+	for _, day := range strings.Split(value, ",") {
+		if len(day) < 2 {
+			return nil, errors.Wrapf(ErrInvalidRRule, "invalid byday `%s`", day)
+		}
+
+		wd, ok := weekdays[day[len(day)-2:]]
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalidRRule, "invalid byday `%s`", day)
+		}
+
+		n := 0
+		if num := day[:len(day)-2]; num != "" {
+			var err error
+
+			n, err = strconv.Atoi(num)
+			if err != nil || n == 0 || n > 5 || n < -5 {
+				return nil, errors.Wrapf(ErrInvalidRRule, "invalid byday `%s`", day)
+			}
+		}
+
+		res = append(res, WeekdayNum{Weekday: wd, N: n})
+	}
+
+	return res, nil
+}
+
+// weekdaysInMonth возвращает подходящие дни недели месяца, начинающегося с monthStart.
+func weekdaysInMonth(monthStart time.Time, wd WeekdayNum) []time.Time {
+	first := monthStart.AddDate(0, 0, (int(wd.Weekday)-int(monthStart.Weekday())+7)%7)
+
+	all := make([]time.Time, 0, 5)
+	for d := first; d.Month() == monthStart.Month(); d = d.AddDate(0, 0, 7) {
+		all = append(all, d)
+	}
+
+	switch {
+	case wd.N == 0:
+		return all
+	case wd.N > 0 && wd.N <= len(all):
+		return all[wd.N-1 : wd.N]
+	case wd.N < 0 && -wd.N <= len(all):
+		return all[len(all)+wd.N : len(all)+wd.N+1]
+	default:
+		return nil
+	}
+}
+
+// mondayOffset возвращает номер дня недели, начиная с понедельника.
+func mondayOffset(wd time.Weekday) int {
+	return (int(wd) + 6) % 7
+}
+
+// daysIn возвращает количество дней в месяце.
+func daysIn(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// sortUnique сортирует даты и удаляет дубликаты.
+func sortUnique(ts []time.Time) []time.Time {
+	sort.Slice(ts, func(i, j int) bool {
+		return ts[i].Before(ts[j])
+	})
+
+	res := make([]time.Time, 0, len(ts))
+	for _, t := range ts {
+		if len(res) > 0 && t.Equal(res[len(res)-1]) {
+			continue
+		}
+
+		res = append(res, t)
+	}
+
+	return res
+}
@@ -0,0 +1,248 @@
+package calendar
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseRRule(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		rule    string
+		want    RRule
+		wantErr bool
+	}{
+		{
+			name: "weekly byday count",
+			rule: "FREQ=WEEKLY;BYDAY=MO,WE;COUNT=10",
+			want: RRule{
+				Freq:     FrequencyWeekly,
+				Interval: 1,
+				ByDay:    []WeekdayNum{{Weekday: time.Monday}, {Weekday: time.Wednesday}},
+				Count:    10,
+			},
+		},
+		{
+			name: "monthly last friday until",
+			rule: "RRULE:FREQ=MONTHLY;INTERVAL=2;BYDAY=-1FR;UNTIL=20221231T000000Z",
+			want: RRule{
+				Freq:     FrequencyMonthly,
+				Interval: 2,
+				ByDay:    []WeekdayNum{{Weekday: time.Friday, N: -1}},
+				Until:    mustParseDateTime("2022-12-31 00:00:00"),
+			},
+		},
+		{name: "empty", rule: "", wantErr: true},
+		{name: "no freq", rule: "COUNT=3", wantErr: true},
+		{name: "unsupported freq", rule: "FREQ=YEARLY", wantErr: true},
+		{name: "invalid count", rule: "FREQ=DAILY;COUNT=0", wantErr: true},
+		{name: "invalid byday", rule: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{name: "numeric byday for weekly", rule: "FREQ=WEEKLY;BYDAY=1MO", wantErr: true},
+		{name: "count and until", rule: "FREQ=DAILY;COUNT=2;UNTIL=20221231", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRRule(tt.rule)
+
+			if tt.wantErr {
+				require.ErrorIs(t, err, ErrInvalidRRule)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEvent_Occurrences(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		event   Event
+		from    time.Time
+		to      time.Time
+		wantAts []string
+	}{
+		{
+			name: "daily count",
+			event: Event{
+				StartAt: mustParseDateTime("2022-10-03 10:00:00"),
+				EndAt:   mustParseDateTime("2022-10-03 10:30:00"),
+				RRule:   "FREQ=DAILY;COUNT=3",
+			},
+			from: mustParseDateTime("2022-10-01 00:00:00"),
+			to:   mustParseDateTime("2022-11-01 00:00:00"),
+			wantAts: []string{
+				"2022-10-03 10:00:00",
+				"2022-10-04 10:00:00",
+				"2022-10-05 10:00:00",
+			},
+		},
+		{
+			name: "weekly byday with exdate",
+			event: Event{
+				StartAt: mustParseDateTime("2022-10-03 10:00:00"), // понедельник
+				EndAt:   mustParseDateTime("2022-10-03 10:15:00"),
+				RRule:   "FREQ=WEEKLY;BYDAY=MO,WE",
+				ExDates: ExDates{mustParseDateTime("2022-10-05 10:00:00")},
+			},
+			from: mustParseDateTime("2022-10-01 00:00:00"),
+			to:   mustParseDateTime("2022-10-14 00:00:00"),
+			wantAts: []string{
+				"2022-10-03 10:00:00",
+				"2022-10-10 10:00:00",
+				"2022-10-12 10:00:00",
+			},
+		},
+		{
+			name: "monthly last friday",
+			event: Event{
+				StartAt: mustParseDateTime("2022-09-30 18:00:00"),
+				EndAt:   mustParseDateTime("2022-09-30 19:00:00"),
+				RRule:   "FREQ=MONTHLY;BYDAY=-1FR",
+			},
+			from: mustParseDateTime("2022-10-01 00:00:00"),
+			to:   mustParseDateTime("2022-12-31 00:00:00"),
+			wantAts: []string{
+				"2022-10-28 18:00:00",
+				"2022-11-25 18:00:00",
+				"2022-12-30 18:00:00",
+			},
+		},
+		{
+			name: "monthly by day of month skips short months",
+			event: Event{
+				StartAt: mustParseDateTime("2022-01-31 09:00:00"),
+				EndAt:   mustParseDateTime("2022-01-31 10:00:00"),
+				RRule:   "FREQ=MONTHLY;UNTIL=20220501T000000Z",
+			},
+			from: mustParseDateTime("2022-01-01 00:00:00"),
+			to:   mustParseDateTime("2022-12-31 00:00:00"),
+			wantAts: []string{
+				"2022-01-31 09:00:00",
+				"2022-03-31 09:00:00",
+			},
+		},
+		{
+			name: "daily keeps local time across dst",
+			event: Event{
+				StartAt:  mustParseDateTime("2022-10-28 08:00:00"), // 10:00 по Берлину (UTC+2)
+				EndAt:    mustParseDateTime("2022-10-28 09:00:00"),
+				TimeZone: "Europe/Berlin",
+				RRule:    "FREQ=DAILY;COUNT=4",
+			},
+			from: mustParseDateTime("2022-10-01 00:00:00"),
+			to:   mustParseDateTime("2022-11-30 00:00:00"),
+			wantAts: []string{
+				"2022-10-28 08:00:00",
+				"2022-10-29 08:00:00",
+				"2022-10-30 09:00:00", // 10:00 по Берлину (UTC+1)
+				"2022-10-31 09:00:00",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			occurrences, err := tt.event.Occurrences(tt.from, tt.to)
+			require.NoError(t, err)
+
+			got := make([]string, 0, len(occurrences))
+			for _, o := range occurrences {
+				require.Equal(t, tt.event.EndAt.Sub(tt.event.StartAt), o.EndAt.Sub(o.StartAt))
+				got = append(got, o.StartAt.UTC().Format("2006-01-02 15:04:05"))
+			}
+
+			require.Equal(t, tt.wantAts, got)
+		})
+	}
+}
+
+func TestEvent_Validate_TimeZone(t *testing.T) {
+	t.Parallel()
+
+	require.NoError(t, (&Event{TimeZone: "Europe/Moscow"}).Validate())
+	require.NoError(t, (&Event{}).Validate())
+	require.ErrorIs(t, (&Event{TimeZone: "Mars/Olympus"}).Validate(), ErrInvalidTimeZone)
+	require.ErrorIs(t, (&Event{TimeZone: "Local"}).Validate(), ErrInvalidTimeZone)
+}
+
+func TestOverlaps(t *testing.T) {
+	t.Parallel()
+
+	standup := &Event{
+		StartAt: mustParseDateTime("2022-10-03 10:00:00"), // понедельник
+		EndAt:   mustParseDateTime("2022-10-03 10:15:00"),
+		RRule:   "FREQ=WEEKLY;BYDAY=MO,WE",
+	}
+
+	tests := []struct {
+		name  string
+		event *Event
+		want  bool
+	}{
+		{
+			name: "single event hits occurrence",
+			event: &Event{
+				StartAt: mustParseDateTime("2022-11-16 10:10:00"),
+				EndAt:   mustParseDateTime("2022-11-16 11:00:00"),
+			},
+			want: true,
+		},
+		{
+			name: "single event between occurrences",
+			event: &Event{
+				StartAt: mustParseDateTime("2022-11-15 10:00:00"),
+				EndAt:   mustParseDateTime("2022-11-15 11:00:00"),
+			},
+			want: false,
+		},
+		{
+			name: "single event contains occurrence",
+			event: &Event{
+				StartAt: mustParseDateTime("2022-11-14 09:00:00"),
+				EndAt:   mustParseDateTime("2022-11-14 12:00:00"),
+			},
+			want: true,
+		},
+		{
+			name: "recurring events on different days",
+			event: &Event{
+				StartAt: mustParseDateTime("2022-10-04 10:00:00"),
+				EndAt:   mustParseDateTime("2022-10-04 11:00:00"),
+				RRule:   "FREQ=WEEKLY;BYDAY=TU,TH",
+			},
+			want: false,
+		},
+		{
+			name: "recurring events meet later",
+			event: &Event{
+				StartAt: mustParseDateTime("2022-10-04 10:00:00"),
+				EndAt:   mustParseDateTime("2022-10-04 11:00:00"),
+				RRule:   "FREQ=DAILY;INTERVAL=8",
+			},
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Overlaps(standup, tt.event)
+
+			require.NoError(t, err)
+			require.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func mustParseDateTime(str string) time.Time {
+	dt, err := time.Parse("2006-01-02 15:04:05", str)
+	if err != nil {
+		panic(err)
+	}
+	return dt
+}
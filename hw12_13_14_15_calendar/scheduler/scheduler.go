@@ -5,20 +5,32 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/metrics"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/tracing"
 )
 
 type Broker interface {
-	SendEventToQueue(ctx context.Context, events ...*calendar.Event) error
+	SendNotificationToQueue(ctx context.Context, notifications ...*calendar.Notification) error
 }
 
 type Repository interface {
 	DeleteEvent(ctx context.Context, ids ...uuid.UUID) error
+	FindTrash(ctx context.Context, filter calendar.TrashFilter) ([]*calendar.Event, error)
+	PurgeEvent(ctx context.Context, ids ...uuid.UUID) error
 	FindEvents(ctx context.Context, filter calendar.EventFilter) ([]*calendar.Event, error)
+	ClaimNotifications(ctx context.Context, events ...*calendar.Event) error
+	FindPendingNotifications(ctx context.Context, limit int) ([]*calendar.Notification, error)
+	MarkNotificationsPublished(ctx context.Context, ids ...uuid.UUID) error
 }
 
+// relayBatchSize количество уведомлений, публикуемых в очередь за раз.
+const relayBatchSize = 100
+
 type Scheduler struct {
 	r   Repository
 	b   Broker
@@ -28,6 +40,9 @@ type Scheduler struct {
 type Config struct {
 	Interval        time.Duration
 	EventLifeInDays uint
+
+	// TrashRetention срок хранения событий в корзине. Нулевое значение отключает очистку корзины.
+	TrashRetention time.Duration
 }
 
 func New(r Repository, b Broker, cfg Config) Scheduler {
@@ -48,10 +63,20 @@ func (s Scheduler) Start(ctx context.Context) error {
 	senderCh := make(chan struct{}, 1)
 	deleteCh := make(chan struct{}, 1)
 
+	errGrp, ctx := errgroup.WithContext(ctx)
+
 	go func() {
+		// если предыдущий тик еще не обработан, то новый с ним объединяется
 		tick := func() {
-			senderCh <- struct{}{}
-			deleteCh <- struct{}{}
+			select {
+			case senderCh <- struct{}{}:
+			default:
+			}
+
+			select {
+			case deleteCh <- struct{}{}:
+			default:
+			}
 		}
 
 		// init tick
@@ -69,8 +94,6 @@ func (s Scheduler) Start(ctx context.Context) error {
 		}
 	}()
 
-	errGrp, ctx := errgroup.WithContext(ctx)
-
 	// Отправка сообщений в Sender.
 	errGrp.Go(func() error {
 		defer cancel()
@@ -82,25 +105,13 @@ func (s Scheduler) Start(ctx context.Context) error {
 			case <-senderCh:
 			}
 
-			events, err := s.r.FindEvents(ctx, calendar.EventFilter{
-				NotNotified: true,
-				NotifyTime:  true,
-			})
-			if err != nil {
-				return err
-			}
-
-			if len(events) == 0 {
-				return nil
-			}
-
-			if err := s.b.SendEventToQueue(ctx, events...); err != nil {
+			if err := s.enqueue(ctx); err != nil {
 				return err
 			}
 		}
 	})
 
-	// Удаление старых событий.
+	// Перемещение старых событий в корзину и очистка корзины.
 	errGrp.Go(func() error {
 		defer cancel()
 
@@ -118,17 +129,107 @@ func (s Scheduler) Start(ctx context.Context) error {
 				return err
 			}
 
-			if len(events) == 0 {
-				return nil
-			}
-
 			for _, e := range events {
 				if err := s.r.DeleteEvent(ctx, e.ID); err != nil {
 					return err
 				}
 			}
+
+			if err := s.purgeTrash(ctx); err != nil {
+				return err
+			}
 		}
 	})
 
 	return errGrp.Wait()
 }
+
+// purgeTrash окончательно удаляет события, пролежавшие в корзине дольше срока хранения.
+func (s Scheduler) purgeTrash(ctx context.Context) error {
+	if s.cfg.TrashRetention <= 0 {
+		return nil
+	}
+
+	events, err := s.r.FindTrash(ctx, calendar.TrashFilter{
+		DeletedBefore: time.Now().Add(-s.cfg.TrashRetention),
+	})
+	if err != nil {
+		return err
+	}
+
+	if len(events) == 0 {
+		return nil
+	}
+
+	ids := make([]uuid.UUID, 0, len(events))
+	for _, e := range events {
+		ids = append(ids, e.ID)
+	}
+
+	return s.r.PurgeEvent(ctx, ids...)
+}
+
+// enqueue ставит в очередь уведомления о наступающих событиях.
+// Контекст трассировки тика передается с уведомлениями в очередь.
+func (s Scheduler) enqueue(ctx context.Context) (err error) {
+	ctx, span := tracing.Start(ctx, "Scheduler.enqueue", trace.WithSpanKind(trace.SpanKindProducer))
+	defer func() { tracing.End(span, err) }()
+
+	defer func(start time.Time) {
+		metrics.SchedulerTickDuration.Observe(time.Since(start).Seconds())
+	}(time.Now())
+
+	events, err := s.r.FindEvents(ctx, calendar.EventFilter{
+		NotNotified: true,
+		NotifyTime:  true,
+	})
+	if err != nil {
+		return err
+	}
+
+	// захваченные события отмечаются уведомленными и на следующем тике не выбираются
+	if err := s.r.ClaimNotifications(ctx, events...); err != nil {
+		return err
+	}
+
+	metrics.SchedulerEventsEnqueued.Add(float64(len(events)))
+	span.SetAttributes(attribute.Int("event.count", len(events)))
+
+	// публикуются и захваченные сейчас, и не опубликованные ранее уведомления
+	return s.relay(ctx)
+}
+
+// relay публикует сохраненные в исходящем ящике уведомления в очередь.
+// Уведомление отмечается опубликованным только после успешной отправки,
+// поэтому при сбое оно будет отправлено повторно, а рассыльщик отбросит дубль.
+func (s Scheduler) relay(ctx context.Context) error {
+	for {
+		notifications, err := s.r.FindPendingNotifications(ctx, relayBatchSize)
+		if err != nil {
+			return err
+		}
+
+		if len(notifications) == 0 {
+			return nil
+		}
+
+		if err := s.b.SendNotificationToQueue(ctx, notifications...); err != nil {
+			return err
+		}
+
+		ids := make([]uuid.UUID, 0, len(notifications))
+		for _, n := range notifications {
+			ids = append(ids, n.ID)
+		}
+
+		if err := s.r.MarkNotificationsPublished(ctx, ids...); err != nil {
+			return err
+		}
+
+		metrics.SchedulerNotificationsPublished.Add(float64(len(notifications)))
+
+		if len(notifications) < relayBatchSize {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package scheduler
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/metrics"
+	mocks "github.com/RomanSarvarov/otus_go_home_work/calendar/mocks/scheduler"
+)
+
+// notifyFilter отбирает фильтр выборки событий для уведомления.
+var notifyFilter = mock.MatchedBy(func(filter calendar.EventFilter) bool {
+	return filter.NotNotified
+})
+
+// deleteFilter отбирает фильтр выборки старых событий.
+var deleteFilter = mock.MatchedBy(func(filter calendar.EventFilter) bool {
+	return !filter.NotNotified
+})
+
+func TestScheduler_Start(t *testing.T) {
+	t.Run("claims and relays notifications", func(t *testing.T) {
+		r := mocks.NewRepository(t)
+		b := mocks.NewBroker(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		events := []*calendar.Event{{ID: uuid.New(), Title: "foo"}}
+		notifications := []*calendar.Notification{{ID: uuid.New()}, {ID: uuid.New()}}
+
+		r.On("FindEvents", mock.Anything, notifyFilter).Return(events, nil).Once()
+		r.On("ClaimNotifications", mock.Anything, events[0]).Return(nil).Once()
+		r.On("FindPendingNotifications", mock.Anything, relayBatchSize).Return(notifications, nil).Once()
+		b.On("SendNotificationToQueue", mock.Anything, notifications[0], notifications[1]).Return(nil).Once()
+		r.On("MarkNotificationsPublished", mock.Anything, notifications[0].ID, notifications[1].ID).
+			Run(func(mock.Arguments) { cancel() }).
+			Return(nil).Once()
+		r.On("FindEvents", mock.Anything, deleteFilter).Return(nil, nil).Maybe()
+		r.On("FindTrash", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+
+		enqueued := testutil.ToFloat64(metrics.SchedulerEventsEnqueued)
+		published := testutil.ToFloat64(metrics.SchedulerNotificationsPublished)
+
+		s := New(r, b, Config{Interval: time.Hour})
+		err := s.Start(ctx)
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, enqueued+1, testutil.ToFloat64(metrics.SchedulerEventsEnqueued))
+		require.Equal(t, published+2, testutil.ToFloat64(metrics.SchedulerNotificationsPublished))
+	})
+
+	t.Run("keeps running without due events", func(t *testing.T) {
+		r := mocks.NewRepository(t)
+		b := mocks.NewBroker(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		ticks := 0
+
+		r.On("FindEvents", mock.Anything, notifyFilter).Return(nil, nil)
+		r.On("ClaimNotifications", mock.Anything).Return(nil)
+		r.On("FindPendingNotifications", mock.Anything, relayBatchSize).
+			Run(func(mock.Arguments) {
+				ticks++
+				if ticks == 3 {
+					cancel()
+				}
+			}).
+			Return(nil, nil)
+		r.On("FindEvents", mock.Anything, deleteFilter).Return(nil, nil).Maybe()
+		r.On("FindTrash", mock.Anything, mock.Anything).Return(nil, nil).Maybe()
+
+		s := New(r, b, Config{Interval: time.Millisecond})
+		err := s.Start(ctx)
+
+		require.ErrorIs(t, err, context.Canceled)
+		require.Equal(t, 3, ticks)
+	})
+
+	t.Run("moves old events to trash and purges expired trash", func(t *testing.T) {
+		r := mocks.NewRepository(t)
+		b := mocks.NewBroker(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		old := &calendar.Event{ID: uuid.New()}
+		trashed := []*calendar.Event{{ID: uuid.New()}, {ID: uuid.New()}}
+		retention := 24 * time.Hour
+		before := time.Now().Add(-retention)
+
+		r.On("FindEvents", mock.Anything, notifyFilter).Return(nil, nil).Maybe()
+		r.On("ClaimNotifications", mock.Anything).Return(nil).Maybe()
+		r.On("FindPendingNotifications", mock.Anything, relayBatchSize).Return(nil, nil).Maybe()
+		r.On("FindEvents", mock.Anything, deleteFilter).Return([]*calendar.Event{old}, nil).Once()
+		r.On("DeleteEvent", mock.Anything, old.ID).Return(nil).Once()
+		r.On("FindTrash", mock.Anything, mock.MatchedBy(func(filter calendar.TrashFilter) bool {
+			d := filter.DeletedBefore.Sub(before)
+
+			return d >= 0 && d < time.Minute
+		})).Return(trashed, nil).Once()
+		r.On("PurgeEvent", mock.Anything, trashed[0].ID, trashed[1].ID).
+			Run(func(mock.Arguments) { cancel() }).
+			Return(nil).Once()
+
+		s := New(r, b, Config{Interval: time.Hour, TrashRetention: retention})
+		err := s.Start(ctx)
+
+		require.ErrorIs(t, err, context.Canceled)
+	})
+
+	t.Run("keeps trash without retention", func(t *testing.T) {
+		r := mocks.NewRepository(t)
+		b := mocks.NewBroker(t)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		r.On("FindEvents", mock.Anything, notifyFilter).Return(nil, nil).Maybe()
+		r.On("ClaimNotifications", mock.Anything).Return(nil).Maybe()
+		r.On("FindPendingNotifications", mock.Anything, relayBatchSize).Return(nil, nil).Maybe()
+		r.On("FindEvents", mock.Anything, deleteFilter).
+			Run(func(mock.Arguments) { cancel() }).
+			Return(nil, nil).Once()
+
+		s := New(r, b, Config{Interval: time.Hour})
+		err := s.Start(ctx)
+
+		require.ErrorIs(t, err, context.Canceled)
+		r.AssertNotCalled(t, "FindTrash", mock.Anything, mock.Anything)
+	})
+}
@@ -0,0 +1,119 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// FileNotifier записывает уведомления в файл.
+// Когда размер файла превышает MaxSize, файл переименовывается в <path>.1,
+// ранее сохраненные копии сдвигаются (<path>.1 в <path>.2 и т.д.),
+// а копии сверх MaxBackups удаляются.
+type FileNotifier struct {
+	mu sync.Mutex
+
+	path       string
+	maxSize    int64
+	maxBackups int
+
+	f    *os.File
+	size int64
+}
+
+// NewFileNotifier открывает файл для записи уведомлений.
+// maxSize задает размер файла в байтах, после которого выполняется ротация (0 - без ротации).
+func NewFileNotifier(path string, maxSize int64, maxBackups int) (*FileNotifier, error) {
+	fn := &FileNotifier{
+		path:       path,
+		maxSize:    maxSize,
+		maxBackups: maxBackups,
+	}
+
+	if err := fn.open(); err != nil {
+		return nil, err
+	}
+
+	return fn, nil
+}
+
+// Notify записывает уведомление в файл.
+func (fn *FileNotifier) Notify(ctx context.Context, n *calendar.Notification) error {
+	fn.mu.Lock()
+	defer fn.mu.Unlock()
+
+	text := notificationText(n)
+
+	if fn.maxSize > 0 && fn.size > 0 && fn.size+int64(len(text)) > fn.maxSize {
+		if err := fn.rotate(); err != nil {
+			return err
+		}
+	}
+
+	written, err := fn.f.WriteString(text)
+	fn.size += int64(written)
+
+	return errors.Wrap(err, "write notification")
+}
+
+// Close закрывает файл.
+func (fn *FileNotifier) Close() error {
+	fn.mu.Lock()
+	defer fn.mu.Unlock()
+
+	return fn.f.Close()
+}
+
+// open открывает файл на дозапись.
+func (fn *FileNotifier) open() error {
+	f, err := os.OpenFile(fn.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return errors.Wrap(err, "open notification file")
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+
+		return errors.Wrap(err, "open notification file")
+	}
+
+	fn.f = f
+	fn.size = info.Size()
+
+	return nil
+}
+
+// rotate сохраняет текущий файл копией и открывает новый.
+func (fn *FileNotifier) rotate() error {
+	if err := fn.f.Close(); err != nil {
+		return errors.Wrap(err, "rotate notification file")
+	}
+
+	if fn.maxBackups > 0 {
+		for i := fn.maxBackups - 1; i > 0; i-- {
+			err := os.Rename(backupPath(fn.path, i), backupPath(fn.path, i+1))
+			if err != nil && !os.IsNotExist(err) {
+				return errors.Wrap(err, "rotate notification file")
+			}
+		}
+
+		if err := os.Rename(fn.path, backupPath(fn.path, 1)); err != nil {
+			return errors.Wrap(err, "rotate notification file")
+		}
+	} else if err := os.Remove(fn.path); err != nil {
+		return errors.Wrap(err, "rotate notification file")
+	}
+
+	return fn.open()
+}
+
+// backupPath возвращает путь i-й копии файла.
+func backupPath(path string, i int) string {
+	return fmt.Sprintf("%s.%d", path, i)
+}
@@ -0,0 +1,53 @@
+package sender
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFileNotifier_Notify(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "notifications.log")
+
+	text := notificationText(testNotification)
+
+	// в файл помещается ровно два уведомления
+	fn, err := NewFileNotifier(path, int64(2*len(text)), 2)
+	require.NoError(t, err)
+
+	for i := 0; i < 7; i++ {
+		require.NoError(t, fn.Notify(ctx, testNotification))
+	}
+
+	require.NoError(t, fn.Close())
+
+	for file, want := range map[string]int{
+		path:        1,
+		path + ".1": 2,
+		path + ".2": 2,
+	} {
+		bs, err := os.ReadFile(file)
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat(text, want), string(bs), file)
+	}
+
+	_, err = os.Stat(path + ".3")
+	require.True(t, os.IsNotExist(err))
+
+	t.Run("appends to existing file", func(t *testing.T) {
+		fn, err := NewFileNotifier(path, 0, 0)
+		require.NoError(t, err)
+
+		require.NoError(t, fn.Notify(ctx, testNotification))
+		require.NoError(t, fn.Close())
+
+		bs, err := os.ReadFile(path)
+		require.NoError(t, err)
+		require.Equal(t, strings.Repeat(text, 2), string(bs))
+	})
+}
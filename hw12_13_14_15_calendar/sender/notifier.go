@@ -0,0 +1,203 @@
+package sender
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+const (
+	// NotifierStdout ключ канала вывода уведомлений в stdout.
+	NotifierStdout = "stdout"
+
+	// NotifierFile ключ канала записи уведомлений в файл с ротацией.
+	NotifierFile = "file"
+
+	// NotifierWebhook ключ канала отправки уведомлений HTTP запросом.
+	NotifierWebhook = "webhook"
+)
+
+// Notifier декларирует контракт канала доставки уведомлений.
+type Notifier interface {
+	// Notify доставить уведомление.
+	Notify(ctx context.Context, n *calendar.Notification) error
+}
+
+// NotifierConfig настройки каналов доставки уведомлений.
+type NotifierConfig struct {
+	// Notifiers ключи каналов доставки.
+	Notifiers []string
+
+	FilePath       string
+	FileMaxSize    int64
+	FileMaxBackups int
+
+	WebhookURL         string
+	WebhookSecret      string
+	WebhookTimeout     time.Duration
+	WebhookMaxAttempts int
+	WebhookBaseDelay   time.Duration
+	WebhookMaxDelay    time.Duration
+}
+
+// Channel канал доставки уведомлений.
+type Channel struct {
+	// Name ключ канала.
+	Name string
+
+	Notifier
+}
+
+// MultiNotifier доставляет уведомление во все каналы.
+type MultiNotifier []Channel
+
+// NewNotifier создает каналы доставки уведомлений, перечисленные в конфиге.
+func NewNotifier(cfg NotifierConfig) (MultiNotifier, error) {
+	notifiers := make(MultiNotifier, 0, len(cfg.Notifiers))
+
+	for _, key := range cfg.Notifiers {
+		key = strings.TrimSpace(key)
+
+		switch key {
+		case NotifierStdout:
+			notifiers = append(notifiers, Channel{Name: key, Notifier: NewWriterNotifier(os.Stdout)})
+		case NotifierFile:
+			fn, err := NewFileNotifier(cfg.FilePath, cfg.FileMaxSize, cfg.FileMaxBackups)
+			if err != nil {
+				_ = notifiers.Close()
+
+				return nil, err
+			}
+
+			notifiers = append(notifiers, Channel{Name: key, Notifier: fn})
+		case NotifierWebhook:
+			if cfg.WebhookURL == "" || cfg.WebhookSecret == "" {
+				_ = notifiers.Close()
+
+				return nil, errors.New("webhook url and secret are required for webhook notifier")
+			}
+
+			notifiers = append(notifiers, Channel{Name: key, Notifier: NewWebhookNotifier(WebhookConfig{
+				URL:         cfg.WebhookURL,
+				Secret:      cfg.WebhookSecret,
+				Timeout:     cfg.WebhookTimeout,
+				MaxAttempts: cfg.WebhookMaxAttempts,
+				BaseDelay:   cfg.WebhookBaseDelay,
+				MaxDelay:    cfg.WebhookMaxDelay,
+			})})
+		default:
+			_ = notifiers.Close()
+
+			return nil, fmt.Errorf("notifier `%s` not found", key)
+		}
+	}
+
+	if len(notifiers) == 0 {
+		return nil, errors.New("at least one notifier is required")
+	}
+
+	return notifiers, nil
+}
+
+// Close закрывает каналы, которые этого требуют.
+func (m MultiNotifier) Close() error {
+	var firstErr error
+
+	for _, ch := range m {
+		c, ok := ch.Notifier.(io.Closer)
+		if !ok {
+			continue
+		}
+
+		if err := c.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+
+	return firstErr
+}
+
+// Notify доставляет уведомление во все каналы. Сбой одного канала не мешает
+// доставке в остальные, но уведомление считается недоставленным.
+// Каналы, в которые уведомление доставлено, добавляются в n.DeliveredChannels
+// и пропускаются при повторной попытке, чтобы не отправлять уведомление в них дважды.
+func (m MultiNotifier) Notify(ctx context.Context, n *calendar.Notification) error {
+	var (
+		firstErr error
+		failed   int
+	)
+
+	for _, ch := range m {
+		if isDelivered(n, ch.Name) {
+			continue
+		}
+
+		if err := ch.Notify(ctx, n); err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+
+			failed++
+
+			continue
+		}
+
+		n.DeliveredChannels = append(n.DeliveredChannels, ch.Name)
+	}
+
+	if firstErr != nil {
+		return errors.Wrapf(firstErr, "notify: %d of %d channels failed", failed, len(m))
+	}
+
+	return nil
+}
+
+// isDelivered проверяет, доставлено ли уведомление в канал name при прошлых попытках.
+func isDelivered(n *calendar.Notification, name string) bool {
+	for _, delivered := range n.DeliveredChannels {
+		if delivered == name {
+			return true
+		}
+	}
+
+	return false
+}
+
+// WriterNotifier выводит уведомления текстом в io.Writer.
+type WriterNotifier struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewWriterNotifier создает канал вывода уведомлений в w.
+func NewWriterNotifier(w io.Writer) *WriterNotifier {
+	return &WriterNotifier{w: w}
+}
+
+// Notify выводит уведомление.
+func (wn *WriterNotifier) Notify(ctx context.Context, n *calendar.Notification) error {
+	wn.mu.Lock()
+	defer wn.mu.Unlock()
+
+	_, err := io.WriteString(wn.w, notificationText(n))
+
+	return errors.Wrap(err, "write notification")
+}
+
+// notificationText формирует текст уведомления.
+func notificationText(n *calendar.Notification) string {
+	return fmt.Sprintf(
+		"Привет, %s!\nВ %s начнется событие: %s\n",
+		n.UserID,
+		n.EventStartAt.Format("15:04"),
+		n.EventTitle,
+	)
+}
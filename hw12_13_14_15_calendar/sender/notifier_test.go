@@ -0,0 +1,105 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	mocks "github.com/RomanSarvarov/otus_go_home_work/calendar/mocks/sender"
+)
+
+// testNotification уведомление для тестов каналов.
+var testNotification = &calendar.Notification{
+	ID:           uuid.MustParse("2f0d2079-e9a2-4810-8cae-eb6729c50580"),
+	EventID:      uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+	EventTitle:   "foo",
+	EventStartAt: time.Date(2022, 10, 3, 10, 30, 0, 0, time.UTC),
+	UserID:       uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+}
+
+func TestWriterNotifier_Notify(t *testing.T) {
+	buf := new(bytes.Buffer)
+
+	err := NewWriterNotifier(buf).Notify(context.Background(), testNotification)
+
+	require.NoError(t, err)
+	require.Equal(t, "Привет, 123e4567-e89b-12d3-a456-426614174000!\nВ 10:30 начнется событие: foo\n", buf.String())
+}
+
+func TestMultiNotifier_Notify(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("all channels", func(t *testing.T) {
+		first, second := mocks.NewNotifier(t), mocks.NewNotifier(t)
+
+		n := *testNotification
+
+		first.On("Notify", mock.Anything, &n).Return(nil).Once()
+		second.On("Notify", mock.Anything, &n).Return(nil).Once()
+
+		m := MultiNotifier{{Name: "first", Notifier: first}, {Name: "second", Notifier: second}}
+
+		require.NoError(t, m.Notify(ctx, &n))
+		require.Equal(t, []string{"first", "second"}, n.DeliveredChannels)
+	})
+
+	t.Run("failed channel does not stop others", func(t *testing.T) {
+		first, second := mocks.NewNotifier(t), mocks.NewNotifier(t)
+
+		errNotify := errors.New("notify")
+
+		n := *testNotification
+
+		first.On("Notify", mock.Anything, &n).Return(errNotify).Once()
+		second.On("Notify", mock.Anything, &n).Return(nil).Once()
+
+		m := MultiNotifier{{Name: "first", Notifier: first}, {Name: "second", Notifier: second}}
+
+		err := m.Notify(ctx, &n)
+
+		require.ErrorIs(t, err, errNotify)
+		require.Contains(t, err.Error(), "1 of 2 channels failed")
+		require.Equal(t, []string{"second"}, n.DeliveredChannels)
+
+		// повторная попытка отправляет уведомление только в канал, где доставка не удалась
+		first.On("Notify", mock.Anything, &n).Return(nil).Once()
+
+		require.NoError(t, m.Notify(ctx, &n))
+		require.Equal(t, []string{"second", "first"}, n.DeliveredChannels)
+	})
+}
+
+func TestNewNotifier(t *testing.T) {
+	t.Run("configured channels", func(t *testing.T) {
+		n, err := NewNotifier(NotifierConfig{
+			Notifiers:     []string{NotifierStdout, " " + NotifierWebhook},
+			WebhookURL:    "http://localhost",
+			WebhookSecret: "secret",
+		})
+		require.NoError(t, err)
+		require.Len(t, n, 2)
+		require.NoError(t, n.Close())
+	})
+
+	t.Run("unknown channel", func(t *testing.T) {
+		_, err := NewNotifier(NotifierConfig{Notifiers: []string{"sms"}})
+		require.Error(t, err)
+	})
+
+	t.Run("webhook without secret", func(t *testing.T) {
+		_, err := NewNotifier(NotifierConfig{Notifiers: []string{NotifierWebhook}, WebhookURL: "http://localhost"})
+		require.Error(t, err)
+	})
+
+	t.Run("no channels", func(t *testing.T) {
+		_, err := NewNotifier(NotifierConfig{})
+		require.Error(t, err)
+	})
+}
@@ -2,25 +2,44 @@ package sender
 
 import (
 	"context"
-	"fmt"
 
 	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/metrics"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/tracing"
 )
 
+// Broker источник уведомлений. Вместе с уведомлением возвращается контекст,
+// содержащий контекст трассировки его отправителя.
+// Прочитанное уведомление подтверждается через CommitNotification только после обработки,
+// поэтому уведомление, обработка которого прервана (например, падением рассыльщика), читается снова.
 type Broker interface {
-	ReadEventFromQueue(ctx context.Context) (*calendar.Event, error)
+	ReadNotificationFromQueue(ctx context.Context) (context.Context, *calendar.Notification, error)
+	CommitNotification(ctx context.Context, n *calendar.Notification) error
+}
+
+// Retrier откладывает повторную обработку уведомления, обработка которого завершилась ошибкой.
+// После исчерпания попыток уведомление помещается в очередь недоставленных.
+type Retrier interface {
+	RetryNotification(ctx context.Context, n *calendar.Notification, cause error) error
 }
 
 type Repository interface {
-	UpdateEvent(ctx context.Context, id uuid.UUID, e *calendar.Event) (*calendar.Event, error)
+	IsNotificationDelivered(ctx context.Context, id uuid.UUID) (bool, error)
+	AckNotification(ctx context.Context, id uuid.UUID) error
 }
 
 type Sender struct {
 	r   Repository
 	b   Broker
+	rt  Retrier
+	n   Notifier
 	cfg Config
 }
 
@@ -28,10 +47,12 @@ type Config struct {
 	Threads int
 }
 
-func New(r Repository, b Broker, cfg Config) Sender {
+func New(r Repository, b Broker, rt Retrier, n Notifier, cfg Config) Sender {
 	return Sender{
 		r:   r,
 		b:   b,
+		rt:  rt,
+		n:   n,
 		cfg: cfg,
 	}
 }
@@ -53,18 +74,18 @@ func (s Sender) Start(ctx context.Context) error {
 				default:
 				}
 
-				event, err := s.b.ReadEventFromQueue(ctx)
+				msgCtx, n, err := s.b.ReadNotificationFromQueue(ctx)
 				if err != nil {
 					return err
 				}
 
-				if err := sendNotification(event); err != nil {
+				if err := s.process(msgCtx, n); err != nil {
 					return err
 				}
 
-				event.IsNotified = true
-				if _, err := s.r.UpdateEvent(ctx, event.ID, event); err != nil {
-					return err
+				// уведомление доставлено и подтверждено в хранилище или отложено для повтора
+				if err := s.b.CommitNotification(ctx, n); err != nil {
+					return errors.Wrap(err, "commit notification")
 				}
 			}
 		})
@@ -73,10 +94,78 @@ func (s Sender) Start(ctx context.Context) error {
 	return errGrp.Wait()
 }
 
-func sendNotification(e *calendar.Event) error {
-	fmt.Printf("Привет, %s!\n", e.UserID)
-	fmt.Printf("В %s начнется событие: %s\n", e.StartAt.Format("15:04"), e.Title)
-	fmt.Printf("Описание: %s\n", e.Description)
+// process доставляет уведомление.
+// Ошибка доставки одного уведомления не останавливает обработку остальных:
+// уведомление откладывается, и ошибка возвращается только если его не удалось отложить.
+func (s Sender) process(ctx context.Context, n *calendar.Notification) (err error) {
+	ctx, span := tracing.Start(ctx, "Sender.process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			attribute.String("notification.id", n.ID.String()),
+			attribute.Int("notification.attempts", n.Attempts),
+		),
+	)
+	defer func() { tracing.End(span, err) }()
+
+	if deliverErr := s.deliver(ctx, n); deliverErr != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+
+		metrics.SenderFailed.Inc()
+		span.RecordError(deliverErr)
+
+		log.Warn().
+			Err(deliverErr).
+			Str("notification_id", n.ID.String()).
+			Int("attempts", n.Attempts+1).
+			Msg("notification delivery failed")
+
+		if err := s.rt.RetryNotification(ctx, n, deliverErr); err != nil {
+			return errors.Wrap(err, "retry notification")
+		}
+	}
+
+	metrics.SenderProcessed.Inc()
+
+	return nil
+}
+
+// deliver отправляет уведомление и подтверждает его доставку.
+// Уведомление, доставленное ранее (повторно опубликованное планировщиком),
+// или удаленное вместе с событием, пропускается.
+// Уведомление без идентификатора прочитано из сообщения прежней версии,
+// записи о нем в хранилище нет, поэтому оно отправляется без подтверждения.
+func (s Sender) deliver(ctx context.Context, n *calendar.Notification) error {
+	if n.ID == uuid.Nil {
+		return s.n.Notify(ctx, n)
+	}
+
+	delivered, err := s.r.IsNotificationDelivered(ctx, n.ID)
+	if errors.Is(err, calendar.ErrNotFound) {
+		log.Warn().
+			Str("notification_id", n.ID.String()).
+			Str("event_id", n.EventID.String()).
+			Msg("notification not found in outbox, skipped")
+
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if delivered {
+		return nil
+	}
+
+	if err := s.n.Notify(ctx, n); err != nil {
+		return err
+	}
+
+	if err := s.r.AckNotification(ctx, n.ID); err != nil && !errors.Is(err, calendar.ErrNotFound) {
+		return err
+	}
 
 	return nil
 }
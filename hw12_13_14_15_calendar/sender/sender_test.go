@@ -0,0 +1,207 @@
+package sender
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	mocks "github.com/RomanSarvarov/otus_go_home_work/calendar/mocks/sender"
+)
+
+func TestSender_Start(t *testing.T) {
+	ctx := context.Background()
+	errStop := errors.New("stop")
+
+	n := &calendar.Notification{
+		ID:           uuid.MustParse("2f0d2079-e9a2-4810-8cae-eb6729c50580"),
+		EventID:      uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+		EventTitle:   "foo",
+		EventStartAt: time.Unix(1664643702, 0),
+		UserID:       uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+	}
+
+	t.Run("acknowledges delivery", func(t *testing.T) {
+		r := mocks.NewRepository(t)
+		b := mocks.NewBroker(t)
+		nt := mocks.NewNotifier(t)
+		rt := mocks.NewRetrier(t)
+
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(ctx, n, nil).Once()
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(nil, nil, errStop).Once()
+		r.On("IsNotificationDelivered", mock.Anything, n.ID).Return(false, nil).Once()
+		nt.On("Notify", mock.Anything, n).Return(nil).Once()
+		r.On("AckNotification", mock.Anything, n.ID).Return(nil).Once()
+		b.On("CommitNotification", mock.Anything, n).Return(nil).Once()
+
+		s := New(r, b, rt, nt, Config{Threads: 1})
+		err := s.Start(ctx)
+
+		require.ErrorIs(t, err, errStop)
+	})
+
+	t.Run("skips delivered and deleted", func(t *testing.T) {
+		r := mocks.NewRepository(t)
+		b := mocks.NewBroker(t)
+		nt := mocks.NewNotifier(t)
+		rt := mocks.NewRetrier(t)
+
+		deleted := *n
+		deleted.ID = uuid.MustParse("3f0d2079-e9a2-4810-8cae-eb6729c50580")
+
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(ctx, n, nil).Once()
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(ctx, &deleted, nil).Once()
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(nil, nil, errStop).Once()
+		r.On("IsNotificationDelivered", mock.Anything, n.ID).Return(true, nil).Once()
+		r.On("IsNotificationDelivered", mock.Anything, deleted.ID).Return(false, calendar.ErrNotFound).Once()
+		b.On("CommitNotification", mock.Anything, n).Return(nil).Once()
+		b.On("CommitNotification", mock.Anything, &deleted).Return(nil).Once()
+
+		s := New(r, b, rt, nt, Config{Threads: 1})
+		err := s.Start(ctx)
+
+		require.ErrorIs(t, err, errStop)
+	})
+
+	t.Run("legacy notification without outbox record", func(t *testing.T) {
+		r := mocks.NewRepository(t)
+		b := mocks.NewBroker(t)
+		nt := mocks.NewNotifier(t)
+		rt := mocks.NewRetrier(t)
+
+		legacy := *n
+		legacy.ID = uuid.Nil
+
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(ctx, &legacy, nil).Once()
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(nil, nil, errStop).Once()
+		nt.On("Notify", mock.Anything, &legacy).Return(nil).Once()
+		b.On("CommitNotification", mock.Anything, &legacy).Return(nil).Once()
+
+		s := New(r, b, rt, nt, Config{Threads: 1})
+		err := s.Start(ctx)
+
+		require.ErrorIs(t, err, errStop)
+	})
+
+	t.Run("failed notification is retried", func(t *testing.T) {
+		r := mocks.NewRepository(t)
+		b := mocks.NewBroker(t)
+		nt := mocks.NewNotifier(t)
+		rt := mocks.NewRetrier(t)
+
+		errNotify := errors.New("notify")
+		errDB := errors.New("db")
+
+		next := *n
+		next.ID = uuid.MustParse("3f0d2079-e9a2-4810-8cae-eb6729c50580")
+
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(ctx, n, nil).Once()
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(ctx, &next, nil).Once()
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(nil, nil, errStop).Once()
+		r.On("IsNotificationDelivered", mock.Anything, n.ID).Return(false, nil).Once()
+		nt.On("Notify", mock.Anything, n).Return(errNotify).Once()
+		rt.On("RetryNotification", mock.Anything, n, mock.MatchedBy(func(err error) bool {
+			return errors.Is(err, errNotify)
+		})).Return(nil).Once()
+		r.On("IsNotificationDelivered", mock.Anything, next.ID).Return(false, errDB).Once()
+		rt.On("RetryNotification", mock.Anything, &next, errDB).Return(nil).Once()
+		b.On("CommitNotification", mock.Anything, n).Return(nil).Once()
+		b.On("CommitNotification", mock.Anything, &next).Return(nil).Once()
+
+		s := New(r, b, rt, nt, Config{Threads: 1})
+		err := s.Start(ctx)
+
+		require.ErrorIs(t, err, errStop)
+	})
+
+	t.Run("retry error", func(t *testing.T) {
+		r := mocks.NewRepository(t)
+		b := mocks.NewBroker(t)
+		nt := mocks.NewNotifier(t)
+		rt := mocks.NewRetrier(t)
+
+		errDB := errors.New("db")
+		errQueue := errors.New("queue")
+
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(ctx, n, nil).Once()
+		r.On("IsNotificationDelivered", mock.Anything, n.ID).Return(false, errDB).Once()
+		rt.On("RetryNotification", mock.Anything, n, errDB).Return(errQueue).Once()
+
+		s := New(r, b, rt, nt, Config{Threads: 1})
+		err := s.Start(ctx)
+
+		require.ErrorIs(t, err, errQueue)
+	})
+
+	t.Run("crash before delivery", func(t *testing.T) {
+		r := mocks.NewRepository(t)
+		b := mocks.NewBroker(t)
+		nt := mocks.NewNotifier(t)
+		rt := mocks.NewRetrier(t)
+
+		crashCtx, crash := context.WithCancel(ctx)
+
+		// рассыльщик останавливается во время доставки: уведомление не подтверждается
+		// и после перезапуска читается и доставляется снова
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(crashCtx, n, nil).Once()
+		r.On("IsNotificationDelivered", mock.Anything, n.ID).Return(false, nil).Twice()
+		nt.On("Notify", mock.Anything, n).Run(func(mock.Arguments) { crash() }).Return(context.Canceled).Once()
+
+		s := New(r, b, rt, nt, Config{Threads: 1})
+		require.ErrorIs(t, s.Start(crashCtx), context.Canceled)
+		b.AssertNotCalled(t, "CommitNotification", mock.Anything, mock.Anything)
+
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(ctx, n, nil).Once()
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(nil, nil, errStop).Once()
+		nt.On("Notify", mock.Anything, n).Return(nil).Once()
+		r.On("AckNotification", mock.Anything, n.ID).Return(nil).Once()
+		b.On("CommitNotification", mock.Anything, n).Return(nil).Once()
+
+		require.ErrorIs(t, s.Start(ctx), errStop)
+	})
+
+	t.Run("continues publisher trace", func(t *testing.T) {
+		r := mocks.NewRepository(t)
+		b := mocks.NewBroker(t)
+		nt := mocks.NewNotifier(t)
+		rt := mocks.NewRetrier(t)
+
+		sr := tracetest.NewSpanRecorder()
+
+		prev := otel.GetTracerProvider()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+		defer otel.SetTracerProvider(prev)
+
+		publisher := trace.NewSpanContext(trace.SpanContextConfig{
+			TraceID:    trace.TraceID{1},
+			SpanID:     trace.SpanID{2},
+			TraceFlags: trace.FlagsSampled,
+			Remote:     true,
+		})
+		msgCtx := trace.ContextWithRemoteSpanContext(ctx, publisher)
+
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(msgCtx, n, nil).Once()
+		b.On("ReadNotificationFromQueue", mock.Anything).Return(nil, nil, errStop).Once()
+		r.On("IsNotificationDelivered", mock.Anything, n.ID).Return(true, nil).Once()
+		b.On("CommitNotification", mock.Anything, n).Return(nil).Once()
+
+		s := New(r, b, rt, nt, Config{Threads: 1})
+		err := s.Start(ctx)
+
+		require.ErrorIs(t, err, errStop)
+
+		spans := sr.Ended()
+		require.Len(t, spans, 1)
+		require.Equal(t, publisher.TraceID(), spans[0].SpanContext().TraceID())
+		require.Equal(t, publisher.SpanID(), spans[0].Parent().SpanID())
+	})
+}
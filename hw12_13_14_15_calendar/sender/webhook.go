@@ -0,0 +1,194 @@
+package sender
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+
+	json "github.com/json-iterator/go"
+	"github.com/pkg/errors"
+	"github.com/rs/zerolog/log"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+const (
+	// WebhookTimestampHeader заголовок с временем отправки запроса (unix, секунды).
+	WebhookTimestampHeader = "X-Calendar-Timestamp"
+
+	// WebhookSignatureHeader заголовок с подписью запроса (см. SignWebhook).
+	WebhookSignatureHeader = "X-Calendar-Signature"
+)
+
+// timeNowFunc текущее время, подменяется в тестах.
+var timeNowFunc = time.Now
+
+// WebhookConfig настройки отправки уведомлений HTTP запросом.
+type WebhookConfig struct {
+	// URL адрес, на который отправляются уведомления.
+	URL string
+
+	// Secret ключ подписи запросов. Если не задан, то запросы не подписываются.
+	Secret string
+
+	// Timeout таймаут одного запроса.
+	Timeout time.Duration
+
+	// MaxAttempts максимальное количество попыток отправки.
+	MaxAttempts int
+
+	// BaseDelay задержка перед первой повторной попыткой,
+	// каждая следующая задержка вдвое больше предыдущей.
+	BaseDelay time.Duration
+
+	// MaxDelay максимальная задержка между попытками.
+	MaxDelay time.Duration
+}
+
+// WebhookNotifier отправляет уведомления POST запросом с JSON телом.
+// Запрос подписывается HMAC-SHA256, при сетевых ошибках, таймаутах и ответах 5xx
+// повторяется с экспоненциально растущей задержкой со случайным разбросом.
+type WebhookNotifier struct {
+	cfg    WebhookConfig
+	client *http.Client
+}
+
+// webhookPayload тело запроса с уведомлением.
+type webhookPayload struct {
+	ID           string    `json:"id"`
+	EventID      string    `json:"event_id"`
+	EventTitle   string    `json:"event_title"`
+	EventStartAt time.Time `json:"event_start_at"`
+	UserID       string    `json:"user_id"`
+
+	// Text готовый текст уведомления для чат-ботов.
+	Text string `json:"text"`
+}
+
+// NewWebhookNotifier создает канал отправки уведомлений.
+func NewWebhookNotifier(cfg WebhookConfig) *WebhookNotifier {
+	if cfg.MaxAttempts < 1 {
+		cfg.MaxAttempts = 1
+	}
+
+	return &WebhookNotifier{
+		cfg:    cfg,
+		client: &http.Client{Timeout: cfg.Timeout},
+	}
+}
+
+// Notify отправляет уведомление. Если все попытки неудачны,
+// то сбой записывается в лог и возвращается ошибка последней попытки.
+func (wn *WebhookNotifier) Notify(ctx context.Context, n *calendar.Notification) error {
+	body, err := json.Marshal(webhookPayload{
+		ID:           n.ID.String(),
+		EventID:      n.EventID.String(),
+		EventTitle:   n.EventTitle,
+		EventStartAt: n.EventStartAt.UTC(),
+		UserID:       n.UserID.String(),
+		Text:         notificationText(n),
+	})
+	if err != nil {
+		return errors.Wrap(err, "send webhook")
+	}
+
+	attempt := 0
+
+	for {
+		attempt++
+
+		retry, err := wn.send(ctx, body)
+		if err == nil {
+			return nil
+		}
+
+		if !retry || attempt == wn.cfg.MaxAttempts {
+			log.Error().
+				Err(err).
+				Str("notification_id", n.ID.String()).
+				Str("event_id", n.EventID.String()).
+				Int("attempts", attempt).
+				Msg("webhook delivery failed")
+
+			return errors.Wrapf(err, "send webhook after %d attempts", attempt)
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Wrap(ctx.Err(), "send webhook")
+		case <-time.After(wn.backoff(attempt)):
+		}
+	}
+}
+
+// send выполняет одну попытку отправки. retry сообщает, имеет ли смысл повторить попытку.
+func (wn *WebhookNotifier) send(ctx context.Context, body []byte) (retry bool, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, wn.cfg.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+
+	if wn.cfg.Secret != "" {
+		timestamp := timeNowFunc().Unix()
+
+		req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+		req.Header.Set(WebhookSignatureHeader, SignWebhook(wn.cfg.Secret, timestamp, body))
+	}
+
+	res, err := wn.client.Do(req)
+	if err != nil {
+		// отмена контекста вызывающим не повторяется, таймаут клиента - повторяется
+		return ctx.Err() == nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		return false, nil
+	}
+
+	retry = res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests
+
+	return retry, errors.Errorf("unexpected status %d", res.StatusCode)
+}
+
+// backoff возвращает задержку перед попыткой, следующей за attempt:
+// случайное значение от половины до полной экспоненциальной задержки.
+func (wn *WebhookNotifier) backoff(attempt int) time.Duration {
+	delay := wn.cfg.BaseDelay
+
+	for i := 1; i < attempt && delay < wn.cfg.MaxDelay; i++ {
+		delay *= 2
+	}
+
+	if wn.cfg.MaxDelay > 0 && delay > wn.cfg.MaxDelay {
+		delay = wn.cfg.MaxDelay
+	}
+
+	if delay <= 0 {
+		return 0
+	}
+
+	half := delay / 2
+
+	return half + time.Duration(rand.Int63n(int64(delay-half)+1)) //nolint:gosec
+}
+
+// SignWebhook возвращает подпись запроса: "sha256=" и HMAC-SHA256 в hex
+// от строки "<timestamp>.<body>". Получатель вычисляет подпись тем же способом
+// и отклоняет запросы с несовпадающей подписью или устаревшим временем.
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
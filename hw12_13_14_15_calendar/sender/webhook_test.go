@@ -0,0 +1,158 @@
+package sender
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWebhookNotifier_Notify(t *testing.T) {
+	ctx := context.Background()
+
+	now := time.Date(2022, 10, 3, 10, 0, 0, 0, time.UTC)
+	timeNowFunc = func() time.Time { return now }
+	defer func() { timeNowFunc = time.Now }()
+
+	newConfig := func(url string) WebhookConfig {
+		return WebhookConfig{
+			URL:         url,
+			Secret:      "secret",
+			Timeout:     time.Second,
+			MaxAttempts: 3,
+			BaseDelay:   time.Millisecond,
+			MaxDelay:    5 * time.Millisecond,
+		}
+	}
+
+	t.Run("posts signed notification", func(t *testing.T) {
+		var body string
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			require.Equal(t, http.MethodPost, req.Method)
+			require.Equal(t, "application/json", req.Header.Get("Content-Type"))
+
+			bs, err := io.ReadAll(req.Body)
+			require.NoError(t, err)
+
+			body = string(bs)
+
+			require.Equal(t, "1664791200", req.Header.Get(WebhookTimestampHeader))
+			require.Equal(t, SignWebhook("secret", now.Unix(), bs), req.Header.Get(WebhookSignatureHeader))
+
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer srv.Close()
+
+		err := NewWebhookNotifier(newConfig(srv.URL)).Notify(ctx, testNotification)
+
+		require.NoError(t, err)
+		require.JSONEq(t, `{
+			"id": "2f0d2079-e9a2-4810-8cae-eb6729c50580",
+			"event_id": "ef0d2079-e9a2-4810-8cae-eb6729c50580",
+			"event_title": "foo",
+			"event_start_at": "2022-10-03T10:30:00Z",
+			"user_id": "123e4567-e89b-12d3-a456-426614174000",
+			"text": "Привет, 123e4567-e89b-12d3-a456-426614174000!\nВ 10:30 начнется событие: foo\n"
+		}`, body)
+	})
+
+	tests := []struct {
+		name      string
+		responses []int
+		wantCalls int32
+		wantErr   string
+	}{
+		{
+			name:      "retries server errors",
+			responses: []int{http.StatusBadGateway, http.StatusTooManyRequests, http.StatusOK},
+			wantCalls: 3,
+		},
+		{
+			name:      "gives up after max attempts",
+			responses: []int{http.StatusInternalServerError, http.StatusInternalServerError, http.StatusInternalServerError},
+			wantCalls: 3,
+			wantErr:   "send webhook after 3 attempts: unexpected status 500",
+		},
+		{
+			name:      "client error is not retried",
+			responses: []int{http.StatusBadRequest},
+			wantCalls: 1,
+			wantErr:   "send webhook after 1 attempts: unexpected status 400",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+				call := atomic.AddInt32(&calls, 1)
+				w.WriteHeader(tt.responses[call-1])
+			}))
+			defer srv.Close()
+
+			err := NewWebhookNotifier(newConfig(srv.URL)).Notify(ctx, testNotification)
+
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+			} else {
+				require.EqualError(t, err, tt.wantErr)
+			}
+
+			require.Equal(t, tt.wantCalls, atomic.LoadInt32(&calls))
+		})
+	}
+
+	t.Run("retries timeout", func(t *testing.T) {
+		var calls int32
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				time.Sleep(100 * time.Millisecond)
+			}
+		}))
+		defer srv.Close()
+
+		cfg := newConfig(srv.URL)
+		cfg.Timeout = 20 * time.Millisecond
+
+		err := NewWebhookNotifier(cfg).Notify(ctx, testNotification)
+
+		require.NoError(t, err)
+		require.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	})
+}
+
+func TestWebhookNotifier_backoff(t *testing.T) {
+	wn := NewWebhookNotifier(WebhookConfig{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second})
+
+	for attempt, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		3: 400 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	} {
+		for i := 0; i < 10; i++ {
+			got := wn.backoff(attempt)
+
+			require.GreaterOrEqual(t, got, want/2)
+			require.LessOrEqual(t, got, want)
+		}
+	}
+}
+
+func TestSignWebhook(t *testing.T) {
+	// echo -n '1664791200.{}' | openssl dgst -sha256 -hmac secret
+	require.Equal(
+		t,
+		"sha256=45384cafb5e909f00dd3b984bc55f374ba0ce6260f97d50168fa5541cab0c808",
+		SignWebhook("secret", 1664791200, []byte("{}")),
+	)
+}
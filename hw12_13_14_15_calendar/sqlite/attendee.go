@@ -0,0 +1,194 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// InviteAttendee пригласить пользователя на событие.
+// Повторное приглашение не меняет ответ участника.
+// Приглашение и запись в журнал изменений выполняются в одной транзакции.
+func (repo *Repository) InviteAttendee(ctx context.Context, eventID, userID uuid.UUID) (*calendar.Attendee, error) {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "invite attendee")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	e, err := findEventByID(ctx, tx, eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "invite attendee")
+	}
+
+	if e.UserID == userID {
+		return nil, errors.Wrap(calendar.ErrInvalidAttendee, "invite attendee")
+	}
+
+	if a := e.Attendee(userID); a != nil {
+		res := *a
+
+		return &res, nil
+	}
+
+	attendee := new(calendar.Attendee)
+	err = tx.QueryRowxContext(
+		ctx,
+		`INSERT INTO event_attendees (event_id, user_id, status) VALUES (?1, ?2, ?3)
+		ON CONFLICT (event_id, user_id) DO UPDATE SET status = event_attendees.status
+		RETURNING *;`,
+		eventID, userID, calendar.RSVPNeedsAction,
+	).StructScan(attendee)
+	if err != nil {
+		return nil, errors.Wrap(err, "invite attendee")
+	}
+
+	if err := addAttendeeRevision(ctx, tx, calendar.RevisionAttendeeInvited, e, nil, attendee); err != nil {
+		return nil, errors.Wrap(err, "invite attendee")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "invite attendee")
+	}
+
+	repo.publishEventChange(ctx, eventID)
+
+	return attendee, nil
+}
+
+// RemoveAttendee удалить участника события.
+// Удаление и запись в журнал изменений выполняются в одной транзакции.
+func (repo *Repository) RemoveAttendee(ctx context.Context, eventID, userID uuid.UUID) error {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "remove attendee")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	e, err := findEventByID(ctx, tx, eventID)
+	if err != nil {
+		return errors.Wrap(err, "remove attendee")
+	}
+
+	removed := e.Attendee(userID)
+	if removed == nil {
+		return errors.Wrap(calendar.ErrNotFound, "remove attendee")
+	}
+
+	_, err = tx.ExecContext(
+		ctx,
+		`DELETE FROM event_attendees WHERE event_id = ?1 AND user_id = ?2`,
+		eventID, userID,
+	)
+	if err != nil {
+		return errors.Wrap(err, "remove attendee")
+	}
+
+	if err := addAttendeeRevision(ctx, tx, calendar.RevisionAttendeeRemoved, e, removed, nil); err != nil {
+		return errors.Wrap(err, "remove attendee")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "remove attendee")
+	}
+
+	repo.publishEventChange(ctx, eventID, userID)
+
+	return nil
+}
+
+// RespondAttendee ответить на приглашение на событие.
+// Принять приглашение можно только если время события у участника свободно.
+// Проверка и сохранение ответа выполняются в одной транзакции.
+func (repo *Repository) RespondAttendee(
+	ctx context.Context,
+	eventID, userID uuid.UUID,
+	status calendar.RSVPStatus,
+) (*calendar.Attendee, error) {
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, errors.Wrap(err, "respond attendee")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	e, err := findEventByID(ctx, tx, eventID)
+	if err != nil {
+		return nil, errors.Wrap(err, "respond attendee")
+	}
+
+	before := e.Attendee(userID)
+	if before == nil {
+		return nil, errors.Wrap(calendar.ErrNotFound, "respond attendee")
+	}
+
+	if status == calendar.RSVPAccepted {
+		if err := checkDateBusy(ctx, tx, e, userID, eventID); err != nil {
+			return nil, errors.Wrap(err, "respond attendee")
+		}
+	}
+
+	attendee := new(calendar.Attendee)
+	err = tx.QueryRowxContext(
+		ctx,
+		`UPDATE event_attendees SET status = ?3 WHERE event_id = ?1 AND user_id = ?2 RETURNING *;`,
+		eventID, userID, status,
+	).StructScan(attendee)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = calendar.ErrNotFound
+		}
+
+		return nil, errors.Wrap(err, "respond attendee")
+	}
+
+	if err := addAttendeeRevision(ctx, tx, calendar.RevisionAttendeeResponded, e, before, attendee); err != nil {
+		return nil, errors.Wrap(err, "respond attendee")
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "respond attendee")
+	}
+
+	repo.publishEventChange(ctx, eventID)
+
+	return attendee, nil
+}
+
+// loadAttendees загрузить участников событий в рамках q (соединения или транзакции).
+func loadAttendees(ctx context.Context, q sqlx.QueryerContext, events []*calendar.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	byID := make(map[uuid.UUID]*calendar.Event, len(events))
+	ids := make([]uuid.UUID, 0, len(events))
+
+	for _, e := range events {
+		byID[e.ID] = e
+		ids = append(ids, e.ID)
+	}
+
+	query, args, err := sqlx.In(`SELECT * FROM event_attendees WHERE event_id IN (?) ORDER BY user_id`, ids)
+	if err != nil {
+		return err
+	}
+
+	attendees := make([]calendar.Attendee, 0)
+
+	if err := sqlx.SelectContext(ctx, q, &attendees, query, args...); err != nil {
+		return errors.Wrap(err, "load attendees")
+	}
+
+	for _, a := range attendees {
+		if e, ok := byID[a.EventID]; ok {
+			e.Attendees = append(e.Attendees, a)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,160 @@
+package sqlite
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+func TestRepository_checkDateBusy(t *testing.T) {
+	tests := []struct {
+		name      string
+		events    []*calendar.Event
+		ignoredID uuid.UUID
+		wantErr   error
+	}{
+		{
+			name: "other event end when needle event begin",
+			events: []*calendar.Event{
+				{
+					StartAt: mustParseDateTime("2022-05-10 14:50:00"),
+					EndAt:   mustParseDateTime("2022-05-10 15:00:00"),
+					UserID:  uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "other event end after needle event begin",
+			events: []*calendar.Event{
+				{
+					StartAt: mustParseDateTime("2022-05-10 14:50:00"),
+					EndAt:   mustParseDateTime("2022-05-10 15:00:01"),
+					UserID:  uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				},
+			},
+			wantErr: calendar.ErrDateBusy,
+		},
+		{
+			name: "other event begin after needle event end",
+			events: []*calendar.Event{
+				{
+					StartAt: mustParseDateTime("2022-05-10 15:30:00"),
+					EndAt:   mustParseDateTime("2022-05-10 16:00:00"),
+					UserID:  uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "other event begin before needle event end",
+			events: []*calendar.Event{
+				{
+					StartAt: mustParseDateTime("2022-05-10 15:29:59"),
+					EndAt:   mustParseDateTime("2022-05-10 15:59:59"),
+					UserID:  uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				},
+			},
+			wantErr: calendar.ErrDateBusy,
+		},
+		{
+			name: "other event begin inside needle event",
+			events: []*calendar.Event{
+				{
+					StartAt: mustParseDateTime("2022-05-10 15:20:00"),
+					EndAt:   mustParseDateTime("2022-05-10 15:21:00"),
+					UserID:  uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				},
+			},
+			wantErr: calendar.ErrDateBusy,
+		},
+		{
+			name: "ignore current event id",
+			events: []*calendar.Event{
+				{
+					ID:      uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+					StartAt: mustParseDateTime("2022-05-10 15:20:00"),
+					EndAt:   mustParseDateTime("2022-05-10 15:21:00"),
+					UserID:  uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+				},
+			},
+			ignoredID: uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+			wantErr:   nil,
+		},
+		{
+			name: "other user",
+			events: []*calendar.Event{
+				{
+					StartAt: mustParseDateTime("2022-05-10 15:20:00"),
+					EndAt:   mustParseDateTime("2022-05-10 15:21:00"),
+					UserID:  uuid.New(),
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name: "accepted invitation of another user",
+			events: []*calendar.Event{
+				{
+					StartAt: mustParseDateTime("2022-05-10 15:20:00"),
+					EndAt:   mustParseDateTime("2022-05-10 15:21:00"),
+					UserID:  uuid.New(),
+					Attendees: []calendar.Attendee{
+						{UserID: uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"), Status: calendar.RSVPAccepted},
+					},
+				},
+			},
+			wantErr: calendar.ErrDateBusy,
+		},
+		{
+			name: "declined invitation of another user",
+			events: []*calendar.Event{
+				{
+					StartAt: mustParseDateTime("2022-05-10 15:20:00"),
+					EndAt:   mustParseDateTime("2022-05-10 15:21:00"),
+					UserID:  uuid.New(),
+					Attendees: []calendar.Attendee{
+						{UserID: uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"), Status: calendar.RSVPDeclined},
+					},
+				},
+			},
+			wantErr: nil,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo := newRepository(t)
+
+			for _, e := range tt.events {
+				if e.ID == uuid.Nil {
+					e.ID = uuid.New()
+				}
+				insertEvent(t, repo, e)
+			}
+
+			event := &calendar.Event{
+				ID:      uuid.MustParse("123e4567-e89b-12d3-a456-426614174000"),
+				StartAt: mustParseDateTime("2022-05-10 15:00:00"),
+				EndAt:   mustParseDateTime("2022-05-10 15:30:00"),
+				UserID:  uuid.MustParse("ef0d2079-e9a2-4810-8cae-eb6729c50580"),
+			}
+
+			err := checkDateBusy(context.Background(), repo.db, event, event.UserID, tt.ignoredID)
+
+			require.ErrorIs(t, err, tt.wantErr)
+		})
+	}
+}
+
+func mustParseDateTime(str string) time.Time {
+	dt, err := time.Parse("2006-01-02 15:04:05", str)
+	if err != nil {
+		panic(err)
+	}
+	return dt
+}
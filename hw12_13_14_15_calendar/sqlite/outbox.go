@@ -0,0 +1,147 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// ClaimNotifications сохранить уведомления о наступивших событиях в исходящий ящик
+// и отметить события уведомленными в одной транзакции.
+// Уже сохраненные напоминания повторно не добавляются, поэтому одновременный
+// захват несколькими планировщиками не приводит к дублям.
+func (repo *Repository) ClaimNotifications(ctx context.Context, events ...*calendar.Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	tx, err := repo.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return errors.Wrap(err, "claim notifications")
+	}
+	defer tx.Rollback() //nolint:errcheck
+
+	for _, e := range events {
+		// отметка блокирует строку события до конца транзакции,
+		// поэтому событие не может быть удалено между отметкой и сохранением уведомлений
+		err := markEventNotified(ctx, tx, e.ID, e.StartAt)
+		if errors.Is(err, calendar.ErrNotFound) {
+			continue
+		}
+
+		if err != nil {
+			return errors.Wrap(err, "claim notifications")
+		}
+
+		for _, n := range calendar.NewNotifications(e) {
+			_, err := tx.ExecContext(
+				ctx,
+				`INSERT INTO notification_outbox (id, event_id, event_title, event_start_at, user_id)
+				VALUES (?1, ?2, ?3, ?4, ?5)
+				ON CONFLICT (event_id, event_start_at, user_id) DO NOTHING`,
+				n.ID, n.EventID, n.EventTitle, n.EventStartAt.UTC(), n.UserID,
+			)
+			if err != nil {
+				return errors.Wrap(err, "claim notifications")
+			}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "claim notifications")
+	}
+
+	return nil
+}
+
+// FindPendingNotifications найти до limit неопубликованных уведомлений в порядке их сохранения.
+// created_at хранится с точностью до секунды, поэтому порядок внутри секунды задает rowid.
+func (repo *Repository) FindPendingNotifications(ctx context.Context, limit int) ([]*calendar.Notification, error) {
+	notifications := make([]*calendar.Notification, 0)
+
+	err := repo.db.SelectContext(
+		ctx,
+		&notifications,
+		`SELECT id, event_id, event_title, event_start_at, user_id
+		FROM notification_outbox
+		WHERE published_at IS NULL
+		ORDER BY created_at, rowid
+		LIMIT ?1`,
+		limit,
+	)
+	if err != nil {
+		return nil, errors.Wrap(err, "find pending notifications")
+	}
+
+	return notifications, nil
+}
+
+// MarkNotificationsPublished отметить уведомления опубликованными в очередь.
+func (repo *Repository) MarkNotificationsPublished(ctx context.Context, ids ...uuid.UUID) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	query, args, err := sqlx.In(
+		`UPDATE notification_outbox SET published_at = ? WHERE id IN (?) AND published_at IS NULL`,
+		timeNowFunc().UTC(), ids,
+	)
+	if err != nil {
+		return err
+	}
+
+	if _, err := repo.db.ExecContext(ctx, query, args...); err != nil {
+		return errors.Wrap(err, "mark notifications published")
+	}
+
+	return nil
+}
+
+// IsNotificationDelivered проверить, доставлено ли уведомление.
+func (repo *Repository) IsNotificationDelivered(ctx context.Context, id uuid.UUID) (bool, error) {
+	var delivered bool
+
+	err := repo.db.GetContext(
+		ctx,
+		&delivered,
+		`SELECT delivered_at IS NOT NULL FROM notification_outbox WHERE id = ?1`,
+		id,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			err = calendar.ErrNotFound
+		}
+
+		return false, errors.Wrap(err, "is notification delivered")
+	}
+
+	return delivered, nil
+}
+
+// AckNotification подтвердить доставку уведомления.
+func (repo *Repository) AckNotification(ctx context.Context, id uuid.UUID) error {
+	res, err := repo.db.ExecContext(
+		ctx,
+		`UPDATE notification_outbox SET delivered_at = COALESCE(delivered_at, ?2) WHERE id = ?1`,
+		id, timeNowFunc().UTC(),
+	)
+	if err != nil {
+		return errors.Wrap(err, "ack notification")
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return errors.Wrap(err, "ack notification")
+	}
+
+	if affected == 0 {
+		return errors.Wrap(calendar.ErrNotFound, "ack notification")
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package sqlite
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+	_ "github.com/mattn/go-sqlite3" // sqlite support
+	"github.com/pkg/errors"
+	goose "github.com/pressly/goose/v3"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// Key обозначает ключ sqlite БД драйвера.
+const Key = "sqlite"
+
+// Config содержит настройки подключения к БД.
+type Config struct {
+	// Path путь к файлу БД.
+	Path string
+}
+
+// Repository является абстракцией к БД SQLite.
+type Repository struct {
+	db *sqlx.DB
+
+	// changes получатель изменений событий.
+	changes calendar.ChangePublisher
+}
+
+// Open открывает соединение к БД.
+// SQLite допускает только одного писателя, поэтому все запросы выполняются
+// через одно соединение по очереди и не получают ошибку SQLITE_BUSY.
+// Транзакция занимает это соединение до завершения, поэтому проверка занятости
+// и сохранение события в ней не пересекаются с другими изменениями.
+func Open(cfg Config) (*Repository, error) {
+	db, err := sqlx.Connect("sqlite3", dsn(cfg))
+	if err != nil {
+		return nil, errors.Wrap(err, "open SQLite connection")
+	}
+
+	db.SetMaxOpenConns(1)
+
+	return &Repository{db: db}, nil
+}
+
+// Close закрывает подключение к БД.
+func (repo *Repository) Close() error {
+	return repo.db.Close()
+}
+
+// Up выполняет миграции.
+func (repo *Repository) Up(dir string) error {
+	if err := goose.SetDialect("sqlite3"); err != nil {
+		return err
+	}
+
+	return goose.Up(repo.db.DB, dir)
+}
+
+// Ping проверяет соединение с БД.
+func (repo *Repository) Ping(ctx context.Context) error {
+	return errors.Wrap(repo.db.PingContext(ctx), "ping SQLite")
+}
+
+// CheckMigrations проверяет, что к БД применены все миграции из dir.
+// Версия БД определяется так же, как это делает goose: по последней записи каждой версии.
+func (repo *Repository) CheckMigrations(ctx context.Context, dir string) error {
+	migrations, err := goose.CollectMigrations(dir, 0, goose.MaxVersion)
+	if err != nil {
+		return errors.Wrap(err, "collect migrations")
+	}
+
+	latest, err := migrations.Last()
+	if err != nil {
+		return errors.Wrap(err, "collect migrations")
+	}
+
+	var current int64
+
+	err = repo.db.GetContext(ctx, &current, `
+		SELECT COALESCE(MAX(version_id), 0)
+		FROM goose_db_version
+		WHERE is_applied AND id IN (SELECT MAX(id) FROM goose_db_version GROUP BY version_id)
+	`)
+	if err != nil {
+		return errors.Wrap(err, "get database version")
+	}
+
+	if current < latest.Version {
+		return errors.Errorf("migrations pending: database version %d, latest %d", current, latest.Version)
+	}
+
+	return nil
+}
+
+// SetChangePublisher задает получателя изменений событий.
+// Устанавливается до начала работы с хранилищем.
+func (repo *Repository) SetChangePublisher(p calendar.ChangePublisher) {
+	repo.changes = p
+}
+
+// publishChange публикует изменение события, если задан получатель.
+func (repo *Repository) publishChange(t calendar.ChangeType, e *calendar.Event, extra ...uuid.UUID) {
+	if repo.changes == nil {
+		return
+	}
+
+	repo.changes.PublishChange(calendar.NewChange(t, e, extra...))
+}
+
+// publishEventChange публикует изменение события, загружая его актуальное состояние.
+// Ошибка загрузки не отменяет уже выполненное изменение, поэтому изменение в этом случае не публикуется.
+func (repo *Repository) publishEventChange(ctx context.Context, id uuid.UUID, extra ...uuid.UUID) {
+	if repo.changes == nil {
+		return
+	}
+
+	e, err := repo.findEventByID(ctx, id)
+	if err != nil {
+		return
+	}
+
+	repo.publishChange(calendar.ChangeUpdated, e, extra...)
+}
+
+// dsn формирует DSN строку из конфига.
+// Внешние ключи в SQLite по умолчанию выключены и включаются для каскадного удаления.
+// Транзакции сразу берут блокировку записи, чтобы их не прерывали изменения из других процессов.
+func dsn(cfg Config) string {
+	return "file:" + cfg.Path + "?_foreign_keys=on&_busy_timeout=5000&_txlock=immediate"
+}
@@ -0,0 +1,97 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/repotest"
+)
+
+// migrationsDir определяет местонахождение миграций SQLite.
+const migrationsDir = "../migrations/sqlite"
+
+// newRepository открывает хранилище во временном файле с выполненными миграциями.
+func newRepository(t *testing.T) *Repository {
+	t.Helper()
+
+	repo, err := Open(Config{Path: filepath.Join(t.TempDir(), "calendar.db")})
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		require.NoError(t, repo.Close())
+	})
+
+	require.NoError(t, repo.Up(migrationsDir))
+
+	return repo
+}
+
+// insertEvent сохраняет событие вместе с участниками без проверок.
+func insertEvent(t *testing.T, repo *Repository, e *calendar.Event) {
+	t.Helper()
+
+	_, err := repo.db.NamedExec(
+		`INSERT INTO events (id, title, description, start_at, end_at, user_id, notification_duration, is_notified, rrule, exdates, notified_until, uid, time_zone) VALUES (:id, :title, :description, :start_at, :end_at, :user_id, :notification_duration, :is_notified, :rrule, :exdates, :notified_until, :uid, :time_zone)`, //nolint:lll
+		e,
+	)
+	require.NoError(t, err)
+
+	for _, a := range e.Attendees {
+		_, err := repo.db.Exec(
+			`INSERT INTO event_attendees (event_id, user_id, status) VALUES (?1, ?2, ?3)`,
+			e.ID, a.UserID, a.Status,
+		)
+		require.NoError(t, err)
+	}
+}
+
+func TestRepository_CheckMigrations(t *testing.T) {
+	repo := newRepository(t)
+
+	require.NoError(t, repo.Ping(context.Background()))
+	require.NoError(t, repo.CheckMigrations(context.Background(), migrationsDir))
+}
+
+func TestKey(t *testing.T) {
+	require.Equal(t, "sqlite", Key)
+}
+
+func TestRepository(t *testing.T) {
+	repotest.Run(t, repotest.Driver{
+		New: func(t *testing.T) repotest.Storage {
+			return newRepository(t)
+		},
+		SetNow: func(now func() time.Time) {
+			timeNowFunc = now
+		},
+	})
+}
+
+func TestRepository_StoresUTC(t *testing.T) {
+	ctx := context.Background()
+	repo := newRepository(t)
+
+	msk := time.FixedZone("MSK", 3*60*60)
+
+	// даты сравниваются строками, поэтому событие в другом поясе должно находиться по UTC
+	e, err := repo.CreateEvent(ctx, &calendar.Event{
+		StartAt: time.Date(2022, 10, 3, 10, 0, 0, 0, msk),
+		EndAt:   time.Date(2022, 10, 3, 11, 0, 0, 0, msk),
+		UserID:  uuid.New(),
+	})
+	require.NoError(t, err)
+	require.Equal(t, mustParseDateTime("2022-10-03 07:00:00"), e.StartAt)
+
+	events, err := repo.FindEvents(ctx, calendar.EventFilter{
+		From: mustParseDateTime("2022-10-03 06:30:00"),
+		To:   time.Date(2022, 10, 3, 11, 30, 0, 0, msk),
+	})
+	require.NoError(t, err)
+	require.Len(t, events, 1)
+}
@@ -8,22 +8,30 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
+	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 	"github.com/stretchr/testify/suite"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
 
 	_ "github.com/lib/pq"
 
 	"github.com/RomanSarvarov/otus_go_home_work/calendar/proto/event"
 )
 
+// userID пользователь, от имени которого выполняются запросы.
+const userID = "ef0d2079-e9a2-4810-8cae-eb6729c50580"
+
 type EventSuite struct {
 	suite.Suite
 	ctx context.Context
@@ -41,15 +49,16 @@ func (s *EventSuite) SetupSuite() {
 	// Postgres setup
 	s.setupPG()
 
-	s.ctx = context.Background()
+	// Auth setup
+	s.ctx = s.userContext(context.Background(), userID)
 }
 
 func (s *EventSuite) SetupTest() {
-	s.cleanTables("events")
+	s.cleanTables("events", "event_revisions")
 }
 
 func (s *EventSuite) TearDownSuite() {
-	s.cleanTables("events")
+	s.cleanTables("events", "event_revisions")
 	s.Require().NoError(s.grpcConn.Close())
 	s.Require().NoError(s.pgConn.Close())
 }
@@ -128,18 +137,28 @@ func (s *EventSuite) TestCreateEvent() {
 		s.Require().Equal(1, count)
 	})
 
-	s.Run("no user passed", func() {
+	s.Run("no token passed", func() {
 		s.SetupTest()
 
-		_, err := s.eventClient.CreateEventV1(s.ctx, &event.CreateEventRequestV1{
+		_, err := s.eventClient.CreateEventV1(context.Background(), &event.CreateEventRequestV1{
 			Title:                "foo",
 			Description:          "bar",
 			StartAt:              1664643900,
 			EndAt:                1664644000,
-			UserId:               "",
+			UserId:               userID,
 			NotificationDuration: 25,
 		})
-		s.Require().Equal(codes.InvalidArgument, status.Code(err))
+		s.Require().Equal(codes.Unauthenticated, status.Code(err))
+
+		_, err = s.eventClient.CreateEventV1(s.ctx, &event.CreateEventRequestV1{
+			Title:                "foo",
+			Description:          "bar",
+			StartAt:              1664643900,
+			EndAt:                1664644000,
+			UserId:               "ef0d2079-29a2-4810-8cae-eb6729c50580",
+			NotificationDuration: 25,
+		})
+		s.Require().Equal(codes.PermissionDenied, status.Code(err))
 
 		query := `
 			SELECT count(*) AS count
@@ -154,6 +173,75 @@ func (s *EventSuite) TestCreateEvent() {
 	})
 }
 
+func (s *EventSuite) TestCreateEventConcurrent() {
+	s.SetupTest()
+
+	const workers = 20
+
+	startAt := time.Date(2022, 10, 13, 12, 0, 0, 0, time.UTC)
+
+	var wg sync.WaitGroup
+
+	errs := make(chan error, workers)
+	start := make(chan struct{})
+
+	// события сдвинуты на 10 минут и длятся час, поэтому каждое пересекается с соседними
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			<-start
+
+			from := startAt.Add(time.Duration(i%5) * 10 * time.Minute)
+
+			_, err := s.eventClient.CreateEventV1(s.ctx, &event.CreateEventRequestV1{
+				Title:   "concurrent",
+				StartAt: from.Unix(),
+				EndAt:   from.Add(time.Hour).Unix(),
+			})
+			errs <- err
+		}(i)
+	}
+
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	created := 0
+
+	for err := range errs {
+		if err == nil {
+			created++
+
+			continue
+		}
+
+		s.Require().Equal(codes.InvalidArgument, status.Code(err))
+	}
+
+	s.Require().NotZero(created)
+
+	var overlapping int
+	err := s.pgConn.QueryRowContext(
+		s.ctx,
+		`SELECT count(*) FROM events a JOIN events b
+		ON a.user_id = b.user_id AND a.id < b.id AND a.start_at < b.end_at AND b.start_at < a.end_at`,
+	).Scan(&overlapping)
+	s.Require().NoError(err)
+	s.Require().Zero(overlapping)
+
+	// ограничение не дает записать пересекающееся событие и в обход приложения
+	_, err = s.pgConn.ExecContext(
+		s.ctx,
+		`INSERT INTO events (id, title, start_at, end_at, user_id) VALUES ($1, $2, $3, $4, $5)`,
+		uuid.New(), "direct", startAt, startAt.Add(time.Hour), userID,
+	)
+	s.Require().Error(err)
+	s.Require().Contains(err.Error(), "events_user_id_period_excl")
+}
+
 func (s *EventSuite) TestGetEventsForDay() {
 	s.Run("empty", func() {
 		s.SetupTest()
@@ -264,6 +352,36 @@ func (s *EventSuite) TestGetEventsForWeek() {
 	s.Require().Equal(uint32(30), e.NotificationDuration)
 }
 
+func (s *EventSuite) TestGetEventsForWeekRecurring() {
+	s.SetupTest()
+
+	// по понедельникам и средам, вхождение 2022-10-10 исключено
+	_, err := s.pgConn.QueryContext(
+		s.ctx,
+		`INSERT INTO events (id, title, description, start_at, end_at, user_id, notification_duration, is_notified, rrule, exdates) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10);`, //nolint:lll
+		uuid.New(), "aaa", "bbb", time.Date(2022, 10, 3, 12, 30, 0, 0, time.UTC), time.Date(2022, 10, 3, 14, 30, 0, 0, time.UTC), "ef0d2079-e9a2-4810-8cae-eb6729c50580", 30, false, "FREQ=WEEKLY;BYDAY=MO,WE", "20221010T123000Z", //nolint:lll
+	)
+	s.Require().NoError(err)
+
+	resp, err := s.eventClient.GetEventsForWeekV1(s.ctx, &event.GetEventsForWeekRequestV1{
+		UserId:    "ef0d2079-e9a2-4810-8cae-eb6729c50580",
+		StartDate: "2022-10-09",
+	})
+
+	s.Require().NoError(err)
+	s.Require().Len(resp.Events, 1)
+
+	e := resp.Events[0]
+
+	s.Require().NotEmpty(e.Id)
+	s.Require().Equal("aaa", e.Title)
+	s.Require().Equal(int64(1665577800), e.StartAt)
+	s.Require().Equal(int64(1665585000), e.EndAt)
+	s.Require().Equal(int64(1665577800), e.RecurrenceId)
+	s.Require().Equal("FREQ=WEEKLY;BYDAY=MO,WE", e.Rrule)
+	s.Require().Equal([]int64{1665405000}, e.Exdates)
+}
+
 func (s *EventSuite) TestGetEventsForMonth() {
 	_, err := s.pgConn.QueryContext(
 		s.ctx,
@@ -291,6 +409,347 @@ func (s *EventSuite) TestGetEventsForMonth() {
 	s.Require().Equal(uint32(30), e.NotificationDuration)
 }
 
+func (s *EventSuite) TestGetEventsForMonthPaging() {
+	s.SetupTest()
+
+	for i, title := range []string{"ccc", "aaa", "bbb"} {
+		start := time.Date(2022, 10, 12+i, 12, 30, 0, 0, time.UTC)
+
+		_, err := s.pgConn.ExecContext(
+			s.ctx,
+			`INSERT INTO events (id, title, description, start_at, end_at, user_id, notification_duration, is_notified) VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`, //nolint:lll
+			uuid.New(), title, "", start, start.Add(time.Hour), userID, 30, false,
+		)
+		s.Require().NoError(err)
+	}
+
+	titles := make([]string, 0)
+	req := &event.GetEventsForMonthRequestV1{
+		StartDate: "2022-10-01",
+		PageSize:  2,
+		OrderBy:   "title",
+	}
+
+	for {
+		resp, err := s.eventClient.GetEventsForMonthV1(s.ctx, req)
+		s.Require().NoError(err)
+
+		for _, e := range resp.Events {
+			titles = append(titles, e.Title)
+		}
+
+		if resp.NextPageToken == "" {
+			break
+		}
+
+		req.PageToken = resp.NextPageToken
+	}
+
+	s.Require().Equal([]string{"aaa", "bbb", "ccc"}, titles)
+}
+
+func (s *EventSuite) TestGetFreeBusy() {
+	s.SetupTest()
+
+	from := time.Date(2022, 10, 12, 0, 0, 0, 0, time.UTC)
+
+	// событие начинается до запрошенного промежутка и должно учитываться в занятости
+	for _, start := range []time.Time{from.Add(-time.Hour), from.Add(10 * time.Hour)} {
+		_, err := s.pgConn.ExecContext(
+			s.ctx,
+			`INSERT INTO events (id, title, description, start_at, end_at, user_id, notification_duration, is_notified) VALUES ($1, $2, $3, $4, $5, $6, $7, $8);`, //nolint:lll
+			uuid.New(), "foo", "", start, start.Add(2*time.Hour), userID, 30, false,
+		)
+		s.Require().NoError(err)
+	}
+
+	resp, err := s.eventClient.GetFreeBusyV1(s.ctx, &event.GetFreeBusyRequestV1{
+		StartAt: from.Unix(),
+		EndAt:   from.Add(24 * time.Hour).Unix(),
+	})
+	s.Require().NoError(err)
+
+	s.Require().Len(resp.Busy, 2)
+	s.Require().Equal(from.Unix(), resp.Busy[0].StartAt)
+	s.Require().Equal(from.Add(time.Hour).Unix(), resp.Busy[0].EndAt)
+	s.Require().Equal(from.Add(10*time.Hour).Unix(), resp.Busy[1].StartAt)
+	s.Require().Equal(from.Add(12*time.Hour).Unix(), resp.Busy[1].EndAt)
+	s.Require().Len(resp.Free, 2)
+}
+
+func (s *EventSuite) TestAttendees() {
+	s.SetupTest()
+
+	const guestID = "2f0d2079-e9a2-4810-8cae-eb6729c50580"
+
+	startAt := time.Date(2022, 10, 12, 12, 30, 0, 0, time.UTC)
+
+	created, err := s.eventClient.CreateEventV1(s.ctx, &event.CreateEventRequestV1{
+		Title:   "meeting",
+		StartAt: startAt.Unix(),
+		EndAt:   startAt.Add(time.Hour).Unix(),
+	})
+	s.Require().NoError(err)
+
+	_, err = s.eventClient.InviteAttendeeV1(s.ctx, &event.InviteAttendeeRequestV1{
+		EventId: created.Event.Id,
+		UserId:  guestID,
+	})
+	s.Require().NoError(err)
+
+	guestCtx := s.userContext(context.Background(), guestID)
+
+	resp, err := s.eventClient.RespondToEventV1(guestCtx, &event.RespondToEventRequestV1{
+		EventId: created.Event.Id,
+		Status:  "accepted",
+	})
+	s.Require().NoError(err)
+	s.Require().Equal("accepted", resp.Attendee.Status)
+
+	day, err := s.eventClient.GetEventsForDayV1(guestCtx, &event.GetEventsForDayRequestV1{Date: "2022-10-12"})
+	s.Require().NoError(err)
+	s.Require().Len(day.Events, 1)
+	s.Require().Equal(created.Event.Id, day.Events[0].Id)
+	s.Require().Len(day.Events[0].Attendees, 1)
+
+	_, err = s.eventClient.CreateEventV1(guestCtx, &event.CreateEventRequestV1{
+		Title:   "conflict",
+		StartAt: startAt.Add(30 * time.Minute).Unix(),
+		EndAt:   startAt.Add(2 * time.Hour).Unix(),
+	})
+	s.Require().Equal(codes.InvalidArgument, status.Code(err))
+}
+
+func (s *EventSuite) TestTrash() {
+	s.SetupTest()
+
+	startAt := time.Date(2022, 10, 14, 12, 30, 0, 0, time.UTC)
+	newEvent := &event.CreateEventRequestV1{
+		Title:   "trashed",
+		StartAt: startAt.Unix(),
+		EndAt:   startAt.Add(time.Hour).Unix(),
+	}
+
+	trashed, err := s.eventClient.CreateEventV1(s.ctx, newEvent)
+	s.Require().NoError(err)
+
+	_, err = s.eventClient.DeleteEventV1(s.ctx, &event.DeleteEventRequestV1{Id: trashed.Event.Id})
+	s.Require().NoError(err)
+
+	trash, err := s.eventClient.ListTrashV1(s.ctx, &event.ListTrashRequestV1{})
+	s.Require().NoError(err)
+	s.Require().Len(trash.Events, 1)
+	s.Require().Equal(trashed.Event.Id, trash.Events[0].Id)
+	s.Require().NotZero(trash.Events[0].DeletedAt)
+
+	day, err := s.eventClient.GetEventsForDayV1(s.ctx, &event.GetEventsForDayRequestV1{Date: "2022-10-14"})
+	s.Require().NoError(err)
+	s.Require().Empty(day.Events)
+
+	// событие в корзине не занимает время
+	replacement, err := s.eventClient.CreateEventV1(s.ctx, newEvent)
+	s.Require().NoError(err)
+
+	_, err = s.eventClient.RestoreEventV1(s.ctx, &event.RestoreEventRequestV1{Id: trashed.Event.Id})
+	s.Require().Equal(codes.InvalidArgument, status.Code(err))
+
+	_, err = s.eventClient.DeleteEventV1(s.ctx, &event.DeleteEventRequestV1{Id: replacement.Event.Id})
+	s.Require().NoError(err)
+
+	restored, err := s.eventClient.RestoreEventV1(s.ctx, &event.RestoreEventRequestV1{Id: trashed.Event.Id})
+	s.Require().NoError(err)
+	s.Require().Zero(restored.Event.DeletedAt)
+
+	_, err = s.eventClient.PurgeEventV1(s.ctx, &event.PurgeEventRequestV1{Id: replacement.Event.Id})
+	s.Require().NoError(err)
+
+	trash, err = s.eventClient.ListTrashV1(s.ctx, &event.ListTrashRequestV1{})
+	s.Require().NoError(err)
+	s.Require().Empty(trash.Events)
+
+	_, err = s.eventClient.PurgeEventV1(s.ctx, &event.PurgeEventRequestV1{Id: trashed.Event.Id})
+	s.Require().Equal(codes.NotFound, status.Code(err))
+}
+
+func (s *EventSuite) TestHistory() {
+	s.SetupTest()
+
+	startAt := time.Date(2022, 10, 14, 12, 30, 0, 0, time.UTC)
+
+	created, err := s.eventClient.CreateEventV1(s.ctx, &event.CreateEventRequestV1{
+		Title:   "foo",
+		StartAt: startAt.Unix(),
+		EndAt:   startAt.Add(time.Hour).Unix(),
+	})
+	s.Require().NoError(err)
+
+	updated, err := s.eventClient.UpdateEventV1(s.ctx, &event.UpdateEventRequestV1{
+		Id:      created.Event.Id,
+		Title:   "bar",
+		StartAt: startAt.Add(time.Hour).Unix(),
+		EndAt:   startAt.Add(2 * time.Hour).Unix(),
+		Version: created.Event.Version,
+	})
+	s.Require().NoError(err)
+
+	history, err := s.eventClient.GetEventHistoryV1(s.ctx, &event.GetEventHistoryRequestV1{EventId: created.Event.Id})
+	s.Require().NoError(err)
+	s.Require().Len(history.Revisions, 2)
+	s.Require().Equal("created", history.Revisions[0].Action)
+	s.Require().Equal("updated", history.Revisions[1].Action)
+	s.Require().Equal(created.Event.UserId, history.Revisions[1].ActorId)
+	s.Require().Equal("title", history.Revisions[1].Changes[0].Field)
+
+	reverted, err := s.eventClient.RevertEventV1(s.ctx, &event.RevertEventRequestV1{
+		EventId:    created.Event.Id,
+		RevisionId: history.Revisions[0].Id,
+		Version:    updated.Event.Version,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal("foo", reverted.Event.Title)
+	s.Require().Equal(startAt.Unix(), reverted.Event.StartAt)
+
+	history, err = s.eventClient.GetEventHistoryV1(s.ctx, &event.GetEventHistoryRequestV1{EventId: created.Event.Id})
+	s.Require().NoError(err)
+	s.Require().Len(history.Revisions, 3)
+
+	_, err = s.eventClient.RevertEventV1(s.ctx, &event.RevertEventRequestV1{
+		EventId:    created.Event.Id,
+		RevisionId: uuid.NewString(),
+		Version:    reverted.Event.Version,
+	})
+	s.Require().Equal(codes.NotFound, status.Code(err))
+}
+
+func (s *EventSuite) TestUpdateVersion() {
+	s.SetupTest()
+
+	startAt := time.Date(2022, 10, 15, 12, 30, 0, 0, time.UTC)
+
+	created, err := s.eventClient.CreateEventV1(s.ctx, &event.CreateEventRequestV1{
+		Title:   "foo",
+		StartAt: startAt.Unix(),
+		EndAt:   startAt.Add(time.Hour).Unix(),
+	})
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(1), created.Event.Version)
+
+	var header metadata.MD
+
+	// версия может передаваться в метаданных, как заголовок If-Match в REST API
+	ctx := metadata.AppendToOutgoingContext(s.ctx, "if-match", `"1"`)
+	updated, err := s.eventClient.UpdateEventV1(ctx, &event.UpdateEventRequestV1{
+		Id:      created.Event.Id,
+		Title:   "bar",
+		StartAt: startAt.Unix(),
+		EndAt:   startAt.Add(time.Hour).Unix(),
+	}, grpc.Header(&header))
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(2), updated.Event.Version)
+	s.Require().Equal([]string{`"2"`}, header.Get("etag"))
+
+	// изменение на основе устаревшей версии отклоняется
+	_, err = s.eventClient.UpdateEventV1(s.ctx, &event.UpdateEventRequestV1{
+		Id:      created.Event.Id,
+		Title:   "baz",
+		StartAt: startAt.Unix(),
+		EndAt:   startAt.Add(time.Hour).Unix(),
+		Version: created.Event.Version,
+	})
+	s.Require().Equal(codes.Aborted, status.Code(err))
+}
+
+func (s *EventSuite) TestPatchEvent() {
+	s.SetupTest()
+
+	startAt := time.Date(2022, 10, 16, 12, 30, 0, 0, time.UTC)
+
+	created, err := s.eventClient.CreateEventV1(s.ctx, &event.CreateEventRequestV1{
+		Title:                "foo",
+		Description:          "bar",
+		StartAt:              startAt.Unix(),
+		EndAt:                startAt.Add(time.Hour).Unix(),
+		NotificationDuration: 15,
+	})
+	s.Require().NoError(err)
+
+	patched, err := s.eventClient.PatchEventV1(s.ctx, &event.PatchEventRequestV1{
+		Id:         created.Event.Id,
+		Event:      &event.EventV1{Title: "baz"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"title"}},
+		Version:    created.Event.Version,
+	})
+	s.Require().NoError(err)
+	s.Require().Equal("baz", patched.Event.Title)
+	s.Require().Equal(uint64(2), patched.Event.Version)
+
+	// поля вне маски не обнуляются
+	s.Require().Equal("bar", patched.Event.Description)
+	s.Require().Equal(startAt.Unix(), patched.Event.StartAt)
+	s.Require().Equal(uint32(15), patched.Event.NotificationDuration)
+
+	_, err = s.eventClient.PatchEventV1(s.ctx, &event.PatchEventRequestV1{
+		Id:         created.Event.Id,
+		Event:      &event.EventV1{UserId: uuid.NewString()},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"user_id"}},
+		Version:    patched.Event.Version,
+	})
+	s.Require().Equal(codes.InvalidArgument, status.Code(err))
+
+	_, err = s.eventClient.PatchEventV1(s.ctx, &event.PatchEventRequestV1{
+		Id:         created.Event.Id,
+		Event:      &event.EventV1{Description: "qux"},
+		UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"description"}},
+		Version:    created.Event.Version,
+	})
+	s.Require().Equal(codes.Aborted, status.Code(err))
+}
+
+func (s *EventSuite) TestWatchEvents() {
+	s.SetupTest()
+
+	ctx, cancel := context.WithTimeout(s.ctx, 10*time.Second)
+	defer cancel()
+
+	stream, err := s.eventClient.WatchEventsV1(ctx, &event.WatchEventsRequestV1{})
+	s.Require().NoError(err)
+
+	// подписка регистрируется при первом чтении заголовков ответа
+	_, err = stream.Header()
+	s.Require().NoError(err)
+
+	startAt := time.Date(2022, 10, 13, 12, 30, 0, 0, time.UTC)
+
+	created, err := s.eventClient.CreateEventV1(s.ctx, &event.CreateEventRequestV1{
+		Title:   "watched",
+		StartAt: startAt.Unix(),
+		EndAt:   startAt.Add(time.Hour).Unix(),
+	})
+	s.Require().NoError(err)
+
+	_, err = s.eventClient.DeleteEventV1(s.ctx, &event.DeleteEventRequestV1{Id: created.Event.Id})
+	s.Require().NoError(err)
+
+	first, err := stream.Recv()
+	s.Require().NoError(err)
+	s.Require().Equal("created", first.Type)
+	s.Require().Equal(created.Event.Id, first.Event.Id)
+
+	second, err := stream.Recv()
+	s.Require().NoError(err)
+	s.Require().Equal("deleted", second.Type)
+	// ревизии журнала общие для всех пользователей, поэтому между изменениями могут быть чужие
+	s.Require().Greater(second.Revision, first.Revision)
+
+	// после переподключения пропущенные изменения передаются повторно
+	resumed, err := s.eventClient.WatchEventsV1(ctx, &event.WatchEventsRequestV1{AfterRevision: first.Revision})
+	s.Require().NoError(err)
+
+	again, err := resumed.Recv()
+	s.Require().NoError(err)
+	s.Require().Equal(second.Revision, again.Revision)
+}
+
 func (s *EventSuite) TestSendEventNotification() {
 	startAt := time.Now().Add(time.Hour * 24).UTC()
 	endAt := startAt.Add(30 * time.Minute).UTC()
@@ -315,6 +774,28 @@ func (s *EventSuite) TestSendEventNotification() {
 	s.Require().NoError(err)
 
 	s.Require().True(isNotified)
+
+	// напоминание сохранено в исходящем ящике ровно один раз и доставлено рассыльщиком
+	var total, delivered int
+	err = s.pgConn.QueryRowContext(
+		s.ctx,
+		`SELECT count(*), count(delivered_at) FROM notification_outbox`,
+	).Scan(&total, &delivered)
+	s.Require().NoError(err)
+
+	s.Require().Equal(1, total)
+	s.Require().Equal(1, delivered)
+}
+
+func (s *EventSuite) TestHealthCheck() {
+	client := healthpb.NewHealthClient(s.grpcConn)
+
+	// проверка состояния доступна без токена доступа
+	for _, service := range []string{"", event.EventService_ServiceDesc.ServiceName} {
+		resp, err := client.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		s.Require().NoError(err)
+		s.Require().Equal(healthpb.HealthCheckResponse_SERVING, resp.Status)
+	}
 }
 
 func TestEventSuite(t *testing.T) {
@@ -360,6 +841,20 @@ func (s *EventSuite) setupGRPC() {
 	s.eventClient = event.NewEventServiceClient(grpcConn)
 }
 
+// userContext возвращает контекст с токеном доступа пользователя.
+func (s *EventSuite) userContext(ctx context.Context, sub string) context.Context {
+	secret := os.Getenv("AUTH_HMAC_SECRET")
+	s.Require().NotEmpty(secret)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, &jwt.RegisteredClaims{
+		Subject:   sub,
+		ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+	}).SignedString([]byte(secret))
+	s.Require().NoError(err)
+
+	return metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+token)
+}
+
 func (s *EventSuite) cleanTables(tables ...string) {
 	_, err := s.pgConn.ExecContext(
 		s.ctx,
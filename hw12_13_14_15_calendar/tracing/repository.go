@@ -0,0 +1,214 @@
+package tracing
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+)
+
+// InstrumentRepository оборачивает хранилище, создавая спан на каждый вызов его методов.
+func InstrumentRepository(driver string, r calendar.Storage) calendar.Storage {
+	return tracedRepository{r: r, driver: driver}
+}
+
+// tracedRepository хранилище, трассирующее вызовы.
+type tracedRepository struct {
+	r      calendar.Storage
+	driver string
+}
+
+// start начинает спан вызова метода хранилища.
+func (repo tracedRepository) start(ctx context.Context, method string) (context.Context, trace.Span) {
+	return Start(ctx, "Repository."+method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.DBSystemKey.String(repo.driver),
+			semconv.DBOperationKey.String(method),
+		),
+	)
+}
+
+func (repo tracedRepository) CreateEvent(ctx context.Context, e *calendar.Event) (res *calendar.Event, err error) {
+	ctx, span := repo.start(ctx, "CreateEvent")
+	defer func() { End(span, err) }()
+
+	return repo.r.CreateEvent(ctx, e)
+}
+
+func (repo tracedRepository) UpdateEvent(
+	ctx context.Context,
+	id uuid.UUID,
+	e *calendar.Event,
+) (res *calendar.Event, err error) {
+	ctx, span := repo.start(ctx, "UpdateEvent")
+	span.SetAttributes(attribute.String("event.id", id.String()))
+	defer func() { End(span, err) }()
+
+	return repo.r.UpdateEvent(ctx, id, e)
+}
+
+func (repo tracedRepository) PatchEvent(
+	ctx context.Context,
+	id uuid.UUID,
+	e *calendar.Event,
+	fields []calendar.EventField,
+) (res *calendar.Event, err error) {
+	ctx, span := repo.start(ctx, "PatchEvent")
+	span.SetAttributes(attribute.String("event.id", id.String()))
+	defer func() { End(span, err) }()
+
+	return repo.r.PatchEvent(ctx, id, e, fields)
+}
+
+func (repo tracedRepository) DeleteEvent(ctx context.Context, ids ...uuid.UUID) (err error) {
+	ctx, span := repo.start(ctx, "DeleteEvent")
+	span.SetAttributes(attribute.Int("event.count", len(ids)))
+	defer func() { End(span, err) }()
+
+	return repo.r.DeleteEvent(ctx, ids...)
+}
+
+func (repo tracedRepository) FindTrash(
+	ctx context.Context,
+	filter calendar.TrashFilter,
+) (res []*calendar.Event, err error) {
+	ctx, span := repo.start(ctx, "FindTrash")
+	defer func() {
+		span.SetAttributes(attribute.Int("event.count", len(res)))
+		End(span, err)
+	}()
+
+	return repo.r.FindTrash(ctx, filter)
+}
+
+func (repo tracedRepository) RestoreEvent(ctx context.Context, id uuid.UUID) (res *calendar.Event, err error) {
+	ctx, span := repo.start(ctx, "RestoreEvent")
+	span.SetAttributes(attribute.String("event.id", id.String()))
+	defer func() { End(span, err) }()
+
+	return repo.r.RestoreEvent(ctx, id)
+}
+
+func (repo tracedRepository) PurgeEvent(ctx context.Context, ids ...uuid.UUID) (err error) {
+	ctx, span := repo.start(ctx, "PurgeEvent")
+	span.SetAttributes(attribute.Int("event.count", len(ids)))
+	defer func() { End(span, err) }()
+
+	return repo.r.PurgeEvent(ctx, ids...)
+}
+
+func (repo tracedRepository) FindEvents(
+	ctx context.Context,
+	filter calendar.EventFilter,
+) (res []*calendar.Event, err error) {
+	ctx, span := repo.start(ctx, "FindEvents")
+	defer func() {
+		span.SetAttributes(attribute.Int("event.count", len(res)))
+		End(span, err)
+	}()
+
+	return repo.r.FindEvents(ctx, filter)
+}
+
+func (repo tracedRepository) FindEventByID(ctx context.Context, id uuid.UUID) (res *calendar.Event, err error) {
+	ctx, span := repo.start(ctx, "FindEventByID")
+	span.SetAttributes(attribute.String("event.id", id.String()))
+	defer func() { End(span, err) }()
+
+	return repo.r.FindEventByID(ctx, id)
+}
+
+func (repo tracedRepository) FindEventRevisions(
+	ctx context.Context,
+	eventID uuid.UUID,
+) (res []*calendar.Revision, err error) {
+	ctx, span := repo.start(ctx, "FindEventRevisions")
+	span.SetAttributes(attribute.String("event.id", eventID.String()))
+	defer func() {
+		span.SetAttributes(attribute.Int("revision.count", len(res)))
+		End(span, err)
+	}()
+
+	return repo.r.FindEventRevisions(ctx, eventID)
+}
+
+func (repo tracedRepository) InviteAttendee(
+	ctx context.Context,
+	eventID, userID uuid.UUID,
+) (res *calendar.Attendee, err error) {
+	ctx, span := repo.start(ctx, "InviteAttendee")
+	span.SetAttributes(attribute.String("event.id", eventID.String()))
+	defer func() { End(span, err) }()
+
+	return repo.r.InviteAttendee(ctx, eventID, userID)
+}
+
+func (repo tracedRepository) RemoveAttendee(ctx context.Context, eventID, userID uuid.UUID) (err error) {
+	ctx, span := repo.start(ctx, "RemoveAttendee")
+	span.SetAttributes(attribute.String("event.id", eventID.String()))
+	defer func() { End(span, err) }()
+
+	return repo.r.RemoveAttendee(ctx, eventID, userID)
+}
+
+func (repo tracedRepository) RespondAttendee(
+	ctx context.Context,
+	eventID, userID uuid.UUID,
+	status calendar.RSVPStatus,
+) (res *calendar.Attendee, err error) {
+	ctx, span := repo.start(ctx, "RespondAttendee")
+	span.SetAttributes(attribute.String("event.id", eventID.String()))
+	defer func() { End(span, err) }()
+
+	return repo.r.RespondAttendee(ctx, eventID, userID, status)
+}
+
+func (repo tracedRepository) ClaimNotifications(ctx context.Context, events ...*calendar.Event) (err error) {
+	ctx, span := repo.start(ctx, "ClaimNotifications")
+	span.SetAttributes(attribute.Int("event.count", len(events)))
+	defer func() { End(span, err) }()
+
+	return repo.r.ClaimNotifications(ctx, events...)
+}
+
+func (repo tracedRepository) FindPendingNotifications(
+	ctx context.Context,
+	limit int,
+) (res []*calendar.Notification, err error) {
+	ctx, span := repo.start(ctx, "FindPendingNotifications")
+	defer func() {
+		span.SetAttributes(attribute.Int("notification.count", len(res)))
+		End(span, err)
+	}()
+
+	return repo.r.FindPendingNotifications(ctx, limit)
+}
+
+func (repo tracedRepository) MarkNotificationsPublished(ctx context.Context, ids ...uuid.UUID) (err error) {
+	ctx, span := repo.start(ctx, "MarkNotificationsPublished")
+	span.SetAttributes(attribute.Int("notification.count", len(ids)))
+	defer func() { End(span, err) }()
+
+	return repo.r.MarkNotificationsPublished(ctx, ids...)
+}
+
+func (repo tracedRepository) IsNotificationDelivered(ctx context.Context, id uuid.UUID) (res bool, err error) {
+	ctx, span := repo.start(ctx, "IsNotificationDelivered")
+	span.SetAttributes(attribute.String("notification.id", id.String()))
+	defer func() { End(span, err) }()
+
+	return repo.r.IsNotificationDelivered(ctx, id)
+}
+
+func (repo tracedRepository) AckNotification(ctx context.Context, id uuid.UUID) (err error) {
+	ctx, span := repo.start(ctx, "AckNotification")
+	span.SetAttributes(attribute.String("notification.id", id.String()))
+	defer func() { End(span, err) }()
+
+	return repo.r.AckNotification(ctx, id)
+}
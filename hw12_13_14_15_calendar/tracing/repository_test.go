@@ -0,0 +1,40 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+
+	"github.com/RomanSarvarov/otus_go_home_work/calendar"
+	"github.com/RomanSarvarov/otus_go_home_work/calendar/inmem"
+)
+
+func TestInstrumentRepository(t *testing.T) {
+	sr := recordSpans(t)
+
+	ctx, parent := Start(context.Background(), "request")
+
+	repo := InstrumentRepository(inmem.Key, inmem.New())
+
+	_, err := repo.FindEventByID(ctx, uuid.New())
+	require.ErrorIs(t, err, calendar.ErrNotFound)
+
+	_, err = repo.FindEvents(ctx, calendar.EventFilter{})
+	require.NoError(t, err)
+
+	parent.End()
+
+	spans := sr.Ended()
+	require.Len(t, spans, 3)
+
+	require.Equal(t, "Repository.FindEventByID", spans[0].Name())
+	require.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	require.Contains(t, spans[0].Attributes(), semconv.DBSystemKey.String(inmem.Key))
+	require.Len(t, spans[0].Events(), 1)
+
+	require.Equal(t, "Repository.FindEvents", spans[1].Name())
+	require.Empty(t, spans[1].Events())
+}
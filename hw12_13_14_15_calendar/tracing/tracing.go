@@ -0,0 +1,119 @@
+// Package tracing настраивает трассировку OpenTelemetry.
+package tracing
+
+import (
+	"context"
+	"os"
+
+	"github.com/pkg/errors"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.12.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// ExporterNone трассировка отключена.
+	ExporterNone = "none"
+
+	// ExporterStdout спаны выводятся в stdout.
+	ExporterStdout = "stdout"
+
+	// ExporterOTLP спаны отправляются в OTLP коллектор по GRPC.
+	ExporterOTLP = "otlp"
+)
+
+// instrumentationName имя библиотеки инструментирования сервиса.
+const instrumentationName = "github.com/RomanSarvarov/otus_go_home_work/calendar"
+
+// Config настройки трассировки.
+type Config struct {
+	// ServiceName имя сервиса в спанах.
+	ServiceName string
+
+	// Exporter способ экспорта спанов: none, stdout, otlp.
+	Exporter string
+
+	// OTLPEndpoint адрес OTLP коллектора.
+	OTLPEndpoint string
+
+	// SampleRatio доля трассируемых запросов.
+	SampleRatio float64
+}
+
+// ShutdownFunc выгружает накопленные спаны и останавливает экспорт.
+type ShutdownFunc func(ctx context.Context) error
+
+// Init настраивает глобальный провайдер трассировки и распространение контекста.
+// Контекст распространяется и при отключенной трассировке, чтобы не разрывать
+// трассы, начатые другими сервисами.
+func Init(ctx context.Context, cfg Config) (ShutdownFunc, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	var exporter sdktrace.SpanExporter
+
+	switch cfg.Exporter {
+	case "", ExporterNone:
+		return func(context.Context) error { return nil }, nil
+	case ExporterStdout:
+		e, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, errors.Wrap(err, "create stdout exporter")
+		}
+
+		exporter = e
+	case ExporterOTLP:
+		e, err := otlptracegrpc.New(ctx,
+			otlptracegrpc.WithEndpoint(cfg.OTLPEndpoint),
+			otlptracegrpc.WithInsecure(),
+		)
+		if err != nil {
+			return nil, errors.Wrap(err, "create otlp exporter")
+		}
+
+		exporter = e
+	default:
+		return nil, errors.Errorf("tracing exporter `%s` not found", cfg.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(
+		semconv.SchemaURL,
+		semconv.ServiceNameKey.String(cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "create tracing resource")
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	otel.SetTracerProvider(tp)
+
+	return tp.Shutdown, nil
+}
+
+// Start начинает спан с именем name.
+func Start(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// End завершает спан, отмечая в нем ошибку err.
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	span.End()
+}
@@ -0,0 +1,74 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// recordSpans подменяет глобальный провайдер трассировки записывающим спаны.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	sr := tracetest.NewSpanRecorder()
+
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr)))
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	return sr
+}
+
+func TestInit(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		exporter string
+		wantErr  bool
+	}{
+		{name: "disabled", exporter: ExporterNone},
+		{name: "default", exporter: ""},
+		{name: "stdout", exporter: ExporterStdout},
+		{name: "unknown", exporter: "jaeger", wantErr: true},
+	}
+
+	prev := otel.GetTracerProvider()
+	defer otel.SetTracerProvider(prev)
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			shutdown, err := Init(ctx, Config{ServiceName: "test", Exporter: tt.exporter, SampleRatio: 1})
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			require.NoError(t, shutdown(ctx))
+		})
+	}
+}
+
+func TestEnd(t *testing.T) {
+	sr := recordSpans(t)
+
+	_, span := Start(context.Background(), "ok")
+	End(span, nil)
+
+	_, span = Start(context.Background(), "failed")
+	End(span, errors.New("boom"))
+
+	spans := sr.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, codes.Unset, spans[0].Status().Code)
+	require.Equal(t, codes.Error, spans[1].Status().Code)
+	require.Equal(t, "boom", spans[1].Status().Description)
+}